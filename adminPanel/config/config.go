@@ -5,22 +5,26 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DatabaseConfig содержит настройки подключения к базе данных PostgreSQL.
 // Включает параметры хоста, порта, пользователя, пароля, имени базы данных,
-// режима SSL и размеров пула соединений.
+// режима SSL, размеров пула соединений и порога медленного запроса (0 отключает лог медленных запросов).
 type DatabaseConfig struct {
-	Host        string
-	Port        int
-	User        string
-	Password    string
-	Name        string
-	SSLMode     string
-	MinPoolSize int
-	MaxPoolSize int
+	Host               string
+	Port               int
+	User               string
+	Password           string
+	Name               string
+	SSLMode            string
+	MinPoolSize        int
+	MaxPoolSize        int
+	SlowQueryThreshold time.Duration
 }
 
 // URL возвращает строку подключения к базе данных в формате PostgreSQL DSN.
@@ -42,16 +46,40 @@ type OTelConfig struct {
 }
 
 // KeycloakConfig содержит настройки для интеграции с Keycloak.
-// Включает URL issuer, audience, JWKS URL, client ID, secret и имя приложения.
+// Включает URL issuer, audience, JWKS URL, client ID, secret, имя приложения, scopes для OAuth в Swagger,
+// роли для чтения, изменения и администрирования, а также пути к данным пользователя и ролям в claims токена
+// и список полей пользователя, обязательных в токене.
 type KeycloakConfig struct {
-	IssuerURL    string
-	Audience     string
-	JWKSURL      string
-	ClientID     string
-	ClientSecret string
-	AppName      string
+	IssuerURL      string
+	Audience       string
+	JWKSURL        string
+	ClientID       string
+	ClientSecret   string
+	AppName        string
+	AdminRole      string
+	ViewerRole     string
+	EditorRole     string
+	SubjectClaim   string
+	UsernameClaim  string
+	NameClaim      string
+	EmailClaim     string
+	RoleClaimPaths []string
+	RequiredClaims []string
+	Scopes         []string
 }
 
+// allowedRequiredClaims перечисляет поля пользователя, допустимые в KEYCLOAK_REQUIRED_CLAIMS.
+var allowedRequiredClaims = []string{"subject", "username", "name", "email", "roles"}
+
+// allowedLocales перечисляет языки сообщений об ошибках, допустимые в DEFAULT_LOCALE.
+var allowedLocales = []string{"en", "ru"}
+
+// allowedLogLevels перечисляет уровни структурированного лога, допустимые в LOG_LEVEL.
+var allowedLogLevels = []string{"debug", "info", "warn", "error"}
+
+// allowedEventPublishers перечисляет реализации шины доменных событий, допустимые в DOMAIN_EVENTS_PUBLISHER.
+var allowedEventPublishers = []string{"noop", "log"}
+
 // CORSConfig содержит настройки для Cross-Origin Resource Sharing (CORS).
 // Определяет разрешенные origins, методы, заголовки, credentials и exposed headers.
 type CORSConfig struct {
@@ -63,24 +91,66 @@ type CORSConfig struct {
 }
 
 // ServerConfig содержит настройки сервера.
-// Включает адрес прослушивания, имя приложения и корневой путь API.
+// Включает адрес прослушивания, имя приложения, корневой путь API и ограничения размера тела запроса:
+// MaxRequestBodyBytes для любых запросов (в том числе multipart с изображением)
+// и более строгий MaxJSONBodyBytes для запросов, не являющихся multipart.
+// RequestTimeout - общий таймаут обработки запроса; ListRequestTimeout, UploadRequestTimeout
+// и ExportRequestTimeout переопределяют его для списков, загрузки изображений и выгрузки/импорта.
+// ShutdownTimeout - время ожидания завершения обрабатываемых запросов при остановке сервера.
+// IdempotencyKeyTTL - время хранения ответов на запросы создания с заголовком Idempotency-Key.
+// DefaultLocale - язык сообщений об ошибках (en или ru) для запросов без заголовка Accept-Language.
+// LogLevel - минимальный уровень структурированного лога запросов (debug, info, warn или error).
 type ServerConfig struct {
-	Address  string
-	AppName  string
-	RootPath string
+	Address              string
+	AppName              string
+	RootPath             string
+	MaxRequestBodyBytes  int
+	MaxJSONBodyBytes     int
+	RequestTimeout       time.Duration
+	ListRequestTimeout   time.Duration
+	UploadRequestTimeout time.Duration
+	ExportRequestTimeout time.Duration
+	ShutdownTimeout      time.Duration
+	IdempotencyKeyTTL    time.Duration
+	DefaultLocale        string
+	LogLevel             string
 }
 
 // MinioConfig содержит настройки для подключения к MinIO (S3-compatible storage).
-// Включает endpoint, ключи доступа, имя bucket, флаг SSL и публичный URL.
+// Включает endpoint, ключи доступа, имя bucket, флаг SSL, публичный URL,
+// таймауты скачивания изображений по внешнему URL, срок действия presigned URL для загрузки
+// таймаут проверки доступности хранилища в /health/s3, а также ограничения пакетной загрузки:
+// максимальное число файлов в одном запросе и число одновременных загрузок.
 type MinioConfig struct {
-	Endpoint  string
-	AccessKey string
-	SecretKey string
-	Bucket    string
-	UseSSL    bool
-	PublicURL string
+	Endpoint               string
+	AccessKey              string
+	SecretKey              string
+	Bucket                 string
+	UseSSL                 bool
+	PublicURL              string
+	DownloadConnectTimeout time.Duration
+	DownloadReadTimeout    time.Duration
+	PresignExpiry          time.Duration
+	MaxImageSizeBytes      int64
+	HealthCheckTimeout     time.Duration
+	BatchMaxFiles          int
+	UploadConcurrency      int
 }
 
+// Ограничения размера загружаемых изображений: значение по умолчанию (10 МБ)
+// и верхняя граница настройки MINIO_MAX_IMAGE_SIZE (100 МБ).
+const (
+	defaultMaxImageSize = 10 * 1024 * 1024
+	maxImageSizeLimit   = 100 * 1024 * 1024
+)
+
+// Ограничения размера тела запроса по умолчанию: общее (12 МБ, с запасом для изображения
+// максимального размера по умолчанию и остальных полей формы) и для JSON-запросов (1 МБ).
+const (
+	defaultMaxRequestBody = 12 * 1024 * 1024
+	defaultMaxJSONBody    = 1024 * 1024
+)
+
 // TestModuleConfig содержит настройки для тестового модуля.
 // Включает базовый URL и флаг включения модуля.
 type TestModuleConfig struct {
@@ -88,8 +158,83 @@ type TestModuleConfig struct {
 	Enabled bool
 }
 
+// ValidationConfig содержит настройки валидации входных данных.
+// StrictUUID включает строгую проверку UUID (только ненулевые UUID версии 4).
+type ValidationConfig struct {
+	StrictUUID bool
+}
+
+// CategoryConfig содержит настройки работы с категориями.
+// CacheTTL - время жизни кэша списка категорий и отдельных категорий в памяти; 0 отключает кэш.
+type CategoryConfig struct {
+	CacheTTL time.Duration
+}
+
+// StatsConfig содержит настройки сводной статистики.
+// CacheTTL - время жизни кэша результата GET /api/v1/stats/overview; 0 отключает кэш.
+type StatsConfig struct {
+	CacheTTL time.Duration
+}
+
+// eventsChannelPattern ограничивает имя канала CONTENT_EVENTS_CHANNEL допустимым идентификатором PostgreSQL.
+var eventsChannelPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// EventsConfig содержит настройки публикации событий изменения контента.
+// Channel - канал PostgreSQL NOTIFY, который слушает publicSide для сброса своего кэша;
+// пустое значение отключает публикацию, и publicSide полагается только на TTL кэша.
+// Publisher - реализация шины доменных событий: noop (события не отправляются) или log (запись в лог).
+type EventsConfig struct {
+	Channel   string
+	Publisher string
+}
+
+// CourseConfig содержит настройки работы с курсами.
+// SoftDelete включает мягкое удаление курсов по умолчанию (заполнение deleted_at вместо удаления строки).
+type CourseConfig struct {
+	SoftDelete bool
+}
+
+// HealthConfig содержит настройки проверок здоровья.
+// DeepCheckEnabled включает эндпоинт /health/deep, выполняющий пробную запись в БД.
+// ExemptPaths - префиксы инфраструктурных путей, которые не проходят аутентификацию и ограничение запросов.
+type HealthConfig struct {
+	DeepCheckEnabled bool
+	ExemptPaths      []string
+}
+
+// MetricsConfig содержит настройки эндпоинта /metrics в формате Prometheus.
+// Enabled включает сбор метрик HTTP-запросов, пула соединений с БД и загрузок в S3 независимо от OpenTelemetry.
+type MetricsConfig struct {
+	Enabled bool
+}
+
+// SwaggerConfig содержит настройки документации API.
+// Enabled регистрирует Swagger UI и раздачу /doc; по умолчанию совпадает с DEBUG.
+// SpecWithAuth при выключенном Swagger оставляет /doc/swagger.json доступным с токеном Keycloak.
+type SwaggerConfig struct {
+	Enabled      bool
+	SpecWithAuth bool
+}
+
+// ContentConfig содержит настройки хранения контента уроков.
+// Compress включает gzip-сжатие контента размером не меньше CompressMinSize байт.
+// RequireBlocks требует контент в формате JSON-массива блоков; при выключенном флаге допускается обычный текст.
+type ContentConfig struct {
+	Compress        bool
+	CompressMinSize int
+	RequireBlocks   bool
+}
+
+// SearchConfig содержит общие ограничения для поисковых запросов.
+// MinQueryLength - минимальная длина запроса в символах, MaxResults - жесткий предел
+// количества строк, которые можно получить по одному запросу с учетом пагинации.
+type SearchConfig struct {
+	MinQueryLength int
+	MaxResults     int
+}
+
 // Settings объединяет все конфигурационные структуры в одну.
-// Содержит настройки базы данных, OTel, Keycloak, CORS, сервера, MinIO, тестового модуля и флаг отладки.
+// Содержит настройки базы данных, OTel, Keycloak, CORS, сервера, MinIO, тестового модуля, валидации, категорий, событий изменения, курсов, проверок здоровья, метрик Prometheus, хранения контента, поиска и флаг отладки.
 type Settings struct {
 	Database   DatabaseConfig
 	OTel       OTelConfig
@@ -99,9 +244,21 @@ type Settings struct {
 	Debug      bool
 	Minio      MinioConfig
 	TestModule TestModuleConfig
+	Validation ValidationConfig
+	Category   CategoryConfig
+	Stats      StatsConfig
+	Events     EventsConfig
+	Course     CourseConfig
+	Health     HealthConfig
+	Metrics    MetricsConfig
+	Swagger    SwaggerConfig
+	Content    ContentConfig
+	Search     SearchConfig
 }
 
-// Validate проверяет наличие обязательных переменных окружения для базы данных.
+// Validate проверяет наличие обязательных переменных окружения для базы данных,
+// допустимость максимального размера изображения и ограничений пакетной загрузки,
+// положительность таймаутов запросов, наличие scope openid в KEYCLOAK_SCOPES и корректность путей к claims токена.
 // Возвращает ошибку, если отсутствуют DB_HOST, DB_USER, DB_PASSWORD или DB_NAME (или DATABASE_URL).
 func (s *Settings) Validate() error {
 	var missingVars []string
@@ -123,21 +280,135 @@ func (s *Settings) Validate() error {
 		return fmt.Errorf("missing required environment variables: %s", strings.Join(missingVars, ", "))
 	}
 
+	if s.Minio.MaxImageSizeBytes <= 0 || s.Minio.MaxImageSizeBytes > maxImageSizeLimit {
+		return fmt.Errorf("MINIO_MAX_IMAGE_SIZE must be between 1 and %d bytes, got %d", maxImageSizeLimit, s.Minio.MaxImageSizeBytes)
+	}
+
+	if s.Minio.BatchMaxFiles < 1 {
+		return fmt.Errorf("MINIO_BATCH_MAX_FILES must be positive, got %d", s.Minio.BatchMaxFiles)
+	}
+	if s.Minio.UploadConcurrency < 1 {
+		return fmt.Errorf("MINIO_UPLOAD_CONCURRENCY must be positive, got %d", s.Minio.UploadConcurrency)
+	}
+
+	// Тело multipart-запроса содержит изображение вместе с остальными полями формы, поэтому общий лимит
+	// должен быть больше MINIO_MAX_IMAGE_SIZE, иначе такие загрузки отклонялись бы с 413 до проверки размера изображения.
+	if int64(s.Server.MaxRequestBodyBytes) <= s.Minio.MaxImageSizeBytes {
+		return fmt.Errorf("MAX_REQUEST_BODY_BYTES (%d) must be greater than MINIO_MAX_IMAGE_SIZE (%d)", s.Server.MaxRequestBodyBytes, s.Minio.MaxImageSizeBytes)
+	}
+	if s.Server.MaxJSONBodyBytes <= 0 || s.Server.MaxJSONBodyBytes > s.Server.MaxRequestBodyBytes {
+		return fmt.Errorf("MAX_JSON_BODY_BYTES must be between 1 and MAX_REQUEST_BODY_BYTES (%d), got %d", s.Server.MaxRequestBodyBytes, s.Server.MaxJSONBodyBytes)
+	}
+
+	if s.Server.RootPath != "" && !strings.HasPrefix(s.Server.RootPath, "/") {
+		return fmt.Errorf("ROOT_PATH must be empty or start with \"/\", got %q", s.Server.RootPath)
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"REQUEST_TIMEOUT", s.Server.RequestTimeout},
+		{"LIST_REQUEST_TIMEOUT", s.Server.ListRequestTimeout},
+		{"UPLOAD_REQUEST_TIMEOUT", s.Server.UploadRequestTimeout},
+		{"EXPORT_REQUEST_TIMEOUT", s.Server.ExportRequestTimeout},
+		{"SHUTDOWN_TIMEOUT", s.Server.ShutdownTimeout},
+		{"IDEMPOTENCY_KEY_TTL", s.Server.IdempotencyKeyTTL},
+	}
+	for _, t := range timeouts {
+		if t.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", t.name, t.value)
+		}
+	}
+
+	if !slices.Contains(s.Keycloak.Scopes, "openid") {
+		return fmt.Errorf("KEYCLOAK_SCOPES must include \"openid\", got %q", strings.Join(s.Keycloak.Scopes, ","))
+	}
+
+	claimPaths := []struct {
+		name  string
+		paths []string
+	}{
+		{"KEYCLOAK_CLAIM_SUBJECT", []string{s.Keycloak.SubjectClaim}},
+		{"KEYCLOAK_CLAIM_USERNAME", []string{s.Keycloak.UsernameClaim}},
+		{"KEYCLOAK_CLAIM_NAME", []string{s.Keycloak.NameClaim}},
+		{"KEYCLOAK_CLAIM_EMAIL", []string{s.Keycloak.EmailClaim}},
+		{"KEYCLOAK_ROLE_CLAIM_PATHS", s.Keycloak.RoleClaimPaths},
+	}
+	for _, claim := range claimPaths {
+		if len(claim.paths) == 0 {
+			return fmt.Errorf("%s must not be empty", claim.name)
+		}
+		for _, path := range claim.paths {
+			if path == "" || slices.Contains(strings.Split(path, "."), "") {
+				return fmt.Errorf("%s must be a dot-separated claim path without empty segments, got %q", claim.name, path)
+			}
+		}
+	}
+	for _, field := range s.Keycloak.RequiredClaims {
+		if !slices.Contains(allowedRequiredClaims, field) {
+			return fmt.Errorf("KEYCLOAK_REQUIRED_CLAIMS must contain only %s, got %q", strings.Join(allowedRequiredClaims, ", "), field)
+		}
+	}
+
+	if s.Events.Channel != "" && !eventsChannelPattern.MatchString(s.Events.Channel) {
+		return fmt.Errorf("CONTENT_EVENTS_CHANNEL must be a lowercase identifier (letters, digits, underscores, up to 63 characters), got %q", s.Events.Channel)
+	}
+
+	if !slices.Contains(allowedLocales, s.Server.DefaultLocale) {
+		return fmt.Errorf("DEFAULT_LOCALE must be one of %s, got %q", strings.Join(allowedLocales, ", "), s.Server.DefaultLocale)
+	}
+
+	if !slices.Contains(allowedLogLevels, s.Server.LogLevel) {
+		return fmt.Errorf("LOG_LEVEL must be one of %s, got %q", strings.Join(allowedLogLevels, ", "), s.Server.LogLevel)
+	}
+
+	if !slices.Contains(allowedEventPublishers, s.Events.Publisher) {
+		return fmt.Errorf("DOMAIN_EVENTS_PUBLISHER must be one of %s, got %q", strings.Join(allowedEventPublishers, ", "), s.Events.Publisher)
+	}
+
+	if s.Database.SlowQueryThreshold < 0 {
+		return fmt.Errorf("DB_SLOW_QUERY_MS must not be negative (0 disables the slow query log), got %d", s.Database.SlowQueryThreshold.Milliseconds())
+	}
+
+	if s.Category.CacheTTL < 0 {
+		return fmt.Errorf("CATEGORY_CACHE_TTL must not be negative (0 disables the cache), got %s", s.Category.CacheTTL)
+	}
+
+	if s.Stats.CacheTTL < 0 {
+		return fmt.Errorf("STATS_CACHE_TTL must not be negative (0 disables the cache), got %s", s.Stats.CacheTTL)
+	}
+
+	if s.Minio.HealthCheckTimeout <= 0 {
+		return fmt.Errorf("MINIO_HEALTH_CHECK_TIMEOUT must be positive, got %s", s.Minio.HealthCheckTimeout)
+	}
+
 	return nil
 }
 
 // NewSettings создает новый экземпляр Settings, загружая конфигурацию из переменных окружения.
 // Использует вспомогательные функции для загрузки каждой части конфигурации.
 func NewSettings() *Settings {
+	debug := getEnvAsBool("DEBUG", false)
 	return &Settings{
 		Database:   loadDatabaseConfig(),
 		OTel:       loadOTelConfig(),
 		Keycloak:   loadKeycloakConfig(),
 		CORS:       loadCORSConfig(),
 		Server:     loadServerConfig(),
-		Debug:      getEnvAsBool("DEBUG", false),
+		Debug:      debug,
 		Minio:      loadMinioConfig(),
 		TestModule: loadTestModuleConfig(),
+		Validation: loadValidationConfig(),
+		Category:   loadCategoryConfig(),
+		Stats:      loadStatsConfig(),
+		Events:     loadEventsConfig(),
+		Course:     loadCourseConfig(),
+		Health:     loadHealthConfig(),
+		Metrics:    loadMetricsConfig(),
+		Swagger:    loadSwaggerConfig(debug),
+		Content:    loadContentConfig(),
+		Search:     loadSearchConfig(),
 	}
 }
 
@@ -148,6 +419,8 @@ func loadDatabaseConfig() DatabaseConfig {
 		MinPoolSize: getEnvAsInt("DATABASE_POOL_MIN_SIZE", 5),
 		MaxPoolSize: getEnvAsInt("DATABASE_POOL_MAX_SIZE", 20),
 		SSLMode:     getEnv("DB_SSLMODE", "disable"),
+
+		SlowQueryThreshold: time.Duration(getEnvAsInt("DB_SLOW_QUERY_MS", 200)) * time.Millisecond,
 	}
 
 	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
@@ -239,12 +512,22 @@ func loadKeycloakConfig() KeycloakConfig {
 	}
 
 	return KeycloakConfig{
-		IssuerURL:    issuer,
-		Audience:     os.Getenv("KEYCLOAK_AUDIENCE"),
-		JWKSURL:      jwksURL,
-		ClientID:     os.Getenv("KEYCLOAK_CLIENT_ID"),
-		ClientSecret: os.Getenv("KEYCLOAK_CLIENT_SECRET"),
-		AppName:      os.Getenv("KEYCLOAK_APP_NAME"),
+		IssuerURL:      issuer,
+		Audience:       os.Getenv("KEYCLOAK_AUDIENCE"),
+		JWKSURL:        jwksURL,
+		ClientID:       os.Getenv("KEYCLOAK_CLIENT_ID"),
+		ClientSecret:   os.Getenv("KEYCLOAK_CLIENT_SECRET"),
+		AppName:        os.Getenv("KEYCLOAK_APP_NAME"),
+		AdminRole:      getEnv("KEYCLOAK_ADMIN_ROLE", "admin"),
+		ViewerRole:     getEnv("KEYCLOAK_VIEWER_ROLE", "viewer"),
+		EditorRole:     getEnv("KEYCLOAK_EDITOR_ROLE", "editor"),
+		SubjectClaim:   getEnv("KEYCLOAK_CLAIM_SUBJECT", "sub"),
+		UsernameClaim:  getEnv("KEYCLOAK_CLAIM_USERNAME", "preferred_username"),
+		NameClaim:      getEnv("KEYCLOAK_CLAIM_NAME", "name"),
+		EmailClaim:     getEnv("KEYCLOAK_CLAIM_EMAIL", "email"),
+		RoleClaimPaths: parseList(getEnv("KEYCLOAK_ROLE_CLAIM_PATHS", "realm_access.roles")),
+		RequiredClaims: parseList(getEnv("KEYCLOAK_REQUIRED_CLAIMS", "subject")),
+		Scopes:         parseList(getEnv("KEYCLOAK_SCOPES", "openid,profile,email")),
 	}
 }
 
@@ -253,8 +536,8 @@ func loadKeycloakConfig() KeycloakConfig {
 func loadCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowOrigins:     getEnv("CORS_ALLOW_ORIGINS", "*"),
-		AllowMethods:     getEnv("CORS_ALLOW_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
-		AllowHeaders:     getEnv("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization"),
+		AllowMethods:     getEnv("CORS_ALLOW_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+		AllowHeaders:     getEnv("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization,Idempotency-Key"),
 		AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
 		ExposeHeaders:    getEnv("CORS_EXPOSE_HEADERS", "Content-Length"),
 	}
@@ -267,19 +550,40 @@ func loadServerConfig() ServerConfig {
 		Address:  getEnv("API_ADDRESS", ":4000"),
 		AppName:  getEnv("APP_NAME", "Admin Panel API"),
 		RootPath: getEnv("ROOT_PATH", "/admin"),
+
+		MaxRequestBodyBytes: getEnvAsInt("MAX_REQUEST_BODY_BYTES", defaultMaxRequestBody),
+		MaxJSONBodyBytes:    getEnvAsInt("MAX_JSON_BODY_BYTES", defaultMaxJSONBody),
+
+		RequestTimeout:       getEnvAsDuration("REQUEST_TIMEOUT", 30*time.Second),
+		ListRequestTimeout:   getEnvAsDuration("LIST_REQUEST_TIMEOUT", 10*time.Second),
+		UploadRequestTimeout: getEnvAsDuration("UPLOAD_REQUEST_TIMEOUT", 2*time.Minute),
+		ExportRequestTimeout: getEnvAsDuration("EXPORT_REQUEST_TIMEOUT", 5*time.Minute),
+		ShutdownTimeout:      getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		IdempotencyKeyTTL:    getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+
+		DefaultLocale: strings.ToLower(strings.TrimSpace(getEnv("DEFAULT_LOCALE", "en"))),
+		LogLevel:      strings.ToLower(strings.TrimSpace(getEnv("LOG_LEVEL", "info"))),
 	}
 }
 
 // loadMinioConfig загружает настройки MinIO из переменных окружения.
-// Включает endpoint, ключи, bucket, SSL и публичный URL.
+// Включает endpoint, ключи, bucket, SSL, публичный URL, таймауты скачивания изображений
+// и срок действия presigned URL.
 func loadMinioConfig() MinioConfig {
 	return MinioConfig{
-		Endpoint:  getEnv("MINIO_ENDPOINT", "localhost:9000"),
-		AccessKey: getEnv("MINIO_ACCESS_KEY", "minioadmin"),
-		SecretKey: getEnv("MINIO_SECRET_KEY", "minioadmin"),
-		Bucket:    getEnv("MINIO_BUCKET", "snapshots"),
-		UseSSL:    getEnvAsBool("MINIO_USE_SSL", false),
-		PublicURL: getEnv("MINIO_PUBLIC_URL", "http://localhost:9000"),
+		Endpoint:               getEnv("MINIO_ENDPOINT", "localhost:9000"),
+		AccessKey:              getEnv("MINIO_ACCESS_KEY", "minioadmin"),
+		SecretKey:              getEnv("MINIO_SECRET_KEY", "minioadmin"),
+		Bucket:                 getEnv("MINIO_BUCKET", "snapshots"),
+		UseSSL:                 getEnvAsBool("MINIO_USE_SSL", false),
+		PublicURL:              getEnv("MINIO_PUBLIC_URL", "http://localhost:9000"),
+		DownloadConnectTimeout: getEnvAsDuration("IMAGE_DOWNLOAD_CONNECT_TIMEOUT", 5*time.Second),
+		DownloadReadTimeout:    getEnvAsDuration("IMAGE_DOWNLOAD_READ_TIMEOUT", 30*time.Second),
+		PresignExpiry:          getEnvAsDuration("MINIO_PRESIGN_EXPIRY", 15*time.Minute),
+		MaxImageSizeBytes:      int64(getEnvAsInt("MINIO_MAX_IMAGE_SIZE", defaultMaxImageSize)),
+		HealthCheckTimeout:     getEnvAsDuration("MINIO_HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		BatchMaxFiles:          getEnvAsInt("MINIO_BATCH_MAX_FILES", 20),
+		UploadConcurrency:      getEnvAsInt("MINIO_UPLOAD_CONCURRENCY", 4),
 	}
 }
 
@@ -292,6 +596,94 @@ func loadTestModuleConfig() TestModuleConfig {
 	}
 }
 
+// loadValidationConfig загружает настройки валидации из переменных окружения.
+// По умолчанию используется мягкая проверка UUID для совместимости.
+func loadValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		StrictUUID: getEnvAsBool("STRICT_UUID_VALIDATION", false),
+	}
+}
+
+// loadCategoryConfig загружает настройки работы с категориями из переменных окружения.
+// По умолчанию категории кэшируются на 60 секунд.
+func loadCategoryConfig() CategoryConfig {
+	return CategoryConfig{
+		CacheTTL: getEnvAsDuration("CATEGORY_CACHE_TTL", 60*time.Second),
+	}
+}
+
+// loadStatsConfig загружает настройки сводной статистики из переменных окружения.
+// По умолчанию статистика кэшируется на 30 секунд.
+func loadStatsConfig() StatsConfig {
+	return StatsConfig{
+		CacheTTL: getEnvAsDuration("STATS_CACHE_TTL", 30*time.Second),
+	}
+}
+
+// loadEventsConfig загружает настройки публикации событий изменения контента.
+// По умолчанию публикация выключена.
+func loadEventsConfig() EventsConfig {
+	return EventsConfig{
+		Channel:   getEnv("CONTENT_EVENTS_CHANNEL", ""),
+		Publisher: strings.ToLower(strings.TrimSpace(getEnv("DOMAIN_EVENTS_PUBLISHER", "noop"))),
+	}
+}
+
+// loadCourseConfig загружает настройки работы с курсами из переменных окружения.
+// По умолчанию курсы удаляются физически.
+func loadCourseConfig() CourseConfig {
+	return CourseConfig{
+		SoftDelete: getEnvAsBool("COURSE_SOFT_DELETE", false),
+	}
+}
+
+// defaultExemptPaths префиксы путей, исключенных из аутентификации, если AUTH_EXEMPT_PATHS не задан.
+const defaultExemptPaths = "/health,/ready,/metrics,/version,/favicon.ico,/admin/swagger"
+
+// loadHealthConfig загружает настройки проверок здоровья из переменных окружения.
+// Глубокая проверка по умолчанию выключена, так как обращается к БД на запись.
+func loadHealthConfig() HealthConfig {
+	return HealthConfig{
+		DeepCheckEnabled: getEnvAsBool("HEALTH_DEEP_CHECK_ENABLED", false),
+		ExemptPaths:      parseList(getEnv("AUTH_EXEMPT_PATHS", defaultExemptPaths)),
+	}
+}
+
+// loadMetricsConfig загружает настройки метрик Prometheus из переменных окружения.
+// По умолчанию эндпоинт /metrics выключен.
+func loadMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		Enabled: getEnvAsBool("METRICS_ENABLED", false),
+	}
+}
+
+// loadSwaggerConfig загружает настройки документации API из переменных окружения.
+// Без SWAGGER_ENABLED Swagger включен только в режиме отладки (debug).
+func loadSwaggerConfig(debug bool) SwaggerConfig {
+	return SwaggerConfig{
+		Enabled:      getEnvAsBool("SWAGGER_ENABLED", debug),
+		SpecWithAuth: getEnvAsBool("SWAGGER_SPEC_WITH_AUTH", false),
+	}
+}
+
+// loadContentConfig загружает настройки хранения контента уроков из переменных окружения.
+// По умолчанию сжатие выключено; уже сжатый контент читается независимо от флага.
+func loadContentConfig() ContentConfig {
+	return ContentConfig{
+		Compress:        getEnvAsBool("LESSON_CONTENT_COMPRESS", false),
+		CompressMinSize: getEnvAsInt("LESSON_CONTENT_COMPRESS_MIN_SIZE", 1024),
+		RequireBlocks:   getEnvAsBool("LESSON_CONTENT_REQUIRE_BLOCKS", false),
+	}
+}
+
+// loadSearchConfig загружает ограничения поиска из переменных окружения.
+func loadSearchConfig() SearchConfig {
+	return SearchConfig{
+		MinQueryLength: getEnvAsInt("SEARCH_MIN_QUERY_LENGTH", 2),
+		MaxResults:     getEnvAsInt("SEARCH_MAX_RESULTS", 200),
+	}
+}
+
 // GetCORSOrigins возвращает список разрешенных origins для CORS.
 // Если AllowOrigins равно "*", возвращает ["*"]; иначе разбивает строку по запятым и удаляет пробелы.
 func (s *Settings) GetCORSOrigins() []string {
@@ -305,6 +697,18 @@ func (s *Settings) GetCORSOrigins() []string {
 	return origins
 }
 
+// parseList разбивает строку по запятым, удаляя пробелы, пустые элементы и повторы.
+func parseList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // DatabaseURL возвращает строку подключения к базе данных, используя метод URL() структуры DatabaseConfig.
 // Это удобный метод для получения полного DSN.
 func (s *Settings) DatabaseURL() string {
@@ -338,3 +742,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvAsDuration получает значение переменной окружения как time.Duration (например, "5s"),
+// возвращая defaultValue при ошибке или отсутствии.
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
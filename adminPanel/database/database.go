@@ -1,6 +1,6 @@
 // Пакет database предоставляет интерфейс для работы с базой данных PostgreSQL.
 // Включает инициализацию пула соединений, выполнение запросов и сканирование результатов.
-// Поддерживает трассировку с помощью OpenTelemetry.
+// Поддерживает трассировку и метрики длительности запросов с помощью OpenTelemetry.
 package database
 
 import (
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"strings"
 	"time"
 
 	"adminPanel/config"
@@ -17,13 +18,18 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Database представляет соединение с базой данных.
-// Содержит пул соединений pgxpool.Pool для выполнения запросов.
+// Содержит пул соединений pgxpool.Pool для выполнения запросов, гистограмму длительности запросов
+// и порог медленного запроса SlowQueryThreshold: запросы дольше порога логируются и помечаются
+// в трассировке атрибутом db.slow; 0 отключает проверку.
 type Database struct {
-	Pool *pgxpool.Pool
+	Pool               *pgxpool.Pool
+	SlowQueryThreshold time.Duration
+	queryDuration      metric.Float64Histogram
 }
 
 // dbInstance глобальная переменная, хранящая единственный экземпляр Database.
@@ -65,7 +71,19 @@ func InitDB(settings *config.Settings) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	dbInstance = &Database{Pool: pool}
+	queryDuration, err := otel.Meter("admin-panel/database").Float64Histogram("db.client.operation.duration",
+		metric.WithDescription("Duration of database queries"),
+		metric.WithUnit("s"))
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to create query duration histogram: %w", err)
+	}
+
+	dbInstance = &Database{
+		Pool:               pool,
+		SlowQueryThreshold: settings.Database.SlowQueryThreshold,
+		queryDuration:      queryDuration,
+	}
 	log.Printf("✅ Database connection pool initialized (host=%s, db=%s)",
 		settings.Database.Host, settings.Database.Name)
 	return dbInstance, nil
@@ -86,10 +104,33 @@ func GetDB() *Database {
 	return dbInstance
 }
 
+// finishQuery записывает длительность запроса с меткой операции и признаком ошибки.
+// Если запрос выполнялся дольше SlowQueryThreshold, логирует его и помечает span атрибутом db.slow.
+func (db *Database) finishQuery(ctx context.Context, span trace.Span, operation, query string, argsCount int, start time.Time, err error) {
+	elapsed := time.Since(start)
+
+	if db.queryDuration != nil {
+		db.queryDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+			attribute.Bool("error", err != nil),
+		))
+	}
+
+	if db.SlowQueryThreshold > 0 && elapsed > db.SlowQueryThreshold {
+		span.SetAttributes(
+			attribute.Bool("db.slow", true),
+			attribute.Int64("db.duration_ms", elapsed.Milliseconds()),
+		)
+		log.Printf("⚠️  Slow query (%s, threshold %s, %d args, %s): %s",
+			elapsed.Round(time.Millisecond), db.SlowQueryThreshold, argsCount, operation, strings.Join(strings.Fields(query), " "))
+	}
+}
+
 // executeQueryReturning выполняет запрос, возвращающий одну строку, с трассировкой.
 // Принимает контекст, SQL-запрос, операцию (для трассировки) и аргументы.
 // Возвращает результат как map[string]interface{} или nil, если строк нет.
-func (db *Database) executeQueryReturning(ctx context.Context, query string, operation string, args ...interface{}) (map[string]interface{}, error) {
+func (db *Database) executeQueryReturning(ctx context.Context, query string, operation string, args ...interface{}) (result map[string]interface{}, err error) {
 	tr := otel.Tracer("admin-panel/database")
 	ctx, span := tr.Start(ctx, "db.query",
 		trace.WithSpanKind(trace.SpanKindClient),
@@ -100,6 +141,7 @@ func (db *Database) executeQueryReturning(ctx context.Context, query string, ope
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { db.finishQuery(ctx, span, operation, query, len(args), start, err) }(time.Now())
 
 	span.AddEvent("db.query.start", trace.WithAttributes(
 		attribute.String("db.query", query),
@@ -121,7 +163,7 @@ func (db *Database) executeQueryReturning(ctx context.Context, query string, ope
 	}
 	defer rows.Close()
 
-	result, err := scanRowToMap(rows)
+	result, err = scanRowToMap(rows)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -149,7 +191,7 @@ func (db *Database) FetchOne(ctx context.Context, query string, args ...interfac
 
 // FetchAll выполняет SELECT-запрос, возвращающий несколько строк.
 // Возвращает все строки результата как []map[string]interface{}.
-func (db *Database) FetchAll(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Database) FetchAll(ctx context.Context, query string, args ...interface{}) (results []map[string]interface{}, err error) {
 	tr := otel.Tracer("admin-panel/database")
 	ctx, span := tr.Start(ctx, "db.query",
 		trace.WithSpanKind(trace.SpanKindClient),
@@ -160,6 +202,7 @@ func (db *Database) FetchAll(ctx context.Context, query string, args ...interfac
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { db.finishQuery(ctx, span, "SELECT", query, len(args), start, err) }(time.Now())
 
 	span.AddEvent("db.query.start", trace.WithAttributes(
 		attribute.String("db.query", query),
@@ -181,7 +224,7 @@ func (db *Database) FetchAll(ctx context.Context, query string, args ...interfac
 	}
 	defer rows.Close()
 
-	results, err := scanRowsToMap(rows)
+	results, err = scanRowsToMap(rows)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -198,7 +241,7 @@ func (db *Database) FetchAll(ctx context.Context, query string, args ...interfac
 
 // Execute выполняет запрос, не возвращающий данные (INSERT, UPDATE, DELETE).
 // Возвращает количество затронутых строк.
-func (db *Database) Execute(ctx context.Context, query string, args ...interface{}) (int64, error) {
+func (db *Database) Execute(ctx context.Context, query string, args ...interface{}) (rowsAffected int64, err error) {
 	tr := otel.Tracer("admin-panel/database")
 	ctx, span := tr.Start(ctx, "db.query",
 		trace.WithSpanKind(trace.SpanKindClient),
@@ -209,6 +252,7 @@ func (db *Database) Execute(ctx context.Context, query string, args ...interface
 		),
 	)
 	defer span.End()
+	defer func(start time.Time) { db.finishQuery(ctx, span, "EXECUTE", query, len(args), start, err) }(time.Now())
 
 	span.AddEvent("db.query.start", trace.WithAttributes(
 		attribute.String("db.query", query),
@@ -229,7 +273,7 @@ func (db *Database) Execute(ctx context.Context, query string, args ...interface
 		return 0, err
 	}
 
-	rowsAffected := result.RowsAffected()
+	rowsAffected = result.RowsAffected()
 	span.SetAttributes(attribute.Int("db.rows_affected", int(rowsAffected)))
 	span.AddEvent("db.query.end", trace.WithAttributes(
 		attribute.Int("db.rows_affected", int(rowsAffected)),
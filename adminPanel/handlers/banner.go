@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BannerHandler обрабатывает HTTP-запросы для баннера публичного сайта.
+// Изменять баннер могут только пользователи с ролью администратора.
+type BannerHandler struct {
+	bannerService *services.BannerService
+	adminRole     string
+}
+
+// NewBannerHandler создает новый экземпляр BannerHandler.
+// Принимает сервис баннера и роль администратора.
+func NewBannerHandler(bannerService *services.BannerService, adminRole string) *BannerHandler {
+	return &BannerHandler{
+		bannerService: bannerService,
+		adminRole:     adminRole,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты баннера.
+// Чтение доступно всем пользователям API, установка и удаление - только администратору.
+func (h *BannerHandler) RegisterRoutes(router fiber.Router) {
+	banner := router.Group("/banner")
+
+	banner.Get("/", h.getBanner)
+	banner.Put("/", middleware.RequireRole(h.adminRole), middleware.ValidateJSONSchema("banner-update.json"), h.setBanner)
+	banner.Delete("/", middleware.RequireRole(h.adminRole), h.clearBanner)
+}
+
+// getBanner обрабатывает GET /banner.
+// Возвращает текущий баннер; если он не задан, возвращается неактивный баннер.
+func (h *BannerHandler) getBanner(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getBanner.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	banner, err := h.bannerService.GetBanner(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.getBanner.end",
+		trace.WithAttributes(attribute.Bool("response.active", banner.Active)))
+
+	return c.JSON(response.BannerResponse{
+		Status: "success",
+		Data:   *banner,
+	})
+}
+
+// setBanner обрабатывает PUT /banner.
+// Устанавливает баннер сайта, заменяя предыдущий.
+func (h *BannerHandler) setBanner(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.setBanner.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	var input request.BannerUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_JSON",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
+			},
+		})
+	}
+
+	banner, err := h.bannerService.SetBanner(ctx, input)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.setBanner.end",
+		trace.WithAttributes(
+			attribute.String("response.severity", banner.Severity),
+			attribute.Bool("response.active", banner.Active),
+		))
+
+	return c.JSON(response.BannerResponse{
+		Status: "success",
+		Data:   *banner,
+	})
+}
+
+// clearBanner обрабатывает DELETE /banner.
+// Удаляет баннер сайта; на публичном сайте он перестает отображаться.
+func (h *BannerHandler) clearBanner(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.clearBanner.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	if err := h.bannerService.ClearBanner(ctx); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.SendStatus(204)
+}
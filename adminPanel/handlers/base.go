@@ -3,12 +3,120 @@
 package handlers
 
 import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/models"
+
+	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+// strictUUID определяет, включена ли строгая проверка UUID.
+var strictUUID atomic.Bool
+
+// SetStrictUUIDValidation включает или выключает строгую проверку UUID.
+// В строгом режиме допускаются только ненулевые UUID версии 4 (RFC 4122).
+func SetStrictUUIDValidation(strict bool) {
+	strictUUID.Store(strict)
+}
+
+// rootPath внешний префикс путей за обратным прокси (ROOT_PATH), без завершающего слэша.
+var rootPath atomic.Value
+
+// SetRootPath задает префикс, под которым API доступен снаружи (например, /admin за nginx).
+// Он добавляется к ссылкам, которые обработчики возвращают клиенту. Пустая строка или "/"
+// означают, что префикса нет.
+func SetRootPath(path string) {
+	rootPath.Store(strings.TrimRight(path, "/"))
+}
+
+// externalPath возвращает внешний путь для внутреннего пути маршрута path, добавляя ROOT_PATH.
+func externalPath(path string) string {
+	prefix, _ := rootPath.Load().(string)
+	return prefix + path
+}
+
 // isValidUUID проверяет, является ли строка валидным UUID.
-// Возвращает true, если строка может быть распарсена как UUID.
+// В мягком режиме возвращает true, если строка может быть распарсена как UUID;
+// в строгом режиме дополнительно отклоняет нулевой UUID и версии, отличные от 4.
 func isValidUUID(u string) bool {
-	_, err := uuid.Parse(u)
-	return err == nil
+	id, err := uuid.Parse(u)
+	if err != nil {
+		return false
+	}
+	if !strictUUID.Load() {
+		return true
+	}
+	return id != uuid.Nil && id.Version() == 4 && id.Variant() == uuid.RFC4122
+}
+
+// errorResponse отправляет ошибку сервиса в стандартном формате response.ErrorResponse.
+// Для middleware.AppError используются его статус и код, для остальных ошибок — 500.
+func errorResponse(c *fiber.Ctx, err error) error {
+	if appErr, ok := err.(*middleware.AppError); ok {
+		return c.Status(appErr.StatusCode).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    appErr.Code,
+				Message: appErr.Localize(c),
+			},
+		})
+	}
+	return c.Status(500).JSON(response.ErrorResponse{
+		Status: "error",
+		Error: response.ErrorDetails{
+			Code:    "SERVER_ERROR",
+			Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
+		},
+	})
+}
+
+// paginationLinks строит ссылки навигации для пагинированного ответа из пути и query
+// текущего запроса, заменяя page (и limit - на фактически примененный). Остальные
+// параметры запроса, например фильтры, сохраняются. Ссылки относительные, без схемы и хоста,
+// и начинаются с ROOT_PATH, чтобы вести на внешний путь за обратным прокси.
+func paginationLinks(c *fiber.Ctx, p models.Pagination) *models.PaginationLinks {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		query = url.Values{}
+	}
+	if p.Limit > 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	path := externalPath(c.Path())
+	link := func(page int) string {
+		query.Set("page", strconv.Itoa(page))
+		return path + "?" + query.Encode()
+	}
+
+	pages := max(p.Pages, 1)
+	links := &models.PaginationLinks{
+		First: link(1),
+		Last:  link(pages),
+	}
+	if p.Page > 1 {
+		prev := link(min(p.Page-1, pages))
+		links.Prev = &prev
+	}
+	if p.Page < pages {
+		next := link(max(p.Page+1, 1))
+		links.Next = &next
+	}
+	return links
+}
+
+// setLastModified задает заголовок Last-Modified по времени изменения ресурса. Клиент может
+// вернуть его в If-Unmodified-Since при изменении ресурса, чтобы не перезаписать чужие изменения.
+func setLastModified(c *fiber.Ctx, updatedAt time.Time) {
+	if updatedAt.IsZero() {
+		return
+	}
+	c.Set(fiber.HeaderLastModified, updatedAt.UTC().Format(http.TimeFormat))
 }
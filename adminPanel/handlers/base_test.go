@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"adminPanel/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIsValidUUID(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		input  string
+		want   bool
+	}{
+		{name: "lenient nil", input: "00000000-0000-0000-0000-000000000000", want: true},
+		{name: "lenient v1", input: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: true},
+		{name: "lenient v4", input: "f47ac10b-58cc-4372-a567-0e02b2c3d479", want: true},
+		{name: "lenient malformed", input: "not-a-uuid", want: false},
+		{name: "strict nil", strict: true, input: "00000000-0000-0000-0000-000000000000", want: false},
+		{name: "strict v1", strict: true, input: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: false},
+		{name: "strict v4", strict: true, input: "f47ac10b-58cc-4372-a567-0e02b2c3d479", want: true},
+		{name: "strict malformed", strict: true, input: "not-a-uuid", want: false},
+	}
+
+	t.Cleanup(func() { SetStrictUUIDValidation(false) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetStrictUUIDValidation(tt.strict)
+			if got := isValidUUID(tt.input); got != tt.want {
+				t.Errorf("isValidUUID(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationLinksIncludeRootPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		rootPath string
+		prefix   string
+	}{
+		{name: "no root path", rootPath: "", prefix: "/api/v1/categories"},
+		{name: "slash root path", rootPath: "/", prefix: "/api/v1/categories"},
+		{name: "admin root path", rootPath: "/admin", prefix: "/admin/api/v1/categories"},
+		{name: "trailing slash", rootPath: "/admin/", prefix: "/admin/api/v1/categories"},
+	}
+
+	t.Cleanup(func() { SetRootPath("") })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRootPath(tt.rootPath)
+
+			app := fiber.New()
+			app.Get("/api/v1/categories", func(c *fiber.Ctx) error {
+				return c.JSON(paginationLinks(c, models.Pagination{Total: 45, Page: 2, Limit: 20, Pages: 3}))
+			})
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/categories?page=2&q=go", nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+
+			var links models.PaginationLinks
+			if err := json.NewDecoder(resp.Body).Decode(&links); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if links.Prev == nil || links.Next == nil {
+				t.Fatalf("links = %+v, want prev and next", links)
+			}
+			want := map[string]string{
+				"first": tt.prefix + "?limit=20&page=1&q=go",
+				"prev":  tt.prefix + "?limit=20&page=1&q=go",
+				"next":  tt.prefix + "?limit=20&page=3&q=go",
+				"last":  tt.prefix + "?limit=20&page=3&q=go",
+			}
+			got := map[string]string{"first": links.First, "prev": *links.Prev, "next": *links.Next, "last": links.Last}
+			for name, link := range want {
+				if got[name] != link {
+					t.Errorf("%s = %q, want %q", name, got[name], link)
+				}
+			}
+		})
+	}
+}
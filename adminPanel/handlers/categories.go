@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"strconv"
+	"time"
+
 	"adminPanel/handlers/dto/request"
 	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
-	"adminPanel/models"
 	"adminPanel/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -31,15 +33,17 @@ func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHand
 func (h *CategoryHandler) RegisterRoutes(router fiber.Router) {
 	categories := router.Group("/categories")
 
-	categories.Get("/", h.getCategories)
-	categories.Post("/", middleware.ValidateJSONSchema("category-create.json"), h.createCategory)
+	categories.Get("/", middleware.ListTimeout(), h.getCategories)
+	categories.Post("/", middleware.Idempotency(), middleware.ValidateJSONSchema("category-create.json"), h.createCategory)
+	categories.Get("/slug-available", h.checkSlugAvailability)
+	categories.Get("/empty", h.getEmptyCategories)
 	categories.Get("/:category_id", h.getCategory)
 	categories.Put("/:category_id", middleware.ValidateJSONSchema("category-update.json"), h.updateCategory)
 	categories.Delete("/:category_id", h.deleteCategory)
 }
 
-// getCategories обрабатывает GET /categories.
-// Возвращает список всех категорий.
+// getCategories обрабатывает GET /categories?page=&limit=.
+// Возвращает страницу категорий, отсортированных по заголовку (по умолчанию первая страница из 20 категорий).
 func (h *CategoryHandler) getCategories(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	span := trace.SpanFromContext(ctx)
@@ -50,14 +54,17 @@ func (h *CategoryHandler) getCategories(c *fiber.Ctx) error {
 			attribute.String("http.query", c.Context().QueryArgs().String()),
 		))
 
-	categories, err := h.categoryService.GetCategories(ctx)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	categories, pagination, err := h.categoryService.ListCategories(ctx, page, limit)
 	if err != nil {
 		if appErr, ok := err.(*middleware.AppError); ok {
 			return c.Status(appErr.StatusCode).JSON(response.ErrorResponse{
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -65,7 +72,7 @@ func (h *CategoryHandler) getCategories(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -74,12 +81,8 @@ func (h *CategoryHandler) getCategories(c *fiber.Ctx) error {
 		Status: "success",
 	}
 	resp.Data.Items = categories
-	resp.Data.Pagination = models.Pagination{
-		Total: len(categories),
-		Page:  1,
-		Limit: len(categories),
-		Pages: 1,
-	}
+	resp.Data.Pagination = pagination
+	resp.Data.Pagination.Links = paginationLinks(c, pagination)
 
 	span.AddEvent("handler.getCategories.end",
 		trace.WithAttributes(
@@ -90,6 +93,72 @@ func (h *CategoryHandler) getCategories(c *fiber.Ctx) error {
 	return c.JSON(resp)
 }
 
+// getEmptyCategories обрабатывает GET /categories/empty.
+// Возвращает категории без курсов с их возрастом в днях, сначала самые старые.
+func (h *CategoryHandler) getEmptyCategories(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getEmptyCategories.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	categories, err := h.categoryService.GetEmptyCategories(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	now := time.Now()
+	items := make([]response.EmptyCategoryDTO, 0, len(categories))
+	for _, category := range categories {
+		items = append(items, response.NewEmptyCategoryDTO(category, now))
+	}
+
+	span.AddEvent("handler.getEmptyCategories.end",
+		trace.WithAttributes(
+			attribute.Int("response.count", len(items)),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(response.EmptyCategoriesResponse{
+		Status: "success",
+		Data:   items,
+	})
+}
+
+// checkSlugAvailability обрабатывает GET /categories/slug-available?slug=&exclude_id=.
+// Возвращает нормализованный slug и признак того, что он не занят другой категорией.
+func (h *CategoryHandler) checkSlugAvailability(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	slug := c.Query("slug")
+	excludeID := c.Query("exclude_id")
+
+	if excludeID != "" && !isValidUUID(excludeID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid exclude_id format"),
+			},
+		})
+	}
+
+	normalized, available, err := h.categoryService.IsSlugAvailable(ctx, slug, excludeID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(response.SlugAvailabilityResponse{
+		Status: "success",
+		Data: response.SlugAvailability{
+			Slug:      normalized,
+			Available: available,
+		},
+	})
+}
+
 // getCategory обрабатывает GET /categories/:category_id.
 // Возвращает категорию по ID.
 func (h *CategoryHandler) getCategory(c *fiber.Ctx) error {
@@ -109,7 +178,7 @@ func (h *CategoryHandler) getCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid category ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
 			},
 		})
 	}
@@ -121,7 +190,7 @@ func (h *CategoryHandler) getCategory(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -129,7 +198,7 @@ func (h *CategoryHandler) getCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -177,7 +246,7 @@ func (h *CategoryHandler) createCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_JSON",
-				Message: "Invalid request body",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
 			},
 		})
 	}
@@ -189,7 +258,7 @@ func (h *CategoryHandler) createCategory(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -197,7 +266,7 @@ func (h *CategoryHandler) createCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -234,7 +303,7 @@ func (h *CategoryHandler) updateCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid category ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
 			},
 		})
 	}
@@ -258,7 +327,7 @@ func (h *CategoryHandler) updateCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_JSON",
-				Message: "Invalid request body",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
 			},
 		})
 	}
@@ -270,7 +339,7 @@ func (h *CategoryHandler) updateCategory(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -278,7 +347,7 @@ func (h *CategoryHandler) updateCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -315,7 +384,7 @@ func (h *CategoryHandler) deleteCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid category ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
 			},
 		})
 	}
@@ -327,7 +396,7 @@ func (h *CategoryHandler) deleteCategory(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -335,7 +404,7 @@ func (h *CategoryHandler) deleteCategory(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
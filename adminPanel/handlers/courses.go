@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"adminPanel/handlers/dto/request"
 	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
+	"adminPanel/models"
 	"adminPanel/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,13 +22,18 @@ import (
 // Содержит сервис для бизнес-логики и методы для маршрутов.
 type CourseHandler struct {
 	courseService *services.CourseService
+	s3Service     *services.S3Service
+	adminRole     string
 }
 
 // NewCourseHandler создает новый экземпляр CourseHandler.
-// Принимает сервис курсов.
-func NewCourseHandler(courseService *services.CourseService) *CourseHandler {
+// Принимает сервис курсов, сервис S3 для удаления изображений удаленных курсов
+// и роль, необходимую для административных маршрутов.
+func NewCourseHandler(courseService *services.CourseService, s3Service *services.S3Service, adminRole string) *CourseHandler {
 	return &CourseHandler{
 		courseService: courseService,
+		s3Service:     s3Service,
+		adminRole:     adminRole,
 	}
 }
 
@@ -33,11 +42,104 @@ func NewCourseHandler(courseService *services.CourseService) *CourseHandler {
 func (h *CourseHandler) RegisterRoutes(router fiber.Router) {
 	courses := router.Group("/categories/:category_id/courses")
 
-	courses.Get("/", h.getCourses)
-	courses.Post("/", middleware.ValidateJSONSchema("course-create.json"), h.createCourse)
+	courses.Get("/", middleware.ListTimeout(), h.getCourses)
+	courses.Get("/search", middleware.ListTimeout(), h.searchCourses)
+	courses.Post("/", middleware.Idempotency(), middleware.ValidateJSONSchema("course-create.json"), h.createCourse)
 	courses.Get("/:course_id", h.getCourse)
 	courses.Put("/:course_id", middleware.ValidateJSONSchema("course-update.json"), h.updateCourse)
+	courses.Patch("/:course_id", middleware.ValidateJSONSchema("course-patch.json"), h.patchCourse)
+	courses.Post("/bulk-delete", h.bulkDeleteCourses)
 	courses.Delete("/:course_id", h.deleteCourse)
+	courses.Post("/:course_id/restore", h.restoreCourse)
+	courses.Post("/:course_id/clone", h.cloneCourse)
+	courses.Post("/:course_id/move", middleware.ValidateJSONSchema("course-move.json"), h.moveCourse)
+
+	router.Get("/courses", middleware.ListTimeout(), h.getAllCourses)
+	router.Get("/courses/slug-available", h.checkSlugAvailability)
+	router.Get("/courses/deleted", middleware.RequireRole(h.adminRole), h.getDeletedCourses)
+}
+
+// getDeletedCourses обрабатывает GET /courses/deleted.
+// Возвращает мягко удаленные курсы (сначала последние) для восстановления через POST .../restore.
+func (h *CourseHandler) getDeletedCourses(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	courses, total, err := h.courseService.GetDeletedCourses(ctx, page, limit)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	pages := (total + limit - 1) / limit
+	if pages == 0 {
+		pages = 1
+	}
+
+	return c.JSON(response.PaginatedCoursesResponse{
+		Status: "success",
+		Data: struct {
+			Items      []models.Course   `json:"items"`
+			Pagination models.Pagination `json:"pagination"`
+		}{
+			Items: courses,
+			Pagination: models.Pagination{
+				Total: total,
+				Page:  page,
+				Limit: limit,
+				Pages: pages,
+			},
+		},
+	})
+}
+
+// checkSlugAvailability обрабатывает GET /courses/slug-available?slug=&category_id=&exclude_id=.
+// Возвращает нормализованный slug и признак того, что он не занят другим курсом категории.
+func (h *CourseHandler) checkSlugAvailability(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	slug := c.Query("slug")
+	categoryID := c.Query("category_id")
+	excludeID := c.Query("exclude_id")
+
+	if !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+	if excludeID != "" && !isValidUUID(excludeID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid exclude_id format"),
+			},
+		})
+	}
+
+	normalized, available, err := h.courseService.IsSlugAvailable(ctx, categoryID, slug, excludeID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(response.SlugAvailabilityResponse{
+		Status: "success",
+		Data: response.SlugAvailability{
+			Slug:      normalized,
+			Available: available,
+		},
+	})
 }
 
 // getCourses обрабатывает GET /categories/:category_id/courses.
@@ -60,12 +162,18 @@ func (h *CourseHandler) getCourses(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid category ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
 			},
 		})
 	}
 	filter := request.CourseFilter{
-		CategoryID: categoryID,
+		CategoryID:     categoryID,
+		Level:          c.Query("level"),
+		Title:          c.Query("title"),
+		IncludeDeleted: c.QueryBool("include_deleted"),
+	}
+	if err := filter.ParseDateRange(func(name string) string { return c.Query(name) }); err != nil {
+		return errorResponse(c, middleware.NewAppError(err.Error(), 400, "VALIDATION_ERROR"))
 	}
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
@@ -80,7 +188,7 @@ func (h *CourseHandler) getCourses(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -88,11 +196,13 @@ func (h *CourseHandler) getCourses(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
 
+	result.Data.Pagination.Links = paginationLinks(c, result.Data.Pagination)
+
 	span.AddEvent("handler.getCourses.end",
 		trace.WithAttributes(
 			attribute.Int("response.count", len(result.Data.Items)),
@@ -102,6 +212,116 @@ func (h *CourseHandler) getCourses(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// getAllCourses обрабатывает GET /courses.
+// Возвращает курсы всех категорий с названием категории, фильтрами (category_id, level, visibility,
+// подстрока title, диапазоны дат, include_deleted), поиском по q и пагинацией.
+func (h *CourseHandler) getAllCourses(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getAllCourses.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("http.query", c.Context().QueryArgs().String()),
+		))
+
+	categoryID := c.Query("category_id")
+	if categoryID != "" && !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+	filter := request.CourseFilter{
+		CategoryID:     categoryID,
+		Level:          c.Query("level"),
+		Visibility:     c.Query("visibility"),
+		Title:          c.Query("title"),
+		IncludeDeleted: c.QueryBool("include_deleted"),
+		Search:         c.Query("q"),
+	}
+	if err := filter.ParseDateRange(func(name string) string { return c.Query(name) }); err != nil {
+		return errorResponse(c, middleware.NewAppError(err.Error(), 400, "VALIDATION_ERROR"))
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	filter.Page = page
+	filter.Limit = limit
+
+	result, err := h.courseService.GetAllCourses(ctx, filter)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	result.Data.Pagination.Links = paginationLinks(c, result.Data.Pagination)
+
+	span.AddEvent("handler.getAllCourses.end",
+		trace.WithAttributes(
+			attribute.Int("response.count", len(result.Data.Items)),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(result)
+}
+
+// searchCourses обрабатывает GET /categories/:category_id/courses/search?q=.
+// Выполняет полнотекстовый поиск курсов категории с фильтрами и пагинацией, как getCourses.
+func (h *CourseHandler) searchCourses(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.searchCourses.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("http.query", c.Context().QueryArgs().String()),
+		))
+
+	categoryID := c.Params("category_id")
+
+	if !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+	filter := request.CourseFilter{
+		CategoryID:     categoryID,
+		Level:          c.Query("level"),
+		Visibility:     c.Query("visibility"),
+		Title:          c.Query("title"),
+		IncludeDeleted: c.QueryBool("include_deleted"),
+	}
+	if err := filter.ParseDateRange(func(name string) string { return c.Query(name) }); err != nil {
+		return errorResponse(c, middleware.NewAppError(err.Error(), 400, "VALIDATION_ERROR"))
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+
+	filter.Page = page
+	filter.Limit = limit
+
+	result, err := h.courseService.SearchCourses(ctx, c.Query("q"), filter)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.searchCourses.end",
+		trace.WithAttributes(
+			attribute.Int("response.count", len(result.Data.Items)),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(result)
+}
+
 // createCourse обрабатывает POST /categories/:category_id/courses.
 // Создает новый курс для категории на основе JSON в теле запроса.
 func (h *CourseHandler) createCourse(c *fiber.Ctx) error {
@@ -121,7 +341,7 @@ func (h *CourseHandler) createCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid category ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
 			},
 		})
 	}
@@ -145,33 +365,13 @@ func (h *CourseHandler) createCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_JSON",
-				Message: "Invalid request body",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
 			},
 		})
 	}
 
 	input.CategoryID = categoryID
 
-	if input.Level != "" && !isValidLevel(input.Level) {
-		return c.Status(400).JSON(response.ErrorResponse{
-			Status: "error",
-			Error: response.ErrorDetails{
-				Code:    "VALIDATION_ERROR",
-				Message: "Level must be one of: hard, medium, easy",
-			},
-		})
-	}
-
-	if input.Visibility != "" && !isValidVisibility(input.Visibility) {
-		return c.Status(400).JSON(response.ErrorResponse{
-			Status: "error",
-			Error: response.ErrorDetails{
-				Code:    "VALIDATION_ERROR",
-				Message: "Visibility must be one of: draft, public, private",
-			},
-		})
-	}
-
 	course, err := h.courseService.CreateCourse(ctx, input)
 	if err != nil {
 		if appErr, ok := err.(*middleware.AppError); ok {
@@ -179,7 +379,7 @@ func (h *CourseHandler) createCourse(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -187,7 +387,7 @@ func (h *CourseHandler) createCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -223,19 +423,24 @@ func (h *CourseHandler) getCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
 			},
 		})
 	}
 
-	course, err := h.courseService.GetCourse(ctx, categoryID, id)
+	getCourse := h.courseService.GetCourse
+	if c.QueryBool("include_deleted") {
+		getCourse = h.courseService.GetCourseIncludingDeleted
+	}
+
+	course, err := getCourse(ctx, categoryID, id)
 	if err != nil {
 		if appErr, ok := err.(*middleware.AppError); ok {
 			return c.Status(appErr.StatusCode).JSON(response.ErrorResponse{
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -243,7 +448,7 @@ func (h *CourseHandler) getCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -255,11 +460,14 @@ func (h *CourseHandler) getCourse(c *fiber.Ctx) error {
 			attribute.String("response.status", "success"),
 		))
 
+	setLastModified(c, course.Data.UpdatedAt)
 	return c.JSON(course)
 }
 
 // updateCourse обрабатывает PUT /categories/:category_id/courses/:course_id.
-// Обновляет курс по ID в категории на основе JSON в теле запроса.
+// Обновляет курс по ID в категории на основе JSON в теле запроса. Для защиты от перезаписи чужих
+// изменений клиент передает updated_at полученного курса в поле version (или Last-Modified ответа
+// в заголовке If-Unmodified-Since); если курс с тех пор изменен, возвращается 409 CONFLICT.
 func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	span := trace.SpanFromContext(ctx)
@@ -279,7 +487,7 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
 			},
 		})
 	}
@@ -302,7 +510,7 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_JSON",
-				Message: "Invalid request body",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
 			},
 		})
 	}
@@ -311,24 +519,11 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 		input.CategoryID = categoryID
 	}
 
-	if input.Level != "" && !isValidLevel(input.Level) {
-		return c.Status(400).JSON(response.ErrorResponse{
-			Status: "error",
-			Error: response.ErrorDetails{
-				Code:    "VALIDATION_ERROR",
-				Message: "Level must be one of: hard, medium, easy",
-			},
-		})
-	}
-
-	if input.Visibility != "" && !isValidVisibility(input.Visibility) {
-		return c.Status(400).JSON(response.ErrorResponse{
-			Status: "error",
-			Error: response.ErrorDetails{
-				Code:    "VALIDATION_ERROR",
-				Message: "Visibility must be one of: draft, public, private",
-			},
-		})
+	// Некорректное значение If-Unmodified-Since игнорируется, как предписывает RFC 9110.
+	if raw := c.Get(fiber.HeaderIfUnmodifiedSince); raw != "" {
+		if t, err := http.ParseTime(raw); err == nil {
+			input.UnmodifiedSince = &t
+		}
 	}
 
 	course, err := h.courseService.UpdateCourse(ctx, categoryID, id, input)
@@ -338,7 +533,7 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -346,7 +541,7 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
@@ -358,11 +553,64 @@ func (h *CourseHandler) updateCourse(c *fiber.Ctx) error {
 			attribute.String("response.status", "success"),
 		))
 
+	setLastModified(c, course.Data.UpdatedAt)
+	return c.JSON(course)
+}
+
+// patchCourse обрабатывает PATCH /categories/:category_id/courses/:course_id.
+// Частично обновляет курс: поля, отсутствующие в JSON, не изменяются,
+// пустые description и image_key очищают описание и изображение.
+func (h *CourseHandler) patchCourse(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.patchCourse.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("course.id", c.Params("course_id")),
+		))
+
+	categoryID := c.Params("category_id")
+	id := c.Params("course_id")
+
+	if !isValidUUID(id) || !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
+			},
+		})
+	}
+
+	var input request.CoursePatch
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_JSON",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
+			},
+		})
+	}
+
+	course, err := h.courseService.PatchCourse(ctx, categoryID, id, input)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.patchCourse.end",
+		trace.WithAttributes(
+			attribute.String("course.id", course.Data.ID),
+			attribute.String("response.status", "success"),
+		))
+
 	return c.JSON(course)
 }
 
 // deleteCourse обрабатывает DELETE /categories/:category_id/courses/:course_id.
-// Удаляет курс по ID в категории.
+// Удаляет курс по ID в категории. Параметр ?mode=soft|hard переопределяет режим удаления из настроек.
 func (h *CourseHandler) deleteCourse(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	span := trace.SpanFromContext(ctx)
@@ -382,19 +630,19 @@ func (h *CourseHandler) deleteCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "INVALID_UUID",
-				Message: "Invalid ID format",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
 			},
 		})
 	}
 
-	err := h.courseService.DeleteCourse(ctx, categoryID, id)
+	imageKeys, err := h.courseService.DeleteCourse(ctx, categoryID, id, strings.ToLower(c.Query("mode")))
 	if err != nil {
 		if appErr, ok := err.(*middleware.AppError); ok {
 			return c.Status(appErr.StatusCode).JSON(response.ErrorResponse{
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    appErr.Code,
-					Message: appErr.Message,
+					Message: appErr.Localize(c),
 				},
 			})
 		}
@@ -402,38 +650,310 @@ func (h *CourseHandler) deleteCourse(c *fiber.Ctx) error {
 			Status: "error",
 			Error: response.ErrorDetails{
 				Code:    "SERVER_ERROR",
-				Message: "Internal server error",
+				Message: middleware.LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 			},
 		})
 	}
 
+	// Ошибка удаления изображения не влияет на результат: курс уже удален.
+	for _, key := range imageKeys {
+		if err := h.s3Service.DeleteImageByKey(ctx, key); err != nil {
+			span.RecordError(err)
+			log.Printf("Failed to delete image %q of deleted course %s: %v", key, id, err)
+		}
+	}
+
 	span.AddEvent("handler.deleteCourse.end",
 		trace.WithAttributes(
 			attribute.String("course.id", id),
+			attribute.Int("course.deleted_images", len(imageKeys)),
 			attribute.String("response.status", "success"),
 		))
 
 	return c.SendStatus(204)
 }
 
-// isValidLevel проверяет, является ли уровень сложности допустимым.
-// Допустимые значения: hard, medium, easy.
-func isValidLevel(level string) bool {
-	switch strings.ToLower(level) {
-	case "hard", "medium", "easy":
-		return true
-	default:
-		return false
+// maxBulkDeleteIDs ограничивает количество курсов в одном запросе пакетного удаления.
+const maxBulkDeleteIDs = 100
+
+// bulkDeleteCourses обрабатывает POST /categories/:category_id/courses/bulk-delete.
+// Принимает {"ids": [...]} и удаляет курсы категории. Параметр ?mode=soft|hard работает так же,
+// как в deleteCourse, включая удаление неиспользуемых изображений при жестком удалении.
+// Если удалены не все курсы, возвращает 207 с итогами по каждому ID.
+func (h *CourseHandler) bulkDeleteCourses(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+
+	categoryID := c.Params("category_id")
+
+	if !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+
+	var input request.CourseBulkDelete
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_JSON",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
+			},
+		})
 	}
+
+	if len(input.IDs) == 0 || len(input.IDs) > maxBulkDeleteIDs {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "VALIDATION_ERROR",
+				Message: middleware.LocalizeMessage(c, "VALIDATION_ERROR", fmt.Sprintf("Field 'ids' must contain from 1 to %d course IDs", maxBulkDeleteIDs)),
+			},
+		})
+	}
+
+	span.AddEvent("handler.bulkDeleteCourses.start",
+		trace.WithAttributes(
+			attribute.String("category.id", categoryID),
+			attribute.Int("courses.requested", len(input.IDs)),
+		))
+
+	results, validIDs, positions := planBulkDelete(input.IDs)
+
+	var imageKeys []string
+	if len(validIDs) > 0 {
+		items, keys, err := h.courseService.BulkDeleteCourses(ctx, categoryID, validIDs, strings.ToLower(c.Query("mode")))
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		for _, item := range items {
+			results[positions[item.ID]] = item
+		}
+		imageKeys = keys
+	}
+
+	// Ошибка удаления изображения не влияет на результат: курсы уже удалены.
+	for _, key := range imageKeys {
+		if err := h.s3Service.DeleteImageByKey(ctx, key); err != nil {
+			span.RecordError(err)
+			log.Printf("Failed to delete image %q of bulk deleted course: %v", key, err)
+		}
+	}
+
+	summary := summarizeBulkDelete(len(input.IDs), results)
+
+	span.AddEvent("handler.bulkDeleteCourses.end",
+		trace.WithAttributes(
+			attribute.Int("courses.requested", summary.Requested),
+			attribute.Int("courses.deleted", summary.Deleted),
+			attribute.Int("courses.deleted_images", len(imageKeys)),
+		))
+
+	if summary.Failed > 0 {
+		return c.Status(207).JSON(response.BulkDeleteResponse{
+			Status: "partial",
+			Data:   summary,
+		})
+	}
+
+	return c.JSON(response.BulkDeleteResponse{
+		Status: "success",
+		Data:   summary,
+	})
 }
 
-// isValidVisibility проверяет, является ли видимость допустимой.
-// Допустимые значения: draft, public, private.
-func isValidVisibility(visibility string) bool {
-	switch strings.ToLower(visibility) {
-	case "draft", "public", "private":
-		return true
-	default:
-		return false
+// planBulkDelete готовит результаты пакетного удаления до вызова сервиса.
+// Некорректные ID сразу получают статус invalid_id, повторы уже встреченного ID — duplicate.
+// Возвращает уникальные ID в нижнем регистре для сервиса и позицию первого вхождения каждого из них.
+func planBulkDelete(ids []string) ([]response.BulkDeleteItem, []string, map[string]int) {
+	results := make([]response.BulkDeleteItem, len(ids))
+	var validIDs []string
+	positions := make(map[string]int)
+	for i, id := range ids {
+		if !isValidUUID(id) {
+			results[i] = response.BulkDeleteItem{
+				ID:     id,
+				Status: response.BulkDeleteStatusInvalidID,
+				Error:  "Invalid course ID format",
+			}
+			continue
+		}
+		key := strings.ToLower(id)
+		if _, seen := positions[key]; seen {
+			results[i] = response.BulkDeleteItem{
+				ID:     id,
+				Status: response.BulkDeleteStatusDuplicate,
+			}
+			continue
+		}
+		validIDs = append(validIDs, key)
+		positions[key] = i
 	}
+	return results, validIDs, positions
+}
+
+// summarizeBulkDelete подсчитывает итоги пакетного удаления по результатам для каждого ID.
+func summarizeBulkDelete(requested int, results []response.BulkDeleteItem) response.BulkDeleteSummary {
+	summary := response.BulkDeleteSummary{
+		Requested: requested,
+		Results:   results,
+	}
+	for _, item := range results {
+		switch item.Status {
+		case response.BulkDeleteStatusDeleted:
+			summary.Deleted++
+		case response.BulkDeleteStatusDuplicate:
+			summary.Duplicates++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// restoreCourse обрабатывает POST /categories/:category_id/courses/:course_id/restore.
+// Отменяет мягкое удаление курса и возвращает восстановленный курс.
+func (h *CourseHandler) restoreCourse(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.restoreCourse.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("course.id", c.Params("course_id")),
+		))
+
+	categoryID := c.Params("category_id")
+	id := c.Params("course_id")
+
+	if !isValidUUID(id) || !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
+			},
+		})
+	}
+
+	course, err := h.courseService.RestoreCourse(ctx, categoryID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.restoreCourse.end",
+		trace.WithAttributes(
+			attribute.String("course.id", course.Data.ID),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(course)
+}
+
+// cloneCourse обрабатывает POST /categories/:category_id/courses/:course_id/clone.
+// Создает копию курса вместе с уроками и возвращает созданный курс.
+func (h *CourseHandler) cloneCourse(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.cloneCourse.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("course.id", c.Params("course_id")),
+		))
+
+	categoryID := c.Params("category_id")
+	id := c.Params("course_id")
+
+	if !isValidUUID(id) || !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
+			},
+		})
+	}
+
+	course, err := h.courseService.CloneCourse(ctx, categoryID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.cloneCourse.end",
+		trace.WithAttributes(
+			attribute.String("course.id", course.Data.ID),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.Status(201).JSON(course)
+}
+
+// moveCourse обрабатывает POST /categories/:category_id/courses/:course_id/move.
+// Переносит курс в категорию target_category_id и возвращает перенесенный курс.
+func (h *CourseHandler) moveCourse(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.moveCourse.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("course.id", c.Params("course_id")),
+		))
+
+	categoryID := c.Params("category_id")
+	id := c.Params("course_id")
+
+	if !isValidUUID(id) || !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid ID format"),
+			},
+		})
+	}
+
+	var input request.CourseMove
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_JSON",
+				Message: middleware.LocalizeMessage(c, "INVALID_JSON", "Invalid request body"),
+			},
+		})
+	}
+
+	if !isValidUUID(input.TargetCategoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid target category ID format"),
+			},
+		})
+	}
+
+	course, err := h.courseService.MoveCourse(ctx, categoryID, id, input.TargetCategoryID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.moveCourse.end",
+		trace.WithAttributes(
+			attribute.String("course.id", course.Data.ID),
+			attribute.String("course.category_id", course.Data.CategoryID),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(course)
 }
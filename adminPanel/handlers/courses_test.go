@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+
+	"adminPanel/handlers/dto/response"
+)
+
+func TestPlanBulkDeleteMarksDuplicates(t *testing.T) {
+	const id = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	const upper = "F47AC10B-58CC-4372-A567-0E02B2C3D479"
+
+	results, validIDs, positions := planBulkDelete([]string{id, "not-a-uuid", upper, id})
+
+	if !slices.Equal(validIDs, []string{id}) {
+		t.Fatalf("validIDs = %v, want [%s]", validIDs, id)
+	}
+	if positions[id] != 0 {
+		t.Errorf("positions[%s] = %d, want 0", id, positions[id])
+	}
+
+	wantStatuses := []string{"", response.BulkDeleteStatusInvalidID, response.BulkDeleteStatusDuplicate, response.BulkDeleteStatusDuplicate}
+	for i, want := range wantStatuses {
+		if results[i].Status != want {
+			t.Errorf("results[%d].Status = %q, want %q", i, results[i].Status, want)
+		}
+	}
+}
+
+func TestSummarizeBulkDeleteCountsDuplicatesOnce(t *testing.T) {
+	const id = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+	results, _, positions := planBulkDelete([]string{id, id})
+	results[positions[id]] = response.BulkDeleteItem{ID: id, Status: response.BulkDeleteStatusDeleted}
+
+	summary := summarizeBulkDelete(2, results)
+	if summary.Requested != 2 || summary.Deleted != 1 || summary.Failed != 0 || summary.Duplicates != 1 {
+		t.Errorf("summary = requested %d, deleted %d, failed %d, duplicates %d; want 2, 1, 0, 1",
+			summary.Requested, summary.Deleted, summary.Failed, summary.Duplicates)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"adminPanel/handlers/dto/response"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DashboardHandler обрабатывает HTTP-запросы для панели управления.
+// Возвращает агрегированную статистику по данным базы знаний.
+type DashboardHandler struct {
+	categoryService *services.CategoryService
+}
+
+// NewDashboardHandler создает новый экземпляр DashboardHandler.
+// Принимает сервис категорий.
+func NewDashboardHandler(categoryService *services.CategoryService) *DashboardHandler {
+	return &DashboardHandler{
+		categoryService: categoryService,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты панели управления.
+// Создает группу /dashboard и привязывает методы к маршрутам.
+func (h *DashboardHandler) RegisterRoutes(router fiber.Router) {
+	dashboard := router.Group("/dashboard")
+
+	dashboard.Get("/categories", h.getCategoryStats)
+}
+
+// getCategoryStats обрабатывает GET /dashboard/categories.
+// Возвращает категории с количеством курсов и уроков, отсортированные по количеству курсов.
+func (h *DashboardHandler) getCategoryStats(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getCategoryStats.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	stats, err := h.categoryService.GetCategoryStats(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.getCategoryStats.end",
+		trace.WithAttributes(
+			attribute.Int("response.count", len(stats)),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(response.CategoryStatsResponse{
+		Status: "success",
+		Data:   stats,
+	})
+}
@@ -0,0 +1,127 @@
+package dto_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/models"
+)
+
+// snakeCase описывает допустимое имя JSON-поля.
+var snakeCase = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+func TestJSONTagsAreSnakeCase(t *testing.T) {
+	types := []any{
+		models.SiteBanner{},
+		models.BaseModel{},
+		models.Pagination{},
+		models.PaginationLinks{},
+		models.QueryList{},
+		models.ResponsePaginationLessonsList{},
+		models.Category{},
+		models.ContentBlock{},
+		models.TextContent{},
+		models.ImageContent{},
+		models.Course{},
+		models.Lesson{},
+		models.LessonOutline{},
+
+		request.BannerUpdate{},
+		request.CategoryCreate{},
+		request.CategoryUpdate{},
+		request.CourseCreate{},
+		request.CourseUpdate{},
+		request.CoursePatch{},
+		request.CourseMove{},
+		request.CourseBulkDelete{},
+		request.CourseFilter{},
+		request.LessonCreate{},
+		request.LessonBulkCreate{},
+		request.LessonUpdate{},
+
+		response.BannerResponse{},
+		response.CategoryResponse{},
+		response.PaginatedCategoriesResponse{},
+		response.EmptyCategoryDTO{},
+		response.EmptyCategoriesResponse{},
+		response.HealthResponse{},
+		response.HealthStep{},
+		response.DeepHealthResponse{},
+		response.PoolStats{},
+		response.DeepDBHealthResponse{},
+		response.S3HealthResponse{},
+		response.ErrorDetails{},
+		response.ErrorResponse{},
+		response.StatusOnly{},
+		response.MessageResponse{},
+		response.ValidationErrorResponse{},
+		response.SlugAvailability{},
+		response.SlugAvailabilityResponse{},
+		response.SlugBackfillResult{},
+		response.SlugBackfillResponse{},
+		response.OrphanedLessonsResult{},
+		response.OrphanedLessonsResponse{},
+		response.OrphanedLessonsDeleteResult{},
+		response.OrphanedLessonsDeleteResponse{},
+		response.CourseResponse{},
+		response.PaginatedCoursesResponse{},
+		response.BulkDeleteItem{},
+		response.BulkDeleteSummary{},
+		response.BulkDeleteResponse{},
+		response.CategoryStatsDTO{},
+		response.CategoryStatsResponse{},
+		response.CategoryExport{},
+		response.CourseExport{},
+		response.CategoryImportResult{},
+		response.CategoryImportResponse{},
+		response.LessonResponse{},
+		response.LessonBulkCreateResponse{},
+		response.LessonListResponse{},
+		response.LessonCountsResponse{},
+		response.StatsOverview{},
+		response.CourseStatsOverview{},
+		response.CourseVisibilityCounts{},
+		response.CourseLevelCounts{},
+		response.StatsOverviewResponse{},
+		response.LessonOutlinesDTO{},
+		response.CourseTreeDTO{},
+		response.CourseTreeListDTO{},
+		response.CategoryTreeDTO{},
+		response.CategoryTreeResponse{},
+		response.ImageUploadResult{},
+		response.BatchUploadResponse{},
+	}
+
+	seen := make(map[reflect.Type]bool)
+	for _, v := range types {
+		checkJSONTags(t, reflect.TypeOf(v), seen)
+	}
+}
+
+// checkJSONTags проверяет теги json у полей структуры и вложенных в нее структур.
+func checkJSONTags(t *testing.T, typ reflect.Type, seen map[reflect.Type]bool) {
+	t.Helper()
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" && !snakeCase.MatchString(name) {
+			t.Errorf("%s.%s: json tag %q is not snake_case", typ, field.Name, name)
+		}
+		checkJSONTags(t, field.Type, seen)
+	}
+}
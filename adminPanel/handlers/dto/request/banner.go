@@ -0,0 +1,9 @@
+package request
+
+// BannerUpdate представляет запрос на установку баннера сайта.
+// Severity по умолчанию info, Active по умолчанию true.
+type BannerUpdate struct {
+	Message  string `json:"message" validate:"required,min=1,max=1000"`
+	Severity string `json:"severity" validate:"omitempty,oneof=info warning critical"`
+	Active   *bool  `json:"active"`
+}
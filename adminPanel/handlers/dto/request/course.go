@@ -1,33 +1,173 @@
 package request
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CourseLevels перечисляет допустимые уровни сложности курса.
+var CourseLevels = []string{"easy", "medium", "hard"}
+
+// allowedCourseVisibilities содержит значения видимости, допустимые ограничением таблицы course_b.
+var allowedCourseVisibilities = map[string]bool{"draft": true, "public": true}
+
+// allowedContentPolicies содержит известные политики разметки контента уроков курса.
+var allowedContentPolicies = map[string]bool{"strict": true, "relaxed": true}
+
+// IsValidContentPolicy сообщает, является ли policy известной политикой разметки контента.
+func IsValidContentPolicy(policy string) bool {
+	return allowedContentPolicies[policy]
+}
+
+// IsValidCourseLevel сообщает, является ли level допустимым уровнем сложности курса.
+func IsValidCourseLevel(level string) bool {
+	return slices.Contains(CourseLevels, level)
+}
+
+// IsValidCourseVisibility сообщает, допускает ли база данных значение видимости курса.
+func IsValidCourseVisibility(visibility string) bool {
+	return allowedCourseVisibilities[visibility]
+}
+
 // CourseCreate представляет запрос на создание нового курса.
 // Содержит все необходимые поля для создания курса с валидацией.
 type CourseCreate struct {
-	Title       string `json:"title" validate:"required,min=1,max=255"`
-	Description string `json:"description"`
-	Level       string `json:"level" validate:"omitempty,oneof=hard medium easy"`
-	CategoryID  string `json:"category_id" validate:"required,uuid4"`
-	Visibility  string `json:"visibility" validate:"omitempty,oneof=draft public private"`
-	ImageKey    string `json:"image_key"`
+	Title         string `json:"title" validate:"required,min=1,max=255"`
+	Description   string `json:"description"`
+	Level         string `json:"level" validate:"omitempty,oneof=hard medium easy"`
+	CategoryID    string `json:"category_id" validate:"required,uuid4"`
+	Visibility    string `json:"visibility" validate:"omitempty,oneof=draft public"`
+	ImageKey      string `json:"image_key"`
+	ContentPolicy string `json:"content_policy" validate:"omitempty,oneof=strict relaxed"`
 }
 
 // CourseUpdate представляет запрос на обновление существующего курса.
 // Все поля опциональны для частичного обновления.
 type CourseUpdate struct {
-	Title       string `json:"title" validate:"omitempty,min=1,max=255"`
-	Description string `json:"description"`
-	Level       string `json:"level" validate:"omitempty,oneof=hard medium easy"`
-	CategoryID  string `json:"category_id" validate:"omitempty,uuid4"`
-	Visibility  string `json:"visibility" validate:"omitempty,oneof=draft public private"`
-	ImageKey    string `json:"image_key"`
+	Title         string `json:"title" validate:"omitempty,min=1,max=255"`
+	Description   string `json:"description"`
+	Level         string `json:"level" validate:"omitempty,oneof=hard medium easy"`
+	CategoryID    string `json:"category_id" validate:"omitempty,uuid4"`
+	Visibility    string `json:"visibility" validate:"omitempty,oneof=draft public"`
+	ImageKey      string `json:"image_key"`
+	ContentPolicy string `json:"content_policy" validate:"omitempty,oneof=strict relaxed"`
+	// Version - значение updated_at курса, на основе которого сделано изменение; если курс с тех пор
+	// обновлен, запрос отклоняется с 409. nil - без проверки.
+	Version *time.Time `json:"version"`
+	// UnmodifiedSince - значение заголовка If-Unmodified-Since (с точностью до секунды), проверяемое так же, как Version.
+	UnmodifiedSince *time.Time `json:"-"`
+}
+
+// CoursePatch представляет запрос на частичное обновление курса (PATCH).
+// Поле, отсутствующее в запросе (nil), не изменяется. Пустая строка очищает описание
+// и изображение курса; для названия, уровня, видимости и политики разметки она недопустима.
+// Категория курса меняется только переносом (CourseMove).
+type CoursePatch struct {
+	Title         *string `json:"title"`
+	Description   *string `json:"description"`
+	Level         *string `json:"level"`
+	Visibility    *string `json:"visibility"`
+	ImageKey      *string `json:"image_key"`
+	ContentPolicy *string `json:"content_policy"`
+}
+
+// IsEmpty сообщает, что запрос не содержит ни одного изменяемого поля.
+func (p CoursePatch) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Level == nil &&
+		p.Visibility == nil && p.ImageKey == nil && p.ContentPolicy == nil
+}
+
+// CourseMove представляет запрос на перенос курса в другую категорию.
+type CourseMove struct {
+	TargetCategoryID string `json:"target_category_id"`
+}
+
+// CourseBulkDelete представляет запрос на удаление нескольких курсов категории.
+type CourseBulkDelete struct {
+	IDs []string `json:"ids"`
 }
 
 // CourseFilter представляет фильтр для поиска курсов.
 // Используется для пагинации и фильтрации по различным критериям.
+// Level может содержать несколько уровней через запятую; разобранные значения хранятся в Levels.
+// Мягко удаленные курсы исключаются, если не задан IncludeDeleted.
+// Границы дат создания и обновления задаются в RFC3339 (см. ParseDateRange): нижняя граница
+// (*After) включается в диапазон, верхняя (*Before) - нет; nil означает отсутствие границы.
+// Search задает полнотекстовый поиск в общем списке курсов (GetAllCourses); пустая строка - без поиска.
 type CourseFilter struct {
-	Level      string `query:"level"`
-	Visibility string `query:"visibility"`
-	CategoryID string `query:"category_id" validate:"omitempty,uuid4"`
-	Page       int    `query:"page" validate:"min=1"`
-	Limit      int    `query:"limit" validate:"min=1,max=100"`
+	Level          string     `query:"level"`
+	Levels         []string   `query:"-"`
+	Visibility     string     `query:"visibility"`
+	CategoryID     string     `query:"category_id" validate:"omitempty,uuid4"`
+	IncludeDeleted bool       `query:"include_deleted"`
+	Search         string     `query:"q"`
+	Title          string     `query:"title"`
+	CreatedAfter   *time.Time `query:"-"`
+	CreatedBefore  *time.Time `query:"-"`
+	UpdatedAfter   *time.Time `query:"-"`
+	UpdatedBefore  *time.Time `query:"-"`
+	Page           int        `query:"page" validate:"min=1"`
+	Limit          int        `query:"limit" validate:"min=1,max=100"`
+}
+
+// ParseDateRange разбирает границы дат из параметров запроса created_after, created_before,
+// updated_after и updated_before (query возвращает значение параметра по имени) и сохраняет их в фильтре.
+// Пустые параметры пропускаются. Возвращает ошибку с именем параметра, если значение не в формате RFC3339
+// или нижняя граница позже верхней.
+func (f *CourseFilter) ParseDateRange(query func(name string) string) error {
+	bounds := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_after", &f.CreatedAfter},
+		{"created_before", &f.CreatedBefore},
+		{"updated_after", &f.UpdatedAfter},
+		{"updated_before", &f.UpdatedBefore},
+	}
+	for _, bound := range bounds {
+		raw := strings.TrimSpace(query(bound.name))
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("query parameter '%s' must be an RFC3339 timestamp (e.g. 2024-01-31T00:00:00Z), got %q", bound.name, raw)
+		}
+		*bound.target = &t
+	}
+
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && f.CreatedAfter.After(*f.CreatedBefore) {
+		return fmt.Errorf("query parameter 'created_after' must not be later than 'created_before'")
+	}
+	if f.UpdatedAfter != nil && f.UpdatedBefore != nil && f.UpdatedAfter.After(*f.UpdatedBefore) {
+		return fmt.Errorf("query parameter 'updated_after' must not be later than 'updated_before'")
+	}
+	return nil
+}
+
+// ParseLevels разбирает список уровней сложности, перечисленных через запятую (например, "easy,medium").
+// Пустая строка и "all" означают отсутствие фильтра. Возвращает ошибку для недопустимого уровня.
+func ParseLevels(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "all" {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var levels []string
+	for _, level := range strings.Split(raw, ",") {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if level == "" || seen[level] {
+			continue
+		}
+		if !IsValidCourseLevel(level) {
+			return nil, fmt.Errorf("invalid level %q: allowed values are %s", level, strings.Join(CourseLevels, ", "))
+		}
+		seen[level] = true
+		levels = append(levels, level)
+	}
+
+	return levels, nil
 }
@@ -7,6 +7,12 @@ type LessonCreate struct {
 	Content string `json:"content" validate:"omitempty"`
 }
 
+// LessonBulkCreate представляет запрос на создание нескольких уроков курса за один раз.
+// Уроки создаются в порядке списка.
+type LessonBulkCreate struct {
+	Lessons []LessonCreate `json:"lessons" validate:"required,min=1,max=100,dive"`
+}
+
 // LessonUpdate представляет запрос на обновление существующего урока.
 // Все поля опциональны для частичного обновления.
 type LessonUpdate struct {
@@ -0,0 +1,9 @@
+package response
+
+import "adminPanel/models"
+
+// BannerResponse представляет ответ API с баннером сайта.
+type BannerResponse struct {
+	Status string            `json:"status"`
+	Data   models.SiteBanner `json:"data"`
+}
@@ -1,7 +1,11 @@
 // Пакет response содержит структуры для ответов API.
 package response
 
-import "adminPanel/models"
+import (
+	"time"
+
+	"adminPanel/models"
+)
 
 // CategoryResponse представляет ответ API с одной категорией.
 // Содержит статус и данные категории.
@@ -19,3 +23,23 @@ type PaginatedCategoriesResponse struct {
 		Pagination models.Pagination `json:"pagination"`
 	} `json:"data"`
 }
+
+// EmptyCategoryDTO представляет категорию без курсов с ее возрастом в полных сутках с момента создания.
+type EmptyCategoryDTO struct {
+	models.Category
+	AgeDays int `json:"age_days"`
+}
+
+// NewEmptyCategoryDTO создает EmptyCategoryDTO, вычисляя возраст категории на момент now.
+func NewEmptyCategoryDTO(category models.Category, now time.Time) EmptyCategoryDTO {
+	return EmptyCategoryDTO{
+		Category: category,
+		AgeDays:  int(now.Sub(category.CreatedAt).Hours() / 24),
+	}
+}
+
+// EmptyCategoriesResponse представляет ответ со списком категорий без курсов.
+type EmptyCategoriesResponse struct {
+	Status string             `json:"status"`
+	Data   []EmptyCategoryDTO `json:"data"`
+}
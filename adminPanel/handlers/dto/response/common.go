@@ -1,5 +1,7 @@
 package response
 
+import "adminPanel/models"
+
 // HealthResponse представляет ответ на health check запрос.
 // Содержит статус сервиса, базы данных и версию.
 type HealthResponse struct {
@@ -8,6 +10,56 @@ type HealthResponse struct {
 	Version  string `json:"version"`
 }
 
+// HealthStep описывает результат одного шага глубокой проверки здоровья.
+type HealthStep struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// DeepHealthResponse представляет ответ на глубокую проверку здоровья.
+// Содержит результаты шагов, имя шага, на котором проверка завершилась ошибкой,
+// и количество уроков без курса (nil, если подсчет не выполнялся).
+type DeepHealthResponse struct {
+	Status          string       `json:"status"`
+	FailedStep      string       `json:"failed_step,omitempty"`
+	Steps           []HealthStep `json:"steps"`
+	OrphanedLessons *int         `json:"orphaned_lessons,omitempty"`
+	Version         string       `json:"version"`
+}
+
+// PoolStats содержит статистику пула соединений с базой данных.
+// Utilization равна доле занятых соединений от максимального размера пула.
+type PoolStats struct {
+	AcquiredConns int32   `json:"acquired_conns"`
+	IdleConns     int32   `json:"idle_conns"`
+	TotalConns    int32   `json:"total_conns"`
+	MaxConns      int32   `json:"max_conns"`
+	Utilization   float64 `json:"utilization"`
+}
+
+// DeepDBHealthResponse представляет ответ на углубленную проверку базы данных.
+// Содержит доступность соединения и схемы knowledge_base, а также статистику пула.
+type DeepDBHealthResponse struct {
+	Status          string    `json:"status"`
+	Database        string    `json:"database"`
+	SchemaReachable bool      `json:"schema_reachable"`
+	Error           string    `json:"error,omitempty"`
+	Pool            PoolStats `json:"pool"`
+	Version         string    `json:"version"`
+}
+
+// S3HealthResponse представляет ответ на проверку доступности объектного хранилища.
+// Storage принимает значения reachable или unreachable.
+type S3HealthResponse struct {
+	Status  string `json:"status"`
+	Storage string `json:"storage"`
+	Bucket  string `json:"bucket"`
+	Error   string `json:"error,omitempty"`
+	Version string `json:"version"`
+}
+
 // ErrorDetails содержит детали ошибки для ответа API.
 // Включает код и сообщение об ошибке.
 type ErrorDetails struct {
@@ -42,3 +94,54 @@ type ValidationErrorResponse struct {
 	Error  ErrorDetails      `json:"error"`
 	Errors map[string]string `json:"errors,omitempty"`
 }
+
+// SlugAvailability содержит результат проверки доступности slug.
+// Slug возвращается в нормализованном виде, в котором он будет сохранен.
+type SlugAvailability struct {
+	Slug      string `json:"slug"`
+	Available bool   `json:"available"`
+}
+
+// SlugAvailabilityResponse представляет ответ на проверку доступности slug.
+type SlugAvailabilityResponse struct {
+	Status string           `json:"status"`
+	Data   SlugAvailability `json:"data"`
+}
+
+// SlugBackfillResult содержит итоги заполнения отсутствующих slug.
+type SlugBackfillResult struct {
+	Categories int `json:"categories"`
+	Courses    int `json:"courses"`
+	Lessons    int `json:"lessons"`
+	Batches    int `json:"batches"`
+	BatchSize  int `json:"batch_size"`
+}
+
+// SlugBackfillResponse представляет ответ на запрос заполнения slug.
+type SlugBackfillResponse struct {
+	Status string             `json:"status"`
+	Data   SlugBackfillResult `json:"data"`
+}
+
+// OrphanedLessonsResult содержит уроки, чей курс не существует, и их количество.
+type OrphanedLessonsResult struct {
+	Count int             `json:"count"`
+	Items []models.Lesson `json:"items"`
+}
+
+// OrphanedLessonsResponse представляет ответ со списком уроков без курса.
+type OrphanedLessonsResponse struct {
+	Status string                `json:"status"`
+	Data   OrphanedLessonsResult `json:"data"`
+}
+
+// OrphanedLessonsDeleteResult содержит количество удаленных уроков без курса.
+type OrphanedLessonsDeleteResult struct {
+	Deleted int `json:"deleted"`
+}
+
+// OrphanedLessonsDeleteResponse представляет ответ на удаление уроков без курса.
+type OrphanedLessonsDeleteResponse struct {
+	Status string                      `json:"status"`
+	Data   OrphanedLessonsDeleteResult `json:"data"`
+}
@@ -18,3 +18,38 @@ type PaginatedCoursesResponse struct {
 		Pagination models.Pagination `json:"pagination"`
 	} `json:"data"`
 }
+
+// Статусы результата удаления отдельного курса в пакетном удалении.
+const (
+	BulkDeleteStatusDeleted   = "deleted"
+	BulkDeleteStatusNotFound  = "not_found"
+	BulkDeleteStatusInvalidID = "invalid_id"
+	BulkDeleteStatusError     = "error"
+	// BulkDeleteStatusDuplicate получает повтор ID, уже встречавшегося в запросе:
+	// курс удаляется один раз, и повтор не учитывается в итогах.
+	BulkDeleteStatusDuplicate = "duplicate"
+)
+
+// BulkDeleteItem описывает результат удаления одного курса.
+type BulkDeleteItem struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkDeleteSummary содержит итоги пакетного удаления и результаты по каждому ID.
+// Повторяющиеся ID учитываются только в Requested и Duplicates.
+type BulkDeleteSummary struct {
+	Requested  int              `json:"requested"`
+	Deleted    int              `json:"deleted"`
+	Failed     int              `json:"failed"`
+	Duplicates int              `json:"duplicates"`
+	Results    []BulkDeleteItem `json:"results"`
+}
+
+// BulkDeleteResponse представляет ответ на пакетное удаление курсов.
+// Status равен "success", если удалены все курсы, и "partial" в остальных случаях.
+type BulkDeleteResponse struct {
+	Status string            `json:"status"`
+	Data   BulkDeleteSummary `json:"data"`
+}
@@ -0,0 +1,16 @@
+package response
+
+// CategoryStatsDTO содержит сводную статистику по категории для панели управления.
+// Включает количество курсов и общее количество уроков во всех курсах категории.
+type CategoryStatsDTO struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	CourseCount int    `json:"course_count"`
+	LessonCount int    `json:"lesson_count"`
+}
+
+// CategoryStatsResponse представляет ответ со статистикой по категориям.
+type CategoryStatsResponse struct {
+	Status string             `json:"status"`
+	Data   []CategoryStatsDTO `json:"data"`
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	"adminPanel/models"
+)
+
+// CategoryExportSchemaVersion версия формата JSON-экспорта категории.
+// Увеличивается при несовместимых изменениях структуры CategoryExport.
+const CategoryExportSchemaVersion = 1
+
+// CategoryExport содержит полный снимок категории с курсами и уроками для резервного копирования и миграции.
+type CategoryExport struct {
+	SchemaVersion int             `json:"schema_version"`
+	ExportedAt    time.Time       `json:"exported_at"`
+	Category      models.Category `json:"category"`
+	Courses       []CourseExport  `json:"courses"`
+}
+
+// CourseExport содержит курс вместе со всеми его уроками, включая контент.
+type CourseExport struct {
+	models.Course
+	Lessons []models.Lesson `json:"lessons"`
+}
+
+// CategoryImportResult содержит итоги импорта категории из выгрузки.
+// CategoryCreated равно false, если курсы добавлены в существующую категорию с тем же заголовком.
+type CategoryImportResult struct {
+	Category        models.Category `json:"category"`
+	CategoryCreated bool            `json:"category_created"`
+	Categories      int             `json:"categories"`
+	Courses         int             `json:"courses"`
+	Lessons         int             `json:"lessons"`
+}
+
+// CategoryImportResponse представляет ответ на импорт категории.
+type CategoryImportResponse struct {
+	Status string               `json:"status"`
+	Data   CategoryImportResult `json:"data"`
+}
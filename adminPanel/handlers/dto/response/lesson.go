@@ -9,9 +9,23 @@ type LessonResponse struct {
 	Data   models.Lesson `json:"data"`
 }
 
+// LessonBulkCreateResponse представляет ответ на пакетное создание уроков.
+// Содержит созданные уроки в порядке запроса.
+type LessonBulkCreateResponse struct {
+	Status string          `json:"status"`
+	Data   []models.Lesson `json:"data"`
+}
+
 // LessonListResponse представляет ответ со списком уроков.
 // Включает пагинацию и список уроков для курса.
 type LessonListResponse struct {
 	Status string                               `json:"status"`
 	Data   models.ResponsePaginationLessonsList `json:"data"`
 }
+
+// LessonCountsResponse представляет ответ с количеством уроков по курсам.
+// Ключ карты — ID курса, значение — количество уроков.
+type LessonCountsResponse struct {
+	Status string         `json:"status"`
+	Data   map[string]int `json:"data"`
+}
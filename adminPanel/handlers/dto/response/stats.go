@@ -0,0 +1,39 @@
+package response
+
+import "time"
+
+// StatsOverview содержит сводную статистику базы знаний для панели управления.
+// Удаленные курсы и их уроки не учитываются.
+type StatsOverview struct {
+	Categories    int                 `json:"categories"`
+	Courses       CourseStatsOverview `json:"courses"`
+	Lessons       int                 `json:"lessons"`
+	LastUpdatedAt *time.Time          `json:"last_updated_at"` // Последнее изменение категории, курса или урока; null для пустой базы.
+	GeneratedAt   time.Time           `json:"generated_at"`    // Время подсчета; ответ может браться из кэша.
+}
+
+// CourseStatsOverview содержит количество курсов всего, по видимости и по уровню сложности.
+type CourseStatsOverview struct {
+	Total        int                    `json:"total"`
+	ByVisibility CourseVisibilityCounts `json:"by_visibility"`
+	ByLevel      CourseLevelCounts      `json:"by_level"`
+}
+
+// CourseVisibilityCounts содержит количество курсов по видимости.
+type CourseVisibilityCounts struct {
+	Draft  int `json:"draft"`
+	Public int `json:"public"`
+}
+
+// CourseLevelCounts содержит количество курсов по уровню сложности.
+type CourseLevelCounts struct {
+	Easy   int `json:"easy"`
+	Medium int `json:"medium"`
+	Hard   int `json:"hard"`
+}
+
+// StatsOverviewResponse представляет ответ со сводной статистикой.
+type StatsOverviewResponse struct {
+	Status string        `json:"status"`
+	Data   StatsOverview `json:"data"`
+}
@@ -0,0 +1,45 @@
+package response
+
+import "adminPanel/models"
+
+// LessonOutlinesDTO содержит оглавление уроков курса в дереве категории.
+// Count - общее количество уроков курса; Truncated сообщает, что в Items попали не все уроки.
+type LessonOutlinesDTO struct {
+	Count     int                    `json:"count"`
+	Truncated bool                   `json:"truncated"`
+	Items     []models.LessonOutline `json:"items"`
+}
+
+// CourseTreeDTO представляет курс в дереве категории: основные поля курса и оглавление уроков.
+type CourseTreeDTO struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Slug       string            `json:"slug"`
+	Level      string            `json:"level"`
+	Visibility string            `json:"visibility"`
+	Lessons    LessonOutlinesDTO `json:"lessons"`
+}
+
+// CourseTreeListDTO содержит курсы категории в дереве.
+// Count - общее количество курсов категории; Truncated сообщает, что в Items попали не все курсы.
+type CourseTreeListDTO struct {
+	Count     int             `json:"count"`
+	Truncated bool            `json:"truncated"`
+	Items     []CourseTreeDTO `json:"items"`
+}
+
+// CategoryTreeDTO представляет категорию с курсами и оглавлениями их уроков.
+// LessonCount - количество уроков в курсах, попавших в дерево.
+type CategoryTreeDTO struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Slug        string            `json:"slug"`
+	LessonCount int               `json:"lesson_count"`
+	Courses     CourseTreeListDTO `json:"courses"`
+}
+
+// CategoryTreeResponse представляет ответ с деревом категории.
+type CategoryTreeResponse struct {
+	Status string          `json:"status"`
+	Data   CategoryTreeDTO `json:"data"`
+}
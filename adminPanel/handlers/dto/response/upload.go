@@ -0,0 +1,19 @@
+package response
+
+// ImageUploadResult представляет результат загрузки одного файла из пакета.
+// При ошибке Key и URL пусты, а Error содержит причину.
+type ImageUploadResult struct {
+	Filename string `json:"filename"`
+	Key      string `json:"key,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BatchUploadResponse представляет ответ на пакетную загрузку изображений.
+// Items следуют в порядке файлов в запросе; частичные ошибки не меняют статус ответа.
+type BatchUploadResponse struct {
+	Status    string              `json:"status"`
+	Items     []ImageUploadResult `json:"items"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExportHandler обрабатывает HTTP-запросы на выгрузку и загрузку данных для резервного копирования и миграции.
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler создает новый экземпляр ExportHandler.
+// Принимает сервис экспорта.
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты экспорта и импорта.
+func (h *ExportHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/categories/:category_id/export", middleware.ExportTimeout(), h.exportCategory)
+	router.Post("/categories/import", middleware.ExportTimeout(), h.importCategory)
+}
+
+// exportCategory обрабатывает GET /categories/:category_id/export?format=json|csv.
+// Отдает выгрузку категории с курсами и уроками как файл; оба формата передаются потоком.
+func (h *ExportHandler) exportCategory(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.exportCategory.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+			attribute.String("export.format", c.Query("format")),
+		))
+
+	categoryID := c.Params("category_id")
+	if !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+
+	format, err := services.ValidateExportFormat(c.Query("format"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	filename, err := h.exportService.ExportFilename(ctx, categoryID, format)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	stream := h.exportService.StreamCategoryJSON
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	if format == services.ExportFormatCSV {
+		stream = h.exportService.StreamCategoryCSV
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+
+	// Выгрузка пишется после возврата из обработчика, поэтому статус уже отправлен:
+	// ошибки в процессе записи можно только залогировать, ответ при этом обрывается.
+	// Контекст запроса отменяется при возврате из обработчика, поэтому запись идет в отвязанном
+	// контексте с тем же дедлайном EXPORT_REQUEST_TIMEOUT.
+	streamCtx, cancel := middleware.DetachTimeout(ctx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		if err := stream(streamCtx, categoryID, w); err != nil {
+			log.Printf("❌ Category %s %s export failed: %v", categoryID, format, err)
+		}
+	})
+
+	span.AddEvent("handler.exportCategory.end")
+	return nil
+}
+
+// importCategory обрабатывает POST /categories/import.
+// Принимает JSON-выгрузку категории в поле file multipart-формы и восстанавливает
+// категорию с курсами и уроками. Возвращает сводку импортированных записей.
+func (h *ExportHandler) importCategory(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return errorResponse(c, middleware.NewAppError(
+			fmt.Sprintf("Failed to read uploaded file: %v", err),
+			400,
+			"MISSING_FILE",
+		))
+	}
+
+	span.SetAttributes(
+		attribute.String("file.name", fileHeader.Filename),
+		attribute.Int64("file.size", fileHeader.Size),
+	)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errorResponse(c, middleware.InternalError(fmt.Sprintf("Failed to open uploaded file: %v", err)))
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return errorResponse(c, middleware.InternalError(fmt.Sprintf("Failed to read uploaded file: %v", err)))
+	}
+
+	result, err := h.exportService.ImportCategory(ctx, data)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.importCategory.end",
+		trace.WithAttributes(
+			attribute.String("category.id", result.Category.ID),
+			attribute.Int("import.courses", result.Courses),
+			attribute.Int("import.lessons", result.Lessons),
+		))
+
+	return c.Status(201).JSON(response.CategoryImportResponse{
+		Status: "success",
+		Data:   *result,
+	})
+}
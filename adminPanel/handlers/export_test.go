@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/repositories"
+	"adminPanel/services"
+	"adminPanel/testutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestExportCategoryStreamsValidJSON(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	contentCfg := config.ContentConfig{}
+	categoryRepo := repositories.NewCategoryRepository(db)
+	courseRepo := repositories.NewCourseRepository(db)
+	lessonRepo := repositories.NewLessonRepository(db, contentCfg, false)
+	exportService := services.NewExportService(
+		services.NewCategoryService(categoryRepo, config.CategoryConfig{}, nil),
+		courseRepo,
+		lessonRepo,
+		repositories.NewImportRepository(db, contentCfg, false),
+	)
+
+	const lessons = 200
+	categoryID := testutil.CreateCategory(t, db)
+	courseID := testutil.CreateCourse(t, db, categoryID, "large course", "hard", "public")
+	testutil.CreateCourse(t, db, categoryID, "empty course", "easy", "draft")
+	content := "<p>" + strings.Repeat("Длинный урок с \"кавычками\" и \\ слэшами. ", 100) + "</p>"
+	for i := 1; i <= lessons; i++ {
+		slug := fmt.Sprintf("lesson-%d", i)
+		if _, err := lessonRepo.Create(ctx, courseID, request.LessonCreate{Title: slug, Content: content}, slug); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	app := fiber.New()
+	NewExportHandler(exportService).RegisterRoutes(app)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/categories/"+categoryID+"/export?format=json", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != fiber.MIMEApplicationJSONCharsetUTF8 {
+		t.Errorf("Content-Type = %q, want %q", got, fiber.MIMEApplicationJSONCharsetUTF8)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentDisposition); !strings.HasPrefix(got, `attachment; filename="`) {
+		t.Errorf("Content-Disposition = %q, want attachment", got)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !json.Valid(body) {
+		t.Fatalf("export is not valid JSON: %.200s...", body)
+	}
+
+	var export response.CategoryExport
+	if err := json.Unmarshal(body, &export); err != nil {
+		t.Fatalf("unmarshal export: %v", err)
+	}
+	if export.SchemaVersion != response.CategoryExportSchemaVersion || export.Category.ID != categoryID {
+		t.Errorf("export header = version %d, category %s, want %d, %s",
+			export.SchemaVersion, export.Category.ID, response.CategoryExportSchemaVersion, categoryID)
+	}
+	if len(export.Courses) != 2 {
+		t.Fatalf("exported %d courses, want 2", len(export.Courses))
+	}
+	for _, course := range export.Courses {
+		want := 0
+		if course.ID == courseID {
+			want = lessons
+		}
+		if len(course.Lessons) != want {
+			t.Errorf("course %q: exported %d lessons, want %d", course.Title, len(course.Lessons), want)
+		}
+		for _, lesson := range course.Lessons {
+			if lesson.Content != content {
+				t.Errorf("lesson %q content is not exported as stored", lesson.Title)
+				break
+			}
+		}
+	}
+}
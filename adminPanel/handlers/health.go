@@ -2,34 +2,43 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"adminPanel/database"
 	"adminPanel/handlers/dto/response"
+	"adminPanel/services"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // HealthHandler обрабатывает HTTP-запросы для проверки здоровья приложения.
-// Содержит соединение с базой данных для проверки доступности.
+// Содержит соединение с базой данных и сервис S3 для проверки их доступности.
 type HealthHandler struct {
-	db *database.Database
+	db        *database.Database
+	s3Service *services.S3Service
 }
 
 // NewHealthHandler создает новый экземпляр HealthHandler.
-// Принимает соединение с базой данных.
-func NewHealthHandler(db *database.Database) *HealthHandler {
+// Принимает соединение с базой данных и сервис S3.
+func NewHealthHandler(db *database.Database, s3Service *services.S3Service) *HealthHandler {
 	return &HealthHandler{
-		db: db,
+		db:        db,
+		s3Service: s3Service,
 	}
 }
 
 // RegisterRoutes регистрирует маршруты для проверки здоровья.
-// Регистрирует /health и /health/db.
+// Регистрирует /health, /health/db, /health/db/deep и /health/s3.
 func (h *HealthHandler) RegisterRoutes(router fiber.Router) {
 	router.Get("/health", h.HealthCheck)
 	router.Get("/health/db", h.DBHealthCheck)
+	router.Get("/health/db/deep", h.DeepDBHealthCheck)
+	router.Get("/health/s3", h.S3HealthCheck)
 }
 
 // HealthCheck обрабатывает GET /health.
@@ -79,3 +88,220 @@ func (h *HealthHandler) DBHealthCheck(c *fiber.Ctx) error {
 		Version:  "1.0.0",
 	})
 }
+
+// DeepDBHealthCheck обрабатывает GET /health/db/deep.
+// Помимо Ping выполняет легкий запрос к knowledge_base.category_d и возвращает статистику пула.
+// Возвращает 503, если база недоступна или схема не читается, даже когда Ping проходит успешно.
+func (h *HealthHandler) DeepDBHealthCheck(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.DeepDBHealthCheck.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result := response.DeepDBHealthResponse{
+		Status:   "healthy",
+		Database: "connected",
+		Version:  "1.0.0",
+	}
+
+	if err := h.db.Pool.Ping(ctx); err != nil {
+		span.RecordError(err)
+		result.Status = "unhealthy"
+		result.Database = "disconnected"
+		result.Error = err.Error()
+	} else {
+		var one int
+		err := h.db.Pool.QueryRow(ctx, "SELECT 1 FROM knowledge_base.category_d LIMIT 1").Scan(&one)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			span.RecordError(err)
+			result.Status = "unhealthy"
+			result.Error = err.Error()
+		} else {
+			result.SchemaReachable = true
+		}
+	}
+
+	stat := h.db.Pool.Stat()
+	result.Pool = response.PoolStats{
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		TotalConns:    stat.TotalConns(),
+		MaxConns:      stat.MaxConns(),
+	}
+	if stat.MaxConns() > 0 {
+		result.Pool.Utilization = float64(stat.AcquiredConns()) / float64(stat.MaxConns())
+	}
+
+	span.AddEvent("handler.DeepDBHealthCheck.end",
+		trace.WithAttributes(
+			attribute.String("response.status", result.Status),
+			attribute.Bool("response.schema_reachable", result.SchemaReachable),
+			attribute.Float64("db.pool.utilization", result.Pool.Utilization),
+		))
+
+	if result.Status != "healthy" {
+		return c.Status(503).JSON(result)
+	}
+	return c.JSON(result)
+}
+
+// S3HealthCheck обрабатывает GET /health/s3.
+// Проверяет доступность MinIO/S3 и наличие bucket независимо от базы данных.
+// Возвращает 503, если хранилище недоступно.
+func (h *HealthHandler) S3HealthCheck(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.S3HealthCheck.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result := response.S3HealthResponse{
+		Status:  "healthy",
+		Storage: "reachable",
+		Bucket:  h.s3Service.Bucket(),
+		Version: "1.0.0",
+	}
+
+	if err := h.s3Service.HealthCheck(ctx); err != nil {
+		result.Status = "unhealthy"
+		result.Storage = "unreachable"
+		result.Error = err.Error()
+		return c.Status(503).JSON(result)
+	}
+
+	return c.JSON(result)
+}
+
+// deepCheckProbeTitle заголовок временной категории, создаваемой глубокой проверкой.
+// Запись никогда не сохраняется: транзакция всегда откатывается.
+const deepCheckProbeTitle = "__health_deep_probe__"
+
+// DeepHealthCheck обрабатывает GET /health/deep.
+// Внутри транзакции, которая всегда откатывается, вставляет и читает временную запись,
+// проверяя права на запись и наличие схемы, затем подсчитывает уроки без курса.
+// Возвращает список шагов и шаг, завершившийся ошибкой.
+// Маршрут доступен только администраторам: проверка выполняет запись в БД.
+func (h *HealthHandler) DeepHealthCheck(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.DeepHealthCheck.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result := response.DeepHealthResponse{
+		Status:  "healthy",
+		Steps:   []response.HealthStep{},
+		Version: "1.0.0",
+	}
+
+	// run выполняет шаг проверки и записывает его результат. Возвращает false при ошибке.
+	run := func(name string, step func() error) bool {
+		start := time.Now()
+		err := step()
+		item := response.HealthStep{
+			Name:       name,
+			Status:     "ok",
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			item.Status = "failed"
+			item.Error = err.Error()
+			result.Status = "unhealthy"
+			result.FailedStep = name
+			span.RecordError(err)
+		}
+		result.Steps = append(result.Steps, item)
+		return err == nil
+	}
+
+	ok := run("ping", func() error {
+		return h.db.Pool.Ping(ctx)
+	})
+
+	if ok {
+		tx, err := h.db.Pool.Begin(ctx)
+		ok = run("begin", func() error { return err })
+		if ok {
+			defer func() { _ = tx.Rollback(ctx) }()
+
+			var id string
+			ok = run("insert", func() error {
+				return tx.QueryRow(ctx, `
+					INSERT INTO knowledge_base.category_d (id, title, created_at, updated_at)
+					VALUES (gen_random_uuid(), $1, NOW(), NOW())
+					RETURNING id::text
+				`, deepCheckProbeTitle).Scan(&id)
+			})
+
+			if ok {
+				ok = run("select", func() error {
+					var title string
+					if err := tx.QueryRow(ctx,
+						"SELECT title FROM knowledge_base.category_d WHERE id = $1", id,
+					).Scan(&title); err != nil {
+						return err
+					}
+					if title != deepCheckProbeTitle {
+						return fmt.Errorf("read back unexpected title %q", title)
+					}
+					return nil
+				})
+			}
+
+			if ok {
+				run("rollback", func() error { return tx.Rollback(ctx) })
+			}
+		}
+	}
+
+	// Уроки без курса - проблема целостности данных, а не доступности, поэтому шаг
+	// помечается предупреждением и не делает проверку неуспешной.
+	if ok {
+		run("orphaned_lessons", func() error {
+			var count int
+			if err := h.db.Pool.QueryRow(ctx, `
+				SELECT COUNT(*) FROM knowledge_base.lesson_d l
+				WHERE NOT EXISTS (SELECT 1 FROM knowledge_base.course_b c WHERE c.id = l.course_id)
+			`).Scan(&count); err != nil {
+				return err
+			}
+			result.OrphanedLessons = &count
+			return nil
+		})
+		if result.OrphanedLessons != nil && *result.OrphanedLessons > 0 {
+			step := &result.Steps[len(result.Steps)-1]
+			step.Status = "warning"
+			step.Error = fmt.Sprintf("%d lessons reference missing courses; see GET /api/v1/maintenance/orphaned-lessons", *result.OrphanedLessons)
+		}
+	}
+
+	span.AddEvent("handler.DeepHealthCheck.end",
+		trace.WithAttributes(
+			attribute.String("response.status", result.Status),
+			attribute.String("response.failed_step", result.FailedStep),
+		))
+
+	if result.Status != "healthy" {
+		return c.Status(503).JSON(result)
+	}
+	return c.JSON(result)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"adminPanel/handlers/dto/request"
 	"adminPanel/handlers/dto/response"
@@ -18,26 +20,106 @@ import (
 // Содержит сервис для бизнес-логики и методы для маршрутов.
 type LessonHandler struct {
 	lessonService *services.LessonService
+	editorRoles   []string
 }
 
 // NewLessonHandler создает новый экземпляр LessonHandler.
-// Принимает сервис уроков.
-func NewLessonHandler(lessonService *services.LessonService) *LessonHandler {
+// Принимает сервис уроков и роли, которым доступен исходный контент урока для редактирования.
+func NewLessonHandler(lessonService *services.LessonService, editorRoles ...string) *LessonHandler {
 	return &LessonHandler{
 		lessonService: lessonService,
+		editorRoles:   editorRoles,
 	}
 }
 
 // RegisterRoutes регистрирует маршруты для уроков.
 // Привязывает методы к маршрутам для группы уроков.
 func (h *LessonHandler) RegisterRoutes(lessons fiber.Router) {
-	lessons.Get("/", h.getLessons)
-	lessons.Post("/", middleware.ValidateJSONSchema("lesson-create.json"), h.createLesson)
+	lessons.Get("/", middleware.ListTimeout(), h.getLessons)
+	lessons.Post("/", middleware.Idempotency(), middleware.ValidateJSONSchema("lesson-create.json"), h.createLesson)
+	lessons.Post("/bulk", middleware.Idempotency(), middleware.ValidateJSONSchema("lesson-bulk-create.json"), h.createLessons)
 	lessons.Get("/:lesson_id", h.getLesson)
+	lessons.Get("/:lesson_id/raw", middleware.RequireRole(h.editorRoles...), h.getLessonRaw)
 	lessons.Put("/:lesson_id", middleware.ValidateJSONSchema("lesson-update.json"), h.updateLesson)
+	lessons.Post("/:lesson_id/duplicate", h.duplicateLesson)
 	lessons.Delete("/:lesson_id", h.deleteLesson)
 }
 
+// maxLessonCountIDs ограничивает количество курсов в одном запросе подсчета уроков.
+const maxLessonCountIDs = 100
+
+// RegisterCourseRoutes регистрирует маршруты уроков, адресуемые по курсу без категории.
+func (h *LessonHandler) RegisterCourseRoutes(router fiber.Router) {
+	router.Get("/courses/lesson-counts", h.getLessonCounts)
+	router.Put("/courses/:course_id/lessons/reorder", h.reorderLessons)
+}
+
+// reorderLessons обрабатывает PUT /courses/:course_id/lessons/reorder.
+// Принимает JSON-массив ID уроков в новом порядке и сохраняет его.
+func (h *LessonHandler) reorderLessons(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	courseID := c.Params("course_id")
+
+	if !isValidUUID(courseID) {
+		return middleware.NewAppError("Invalid course ID format", 400, "INVALID_UUID")
+	}
+
+	var lessonIDs []string
+	if err := json.Unmarshal(c.Body(), &lessonIDs); err != nil {
+		return middleware.NewAppError("Request body must be a JSON array of lesson IDs", 400, "VALIDATION_ERROR")
+	}
+	if len(lessonIDs) == 0 {
+		return middleware.ValidationError("Lesson IDs list must not be empty")
+	}
+	for i, id := range lessonIDs {
+		if !isValidUUID(id) {
+			return middleware.NewAppError(fmt.Sprintf("Invalid lesson ID format: %s", id), 400, "INVALID_UUID")
+		}
+		lessonIDs[i] = strings.ToLower(id)
+	}
+
+	if err := h.lessonService.ReorderLessons(ctx, courseID, lessonIDs); err != nil {
+		return err
+	}
+
+	return c.JSON(response.StatusOnly{Status: "success"})
+}
+
+// getLessonCounts обрабатывает GET /courses/lesson-counts?ids=id1,id2.
+// Возвращает количество уроков для каждого из перечисленных курсов.
+func (h *LessonHandler) getLessonCounts(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var courseIDs []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !isValidUUID(id) {
+			return middleware.NewAppError(fmt.Sprintf("Invalid course ID format: %s", id), 400, "INVALID_UUID")
+		}
+		courseIDs = append(courseIDs, id)
+	}
+
+	if len(courseIDs) == 0 {
+		return middleware.ValidationError("Query parameter 'ids' is required")
+	}
+	if len(courseIDs) > maxLessonCountIDs {
+		return middleware.ValidationError(fmt.Sprintf("Too many course IDs, maximum is %d", maxLessonCountIDs))
+	}
+
+	counts, err := h.lessonService.GetLessonCounts(ctx, courseIDs)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(response.LessonCountsResponse{
+		Status: "success",
+		Data:   counts,
+	})
+}
+
 // getLessons обрабатывает GET /lessons.
 // Возвращает список уроков для курса с пагинацией и сортировкой.
 func (h *LessonHandler) getLessons(c *fiber.Ctx) error {
@@ -59,6 +141,8 @@ func (h *LessonHandler) getLessons(c *fiber.Ctx) error {
 		return err
 	}
 
+	lessonsResponse.Data.Pagination.Links = paginationLinks(c, lessonsResponse.Data.Pagination)
+
 	span.AddEvent("handler.getLessons.end", trace.WithAttributes(
 		attribute.Int("response.count", len(lessonsResponse.Data.Items)),
 	))
@@ -85,6 +169,34 @@ func (h *LessonHandler) getLesson(c *fiber.Ctx) error {
 	return c.JSON(lesson)
 }
 
+// getLessonRaw обрабатывает GET /lessons/:lesson_id/raw.
+// Отдает контент урока байт в байт так, как он сохранен: JSON-массив блоков с типом application/json,
+// остальной контент (HTML или текст) - как text/plain. Ответ не кэшируется, чтобы редактор
+// всегда получал актуальную версию.
+func (h *LessonHandler) getLessonRaw(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	courseID := c.Params("course_id")
+	lessonID := c.Params("lesson_id")
+
+	if !isValidUUID(courseID) || !isValidUUID(lessonID) {
+		return middleware.NewAppError("Invalid course or lesson ID format", 400, "INVALID_UUID")
+	}
+
+	content, err := h.lessonService.GetLessonRawContent(ctx, lessonID, courseID)
+	if err != nil {
+		return err
+	}
+
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "[") && json.Valid([]byte(trimmed)) {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.SendString(content)
+}
+
 // createLesson обрабатывает POST /lessons.
 // Создает новый урок на основе данных из тела запроса.
 func (h *LessonHandler) createLesson(c *fiber.Ctx) error {
@@ -108,6 +220,48 @@ func (h *LessonHandler) createLesson(c *fiber.Ctx) error {
 	return c.Status(201).JSON(lesson)
 }
 
+// createLessons обрабатывает POST /lessons/bulk.
+// Создает несколько уроков в одной транзакции и возвращает их в порядке запроса.
+func (h *LessonHandler) createLessons(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	courseID := c.Params("course_id")
+
+	if !isValidUUID(courseID) {
+		return middleware.NewAppError("Invalid course ID format", 400, "INVALID_UUID")
+	}
+
+	var input request.LessonBulkCreate
+	if err := c.BodyParser(&input); err != nil {
+		return middleware.NewAppError(fmt.Sprintf("Invalid request body: %v", err), 400, "VALIDATION_ERROR")
+	}
+
+	lessons, err := h.lessonService.CreateLessons(ctx, courseID, input.Lessons)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(lessons)
+}
+
+// duplicateLesson обрабатывает POST /lessons/:id/duplicate.
+// Создает копию урока сразу после исходного и возвращает ее.
+func (h *LessonHandler) duplicateLesson(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	courseID := c.Params("course_id")
+	lessonID := c.Params("lesson_id")
+
+	if !isValidUUID(courseID) || !isValidUUID(lessonID) {
+		return middleware.NewAppError("Invalid course or lesson ID format", 400, "INVALID_UUID")
+	}
+
+	lesson, err := h.lessonService.DuplicateLesson(ctx, lessonID, courseID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(201).JSON(lesson)
+}
+
 // updateLesson обрабатывает PUT /lessons/:id.
 // Обновляет существующий урок по его ID на основе данных из тела запроса.
 func (h *LessonHandler) updateLesson(c *fiber.Ctx) error {
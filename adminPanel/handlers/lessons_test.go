@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/repositories"
+	"adminPanel/services"
+	"adminPanel/testutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetLessonRawReturnsStoredContent(t *testing.T) {
+	db := testutil.NewDB(t)
+	contentCfg := config.ContentConfig{Compress: true, CompressMinSize: 1}
+	lessonRepo := repositories.NewLessonRepository(db, contentCfg, false)
+	lessonService := services.NewLessonService(lessonRepo, repositories.NewCourseRepository(db), contentCfg, nil)
+
+	categoryID := testutil.CreateCategory(t, db)
+	courseID := testutil.CreateCourse(t, db, categoryID, "test course", "easy", "draft")
+
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+	}{
+		{
+			name:        "blocks",
+			content:     `[{"type":"text","data":{"text":"<b>bold</b>  and\ttabs"}}, {"type":"code","data":{"code":"a < b && c","language":"go"}}]`,
+			contentType: fiber.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			name:        "html",
+			content:     "<p onclick=\"x()\">Урок <script>alert(1)</script></p>\r\n" + string(bytes.Repeat([]byte("повтор "), 200)),
+			contentType: fiber.MIMETextPlainCharsetUTF8,
+		},
+	}
+
+	app := fiber.New()
+	NewLessonHandler(lessonService).RegisterRoutes(app.Group("/categories/:category_id/courses/:course_id/lessons"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lesson, err := lessonRepo.Create(context.Background(), courseID, request.LessonCreate{Title: tt.name, Content: tt.content}, tt.name)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			var stored []byte
+			if err := db.Pool.QueryRow(context.Background(),
+				`SELECT content FROM knowledge_base.lesson_d WHERE id = $1`, lesson.ID).Scan(&stored); err != nil {
+				t.Fatalf("read stored content: %v", err)
+			}
+
+			path := "/categories/" + categoryID + "/courses/" + courseID + "/lessons/" + lesson.ID + "/raw"
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, body %s", resp.StatusCode, body)
+			}
+			if string(body) != tt.content {
+				t.Errorf("raw content = %q, want stored content %q", body, tt.content)
+			}
+			if tt.name == "html" && bytes.Equal(stored, body) {
+				t.Error("stored content is not compressed, want the endpoint to decompress it")
+			}
+			if got := resp.Header.Get(fiber.HeaderContentType); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := resp.Header.Get(fiber.HeaderCacheControl); got != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaintenanceHandler обрабатывает HTTP-запросы для операций обслуживания данных.
+// Все маршруты доступны только пользователям с ролью администратора.
+type MaintenanceHandler struct {
+	maintenanceService *services.MaintenanceService
+	adminRole          string
+}
+
+// NewMaintenanceHandler создает новый экземпляр MaintenanceHandler.
+// Принимает сервис обслуживания и роль администратора.
+func NewMaintenanceHandler(maintenanceService *services.MaintenanceService, adminRole string) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+		adminRole:          adminRole,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты обслуживания.
+// Создает группу /maintenance, защищенную проверкой роли администратора.
+func (h *MaintenanceHandler) RegisterRoutes(router fiber.Router) {
+	maintenance := router.Group("/maintenance", middleware.RequireRole(h.adminRole))
+
+	maintenance.Post("/backfill-slugs", h.backfillSlugs)
+	maintenance.Get("/orphaned-lessons", h.getOrphanedLessons)
+	maintenance.Delete("/orphaned-lessons", h.deleteOrphanedLessons)
+}
+
+// backfillSlugs обрабатывает POST /maintenance/backfill-slugs.
+// Генерирует slug для категорий, курсов и уроков без него; размер пакета задается параметром batch_size.
+func (h *MaintenanceHandler) backfillSlugs(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.backfillSlugs.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	batchSize := c.QueryInt("batch_size", services.DefaultSlugBackfillBatchSize)
+	result, err := h.maintenanceService.BackfillSlugs(ctx, batchSize)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.backfillSlugs.end",
+		trace.WithAttributes(
+			attribute.Int("response.categories", result.Categories),
+			attribute.Int("response.courses", result.Courses),
+			attribute.Int("response.lessons", result.Lessons),
+		))
+
+	return c.JSON(response.SlugBackfillResponse{
+		Status: "success",
+		Data:   *result,
+	})
+}
+
+// getOrphanedLessons обрабатывает GET /maintenance/orphaned-lessons.
+// Возвращает уроки, чей курс не существует, и их количество.
+func (h *MaintenanceHandler) getOrphanedLessons(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getOrphanedLessons.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	result, err := h.maintenanceService.FindOrphanedLessons(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.getOrphanedLessons.end",
+		trace.WithAttributes(attribute.Int("response.count", result.Count)))
+
+	return c.JSON(response.OrphanedLessonsResponse{
+		Status: "success",
+		Data:   *result,
+	})
+}
+
+// deleteOrphanedLessons обрабатывает DELETE /maintenance/orphaned-lessons.
+// Удаляет уроки, чей курс не существует, и возвращает их количество.
+func (h *MaintenanceHandler) deleteOrphanedLessons(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.deleteOrphanedLessons.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	result, err := h.maintenanceService.DeleteOrphanedLessons(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.deleteOrphanedLessons.end",
+		trace.WithAttributes(attribute.Int("response.deleted", result.Deleted)))
+
+	return c.JSON(response.OrphanedLessonsDeleteResponse{
+		Status: "success",
+		Data:   *result,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"adminPanel/handlers/dto/response"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StatsHandler обрабатывает HTTP-запросы сводной статистики базы знаний.
+type StatsHandler struct {
+	statsService *services.StatsService
+}
+
+// NewStatsHandler создает новый экземпляр StatsHandler.
+func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// RegisterRoutes регистрирует маршруты статистики в группе /stats.
+func (h *StatsHandler) RegisterRoutes(router fiber.Router) {
+	stats := router.Group("/stats")
+
+	stats.Get("/overview", h.getOverview)
+}
+
+// getOverview обрабатывает GET /stats/overview.
+// Возвращает количество категорий, курсов по видимости и уровню, уроков и время последнего изменения.
+func (h *StatsHandler) getOverview(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getStatsOverview.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+		))
+
+	overview, err := h.statsService.GetOverview(ctx)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.getStatsOverview.end",
+		trace.WithAttributes(
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(response.StatsOverviewResponse{
+		Status: "success",
+		Data:   *overview,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/services"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CategoryTreeHandler обрабатывает HTTP-запросы дерева категории (курсы и оглавления уроков).
+type CategoryTreeHandler struct {
+	treeService *services.CategoryTreeService
+	editorRoles []string
+}
+
+// NewCategoryTreeHandler создает новый экземпляр CategoryTreeHandler.
+// Принимает сервис дерева категорий и роли, которым доступно дерево.
+func NewCategoryTreeHandler(treeService *services.CategoryTreeService, editorRoles ...string) *CategoryTreeHandler {
+	return &CategoryTreeHandler{
+		treeService: treeService,
+		editorRoles: editorRoles,
+	}
+}
+
+// RegisterRoutes регистрирует маршрут дерева категории.
+func (h *CategoryTreeHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/categories/:category_id/tree", middleware.RequireRole(h.editorRoles...), h.getCategoryTree)
+}
+
+// getCategoryTree обрабатывает GET /categories/:category_id/tree.
+// Возвращает категорию с курсами и оглавлениями их уроков (заголовки и порядок, без контента).
+func (h *CategoryTreeHandler) getCategoryTree(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("handler.getCategoryTree.start",
+		trace.WithAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.path", c.Path()),
+			attribute.String("category.id", c.Params("category_id")),
+		))
+
+	categoryID := c.Params("category_id")
+
+	if !isValidUUID(categoryID) {
+		return c.Status(400).JSON(response.ErrorResponse{
+			Status: "error",
+			Error: response.ErrorDetails{
+				Code:    "INVALID_UUID",
+				Message: middleware.LocalizeMessage(c, "INVALID_UUID", "Invalid category ID format"),
+			},
+		})
+	}
+
+	tree, err := h.treeService.GetCategoryTree(ctx, categoryID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	span.AddEvent("handler.getCategoryTree.end",
+		trace.WithAttributes(
+			attribute.Int("response.courses", len(tree.Courses.Items)),
+			attribute.String("response.status", "success"),
+		))
+
+	return c.JSON(response.CategoryTreeResponse{
+		Status: "success",
+		Data:   *tree,
+	})
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"fmt"
+	"time"
 
+	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
 	"adminPanel/services"
 
@@ -13,20 +15,36 @@ import (
 
 // UploadHandler обрабатывает запросы на загрузку изображений в S3-совместимое хранилище.
 type UploadHandler struct {
-	s3Service *services.S3Service
+	s3Service   *services.S3Service
+	editorRoles []string
 }
 
 // NewUploadHandler создает новый экземпляр UploadHandler с заданным сервисом S3.
-func NewUploadHandler(s3Service *services.S3Service) *UploadHandler {
+// editorRoles - роли, которым разрешена загрузка через API.
+func NewUploadHandler(s3Service *services.S3Service, editorRoles ...string) *UploadHandler {
 	return &UploadHandler{
-		s3Service: s3Service,
+		s3Service:   s3Service,
+		editorRoles: editorRoles,
 	}
 }
 
 // RegisterRoutes регистрирует маршруты для загрузки изображений на переданном роутере.
+// Роутер должен быть защищен AuthMiddleware. Все маршруты записывают объекты в bucket
+// (GET /presign выдает политику для записи), поэтому роли редактора проверяются для всей группы,
+// а не по HTTP-методу.
 func (h *UploadHandler) RegisterRoutes(upload fiber.Router) {
+	upload.Use(middleware.RequireRole(h.editorRoles...), middleware.UploadTimeout())
 	upload.Post("/image", h.uploadImage)
+	upload.Post("/batch", h.uploadImagesBatch)
 	upload.Post("/image-from-url", h.uploadImageFromURL)
+	upload.Get("/presign", h.presignUpload)
+}
+
+// RegisterWebRoutes регистрирует маршрут POST /upload/image для WYSIWYG-редактора веб-интерфейса.
+// Редактор работает без Bearer-токена, поэтому маршрут регистрируется в группе веб-форм
+// под защитой WebCSRF: запрос должен содержать поле _csrf, как и остальные формы.
+func (h *UploadHandler) RegisterWebRoutes(web fiber.Router) {
+	web.Post("/upload/image", middleware.UploadTimeout(), h.uploadImage)
 }
 
 // UploadImageResponse представляет ответ на запрос загрузки изображения.
@@ -72,6 +90,49 @@ func (h *UploadHandler) uploadImage(c *fiber.Ctx) error {
 	})
 }
 
+// uploadImagesBatch обрабатывает POST /upload/batch.
+// Загружает несколько изображений из полей files[] multipart формы. Ошибки отдельных файлов
+// возвращаются в их результатах, а запрос целиком завершается успешно.
+func (h *UploadHandler) uploadImagesBatch(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	span := trace.SpanFromContext(ctx)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return middleware.NewAppError(
+			fmt.Sprintf("Failed to read multipart form: %v", err),
+			400,
+			"MISSING_FILE",
+		)
+	}
+
+	files := form.File["files[]"]
+	if len(files) == 0 {
+		files = form.File["files"]
+	}
+	span.SetAttributes(attribute.Int("upload.files_total", len(files)))
+
+	results, err := h.s3Service.UploadImages(ctx, files)
+	if err != nil {
+		return err
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Error == "" {
+			succeeded++
+		}
+	}
+	span.SetAttributes(attribute.Int("upload.files_succeeded", succeeded))
+
+	return c.JSON(response.BatchUploadResponse{
+		Status:    "success",
+		Items:     results,
+		Succeeded: succeeded,
+		Failed:    len(results) - succeeded,
+	})
+}
+
 // UploadImageFromURLRequest представляет запрос на загрузку изображения по URL.
 type UploadImageFromURLRequest struct {
 	URL string `json:"url" validate:"required,url"`
@@ -117,3 +178,46 @@ func (h *UploadHandler) uploadImageFromURL(c *fiber.Ctx) error {
 		Message:  "Image uploaded successfully from URL",
 	})
 }
+
+// PresignedUploadResponse представляет ответ с подписанной политикой для прямой загрузки в хранилище.
+// Клиент отправляет POST на UploadURL в формате multipart/form-data: сначала все поля Fields,
+// затем файл в поле file. После загрузки клиент передает ObjectKey в поле image_key курса.
+type PresignedUploadResponse struct {
+	Status    string            `json:"status"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	ObjectKey string            `json:"object_key"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// presignUpload обрабатывает GET /upload/presign?content_type=image/png.
+// Выдает подписанную политику POST-загрузки, по которой клиент загружает изображение напрямую
+// в S3-совместимое хранилище. Хранилище принимает только файл заявленного типа не больше MINIO_MAX_IMAGE_SIZE.
+func (h *UploadHandler) presignUpload(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	contentType := c.Query("content_type")
+	if contentType == "" {
+		return middleware.NewAppError(
+			"Query parameter 'content_type' is required",
+			400,
+			"MISSING_CONTENT_TYPE",
+		)
+	}
+
+	expiresAt := time.Now().Add(h.s3Service.PresignExpiry()).UTC()
+	post, err := h.s3Service.GeneratePresignedPost(ctx, contentType)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(PresignedUploadResponse{
+		Status:    "success",
+		UploadURL: post.URL,
+		Method:    fiber.MethodPost,
+		Fields:    post.Fields,
+		ObjectKey: post.ObjectKey,
+		ExpiresAt: expiresAt,
+	})
+}
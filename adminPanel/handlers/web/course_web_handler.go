@@ -3,25 +3,30 @@ package web
 import (
 	"adminPanel/config"
 	"adminPanel/handlers/dto/request"
+	"adminPanel/middleware"
 	"adminPanel/services"
 	"context"
 
 	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // CourseView представляет курс для отображения в веб-интерфейсе.
 type CourseView struct {
-	ID          string
-	CategoryID  string
-	Title       string
-	Description string
-	Level       string
-	LevelRu     string
-	Visible     bool
-	CreatedAt   string
-	UpdatedAt   string
-	ImageKey    string
-	Tests       CourseTestsView
+	ID           string
+	CategoryID   string
+	Title        string
+	Description  string
+	Level        string
+	LevelRu      string
+	Visible      bool
+	CreatedAt    string
+	UpdatedAt    string
+	ImageKey     string
+	ThumbnailKey string
+	LessonCount  int
+	Tests        CourseTestsView
 }
 
 // CourseTestsView представляет информацию о тестах курса.
@@ -62,15 +67,17 @@ func levelToRussian(level string) string {
 type CourseWebHandler struct {
 	courseService    *services.CourseService
 	categoryService  *services.CategoryService
+	lessonService    *services.LessonService
 	s3Service        *services.S3Service
 	testModuleConfig config.TestModuleConfig
 }
 
 // NewCourseWebHandler создает новый обработчик веб-страниц курсов.
-func NewCourseWebHandler(courseService *services.CourseService, categoryService *services.CategoryService, s3Service *services.S3Service, testModuleConfig config.TestModuleConfig) *CourseWebHandler {
+func NewCourseWebHandler(courseService *services.CourseService, categoryService *services.CategoryService, lessonService *services.LessonService, s3Service *services.S3Service, testModuleConfig config.TestModuleConfig) *CourseWebHandler {
 	return &CourseWebHandler{
 		courseService:    courseService,
 		categoryService:  categoryService,
+		lessonService:    lessonService,
 		s3Service:        s3Service,
 		testModuleConfig: testModuleConfig,
 	}
@@ -119,19 +126,30 @@ func (h *CourseWebHandler) RenderCoursesEditor(c *fiber.Ctx) error {
 		totalCount = len(totalResp.Data.Items)
 	}
 
+	courseIDs := make([]string, 0, len(coursesResp.Data.Items))
+	for _, course := range coursesResp.Data.Items {
+		courseIDs = append(courseIDs, course.ID)
+	}
+	lessonCounts, err := h.lessonService.GetLessonCounts(ctx, courseIDs)
+	if err != nil {
+		lessonCounts = map[string]int{}
+	}
+
 	courseViews := make([]CourseView, 0, len(coursesResp.Data.Items))
 	for _, course := range coursesResp.Data.Items {
 		courseViews = append(courseViews, CourseView{
-			ID:          course.ID,
-			CategoryID:  course.CategoryID,
-			Title:       course.Title,
-			Description: course.Description,
-			Level:       course.Level,
-			LevelRu:     levelToRussian(course.Level),
-			Visible:     course.Visibility == "public",
-			CreatedAt:   formatDateTime(course.CreatedAt),
-			UpdatedAt:   formatDateTime(course.UpdatedAt),
-			ImageKey:    course.ImageKey,
+			ID:           course.ID,
+			CategoryID:   course.CategoryID,
+			Title:        course.Title,
+			Description:  course.Description,
+			Level:        course.Level,
+			LevelRu:      levelToRussian(course.Level),
+			Visible:      course.Visibility == "public",
+			CreatedAt:    formatDateTime(course.CreatedAt),
+			UpdatedAt:    formatDateTime(course.UpdatedAt),
+			ImageKey:     course.ImageKey,
+			ThumbnailKey: services.ThumbnailKey(course.ImageKey),
+			LessonCount:  lessonCounts[course.ID],
 		})
 	}
 
@@ -338,6 +356,12 @@ func (h *CourseWebHandler) UpdateCourse(c *fiber.Ctx) error {
 		visibility = "public"
 	}
 
+	// Запоминаем текущий ключ изображения, чтобы удалить старый объект после успешной замены.
+	var previousImageKey string
+	if existing, err := h.courseService.GetCourse(ctx, categoryID, courseID); err == nil {
+		previousImageKey = existing.Data.ImageKey
+	}
+
 	var imageKey string
 	file, err := c.FormFile("image")
 	if err == nil && file != nil {
@@ -369,16 +393,20 @@ func (h *CourseWebHandler) UpdateCourse(c *fiber.Ctx) error {
 		}
 	}
 
-	input := request.CourseUpdate{
-		Title:       title,
-		Description: description,
-		Level:       level,
-		CategoryID:  categoryID,
-		Visibility:  visibility,
-		ImageKey:    imageKey,
+	// Частичное обновление: без нового файла ключ изображения не передается и сохраняется.
+	input := request.CoursePatch{
+		Title:       &title,
+		Description: &description,
+		Visibility:  &visibility,
+	}
+	if level != "" {
+		input.Level = &level
+	}
+	if imageKey != "" {
+		input.ImageKey = &imageKey
 	}
 
-	_, err = h.courseService.UpdateCourse(ctx, categoryID, courseID, input)
+	_, err = h.courseService.PatchCourse(ctx, categoryID, courseID, input)
 	if err != nil {
 		course, _ := h.courseService.GetCourse(ctx, categoryID, courseID)
 		var courseView *CourseView
@@ -405,19 +433,61 @@ func (h *CourseWebHandler) UpdateCourse(c *fiber.Ctx) error {
 		}, "layouts/main")
 	}
 
+	if imageKey != "" && previousImageKey != "" && previousImageKey != imageKey {
+		h.deleteReplacedImage(ctx, courseID, previousImageKey)
+	}
+
 	return c.Redirect("/admin/categories/" + categoryID + "/courses")
 }
 
+// deleteReplacedImage удаляет из S3 изображение, замененное при обновлении курса.
+// Изображение, на которое ссылаются другие курсы (например, копии), не удаляется.
+// Ошибка удаления не влияет на результат обновления: она только логируется и записывается в трейс.
+func (h *CourseWebHandler) deleteReplacedImage(ctx context.Context, courseID, imageKey string) {
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("course.image.cleanup", trace.WithAttributes(
+		attribute.String("course.id", courseID),
+		attribute.String("object.key", imageKey),
+	))
+
+	// Копии курса ссылаются на то же изображение: удаляем объект, только если он больше никому не нужен.
+	inUse, err := h.courseService.IsImageKeyInUse(ctx, imageKey)
+	if err != nil {
+		span.RecordError(err)
+		middleware.LoggerFromContext(ctx).Warn("Failed to check usage of replaced image", "image_key", imageKey, "course_id", courseID, "error", err)
+		return
+	}
+	if inUse {
+		span.AddEvent("course.image.cleanup.skipped", trace.WithAttributes(
+			attribute.String("reason", "image is used by another course"),
+		))
+		return
+	}
+
+	if err := h.s3Service.DeleteImageByKey(ctx, imageKey); err != nil {
+		span.RecordError(err)
+		middleware.LoggerFromContext(ctx).Warn("Failed to delete replaced image", "image_key", imageKey, "course_id", courseID, "error", err)
+	}
+}
+
 // DeleteCourse обрабатывает удаление курса.
 func (h *CourseWebHandler) DeleteCourse(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	categoryID := c.Params("category_id")
 	courseID := c.Params("course_id")
 
-	err := h.courseService.DeleteCourse(ctx, categoryID, courseID)
+	imageKeys, err := h.courseService.DeleteCourse(ctx, categoryID, courseID, "")
 	if err != nil {
 		return c.Redirect("/admin/categories/" + categoryID + "/courses")
 	}
 
+	// Ошибка удаления изображения не влияет на результат: курс уже удален.
+	for _, key := range imageKeys {
+		if err := h.s3Service.DeleteImageByKey(ctx, key); err != nil {
+			trace.SpanFromContext(ctx).RecordError(err)
+			middleware.LoggerFromContext(ctx).Warn("Failed to delete image of deleted course", "image_key", key, "course_id", courseID, "error", err)
+		}
+	}
+
 	return c.Redirect("/admin/categories/" + categoryID + "/courses")
 }
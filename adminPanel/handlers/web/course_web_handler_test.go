@@ -0,0 +1,58 @@
+package web
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/repositories"
+	"adminPanel/services"
+	"adminPanel/testutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUpdateCourseWithoutNewImageKeepsImage(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+
+	categoryRepo := repositories.NewCategoryRepository(db)
+	courseService := services.NewCourseService(repositories.NewCourseRepository(db), categoryRepo, config.CourseConfig{}, config.SearchConfig{}, nil)
+	categoryService := services.NewCategoryService(categoryRepo, config.CategoryConfig{}, nil)
+	h := NewCourseWebHandler(courseService, categoryService, nil, nil, config.TestModuleConfig{})
+
+	categoryID := testutil.CreateCategory(t, db)
+	courseID := testutil.CreateCourse(t, db, categoryID, "old title", "easy", "draft")
+	const imageKey = "courses/cover.png"
+	if _, err := db.Pool.Exec(ctx, `UPDATE knowledge_base.course_b SET image_key = $1 WHERE id = $2`, imageKey, courseID); err != nil {
+		t.Fatalf("set image key: %v", err)
+	}
+
+	app := fiber.New()
+	app.Post("/admin/categories/:category_id/courses/:course_id", h.UpdateCourse)
+
+	form := url.Values{"title": {"new title"}, "description": {""}, "level": {"easy"}}
+	req := httptest.NewRequest(fiber.MethodPost, "/admin/categories/"+categoryID+"/courses/"+courseID, strings.NewReader(form.Encode()))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != fiber.StatusFound {
+		t.Fatalf("status = %d, want redirect after update", resp.StatusCode)
+	}
+
+	var title, storedKey string
+	if err := db.Pool.QueryRow(ctx, `SELECT title, COALESCE(image_key, '') FROM knowledge_base.course_b WHERE id = $1`, courseID).Scan(&title, &storedKey); err != nil {
+		t.Fatalf("read course: %v", err)
+	}
+	if title != "new title" {
+		t.Errorf("title = %q, want %q", title, "new title")
+	}
+	if storedKey != imageKey {
+		t.Errorf("image_key = %q, want %q kept", storedKey, imageKey)
+	}
+}
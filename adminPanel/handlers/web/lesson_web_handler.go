@@ -2,9 +2,9 @@ package web
 
 import (
 	"adminPanel/handlers/dto/request"
+	"adminPanel/middleware"
 	"adminPanel/models"
 	"adminPanel/services"
-	"log"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -81,9 +81,10 @@ func (h *LessonWebHandler) RenderLessonsEditor(c *fiber.Ctx) error {
 		}, "layouts/main")
 	}
 
+	logger := middleware.LoggerFromContext(ctx)
 	lessonViews := make([]LessonView, 0, len(lessonsResp.Data.Items))
 	for i, lesson := range lessonsResp.Data.Items {
-		log.Printf("[DEBUG] Lesson #%d: ID=%s, Title=%s", i+1, lesson.ID, lesson.Title)
+		logger.Debug("Lesson listed", "number", i+1, "lesson_id", lesson.ID, "title", lesson.Title)
 		lessonViews = append(lessonViews, LessonView{
 			ID:        lesson.ID,
 			CourseID:  lesson.CourseID,
@@ -203,7 +204,8 @@ func (h *LessonWebHandler) CreateLesson(c *fiber.Ctx) error {
 	title := c.FormValue("title")
 	content := c.FormValue("content")
 
-	log.Printf("[DEBUG] CreateLesson: title=%s, content length=%d", title, len(content))
+	middleware.LoggerFromContext(ctx).Debug("CreateLesson form submitted",
+		"course_id", courseID, "title", title, "content_length", len(content))
 
 	if title == "" {
 		category, _ := h.categoryService.GetCategory(ctx, categoryID)
@@ -252,8 +254,9 @@ func (h *LessonWebHandler) UpdateLesson(c *fiber.Ctx) error {
 	title := c.FormValue("title")
 	content := c.FormValue("content")
 
-	log.Printf("[DEBUG] UpdateLesson: lessonID=%s, title=%s, content length=%d", lessonID, title, len(content))
-	log.Printf("[DEBUG] Content first 100 chars: %s", content[:min(100, len(content))])
+	middleware.LoggerFromContext(ctx).Debug("UpdateLesson form submitted",
+		"lesson_id", lessonID, "title", title, "content_length", len(content),
+		"content_preview", content[:min(100, len(content))])
 
 	if title == "" {
 		category, _ := h.categoryService.GetCategory(ctx, categoryID)
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"adminPanel/config"
@@ -26,8 +29,11 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
@@ -71,6 +77,96 @@ func setupTracerProvider(ctx context.Context, cfg config.OTelConfig) (*tracesdk.
 	return tp, nil
 }
 
+// setupMeterProvider настраивает провайдер метрик OpenTelemetry с периодическим экспортом по OTLP.
+// Включается тем же флагом, что и трассировка. Возвращает MeterProvider или nil если метрики отключены.
+func setupMeterProvider(ctx context.Context, cfg config.OTelConfig) (*metricsdk.MeterProvider, error) {
+	if !cfg.Enabled {
+		log.Println("ℹ️  OpenTelemetry metrics are disabled (OTEL_EXPORTER_OTLP_ENDPOINT not set)")
+		return nil, nil
+	}
+
+	target := strings.TrimPrefix(strings.TrimPrefix(cfg.Endpoint, "http://"), "https://")
+	exp, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(target),
+		otlpmetricgrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := metricsdk.NewMeterProvider(
+		metricsdk.WithReader(metricsdk.NewPeriodicReader(exp)),
+		metricsdk.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+
+	log.Printf("✅ OpenTelemetry metrics initialized (endpoint=%s, service=%s)", cfg.Endpoint, cfg.ServiceName)
+	return mp, nil
+}
+
+// metricsMiddleware возвращает промежуточное ПО, записывающее метрики HTTP-запросов:
+// число запросов, число запросов в обработке и гистограмму длительности.
+// Метки - метод, шаблон маршрута и статус ответа. Без настроенного провайдера метрик записи не выполняются.
+func metricsMiddleware(meter metric.Meter) (fiber.Handler, error) {
+	requests, err := meter.Int64Counter("http.server.request.count",
+		metric.WithDescription("Number of handled HTTP requests"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+	active, err := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("Number of HTTP requests in flight"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP requests"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *fiber.Ctx) error {
+		startTime := time.Now()
+		method := attribute.String("http.request.method", c.Method())
+		ctx := c.UserContext()
+
+		active.Add(ctx, 1, metric.WithAttributes(method))
+		err := c.Next()
+		active.Add(ctx, -1, metric.WithAttributes(method))
+
+		// Маршрут известен только после маршрутизации; ошибки, не обработанные ниже по цепочке,
+		// оформляются FiberErrorHandler позже, поэтому их статус определяется по самой ошибке.
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+		attrs := metric.WithAttributes(
+			method,
+			attribute.String("http.route", c.Route().Path),
+			attribute.Int("http.response.status_code", status),
+		)
+		requests.Add(ctx, 1, attrs)
+		duration.Record(ctx, time.Since(startTime).Seconds(), attrs)
+
+		return err
+	}, nil
+}
+
 // tracingMiddleware возвращает промежуточное ПО для трассировки HTTP-запросов.
 // Создает span для каждого запроса и записывает метрики.
 func tracingMiddleware(tracer trace.Tracer) fiber.Handler {
@@ -186,7 +282,8 @@ func tracingMiddleware(tracer trace.Tracer) fiber.Handler {
 // 8. Создает репозитории, сервисы и обработчики для категорий, курсов, уроков и загрузки файлов.
 // 9. Регистрирует API маршруты с аутентификацией.
 // 10. Регистрирует веб-маршруты для админ-интерфейса.
-// 11. Запускает HTTP-сервер на указанном адресе.
+// 11. Запускает HTTP-сервер на указанном адресе и при SIGINT/SIGTERM останавливает его,
+// дожидаясь завершения обрабатываемых запросов (SHUTDOWN_TIMEOUT).
 func main() {
 	ctx := context.Background()
 
@@ -198,9 +295,28 @@ func main() {
 
 	log.Printf("📋 Configuration loaded (debug=%v)", settings.Debug)
 
+	handlers.SetStrictUUIDValidation(settings.Validation.StrictUUID)
+	handlers.SetRootPath(settings.Server.RootPath)
+
 	if err := middleware.InitAuth(); err != nil {
 		log.Fatalf("⚠️  Failed to initialize auth: %v", err)
 	}
+	middleware.SetExemptPaths(settings.Health.ExemptPaths)
+	middleware.SetClaimPaths(middleware.ClaimPaths{
+		Subject:  settings.Keycloak.SubjectClaim,
+		Username: settings.Keycloak.UsernameClaim,
+		Name:     settings.Keycloak.NameClaim,
+		Email:    settings.Keycloak.EmailClaim,
+		Roles:    settings.Keycloak.RoleClaimPaths,
+		Required: settings.Keycloak.RequiredClaims,
+	})
+	middleware.SetRouteTimeouts(middleware.RouteTimeouts{
+		List:   settings.Server.ListRequestTimeout,
+		Upload: settings.Server.UploadRequestTimeout,
+		Export: settings.Server.ExportRequestTimeout,
+	})
+	middleware.SetIdempotencyTTL(settings.Server.IdempotencyKeyTTL)
+	middleware.SetDefaultLocale(settings.Server.DefaultLocale)
 
 	db, err := database.InitDB(settings)
 	if err != nil {
@@ -219,21 +335,55 @@ func main() {
 		}()
 	}
 
+	mp, err := setupMeterProvider(ctx, settings.OTel)
+	if err != nil {
+		log.Printf("⚠️  Failed to initialize metrics: %v", err)
+	} else if mp != nil {
+		defer func() {
+			if shutdownErr := mp.Shutdown(ctx); shutdownErr != nil {
+				log.Printf("⚠️  Failed to shutdown meter provider: %v", shutdownErr)
+			}
+		}()
+	}
+
 	engine := handlebars.New("./templates", ".hbs")
 
 	engine.AddFunc("eq", func(a, b string) bool {
 		return a == b
 	})
 
+	// Маршрутизация нестрогая и регистронезависимая; завершающий слэш дополнительно
+	// удаляется NormalizeTrailingSlash, чтобы все промежуточные обработчики видели канонический путь.
+	// Общий лимит тела запроса (MAX_REQUEST_BODY_BYTES) больше MINIO_MAX_IMAGE_SIZE, что проверяется в Validate;
+	// превышение отклоняется сервером до обработчиков и оформляется FiberErrorHandler как 413.
 	app := fiber.New(fiber.Config{
 		AppName:               settings.Server.AppName,
+		BodyLimit:             settings.Server.MaxRequestBodyBytes,
+		ErrorHandler:          middleware.FiberErrorHandler,
 		DisableStartupMessage: false,
 		Views:                 engine,
+		StrictRouting:         false,
+		CaseSensitive:         false,
 	})
 
+	app.Use(middleware.NormalizeTrailingSlash())
 	app.Use(recover.New())
 	app.Use(logger.New())
 	app.Use(tracingMiddleware(otel.Tracer(settings.OTel.ServiceName)))
+	// Логгер запроса с request_id, trace_id и span_id; доступен обработчикам через middleware.LoggerFromContext.
+	app.Use(middleware.RequestLogger(middleware.NewLogger(settings.Server.LogLevel)))
+	httpMetrics, err := metricsMiddleware(otel.Meter(settings.OTel.ServiceName))
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize HTTP metrics: %v", err)
+	}
+	app.Use(httpMetrics)
+	if settings.Metrics.Enabled {
+		if err := middleware.InitPrometheus(db.Pool); err != nil {
+			log.Fatalf("❌ Failed to initialize Prometheus metrics: %v", err)
+		}
+		app.Use(middleware.PrometheusMiddleware())
+		log.Println("✅ Prometheus metrics enabled at /metrics")
+	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     strings.Join(settings.GetCORSOrigins(), ","),
 		AllowMethods:     settings.CORS.AllowMethods,
@@ -243,32 +393,8 @@ func main() {
 	}))
 
 	app.Use(middleware.ErrorHandlerMiddleware())
-
-	healthHandler := handlers.NewHealthHandler(db)
-	app.Get("/health", healthHandler.HealthCheck)
-	app.Get("/health/db", healthHandler.DBHealthCheck)
-
-	app.Static("/doc", "./docs")
-
-	app.Get("/swagger/*", swagger.New(swagger.Config{
-		URL:         "/doc/swagger.json",
-		DeepLinking: true,
-		Title:       settings.Server.AppName,
-		OAuth: &swagger.OAuthConfig{
-			ClientId:     settings.Keycloak.ClientID,
-			ClientSecret: settings.Keycloak.ClientSecret,
-			AppName:      settings.Keycloak.AppName,
-			Scopes:       []string{"openid", "profile", "email"},
-		},
-	}))
-
-	categoryRepo := repositories.NewCategoryRepository(db)
-	courseRepo := repositories.NewCourseRepository(db)
-	lessonRepo := repositories.NewLessonRepository(db)
-
-	categoryService := services.NewCategoryService(categoryRepo)
-	courseService := services.NewCourseService(courseRepo, categoryRepo)
-	lessonService := services.NewLessonService(lessonRepo, courseRepo)
+	// Общий таймаут запроса; списки, загрузка и выгрузка переопределяют его при регистрации маршрутов.
+	app.Use(middleware.RequestTimeout(settings.Server.RequestTimeout))
 
 	s3Service, err := services.NewS3Service(settings.Minio)
 	if err != nil {
@@ -281,6 +407,56 @@ func main() {
 		log.Printf("✅ S3 bucket '%s' is ready", settings.Minio.Bucket)
 	}
 
+	healthHandler := handlers.NewHealthHandler(db, s3Service)
+	app.Get("/health", healthHandler.HealthCheck)
+	app.Get("/health/db", healthHandler.DBHealthCheck)
+	app.Get("/health/db/deep", healthHandler.DeepDBHealthCheck)
+	app.Get("/health/s3", healthHandler.S3HealthCheck)
+	if settings.Health.DeepCheckEnabled {
+		app.Get("/health/deep", middleware.AuthMiddleware(), middleware.RequireRole(settings.Keycloak.AdminRole), healthHandler.DeepHealthCheck)
+	}
+	if settings.Metrics.Enabled {
+		app.Get("/metrics", middleware.PrometheusHandler())
+	}
+
+	// В production Swagger выключен (SWAGGER_ENABLED), и его маршруты отвечают 404.
+	// Спецификация при этом может оставаться доступной по токену (SWAGGER_SPEC_WITH_AUTH).
+	if settings.Swagger.Enabled {
+		app.Static("/doc", "./docs")
+
+		app.Get("/swagger/*", swagger.New(swagger.Config{
+			URL:         "/doc/swagger.json",
+			DeepLinking: true,
+			Title:       settings.Server.AppName,
+			OAuth: &swagger.OAuthConfig{
+				ClientId:     settings.Keycloak.ClientID,
+				ClientSecret: settings.Keycloak.ClientSecret,
+				AppName:      settings.Keycloak.AppName,
+				Scopes:       settings.Keycloak.Scopes,
+			},
+		}))
+	} else if settings.Swagger.SpecWithAuth {
+		app.Get("/doc/swagger.json", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+			return c.SendFile("./docs/swagger.json")
+		})
+	}
+
+	categoryRepo := repositories.NewCategoryRepository(db)
+	courseRepo := repositories.NewCourseRepository(db)
+	lessonRepo := repositories.NewLessonRepository(db, settings.Content, settings.Debug)
+	importRepo := repositories.NewImportRepository(db, settings.Content, settings.Debug)
+
+	changePublisher := services.NewChangePublisher(repositories.NewChangeEventRepository(db), settings.Events)
+	eventPublisher := services.NewEventPublisher(settings.Events, changePublisher.Subscriber())
+	categoryService := services.NewCategoryService(categoryRepo, settings.Category, eventPublisher)
+	courseService := services.NewCourseService(courseRepo, categoryRepo, settings.Course, settings.Search, eventPublisher)
+	lessonService := services.NewLessonService(lessonRepo, courseRepo, settings.Content, eventPublisher)
+	maintenanceService := services.NewMaintenanceService(categoryRepo, courseRepo, lessonRepo)
+	exportService := services.NewExportService(categoryService, courseRepo, lessonRepo, importRepo)
+	treeService := services.NewCategoryTreeService(categoryService, courseRepo, lessonRepo)
+	bannerService := services.NewBannerService(repositories.NewBannerRepository(db))
+	statsService := services.NewStatsService(repositories.NewStatsRepository(db), settings.Stats)
+
 	// Добавляем вспомогательную функцию для генерации URL изображений в шаблонах
 	engine.AddFunc("s3ImageURL", func(imageKey string) string {
 		if imageKey == "" {
@@ -290,27 +466,45 @@ func main() {
 	})
 
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
-	courseHandler := handlers.NewCourseHandler(courseService)
-	lessonHandler := handlers.NewLessonHandler(lessonService)
-	uploadHandler := handlers.NewUploadHandler(s3Service)
+	courseHandler := handlers.NewCourseHandler(courseService, s3Service, settings.Keycloak.AdminRole)
+	lessonHandler := handlers.NewLessonHandler(lessonService, settings.Keycloak.EditorRole, settings.Keycloak.AdminRole)
+	uploadHandler := handlers.NewUploadHandler(s3Service, settings.Keycloak.EditorRole, settings.Keycloak.AdminRole)
+	dashboardHandler := handlers.NewDashboardHandler(categoryService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService, settings.Keycloak.AdminRole)
+	exportHandler := handlers.NewExportHandler(exportService)
+	treeHandler := handlers.NewCategoryTreeHandler(treeService, settings.Keycloak.EditorRole, settings.Keycloak.AdminRole)
+	bannerHandler := handlers.NewBannerHandler(bannerService, settings.Keycloak.AdminRole)
 
 	api := app.Group("/api/v1")
 
-	upload := api.Group("/upload")
-	uploadHandler.RegisterRoutes(upload)
-
+	api.Use(middleware.JSONBodyLimit(settings.Server.MaxJSONBodyBytes))
 	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.RequireMethodRoles(
+		[]string{settings.Keycloak.ViewerRole, settings.Keycloak.EditorRole, settings.Keycloak.AdminRole},
+		[]string{settings.Keycloak.EditorRole, settings.Keycloak.AdminRole},
+	))
 	categoryHandler.RegisterRoutes(api)
 	courseHandler.RegisterRoutes(api)
+	lessonHandler.RegisterCourseRoutes(api)
+	dashboardHandler.RegisterRoutes(api)
+	statsHandler.RegisterRoutes(api)
+	maintenanceHandler.RegisterRoutes(api)
+	exportHandler.RegisterRoutes(api)
+	treeHandler.RegisterRoutes(api)
+	bannerHandler.RegisterRoutes(api)
+	uploadHandler.RegisterRoutes(api.Group("/upload"))
 	lessons := api.Group("/categories/:category_id/courses/:course_id/lessons")
 	lessonHandler.RegisterRoutes(lessons)
 
 	app.Static("/static", "./static")
 
 	web := app.Group("")
+	web.Use(middleware.WebCSRF(), middleware.CSRFTemplateToken)
+	uploadHandler.RegisterWebRoutes(web)
 
 	categoryWebHandler := webhandlers.NewCategoryWebHandler(categoryService)
-	courseWebHandler := webhandlers.NewCourseWebHandler(courseService, categoryService, s3Service, settings.TestModule)
+	courseWebHandler := webhandlers.NewCourseWebHandler(courseService, categoryService, lessonService, s3Service, settings.TestModule)
 	lessonWebHandler := webhandlers.NewLessonWebHandler(lessonService, courseService, categoryService)
 	homeWebHandler := webhandlers.NewHomeWebHandler(categoryService, courseService, lessonService)
 
@@ -337,13 +531,47 @@ func main() {
 	web.Post("/categories/:category_id/courses/:course_id/lessons/:lesson_id/delete", lessonWebHandler.DeleteLesson)
 
 	log.Printf("🚀 Server starting on %s", settings.Server.Address)
-	log.Printf("📚 Swagger UI (via nginx): http://localhost/admin/swagger/")
-	log.Printf("📖 Swagger JSON (via nginx): http://localhost/admin/doc/swagger.json")
+	if settings.Swagger.Enabled {
+		log.Printf("📚 Swagger UI (via nginx): http://localhost/admin/swagger/")
+		log.Printf("📖 Swagger JSON (via nginx): http://localhost/admin/doc/swagger.json")
+	} else {
+		log.Printf("📚 Swagger UI is disabled (SWAGGER_ENABLED=false)")
+	}
 	log.Printf("🏥 Health check (via nginx): http://localhost/health")
 	log.Printf("📍 API (via nginx): http://localhost/admin/api/v1/")
 
-	if err := app.Listen(settings.Server.Address); err != nil {
+	// Отложенные закрытие пула БД и остановка провайдеров OTel выполняются после возврата из serve.
+	if err := serve(app, settings.Server.Address, settings.Server.ShutdownTimeout); err != nil {
 		log.Fatalf("❌ Failed to start server: %v", err)
 	}
+}
+
+// serve запускает HTTP-сервер и блокируется до его остановки. По SIGINT или SIGTERM сервер
+// перестает принимать соединения и ждет завершения обрабатываемых запросов не дольше timeout;
+// повторный сигнал завершает процесс немедленно. Возвращает только ошибку запуска сервера.
+func serve(app *fiber.App, address string, timeout time.Duration) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- app.Listen(address)
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
 
+	open := app.Server().GetOpenConnectionsCount()
+	log.Printf("🛑 Shutdown signal received, draining %d open connections (timeout %s)", open, timeout)
+
+	start := time.Now()
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		log.Printf("⚠️  Server shutdown did not complete cleanly: %v (%d connections still open)", err, app.Server().GetOpenConnectionsCount())
+	}
+	log.Printf("✅ Server stopped, drained in %s", time.Since(start).Round(time.Millisecond))
+	return nil
 }
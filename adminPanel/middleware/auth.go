@@ -3,6 +3,7 @@
 package middleware
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -75,15 +76,12 @@ func InitAuth() error {
 }
 
 // AuthMiddleware возвращает промежуточное ПО для аутентификации JWT-токенов.
-// Пропускает определенные пути без проверки и проверяет токены для остальных.
+// Пропускает без проверки пути из списка исключений (см. IsExemptPath) и проверяет токены для остальных.
+// Из claims проверенного токена заполняется User (см. SetClaimPaths и CurrentUser); токен без
+// обязательных claims отклоняется с 401.
 func AuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		path := c.Path()
-
-		if strings.HasPrefix(path, "/admin/swagger") ||
-			strings.HasPrefix(path, "/health") ||
-			path == "/favicon.ico" ||
-			path == "/admin/swagger/doc.json" {
+		if IsExemptPath(c.Path()) {
 			return c.Next()
 		}
 
@@ -128,13 +126,62 @@ func AuthMiddleware() fiber.Handler {
 			log.Printf("⚠️  Token audience mismatch. Expected: %s", authConfig.Audience)
 		}
 
-		if preferredUsername, ok := claims["preferred_username"].(string); ok {
-			log.Printf("✅ Authenticated user: %s", preferredUsername)
+		user, err := userFromClaims(claims, claimPaths)
+		if err != nil {
+			log.Printf("❌ Invalid JWT: %v", err)
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Token is missing required claims",
+				"code":  "UNAUTHORIZED",
+			})
+		}
+		if user.Username != "" {
+			log.Printf("✅ Authenticated user: %s", user.Username)
+		}
+
+		c.Locals(userLocalsKey, user)
+		return c.Next()
+	}
+}
+
+// RequireRole возвращает промежуточное ПО, пропускающее только пользователей хотя бы с одной из ролей `roles`.
+// Роли читаются из claims токена по путям SetClaimPaths. В ответе 403 перечисляются требуемые роли.
+// Должно выполняться после AuthMiddleware. Если аутентификация не настроена, проверка пропускается.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return checkRoles(c, roles)
+	}
+}
+
+// RequireMethodRoles возвращает промежуточное ПО, выбирающее требуемые роли по HTTP-методу:
+// для GET, HEAD и OPTIONS - readRoles, для остальных (POST, PUT, PATCH, DELETE) - writeRoles.
+// Должно выполняться после AuthMiddleware. Если аутентификация не настроена, проверка пропускается.
+func RequireMethodRoles(readRoles, writeRoles []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return checkRoles(c, readRoles)
+		default:
+			return checkRoles(c, writeRoles)
 		}
+	}
+}
 
-		c.Locals("userClaims", claims)
+// checkRoles пропускает запрос дальше, если у пользователя есть хотя бы одна из ролей,
+// иначе отвечает 403 FORBIDDEN с перечнем требуемых ролей.
+func checkRoles(c *fiber.Ctx, roles []string) error {
+	if authConfig == nil || jwks == nil {
 		return c.Next()
 	}
+
+	user, ok := CurrentUser(c)
+	if !ok || !user.HasAnyRole(roles) {
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{
+			"error": fmt.Sprintf("Insufficient permissions: requires one of roles [%s]", strings.Join(roles, ", ")),
+			"code":  "FORBIDDEN",
+		})
+	}
+
+	return c.Next()
 }
 
 // verifyAudience проверяет, соответствует ли аудитория токена ожидаемой.
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JSONBodyLimit возвращает промежуточное ПО, отклоняющее с 413 запросы, не являющиеся multipart,
+// с телом больше limit байт. Multipart-запросы (загрузка изображений и файлов импорта)
+// ограничиваются только общим BodyLimit сервера и проверкой размера изображения.
+func JSONBodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(strings.ToLower(c.Get(fiber.HeaderContentType)), fiber.MIMEMultipartForm) {
+			return c.Next()
+		}
+
+		if c.Request().Header.ContentLength() > limit || len(c.Body()) > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+				Status: "error",
+				Error: ErrorDetails{
+					Code:    "REQUEST_TOO_LARGE",
+					Message: LocalizeMessage(c, "REQUEST_TOO_LARGE", fmt.Sprintf("Request body exceeds maximum allowed size of %d bytes", limit)),
+				},
+			})
+		}
+
+		return c.Next()
+	}
+}
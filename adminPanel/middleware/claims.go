@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// userLocalsKey ключ, под которым AuthMiddleware сохраняет User в c.Locals.
+const userLocalsKey = "user"
+
+// User пользователь, извлеченный из claims токена по путям ClaimPaths.
+type User struct {
+	ID       string
+	Username string
+	Name     string
+	Email    string
+	Roles    []string
+}
+
+// HasAnyRole проверяет, есть ли у пользователя хотя бы одна из ролей roles.
+func (u User) HasAnyRole(roles []string) bool {
+	for _, r := range u.Roles {
+		if slices.Contains(roles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentUser возвращает пользователя, сохраненного AuthMiddleware для текущего запроса.
+// Второй результат равен false, если запрос не прошел аутентификацию (или она не настроена).
+func CurrentUser(c *fiber.Ctx) (User, bool) {
+	user, ok := c.Locals(userLocalsKey).(User)
+	return user, ok
+}
+
+// ClaimPaths пути к данным пользователя в claims токена, сегменты разделены точками
+// (например, realm_access.roles или resource_access.<client>.roles).
+// Required перечисляет поля пользователя (subject, username, name, email, roles),
+// без которых токен отклоняется.
+type ClaimPaths struct {
+	Subject  string
+	Username string
+	Name     string
+	Email    string
+	Roles    []string
+	Required []string
+}
+
+// defaultClaimPaths пути к claims токена Keycloak, если SetClaimPaths не вызывалась.
+var defaultClaimPaths = ClaimPaths{
+	Subject:  "sub",
+	Username: "preferred_username",
+	Name:     "name",
+	Email:    "email",
+	Roles:    []string{"realm_access.roles"},
+	Required: []string{"subject"},
+}
+
+// claimPaths пути к claims, по которым AuthMiddleware заполняет User.
+var claimPaths = defaultClaimPaths
+
+// SetClaimPaths задает пути к данным пользователя в claims токена (настройки KEYCLOAK_CLAIM_*,
+// KEYCLOAK_ROLE_CLAIM_PATHS и KEYCLOAK_REQUIRED_CLAIMS). Незаданные пути заменяются путями Keycloak
+// по умолчанию. Должна вызываться до обработки запросов.
+func SetClaimPaths(paths ClaimPaths) {
+	if paths.Subject == "" {
+		paths.Subject = defaultClaimPaths.Subject
+	}
+	if paths.Username == "" {
+		paths.Username = defaultClaimPaths.Username
+	}
+	if paths.Name == "" {
+		paths.Name = defaultClaimPaths.Name
+	}
+	if paths.Email == "" {
+		paths.Email = defaultClaimPaths.Email
+	}
+	if len(paths.Roles) == 0 {
+		paths.Roles = defaultClaimPaths.Roles
+	}
+	claimPaths = paths
+}
+
+// userFromClaims заполняет User из claims токена по путям paths. Роли объединяются по всем путям
+// paths.Roles. Возвращает ошибку со списком полей из paths.Required, которые не удалось заполнить.
+func userFromClaims(claims jwt.MapClaims, paths ClaimPaths) (User, error) {
+	user := User{
+		ID:       claimString(claims, paths.Subject),
+		Username: claimString(claims, paths.Username),
+		Name:     claimString(claims, paths.Name),
+		Email:    claimString(claims, paths.Email),
+	}
+	hasRoles := false
+	for _, path := range paths.Roles {
+		if roles, ok := claimStrings(claims, path); ok {
+			hasRoles = true
+			user.Roles = append(user.Roles, roles...)
+		}
+	}
+
+	present := map[string]bool{
+		"subject":  user.ID != "",
+		"username": user.Username != "",
+		"name":     user.Name != "",
+		"email":    user.Email != "",
+		"roles":    hasRoles,
+	}
+	var missing []string
+	for _, field := range paths.Required {
+		if !present[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return user, fmt.Errorf("token is missing required claims: %s", strings.Join(missing, ", "))
+	}
+
+	return user, nil
+}
+
+// claimValue возвращает значение по пути path (сегменты через точку) во вложенных claims.
+func claimValue(claims jwt.MapClaims, path string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(claims)
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// claimString возвращает строку по пути path. Если путь не найден или значение не является строкой,
+// возвращает пустую строку.
+func claimString(claims jwt.MapClaims, path string) string {
+	value, _ := claimValue(claims, path)
+	s, _ := value.(string)
+	return s
+}
+
+// claimStrings возвращает строковые элементы списка по пути path.
+// Второй результат равен false, если путь не найден или значение не является списком.
+func claimStrings(claims jwt.MapClaims, path string) ([]string, bool) {
+	value, _ := claimValue(claims, path)
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestUserFromClaims(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		paths   ClaimPaths
+		want    User
+		wantErr bool
+	}{
+		{
+			name: "keycloak defaults",
+			claims: jwt.MapClaims{
+				"sub":                "user-1",
+				"preferred_username": "jdoe",
+				"name":               "John Doe",
+				"email":              "jdoe@example.com",
+				"realm_access":       map[string]interface{}{"roles": []interface{}{"admin", "offline_access"}},
+			},
+			paths: defaultClaimPaths,
+			want:  User{ID: "user-1", Username: "jdoe", Name: "John Doe", Email: "jdoe@example.com", Roles: []string{"admin", "offline_access"}},
+		},
+		{
+			name: "client roles merged with realm roles",
+			claims: jwt.MapClaims{
+				"sub":             "user-2",
+				"realm_access":    map[string]interface{}{"roles": []interface{}{"viewer"}},
+				"resource_access": map[string]interface{}{"admin-panel": map[string]interface{}{"roles": []interface{}{"editor", 42}}},
+			},
+			paths: ClaimPaths{
+				Subject: "sub",
+				Roles:   []string{"realm_access.roles", "resource_access.admin-panel.roles"},
+			},
+			want: User{ID: "user-2", Roles: []string{"viewer", "editor"}},
+		},
+		{
+			name: "nested custom claims",
+			claims: jwt.MapClaims{
+				"oid":     "user-3",
+				"upn":     "jane@corp.example",
+				"profile": map[string]interface{}{"display_name": "Jane Roe", "mail": "jane@example.com"},
+				"groups":  []interface{}{"lms-admins"},
+			},
+			paths: ClaimPaths{
+				Subject:  "oid",
+				Username: "upn",
+				Name:     "profile.display_name",
+				Email:    "profile.mail",
+				Roles:    []string{"groups"},
+				Required: []string{"subject", "email", "roles"},
+			},
+			want: User{ID: "user-3", Username: "jane@corp.example", Name: "Jane Roe", Email: "jane@example.com", Roles: []string{"lms-admins"}},
+		},
+		{
+			name:   "values of wrong type are ignored",
+			claims: jwt.MapClaims{"sub": 12345, "email": []interface{}{"a@example.com"}, "realm_access": "admin"},
+			paths:  ClaimPaths{Subject: "sub", Email: "email", Roles: []string{"realm_access.roles"}},
+			want:   User{},
+		},
+		{
+			name:    "missing required subject",
+			claims:  jwt.MapClaims{"preferred_username": "jdoe"},
+			paths:   defaultClaimPaths,
+			wantErr: true,
+		},
+		{
+			name:    "missing required roles",
+			claims:  jwt.MapClaims{"sub": "user-4", "roles": []interface{}{"admin"}},
+			paths:   ClaimPaths{Subject: "sub", Roles: []string{"realm_access.roles"}, Required: []string{"subject", "roles"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := userFromClaims(tt.claims, tt.paths)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("userFromClaims() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("userFromClaims() error = %v", err)
+			}
+			if got.ID != tt.want.ID || got.Username != tt.want.Username || got.Name != tt.want.Name ||
+				got.Email != tt.want.Email || !slices.Equal(got.Roles, tt.want.Roles) {
+				t.Errorf("userFromClaims() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareUsesClaimPaths(t *testing.T) {
+	enableTestAuth(t)
+	prevPaths := claimPaths
+	t.Cleanup(func() { claimPaths = prevPaths })
+	SetClaimPaths(ClaimPaths{
+		Subject:  "oid",
+		Email:    "profile.mail",
+		Roles:    []string{"groups"},
+		Required: []string{"subject", "email"},
+	})
+
+	app := fiber.New()
+	app.Use(AuthMiddleware())
+	app.Get("/me", func(c *fiber.Ctx) error {
+		user, ok := CurrentUser(c)
+		if !ok {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.JSON(user)
+	})
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		status int
+		want   User
+	}{
+		{
+			name: "all required claims",
+			claims: jwt.MapClaims{
+				"oid":                "user-1",
+				"preferred_username": "jdoe",
+				"profile":            map[string]interface{}{"mail": "jdoe@example.com"},
+				"groups":             []interface{}{"lms-admins"},
+			},
+			status: fiber.StatusOK,
+			want:   User{ID: "user-1", Username: "jdoe", Email: "jdoe@example.com", Roles: []string{"lms-admins"}},
+		},
+		{
+			name:   "missing required email",
+			claims: jwt.MapClaims{"oid": "user-2", "email": "jdoe@example.com"},
+			status: fiber.StatusUnauthorized,
+		},
+		{
+			// Путь к subject переопределен, поэтому стандартный sub не используется.
+			name:   "subject at default path only",
+			claims: jwt.MapClaims{"sub": "user-3", "profile": map[string]interface{}{"mail": "jdoe@example.com"}},
+			status: fiber.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, tt.claims))
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status != fiber.StatusOK {
+				return
+			}
+
+			var got User
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.ID != tt.want.ID || got.Username != tt.want.Username || got.Email != tt.want.Email ||
+				!slices.Equal(got.Roles, tt.want.Roles) {
+				t.Errorf("user = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// signTestToken подписывает токен с claims ключом, который enableTestAuth добавляет в JWKS.
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
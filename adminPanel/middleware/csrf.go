@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/csrf"
+)
+
+// CSRFFormField имя скрытого поля формы, в котором передается CSRF-токен.
+const CSRFFormField = "_csrf"
+
+// csrfContextKey ключ c.Locals, под которым middleware csrf хранит токен текущего запроса.
+const csrfContextKey = "csrf"
+
+// WebCSRF возвращает промежуточное ПО защиты веб-форм от CSRF.
+// Для безопасных методов выдает токен (double submit cookie), для POST/PUT/DELETE сверяет
+// поле формы _csrf с cookie и отклоняет несовпадение ошибкой 403.
+// Регистрируется только для веб-маршрутов вместе с CSRFTemplateToken: API защищено Bearer-токеном.
+func WebCSRF() fiber.Handler {
+	return csrf.New(csrf.Config{
+		KeyLookup:      "form:" + CSRFFormField,
+		CookieName:     "admin_csrf",
+		CookiePath:     "/",
+		CookieSameSite: "Lax",
+		CookieHTTPOnly: true,
+		ContextKey:     csrfContextKey,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return NewAppError("Invalid or missing CSRF token, reload the page and try again", 403, "CSRF_TOKEN_INVALID")
+		},
+	})
+}
+
+// CSRFTemplateToken добавляет CSRF-токен текущего запроса в данные шаблонов как csrfToken,
+// чтобы формы могли передать его в скрытом поле _csrf. Должно регистрироваться после WebCSRF.
+func CSRFTemplateToken(c *fiber.Ctx) error {
+	if token, ok := c.Locals(csrfContextKey).(string); ok {
+		if err := c.Bind(fiber.Map{"csrfToken": token}); err != nil {
+			return err
+		}
+	}
+	return c.Next()
+}
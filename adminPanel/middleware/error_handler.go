@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"log"
 	"strings"
 
@@ -8,10 +9,13 @@ import (
 )
 
 // AppError представляет пользовательскую ошибку приложения с кодом и статусом HTTP.
+// format и args сохраняют шаблон сообщения, чтобы Localize мог перевести его вместе с аргументами.
 type AppError struct {
 	Message    string `json:"error"`
 	StatusCode int    `json:"-"`
 	Code       string `json:"code"`
+	format     string
+	args       []string
 }
 
 // Error реализует интерфейс error для AppError.
@@ -31,10 +35,13 @@ func NewAppError(message string, statusCode int, code string) *AppError {
 // NotFoundError создает ошибку 404 для не найденного ресурса.
 func NotFoundError(resource, identifier string) *AppError {
 	message := resource + " not found"
+	err := NewAppError(message, 404, "NOT_FOUND")
+	err.format, err.args = "%s not found", []string{resource}
 	if identifier != "" {
-		message = resource + " with id '" + identifier + "' not found"
+		err.Message = resource + " with id '" + identifier + "' not found"
+		err.format, err.args = "%s with id '%s' not found", []string{resource, identifier}
 	}
-	return NewAppError(message, 404, "NOT_FOUND")
+	return err
 }
 
 // ConflictError создает ошибку 409 для конфликта ресурсов.
@@ -70,7 +77,8 @@ type ErrorResponse struct {
 }
 
 // ErrorHandlerMiddleware возвращает промежуточное ПО для обработки ошибок.
-// Преобразует ошибки в соответствующие HTTP-ответы для API или HTML.
+// Преобразует ошибки в соответствующие HTTP-ответы для API или HTML;
+// сообщения переводятся на язык запроса (см. Locale).
 func ErrorHandlerMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		err := c.Next()
@@ -87,14 +95,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 						Status: "error",
 						Error: ErrorDetails{
 							Code:    e.Code,
-							Message: e.Message,
+							Message: e.Localize(c),
 						},
 					})
 				} else {
 					return c.Status(e.StatusCode).Render("pages/error", fiber.Map{
 						"title":      "Ошибка",
 						"HTTPStatus": e.StatusCode,
-						"Message":    e.Message,
+						"Message":    e.Localize(c),
 					}, "layouts/main")
 				}
 
@@ -104,14 +112,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 						Status: "error",
 						Error: ErrorDetails{
 							Code:    getErrorCode(e.Code),
-							Message: e.Message,
+							Message: LocalizeMessage(c, getErrorCode(e.Code), e.Message),
 						},
 					})
 				} else {
 					return c.Status(e.Code).Render("pages/error", fiber.Map{
 						"title":      "Ошибка",
 						"HTTPStatus": e.Code,
-						"Message":    e.Message,
+						"Message":    LocalizeMessage(c, getErrorCode(e.Code), e.Message),
 					}, "layouts/main")
 				}
 
@@ -125,14 +133,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 							Status: "error",
 							Error: ErrorDetails{
 								Code:    "NOT_FOUND",
-								Message: "Resource not found",
+								Message: LocalizeMessage(c, "NOT_FOUND", "Resource not found"),
 							},
 						})
 					} else {
 						return c.Status(404).Render("pages/error", fiber.Map{
 							"title":      "Ошибка",
 							"HTTPStatus": 404,
-							"Message":    "Resource not found",
+							"Message":    LocalizeMessage(c, "NOT_FOUND", "Resource not found"),
 						}, "layouts/main")
 					}
 
@@ -142,14 +150,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 							Status: "error",
 							Error: ErrorDetails{
 								Code:    "ALREADY_EXISTS",
-								Message: "Resource already exists",
+								Message: LocalizeMessage(c, "ALREADY_EXISTS", "Resource already exists"),
 							},
 						})
 					} else {
 						return c.Status(409).Render("pages/error", fiber.Map{
 							"title":      "Ошибка",
 							"HTTPStatus": 409,
-							"Message":    "Resource already exists",
+							"Message":    LocalizeMessage(c, "ALREADY_EXISTS", "Resource already exists"),
 						}, "layouts/main")
 					}
 
@@ -159,14 +167,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 							Status: "error",
 							Error: ErrorDetails{
 								Code:    "INVALID_REFERENCE",
-								Message: "Invalid reference",
+								Message: LocalizeMessage(c, "INVALID_REFERENCE", "Invalid reference"),
 							},
 						})
 					} else {
 						return c.Status(400).Render("pages/error", fiber.Map{
 							"title":      "Ошибка",
 							"HTTPStatus": 400,
-							"Message":    "Invalid reference",
+							"Message":    LocalizeMessage(c, "INVALID_REFERENCE", "Invalid reference"),
 						}, "layouts/main")
 					}
 
@@ -176,14 +184,14 @@ func ErrorHandlerMiddleware() fiber.Handler {
 							Status: "error",
 							Error: ErrorDetails{
 								Code:    "SERVER_ERROR",
-								Message: "Internal server error",
+								Message: LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 							},
 						})
 					} else {
 						return c.Status(500).Render("pages/error", fiber.Map{
 							"title":      "Ошибка",
 							"HTTPStatus": 500,
-							"Message":    "Internal server error",
+							"Message":    LocalizeMessage(c, "SERVER_ERROR", "Internal server error"),
 						}, "layouts/main")
 					}
 				}
@@ -194,6 +202,35 @@ func ErrorHandlerMiddleware() fiber.Handler {
 	}
 }
 
+// FiberErrorHandler обрабатывает ошибки, возникшие вне цепочки обработчиков, например
+// отклонение сервером тела запроса больше BodyLimit (413). Для API возвращает стандартный
+// ErrorResponse, для веб-страниц - страницу ошибки.
+func FiberErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	message := "Internal server error"
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		code = fe.Code
+		message = fe.Message
+	}
+
+	message = LocalizeMessage(c, getErrorCode(code), message)
+	if strings.HasPrefix(c.Path(), "/api/") {
+		return c.Status(code).JSON(ErrorResponse{
+			Status: "error",
+			Error: ErrorDetails{
+				Code:    getErrorCode(code),
+				Message: message,
+			},
+		})
+	}
+	return c.Status(code).Render("pages/error", fiber.Map{
+		"title":      "Ошибка",
+		"HTTPStatus": code,
+		"Message":    message,
+	}, "layouts/main")
+}
+
 // getErrorCode возвращает строковый код ошибки по HTTP-статус коду.
 func getErrorCode(statusCode int) string {
 	switch statusCode {
@@ -207,10 +244,14 @@ func getErrorCode(statusCode int) string {
 		return "NOT_FOUND"
 	case 409:
 		return "ALREADY_EXISTS"
+	case 413:
+		return "REQUEST_TOO_LARGE"
 	case 422:
 		return "VALIDATION_ERROR"
 	case 500:
 		return "SERVER_ERROR"
+	case 504:
+		return "REQUEST_TIMEOUT"
 	default:
 		return "UNKNOWN_ERROR"
 	}
@@ -0,0 +1,36 @@
+package middleware
+
+import "strings"
+
+// deepHealthPath путь глубокой проверки здоровья. Он выполняет пробную запись в БД,
+// поэтому всегда требует аутентификации, даже если /health входит в список исключений.
+const deepHealthPath = "/health/deep"
+
+// exemptPaths префиксы инфраструктурных эндпоинтов (проверки здоровья, метрики, документация),
+// которые не проходят аутентификацию в AuthMiddleware. Задается через SetExemptPaths.
+var exemptPaths []string
+
+// SetExemptPaths задает префиксы путей, исключенных из аутентификации
+// (настройка AUTH_EXEMPT_PATHS). Должна вызываться до обработки запросов.
+func SetExemptPaths(prefixes []string) {
+	exemptPaths = prefixes
+}
+
+// IsExemptPath сообщает, относится ли путь к исключенным инфраструктурным эндпоинтам.
+// Префикс совпадает с путем целиком или по границе сегмента: /health исключает /health/db,
+// но не /healthcheck.
+func IsExemptPath(path string) bool {
+	if path == deepHealthPath {
+		return false
+	}
+	for _, prefix := range exemptPaths {
+		prefix = strings.TrimRight(prefix, "/")
+		if prefix == "" {
+			continue
+		}
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
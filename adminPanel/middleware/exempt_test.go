@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+// enableTestAuth включает проверку токенов в AuthMiddleware на время теста.
+func enableTestAuth(t *testing.T) {
+	t.Helper()
+
+	prevConfig, prevJWKS, prevExempt := authConfig, jwks, exemptPaths
+	t.Cleanup(func() {
+		authConfig, jwks, exemptPaths = prevConfig, prevJWKS, prevExempt
+	})
+
+	authConfig = &AuthConfig{IssuerURL: "http://keycloak.test/realms/test"}
+	jwks = keyfunc.NewGiven(map[string]keyfunc.GivenKey{
+		"test": keyfunc.NewGivenHMAC([]byte("test-secret"), keyfunc.GivenKeyOptions{}),
+	})
+}
+
+func TestAuthMiddlewareSkipsExemptPaths(t *testing.T) {
+	enableTestAuth(t)
+	SetExemptPaths([]string{"/health", "/metrics/"})
+
+	app := fiber.New()
+	app.Use(AuthMiddleware())
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	for _, path := range []string{"/health", "/health/db", "/health/deep", "/healthcheck", "/metrics", "/api/v1/categories"} {
+		app.Get(path, ok)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/health", want: fiber.StatusOK},
+		{path: "/health/db", want: fiber.StatusOK},
+		{path: "/metrics", want: fiber.StatusOK},
+		{path: "/health/deep", want: fiber.StatusUnauthorized},
+		{path: "/healthcheck", want: fiber.StatusUnauthorized},
+		{path: "/api/v1/categories", want: fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
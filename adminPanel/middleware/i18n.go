@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Поддерживаемые языки сообщений об ошибках.
+const (
+	LocaleEnglish = "en"
+	LocaleRussian = "ru"
+)
+
+// SupportedLocales перечисляет языки, для которых есть каталог сообщений.
+var SupportedLocales = []string{LocaleEnglish, LocaleRussian}
+
+// defaultLocale язык сообщений для запросов без заголовка Accept-Language.
+var defaultLocale atomic.Value
+
+// SetDefaultLocale задает язык сообщений для запросов без заголовка Accept-Language.
+// Неподдерживаемый язык заменяется английским.
+func SetDefaultLocale(locale string) {
+	if !slices.Contains(SupportedLocales, locale) {
+		locale = LocaleEnglish
+	}
+	defaultLocale.Store(locale)
+}
+
+// Locale определяет язык ответа по заголовку Accept-Language с учетом q-весов.
+// Без заголовка используется язык по умолчанию (SetDefaultLocale), для неизвестных языков - английский.
+func Locale(c *fiber.Ctx) string {
+	fallback, _ := defaultLocale.Load().(string)
+	if fallback == "" {
+		fallback = LocaleEnglish
+	}
+	if c.Get(fiber.HeaderAcceptLanguage) == "" {
+		return fallback
+	}
+
+	// Язык по умолчанию идет первым, чтобы "*" выбирал именно его.
+	offers := append([]string{fallback}, slices.DeleteFunc(slices.Clone(SupportedLocales), func(l string) bool {
+		return l == fallback
+	})...)
+	if locale := c.AcceptsLanguages(offers...); locale != "" {
+		return locale
+	}
+	return LocaleEnglish
+}
+
+// messageCatalog содержит переводы сообщений об ошибках: язык -> английский текст или шаблон -> перевод.
+// Английские тексты служат ключами, поэтому для английского каталог не нужен.
+var messageCatalog = map[string]map[string]string{
+	LocaleRussian: {
+		// Шаблоны NotFoundError и названия ресурсов.
+		"%s not found":              "Не найдено: %s",
+		"%s with id '%s' not found": "Не найдено: %s с id '%s'",
+		"Category":                  "категория",
+		"Course":                    "курс",
+		"Lesson":                    "урок",
+
+		"Invalid ID format":                 "Неверный формат ID",
+		"Invalid category ID format":        "Неверный формат ID категории",
+		"Invalid target category ID format": "Неверный формат ID целевой категории",
+		"Invalid exclude_id format":         "Неверный формат exclude_id",
+		"Invalid request body":              "Некорректное тело запроса",
+		"Invalid JSON format":               "Некорректный формат JSON",
+		"Validation failed":                 "Ошибка валидации",
+		"Internal server error":             "Внутренняя ошибка сервера",
+		"Resource not found":                "Ресурс не найден",
+		"Resource already exists":           "Ресурс уже существует",
+		"Invalid reference":                 "Ссылка на несуществующий ресурс",
+		"Invalid or missing CSRF token, reload the page and try again": "Неверный или отсутствующий CSRF-токен, обновите страницу и попробуйте снова",
+		"At least one field must be provided":                          "Нужно указать хотя бы одно поле",
+		"Title must be between 1 and 255 characters":                   "Заголовок должен содержать от 1 до 255 символов",
+		"Level must be one of: hard, medium, easy":                     "Уровень сложности должен быть одним из: hard, medium, easy",
+		"Visibility must be one of: draft, public":                     "Видимость должна быть одной из: draft, public",
+		"Mode must be one of: hard, soft":                              "Режим должен быть одним из: hard, soft",
+		"Slug must contain at least one letter or digit":               "Slug должен содержать хотя бы одну букву или цифру",
+		"Category with this title already exists":                      "Категория с таким заголовком уже существует",
+		"Cannot delete category with associated courses":               "Нельзя удалить категорию, в которой есть курсы",
+		"Lesson IDs list must not be empty":                            "Список ID уроков не должен быть пустым",
+		"Lesson IDs must list every lesson of the course exactly once": "Список ID уроков должен содержать каждый урок курса ровно один раз",
+		"Lesson content must be a JSON array of content blocks":        "Контент урока должен быть JSON-массивом блоков",
+		"Query parameter 'ids' is required":                            "Параметр запроса 'ids' обязателен",
+		"Banner message is required":                                   "Текст баннера обязателен",
+		"Banner severity must be one of: info, warning, critical":      "Уровень баннера должен быть одним из: info, warning, critical",
+	},
+}
+
+// codeMessages содержит общие переводы для кодов ошибок. Они используются, когда точного
+// перевода сообщения нет: исходный текст с подробностями добавляется после двоеточия.
+var codeMessages = map[string]map[string]string{
+	LocaleRussian: {
+		"INVALID_UUID":      "Неверный формат ID",
+		"INVALID_JSON":      "Некорректный JSON",
+		"VALIDATION_ERROR":  "Ошибка валидации",
+		"NOT_FOUND":         "Не найдено",
+		"ALREADY_EXISTS":    "Конфликт",
+		"CONFLICT":          "Конфликт",
+		"BAD_REQUEST":       "Некорректный запрос",
+		"UNAUTHORIZED":      "Требуется аутентификация",
+		"FORBIDDEN":         "Доступ запрещен",
+		"SERVER_ERROR":      "Внутренняя ошибка сервера",
+		"REQUEST_TIMEOUT":   "Превышено время обработки запроса",
+		"REQUEST_TOO_LARGE": "Слишком большое тело запроса",
+	},
+}
+
+// LocalizeMessage переводит сообщение об ошибке с кодом code на язык запроса.
+// Сообщение ищется в каталоге целиком; если его там нет, к общему переводу кода добавляется
+// исходный текст. Для английского и для неизвестных кодов возвращается message без изменений.
+func LocalizeMessage(c *fiber.Ctx, code, message string) string {
+	return localize(Locale(c), code, message)
+}
+
+// Localize возвращает сообщение ошибки на языке запроса. Для ошибок, созданных по шаблону
+// (NotFoundError), переводится шаблон и его аргументы, поэтому подробности сохраняются.
+func (e *AppError) Localize(c *fiber.Ctx) string {
+	locale := Locale(c)
+	if e.format != "" {
+		if format, ok := messageCatalog[locale][e.format]; ok {
+			args := make([]any, len(e.args))
+			for i, arg := range e.args {
+				args[i] = translate(locale, arg)
+			}
+			return fmt.Sprintf(format, args...)
+		}
+	}
+	return localize(locale, e.Code, e.Message)
+}
+
+// localize переводит сообщение на заданный язык.
+func localize(locale, code, message string) string {
+	if translated, ok := messageCatalog[locale][message]; ok {
+		return translated
+	}
+	if generic, ok := codeMessages[locale][code]; ok {
+		if message == "" {
+			return generic
+		}
+		return generic + ": " + message
+	}
+	return message
+}
+
+// translate возвращает перевод строки из каталога или ее саму, если перевода нет.
+func translate(locale, s string) string {
+	if translated, ok := messageCatalog[locale][s]; ok {
+		return translated
+	}
+	return s
+}
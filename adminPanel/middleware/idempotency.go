@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IdempotencyKeyHeader заголовок, которым клиент помечает повторяемый запрос создания.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength ограничивает длину ключа идемпотентности.
+const maxIdempotencyKeyLength = 255
+
+// idempotencySweepInterval минимальный интервал между очистками истекших ключей.
+const idempotencySweepInterval = time.Minute
+
+// idempotentResponse сохраненный ответ на запрос с ключом идемпотентности.
+// Пока запрос обрабатывается, done равно false.
+type idempotentResponse struct {
+	bodyHash    [sha256.Size]byte
+	done        bool
+	status      int
+	contentType string
+	location    string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// idempotencyStore хранит ответы на запросы с ключом идемпотентности в памяти процесса.
+type idempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*idempotentResponse
+	lastSweep time.Time
+}
+
+// idempotency хранилище ключей, используемое Idempotency; задается через SetIdempotencyTTL.
+var idempotency = &idempotencyStore{
+	ttl:     24 * time.Hour,
+	entries: make(map[string]*idempotentResponse),
+}
+
+// SetIdempotencyTTL задает время хранения ключей идемпотентности (настройка IDEMPOTENCY_KEY_TTL).
+// Должна вызываться до обработки запросов.
+func SetIdempotencyTTL(ttl time.Duration) {
+	idempotency.mu.Lock()
+	defer idempotency.mu.Unlock()
+	idempotency.ttl = ttl
+}
+
+// Idempotency возвращает промежуточное ПО для запросов создания с заголовком Idempotency-Key.
+// Успешный (2xx) ответ сохраняется на время IDEMPOTENCY_KEY_TTL, и повтор запроса с тем же ключом
+// возвращает его без повторного создания ресурса. Ключ действует в пределах пути и пользователя.
+// Повтор с тем же ключом, но другим телом, а также повтор, пока исходный запрос еще обрабатывается,
+// отклоняются с 409. Неуспешные ответы не сохраняются, и запрос можно повторить с тем же ключом.
+// Запросы без заголовка передаются дальше без изменений.
+func Idempotency() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return NewAppError("Idempotency-Key must not exceed 255 characters", fiber.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY")
+		}
+
+		scope := c.Method() + " " + c.Path() + " " + idempotencySubject(c) + " " + key
+		bodyHash := sha256.Sum256(c.Body())
+
+		cached, err := idempotency.begin(scope, bodyHash)
+		if err != nil {
+			return err
+		}
+		if cached != nil {
+			c.Set("Idempotent-Replayed", "true")
+			if cached.location != "" {
+				c.Set(fiber.HeaderLocation, cached.location)
+			}
+			c.Set(fiber.HeaderContentType, cached.contentType)
+			return c.Status(cached.status).Send(cached.body)
+		}
+
+		// Ключ освобождается при любом неуспешном завершении, включая панику в обработчике.
+		completed := false
+		defer func() {
+			if !completed {
+				idempotency.abort(scope)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			return nil
+		}
+
+		completed = true
+		idempotency.complete(scope, &idempotentResponse{
+			bodyHash:    bodyHash,
+			status:      status,
+			contentType: string(c.Response().Header.ContentType()),
+			location:    string(c.Response().Header.Peek(fiber.HeaderLocation)),
+			body:        append([]byte(nil), c.Response().Body()...),
+		})
+		return nil
+	}
+}
+
+// idempotencySubject возвращает идентификатор пользователя, чтобы ключи
+// разных пользователей не пересекались. Без аутентификации возвращает пустую строку.
+func idempotencySubject(c *fiber.Ctx) string {
+	user, _ := CurrentUser(c)
+	return user.ID
+}
+
+// begin регистрирует начало обработки запроса с ключом scope. Возвращает сохраненный ответ,
+// если запрос с этим ключом уже успешно выполнен, или nil, если запрос нужно обработать.
+// Возвращает ошибку 409, если ключ использован с другим телом или исходный запрос еще выполняется.
+func (s *idempotencyStore) begin(scope string, bodyHash [sha256.Size]byte) (*idempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[scope]; ok && now.Before(entry.expiresAt) {
+		if entry.bodyHash != bodyHash {
+			return nil, NewAppError("Idempotency-Key was already used with a different request body", fiber.StatusConflict, "IDEMPOTENCY_KEY_REUSED")
+		}
+		if !entry.done {
+			return nil, NewAppError("A request with this Idempotency-Key is still being processed", fiber.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS")
+		}
+		return entry, nil
+	}
+
+	s.entries[scope] = &idempotentResponse{bodyHash: bodyHash, expiresAt: now.Add(s.ttl)}
+	return nil, nil
+}
+
+// complete сохраняет успешный ответ для ключа scope на время ttl.
+func (s *idempotencyStore) complete(scope string, resp *idempotentResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp.done = true
+	resp.expiresAt = time.Now().Add(s.ttl)
+	s.entries[scope] = resp
+}
+
+// abort освобождает ключ scope после неуспешного запроса, чтобы его можно было повторить.
+func (s *idempotencyStore) abort(scope string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, scope)
+}
+
+// sweep удаляет истекшие ключи не чаще одного раза в idempotencySweepInterval.
+// Вызывается с захваченной блокировкой.
+func (s *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for scope, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, scope)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// useIdempotencyStore подменяет хранилище ключей идемпотентности пустым на время теста.
+func useIdempotencyStore(t *testing.T, ttl time.Duration) {
+	t.Helper()
+	prev := idempotency
+	t.Cleanup(func() { idempotency = prev })
+	idempotency = &idempotencyStore{ttl: ttl, entries: make(map[string]*idempotentResponse)}
+}
+
+// newIdempotencyApp возвращает приложение с POST /api/v1/items за Idempotency.
+// Обработчик увеличивает calls и отвечает 201 с номером вызова, либо 500, если fail равно true.
+func newIdempotencyApp(calls *atomic.Int32, fail *atomic.Bool, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(ErrorHandlerMiddleware())
+	if handler == nil {
+		handler = func(c *fiber.Ctx) error {
+			n := calls.Add(1)
+			if fail != nil && fail.Load() {
+				return c.Status(fiber.StatusInternalServerError).SendString("failed")
+			}
+			c.Location("/api/v1/items/1")
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": n})
+		}
+	}
+	app.Post("/api/v1/items", Idempotency(), handler)
+	return app
+}
+
+// postIdempotent отправляет POST /api/v1/items с ключом key и телом body.
+func postIdempotent(t *testing.T, app *fiber.App, key, body string) (*idempotencyResult, error) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodPost, "/api/v1/items", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(IdempotencyKeyHeader, key)
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &idempotencyResult{
+		status:   resp.StatusCode,
+		replayed: resp.Header.Get("Idempotent-Replayed"),
+		location: resp.Header.Get(fiber.HeaderLocation),
+		body:     string(data),
+	}, nil
+}
+
+// idempotencyResult содержит поля ответа, которые проверяют тесты идемпотентности.
+type idempotencyResult struct {
+	status   int
+	replayed string
+	location string
+	body     string
+}
+
+// errorCode возвращает код ошибки из тела ErrorResponse.
+func (r *idempotencyResult) errorCode() string {
+	var body ErrorResponse
+	_ = json.Unmarshal([]byte(r.body), &body)
+	return body.Error.Code
+}
+
+func TestIdempotencyReplaysSuccessfulResponse(t *testing.T) {
+	useIdempotencyStore(t, time.Hour)
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls, nil, nil)
+
+	first, err := postIdempotent(t, app, "key-1", `{"title":"a"}`)
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	second, err := postIdempotent(t, app, "key-1", `{"title":"a"}`)
+	if err != nil {
+		t.Fatalf("replayed request: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("handler called %d times, want 1", calls.Load())
+	}
+	if second.status != fiber.StatusCreated || second.body != first.body {
+		t.Errorf("replay = %d %s, want %d %s", second.status, second.body, first.status, first.body)
+	}
+	if second.replayed != "true" || second.location != "/api/v1/items/1" {
+		t.Errorf("replay headers: Idempotent-Replayed = %q, Location = %q", second.replayed, second.location)
+	}
+
+	// Другой ключ означает новый запрос.
+	if _, err := postIdempotent(t, app, "key-2", `{"title":"a"}`); err != nil {
+		t.Fatalf("request with another key: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("handler called %d times after a new key, want 2", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsDifferentBody(t *testing.T) {
+	useIdempotencyStore(t, time.Hour)
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls, nil, nil)
+
+	if _, err := postIdempotent(t, app, "key", `{"title":"a"}`); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	resp, err := postIdempotent(t, app, "key", `{"title":"b"}`)
+	if err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+
+	if resp.status != fiber.StatusConflict || resp.errorCode() != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("response = %d %s, want 409 IDEMPOTENCY_KEY_REUSED", resp.status, resp.errorCode())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler called %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsRequestInFlight(t *testing.T) {
+	useIdempotencyStore(t, time.Hour)
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app := newIdempotencyApp(&calls, nil, func(c *fiber.Ctx) error {
+		calls.Add(1)
+		close(started)
+		<-release
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := postIdempotent(t, app, "key", `{}`)
+		done <- err
+	}()
+	<-started
+
+	resp, err := postIdempotent(t, app, "key", `{}`)
+	close(release)
+	if err != nil {
+		t.Fatalf("concurrent request: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	if resp.status != fiber.StatusConflict || resp.errorCode() != "IDEMPOTENCY_KEY_IN_PROGRESS" {
+		t.Errorf("response = %d %s, want 409 IDEMPOTENCY_KEY_IN_PROGRESS", resp.status, resp.errorCode())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler called %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyReleasesKeyOnFailure(t *testing.T) {
+	useIdempotencyStore(t, time.Hour)
+	var calls atomic.Int32
+	var fail atomic.Bool
+	fail.Store(true)
+	app := newIdempotencyApp(&calls, &fail, nil)
+
+	first, err := postIdempotent(t, app, "key", `{}`)
+	if err != nil {
+		t.Fatalf("failed request: %v", err)
+	}
+	if first.status != fiber.StatusInternalServerError {
+		t.Fatalf("first status = %d, want 500", first.status)
+	}
+
+	fail.Store(false)
+	second, err := postIdempotent(t, app, "key", `{}`)
+	if err != nil {
+		t.Fatalf("retried request: %v", err)
+	}
+
+	if second.status != fiber.StatusCreated || second.replayed != "" {
+		t.Errorf("retry = %d (replayed %q), want fresh 201", second.status, second.replayed)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("handler called %d times, want 2", calls.Load())
+	}
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	const ttl = 50 * time.Millisecond
+	useIdempotencyStore(t, ttl)
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls, nil, nil)
+
+	if _, err := postIdempotent(t, app, "key", `{}`); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	time.Sleep(2 * ttl)
+
+	resp, err := postIdempotent(t, app, "key", `{}`)
+	if err != nil {
+		t.Fatalf("request after expiry: %v", err)
+	}
+
+	if resp.status != fiber.StatusCreated || resp.replayed != "" {
+		t.Errorf("response after expiry = %d (replayed %q), want fresh 201", resp.status, resp.replayed)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("handler called %d times, want 2", calls.Load())
+	}
+}
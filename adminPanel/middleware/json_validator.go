@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/gofiber/fiber/v2"
@@ -53,9 +56,13 @@ func (v *SchemaValidator) loadSchemas() error {
 		"course_schema.json",
 		"course-create.json",
 		"course-update.json",
+		"course-patch.json",
+		"course-move.json",
 		"lesson_schema.json",
 		"lesson-create.json",
+		"lesson-bulk-create.json",
 		"lesson-update.json",
+		"banner-update.json",
 	}
 
 	for _, schemaFile := range schemaFiles {
@@ -102,7 +109,8 @@ func (v *SchemaValidator) GetSchema(schemaName string) (*jsonschema.Schema, erro
 }
 
 // ValidateJSONSchema возвращает промежуточное ПО для валидации тела запроса по JSON-схеме.
-// В случае ошибки валидации возвращает 422 с деталями ошибок.
+// В случае ошибки валидации возвращает 422 с картой нарушений по путям JSON Pointer к полям
+// (например, "/title": "length must be >= 1, but got 0"), см. extractValidationErrors.
 func ValidateJSONSchema(schemaName string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		v := GetValidator()
@@ -124,7 +132,7 @@ func ValidateJSONSchema(schemaName string) fiber.Handler {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    "INVALID_JSON",
-					Message: "Invalid JSON format",
+					Message: LocalizeMessage(c, "INVALID_JSON", "Invalid JSON format"),
 				},
 			})
 		}
@@ -142,7 +150,7 @@ func ValidateJSONSchema(schemaName string) fiber.Handler {
 				Status: "error",
 				Error: response.ErrorDetails{
 					Code:    "VALIDATION_ERROR",
-					Message: "Validation failed",
+					Message: LocalizeMessage(c, "VALIDATION_ERROR", "Validation failed"),
 				},
 				Errors: validationErrors,
 			})
@@ -154,24 +162,58 @@ func ValidateJSONSchema(schemaName string) fiber.Handler {
 	}
 }
 
-// extractValidationErrors извлекает ошибки валидации из ValidationError в карту полей.
+// quotedPropertyPattern выделяет имена свойств в одинарных кавычках из сообщений
+// ключевых слов required и additionalProperties, например "missing properties: 'title'".
+var quotedPropertyPattern = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+
+// extractValidationErrors собирает нарушения схемы из ValidationError в карту
+// "путь JSON Pointer к полю -> сообщение". Учитываются только листовые ошибки: промежуточные
+// лишь сообщают, что не прошла вложенная схема. Отсутствующие и лишние свойства относятся
+// к пути самого свойства, а не объекта; пустой путь означает корень документа.
+// Несколько нарушений одного поля объединяются через "; ".
 func extractValidationErrors(ve *jsonschema.ValidationError) map[string]string {
 	errors := make(map[string]string)
+	collectValidationErrors(ve, errors)
+	return errors
+}
 
-	if ve.InstanceLocation != "" {
-		fieldName := ve.InstanceLocation
-		if fieldName == "" {
-			fieldName = "root"
+// collectValidationErrors рекурсивно добавляет листовые ошибки ve в errors.
+func collectValidationErrors(ve *jsonschema.ValidationError, errors map[string]string) {
+	if len(ve.Causes) > 0 {
+		for _, cause := range ve.Causes {
+			collectValidationErrors(cause, errors)
 		}
-		errors[fieldName] = ve.Message
+		return
 	}
 
-	for _, cause := range ve.Causes {
-		fieldErrors := extractValidationErrors(cause)
-		for field, msg := range fieldErrors {
-			errors[field] = msg
+	var message string
+	switch path.Base(ve.KeywordLocation) {
+	case "required":
+		message = "missing property"
+	case "additionalProperties":
+		message = "property is not allowed"
+	}
+	if message != "" {
+		if names := quotedPropertyPattern.FindAllStringSubmatch(ve.Message, -1); len(names) > 0 {
+			for _, name := range names {
+				addValidationError(errors, ve.InstanceLocation+"/"+pointerToken(strings.ReplaceAll(name[1], `\'`, `'`)), message)
+			}
+			return
 		}
 	}
 
-	return errors
+	addValidationError(errors, ve.InstanceLocation, ve.Message)
+}
+
+// addValidationError добавляет сообщение к полю, сохраняя уже найденные нарушения.
+func addValidationError(errors map[string]string, field, message string) {
+	if existing, ok := errors[field]; ok {
+		message = existing + "; " + message
+	}
+	errors[field] = message
+}
+
+// pointerToken экранирует имя свойства для пути JSON Pointer (RFC 6901).
+func pointerToken(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
 }
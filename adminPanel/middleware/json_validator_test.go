@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"adminPanel/handlers/dto/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestValidateJSONSchemaReportsFieldErrors(t *testing.T) {
+	// Схемы загружаются из docs/schemas относительно корня приложения.
+	t.Chdir("..")
+
+	const categoryID = "6f1f3c2e-8a4b-4c1d-9e2f-3a5b7c9d1e0f"
+	tests := []struct {
+		name   string
+		schema string
+		body   string
+		// errors содержит ожидаемые поля и фрагменты их сообщений.
+		errors map[string]string
+	}{
+		{
+			name:   "missing required fields",
+			schema: "course-create.json",
+			body:   `{}`,
+			errors: map[string]string{"/title": "missing property", "/category_id": "missing property"},
+		},
+		{
+			name:   "empty title",
+			schema: "course-create.json",
+			body:   `{"title": "", "category_id": "` + categoryID + `"}`,
+			errors: map[string]string{"/title": "length must be >= 1"},
+		},
+		{
+			name:   "unknown level and visibility",
+			schema: "course-create.json",
+			body:   `{"title": "Go", "category_id": "` + categoryID + `", "level": "expert", "visibility": "private"}`,
+			errors: map[string]string{"/level": "must be one of", "/visibility": "must be one of"},
+		},
+		{
+			name:   "level and visibility on update",
+			schema: "course-update.json",
+			body:   `{"level": "Easy", "visibility": "private"}`,
+			errors: map[string]string{"/level": "must be one of", "/visibility": "must be one of"},
+		},
+		{
+			name:   "additional properties",
+			schema: "course-create.json",
+			body:   `{"title": "Go", "category_id": "` + categoryID + `", "owner": "me", "a/b": 1}`,
+			errors: map[string]string{"/owner": "property is not allowed", "/a~1b": "property is not allowed"},
+		},
+		{
+			name:   "wrong types",
+			schema: "course-create.json",
+			body:   `{"title": 42, "category_id": "not-a-uuid"}`,
+			errors: map[string]string{"/title": "expected string", "/category_id": "does not match pattern"},
+		},
+		{
+			name:   "nested array item",
+			schema: "lesson-bulk-create.json",
+			body:   `{"lessons": [{"title": "ok"}, {}]}`,
+			errors: map[string]string{"/lessons/1/title": "missing property"},
+		},
+		{
+			name:   "document root",
+			schema: "course-create.json",
+			body:   `[]`,
+			errors: map[string]string{"": "expected object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := validateBody(t, tt.schema, tt.body)
+			if resp.StatusCode != fiber.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want 422", resp.StatusCode)
+			}
+
+			var body response.ValidationErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Error.Code != "VALIDATION_ERROR" {
+				t.Errorf("code = %q, want VALIDATION_ERROR", body.Error.Code)
+			}
+			if len(body.Errors) != len(tt.errors) {
+				t.Errorf("errors = %v, want fields %v", body.Errors, tt.errors)
+			}
+			for field, fragment := range tt.errors {
+				if got, ok := body.Errors[field]; !ok || !strings.Contains(got, fragment) {
+					t.Errorf("errors[%q] = %q (present %v), want it to contain %q", field, got, ok, fragment)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchemaPassesValidBody(t *testing.T) {
+	t.Chdir("..")
+
+	body := `{"title": "Go", "category_id": "6f1f3c2e-8a4b-4c1d-9e2f-3a5b7c9d1e0f", "level": "easy", "visibility": "public"}`
+	if resp := validateBody(t, "course-create.json", body); resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+
+	if resp := validateBody(t, "course-create.json", `{"title":`); resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("invalid JSON status = %d, want 400", resp.StatusCode)
+	}
+}
+
+// validateBody отправляет body в обработчик, защищенный ValidateJSONSchema(schema).
+func validateBody(t *testing.T, schema, body string) *http.Response {
+	t.Helper()
+
+	app := fiber.New()
+	app.Post("/", ValidateJSONSchema(schema), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	return resp
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// prometheusMetrics содержит собственный реестр Prometheus и метрики, отдаваемые на /metrics.
+// Реестр не связан с конвейером OpenTelemetry: метрики OTLP записываются независимо от него.
+type prometheusMetrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	duration        *prometheus.HistogramVec
+	s3Uploads       *prometheus.CounterVec
+	s3UploadedBytes *prometheus.CounterVec
+}
+
+// promMetrics метрики Prometheus; nil, пока InitPrometheus не вызвана (METRICS_ENABLED=false).
+var promMetrics *prometheusMetrics
+
+// InitPrometheus включает метрики Prometheus: счетчик и гистограмму длительности HTTP-запросов,
+// статистику пула соединений с БД, счетчики загрузок в S3, а также стандартные метрики Go и процесса.
+// Должна вызываться до обработки запросов; pool может быть nil, тогда статистика пула не отдается.
+func InitPrometheus(pool *pgxpool.Pool) error {
+	m := &prometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of handled HTTP requests.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		s3Uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "s3_uploads_total",
+			Help: "Number of objects uploaded to S3.",
+		}, []string{"bucket", "kind"}),
+		s3UploadedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "s3_upload_bytes_total",
+			Help: "Bytes uploaded to S3.",
+		}, []string{"bucket", "kind"}),
+	}
+
+	cs := []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.s3Uploads,
+		m.s3UploadedBytes,
+	}
+	if pool != nil {
+		cs = append(cs, dbPoolCollectors(pool)...)
+	}
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	promMetrics = m
+	return nil
+}
+
+// dbPoolCollectors возвращает метрики состояния пула соединений pgxpool, вычисляемые при каждом сборе.
+func dbPoolCollectors(pool *pgxpool.Pool) []prometheus.Collector {
+	gauge := func(name, help string, value func(s *pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return value(pool.Stat())
+		})
+	}
+	counter := func(name, help string, value func(s *pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return value(pool.Stat())
+		})
+	}
+
+	return []prometheus.Collector{
+		gauge("db_pool_max_connections", "Maximum size of the database connection pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }),
+		gauge("db_pool_total_connections", "Total number of connections in the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }),
+		gauge("db_pool_acquired_connections", "Number of connections currently in use.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }),
+		gauge("db_pool_idle_connections", "Number of idle connections in the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }),
+		counter("db_pool_acquires_total", "Number of successful connection acquires from the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }),
+		counter("db_pool_empty_acquires_total", "Number of acquires that had to wait for a free connection.",
+			func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }),
+		counter("db_pool_acquire_wait_seconds_total", "Total time spent waiting for a connection.",
+			func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }),
+	}
+}
+
+// PrometheusMiddleware возвращает промежуточное ПО, записывающее число и длительность HTTP-запросов
+// с метками метода, шаблона маршрута и статуса. Без InitPrometheus запрос передается дальше без записи.
+func PrometheusMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if promMetrics == nil {
+			return c.Next()
+		}
+
+		startTime := time.Now()
+		err := c.Next()
+
+		// Ошибки, не обработанные ниже по цепочке, оформляются FiberErrorHandler позже,
+		// поэтому их статус определяется по самой ошибке.
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+		labels := prometheus.Labels{
+			"method": c.Method(),
+			"route":  c.Route().Path,
+			"status": strconv.Itoa(status),
+		}
+		promMetrics.requests.With(labels).Inc()
+		promMetrics.duration.With(labels).Observe(time.Since(startTime).Seconds())
+
+		return err
+	}
+}
+
+// PrometheusHandler возвращает обработчик, отдающий метрики в текстовом формате Prometheus.
+// Если метрики не включены, отвечает 404.
+func PrometheusHandler() fiber.Handler {
+	if promMetrics == nil {
+		return func(c *fiber.Ctx) error {
+			return fiber.ErrNotFound
+		}
+	}
+	return adaptor.HTTPHandler(promhttp.HandlerFor(promMetrics.registry, promhttp.HandlerOpts{}))
+}
+
+// RecordS3Upload учитывает загрузку объекта размером size байт в bucket в метриках Prometheus.
+// kind различает исходные изображения и миниатюры. Без InitPrometheus ничего не делает.
+func RecordS3Upload(bucket, kind string, size int64) {
+	if promMetrics == nil {
+		return
+	}
+	promMetrics.s3Uploads.WithLabelValues(bucket, kind).Inc()
+	promMetrics.s3UploadedBytes.WithLabelValues(bucket, kind).Add(float64(size))
+}
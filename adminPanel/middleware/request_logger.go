@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderRequestID заголовок с идентификатором запроса. Значение из запроса используется как есть,
+// если оно корректно, иначе генерируется новый UUID; итоговый ID возвращается в ответе.
+const HeaderRequestID = "X-Request-ID"
+
+// requestIDPattern ограничивает принимаемые от клиента ID запроса, чтобы в лог не попадали
+// переводы строк и произвольно длинные значения.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// loggerContextKey ключ логгера запроса в context.Context.
+type loggerContextKey struct{}
+
+// NewLogger создает структурированный JSON-логгер с минимальным уровнем level
+// (debug, info, warn или error; неизвестное значение считается info).
+func NewLogger(level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl}))
+}
+
+// WithLogger возвращает копию ctx с логгером logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFromContext возвращает логгер запроса, сохраненный RequestLogger, или slog.Default(),
+// если контекст создан вне обработки запроса.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// RequestLogger возвращает промежуточное ПО, которое сохраняет в контексте запроса логгер base,
+// дополненный request_id, method и path, а также trace_id и span_id текущего span.
+// Должно подключаться после промежуточного ПО трассировки, чтобы span уже был в контексте.
+// ID запроса берется из заголовка X-Request-ID или генерируется и возвращается в том же заголовке.
+func RequestLogger(base *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(HeaderRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(HeaderRequestID, requestID)
+
+		ctx := c.UserContext()
+		attrs := []any{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			attrs = append(attrs,
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+
+		c.SetUserContext(WithLogger(ctx, base.With(attrs...)))
+		return c.Next()
+	}
+}
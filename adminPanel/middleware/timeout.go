@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteTimeouts содержит переопределения общего таймаута запроса для отдельных групп маршрутов:
+// списков (короче общего), загрузки изображений и выгрузки/импорта категорий (длиннее общего).
+type RouteTimeouts struct {
+	List   time.Duration
+	Upload time.Duration
+	Export time.Duration
+}
+
+// routeTimeouts переопределения таймаута, применяемые ListTimeout, UploadTimeout и ExportTimeout.
+// Задается через SetRouteTimeouts; нулевое значение отключает соответствующее переопределение.
+var routeTimeouts RouteTimeouts
+
+// SetRouteTimeouts задает переопределения таймаута для групп маршрутов
+// (настройки LIST_REQUEST_TIMEOUT, UPLOAD_REQUEST_TIMEOUT и EXPORT_REQUEST_TIMEOUT).
+// Должна вызываться до обработки запросов.
+func SetRouteTimeouts(timeouts RouteTimeouts) {
+	routeTimeouts = timeouts
+}
+
+// RequestTimeout возвращает промежуточное ПО, ограничивающее время обработки запроса значением d.
+// Контекст с дедлайном передается дальше через c.UserContext(). Повторное применение
+// (например, к группе маршрутов поверх общего таймаута) заменяет дедлайн, а не сужает его,
+// поэтому переопределение может быть как короче, так и длиннее общего значения.
+// Если дедлайн истек и обработчик завершился ошибкой, ответ заменяется на 504 REQUEST_TIMEOUT.
+// При d <= 0 запрос передается дальше без ограничения.
+func RequestTimeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if d <= 0 {
+			return c.Next()
+		}
+
+		parent := c.UserContext()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), d)
+		defer cancel()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+		c.SetUserContext(parent)
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) &&
+			(err != nil || c.Response().StatusCode() >= fiber.StatusInternalServerError) {
+			return NewAppError(fmt.Sprintf("Request timed out after %s", d), fiber.StatusGatewayTimeout, "REQUEST_TIMEOUT")
+		}
+		return err
+	}
+}
+
+// ListTimeout ограничивает время обработки запросов списков значением RouteTimeouts.List.
+func ListTimeout() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return RequestTimeout(routeTimeouts.List)(c)
+	}
+}
+
+// UploadTimeout ограничивает время обработки загрузки изображений значением RouteTimeouts.Upload.
+func UploadTimeout() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return RequestTimeout(routeTimeouts.Upload)(c)
+	}
+}
+
+// ExportTimeout ограничивает время выгрузки и импорта категорий значением RouteTimeouts.Export.
+func ExportTimeout() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return RequestTimeout(routeTimeouts.Export)(c)
+	}
+}
+
+// DetachTimeout возвращает контекст с тем же дедлайном, что и ctx, который не отменяется
+// по завершении обработчика. Используется для работы, продолжающейся после возврата из
+// обработчика (потоковая запись ответа): RequestTimeout отменяет контекст запроса при возврате.
+func DetachTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithCancel(detached)
+}
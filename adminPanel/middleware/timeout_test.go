@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRouteTimeoutOverridesGlobalDeadline(t *testing.T) {
+	prev := routeTimeouts
+	t.Cleanup(func() { SetRouteTimeouts(prev) })
+	SetRouteTimeouts(RouteTimeouts{List: 5 * time.Second, Upload: 2 * time.Minute, Export: 5 * time.Minute})
+
+	app := fiber.New()
+	app.Use(RequestTimeout(30 * time.Second))
+	remaining := func(c *fiber.Ctx) error {
+		deadline, ok := c.UserContext().Deadline()
+		if !ok {
+			return c.SendString("none")
+		}
+		return c.SendString(time.Until(deadline).Round(time.Second).String())
+	}
+	app.Get("/default", remaining)
+	app.Get("/list", ListTimeout(), remaining)
+	app.Post("/upload", UploadTimeout(), remaining)
+	app.Get("/export", ExportTimeout(), remaining)
+
+	tests := []struct {
+		method string
+		path   string
+		want   time.Duration
+	}{
+		{method: fiber.MethodGet, path: "/default", want: 30 * time.Second},
+		{method: fiber.MethodGet, path: "/list", want: 5 * time.Second},
+		{method: fiber.MethodPost, path: "/upload", want: 2 * time.Minute},
+		{method: fiber.MethodGet, path: "/export", want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			body := make([]byte, 32)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != tt.want.String() {
+				t.Errorf("deadline seen by handler in %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutReturnsGatewayTimeout(t *testing.T) {
+	prev := routeTimeouts
+	t.Cleanup(func() { SetRouteTimeouts(prev) })
+	SetRouteTimeouts(RouteTimeouts{List: 20 * time.Millisecond})
+
+	app := fiber.New()
+	app.Use(ErrorHandlerMiddleware())
+	app.Use(RequestTimeout(30 * time.Second))
+	app.Get("/api/v1/slow", ListTimeout(), func(c *fiber.Ctx) error {
+		<-c.UserContext().Done()
+		return c.UserContext().Err()
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/slow", nil), 5000)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusGatewayTimeout || body.Error.Code != "REQUEST_TIMEOUT" {
+		t.Errorf("response = %d %s, want 504 REQUEST_TIMEOUT", resp.StatusCode, body.Error.Code)
+	}
+}
+
+func TestDetachTimeoutOutlivesHandler(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestTimeout(time.Minute))
+
+	var requestCtx, detached context.Context
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		requestCtx = c.UserContext()
+		var cancel context.CancelFunc
+		detached, cancel = DetachTimeout(requestCtx)
+		t.Cleanup(cancel)
+		return nil
+	})
+
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/stream", nil)); err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if requestCtx.Err() == nil {
+		t.Error("request context is not canceled after the handler returned")
+	}
+	if err := detached.Err(); err != nil {
+		t.Errorf("detached context error = %v, want nil", err)
+	}
+	want, _ := requestCtx.Deadline()
+	if got, ok := detached.Deadline(); !ok || !got.Equal(want) {
+		t.Errorf("detached deadline = %v, %v; want %v", got, ok, want)
+	}
+}
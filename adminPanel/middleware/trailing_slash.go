@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NormalizeTrailingSlash возвращает промежуточное ПО, удаляющее завершающий слэш из пути запроса.
+// Соглашение маршрутизации: канонический путь не содержит завершающего слэша
+// (/categories/:category_id/courses, а не /categories/:category_id/courses/).
+// Путь переписывается до сопоставления маршрутов, поэтому обе формы попадают в один обработчик,
+// а последующие промежуточные обработчики (аутентификация, логирование, трассировка) видят канонический путь.
+// Должно регистрироваться первым.
+func NormalizeTrailingSlash() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if len(path) > 1 && strings.HasSuffix(path, "/") {
+			trimmed := strings.TrimRight(path, "/")
+			if trimmed == "" {
+				trimmed = "/"
+			}
+			c.Path(trimmed)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Уровни важности баннера сайта.
+const (
+	BannerSeverityInfo     = "info"
+	BannerSeverityWarning  = "warning"
+	BannerSeverityCritical = "critical"
+)
+
+// SiteBanner представляет общее для всего публичного сайта уведомление (например, о плановых работах).
+// Баннер показывается только при Active = true.
+type SiteBanner struct {
+	Message   string     `json:"message"`
+	Severity  string     `json:"severity"`
+	Active    bool       `json:"active"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -13,12 +13,23 @@ type BaseModel struct {
 }
 
 // Pagination содержит информацию о пагинации для списков.
-// Включает общее количество элементов, текущую страницу, лимит и общее количество страниц.
+// Включает общее количество элементов, текущую страницу, лимит и общее количество страниц,
+// а также ссылки навигации, если их заполнил обработчик.
 type Pagination struct {
-	Total int `json:"total"`
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
-	Pages int `json:"pages"`
+	Total int              `json:"total"`
+	Page  int              `json:"page"`
+	Limit int              `json:"limit"`
+	Pages int              `json:"pages"`
+	Links *PaginationLinks `json:"links,omitempty"`
+}
+
+// PaginationLinks содержит готовые ссылки (внешний путь с ROOT_PATH и query текущего запроса) на соседние,
+// первую и последнюю страницы. Next равен null на последней странице, Prev - на первой.
+type PaginationLinks struct {
+	First string  `json:"first"`
+	Last  string  `json:"last"`
+	Next  *string `json:"next"`
+	Prev  *string `json:"prev"`
 }
 
 // QueryList представляет параметры запроса для получения списков.
@@ -1,8 +1,9 @@
 package models
 
 // Category представляет категорию в системе.
-// Встраивает BaseModel и содержит поле Title для названия категории.
+// Встраивает BaseModel и содержит поля Title для названия категории и Slug для человекочитаемого URL.
 type Category struct {
 	BaseModel
 	Title string `json:"title"`
+	Slug  string `json:"slug"`
 }
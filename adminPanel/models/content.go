@@ -9,8 +9,20 @@ import (
 const (
 	ContentTypeText  = "text"
 	ContentTypeImage = "image"
+	ContentTypeCode  = "code"
+	ContentTypeVideo = "video"
 )
 
+// KnownContentTypes перечисляет допустимые типы блоков структурированного контента урока.
+var KnownContentTypes = []string{ContentTypeText, ContentTypeImage, ContentTypeCode, ContentTypeVideo}
+
+// ContentBlock представляет блок структурированного контента урока: тип и данные блока.
+// Используется для проверки контента, сохраняемого как JSON-массив блоков.
+type ContentBlock struct {
+	ContentType string          `json:"content_type"`
+	Data        json.RawMessage `json:"data"`
+}
+
 // Content интерфейс для различных типов контента.
 // Определяет метод Type(), возвращающий тип контента.
 type Content interface {
@@ -1,14 +1,29 @@
 package models
 
+import "time"
+
+// Политики разметки HTML-контента уроков курса: strict допускает только базовое форматирование,
+// relaxed дополнительно разрешает классы, data-атрибуты и ряд элементов.
+// Политика применяется при отображении уроков на публичной стороне.
+const (
+	ContentPolicyStrict  = "strict"
+	ContentPolicyRelaxed = "relaxed"
+)
+
 // Course представляет курс в системе.
-// Встраивает BaseModel и содержит поля для заголовка, описания, уровня сложности,
-// ID категории, видимости и ключа изображения.
+// Встраивает BaseModel и содержит поля для заголовка, slug, описания, уровня сложности,
+// ID категории, видимости, ключа изображения, политики разметки контента и времени мягкого удаления.
+// CategoryTitle заполняется только в общем списке курсов всех категорий.
 type Course struct {
 	BaseModel
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Level       string `json:"level"`
-	CategoryID  string `json:"category_id"`
-	Visibility  string `json:"visibility"`
-	ImageKey    string `json:"image_key"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Description   string     `json:"description"`
+	Level         string     `json:"level"`
+	CategoryID    string     `json:"category_id"`
+	CategoryTitle string     `json:"category_title,omitempty"`
+	Visibility    string     `json:"visibility"`
+	ImageKey      string     `json:"image_key"`
+	ContentPolicy string     `json:"content_policy"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
 }
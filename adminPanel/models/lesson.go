@@ -1,10 +1,19 @@
 package models
 
 // Lesson представляет урок в системе.
-// Встраивает BaseModel и содержит поля для заголовка, ID курса и контента урока.
+// Встраивает BaseModel и содержит поля для заголовка, slug, ID курса, контента урока и его позиции в курсе.
 type Lesson struct {
 	BaseModel
-	Title    string `json:"title"`
-	CourseID string `json:"course_id"`
-	Content  string `json:"content"`
+	Title      string `json:"title"`
+	Slug       string `json:"slug"`
+	CourseID   string `json:"course_id"`
+	Content    string `json:"content"`
+	OrderIndex int    `json:"order_index"`
+}
+
+// LessonOutline представляет урок в оглавлении курса: заголовок и позиция без контента.
+type LessonOutline struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	OrderIndex int    `json:"order_index"`
 }
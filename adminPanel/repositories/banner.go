@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+
+	"adminPanel/database"
+)
+
+// BannerRepository предоставляет методы для работы с баннером сайта.
+// Таблица site_banner содержит не более одной строки.
+type BannerRepository struct {
+	db *database.Database
+}
+
+// NewBannerRepository создает новый экземпляр BannerRepository.
+func NewBannerRepository(db *database.Database) *BannerRepository {
+	return &BannerRepository{db: db}
+}
+
+// Get получает баннер сайта.
+// Возвращает nil, если баннер не задан.
+func (r *BannerRepository) Get(ctx context.Context) (map[string]interface{}, error) {
+	query := "SELECT message, severity, active, updated_at FROM knowledge_base.site_banner WHERE id = 1"
+	return r.db.FetchOne(ctx, query)
+}
+
+// Upsert создает или заменяет баннер сайта и возвращает сохраненную запись.
+func (r *BannerRepository) Upsert(ctx context.Context, message, severity string, active bool) (map[string]interface{}, error) {
+	query := `
+		INSERT INTO knowledge_base.site_banner (id, message, severity, active, updated_at)
+		VALUES (1, $1, $2, $3, NOW())
+		ON CONFLICT (id) DO UPDATE
+		SET message = EXCLUDED.message,
+			severity = EXCLUDED.severity,
+			active = EXCLUDED.active,
+			updated_at = EXCLUDED.updated_at
+		RETURNING message, severity, active, updated_at
+	`
+	return r.db.ExecuteReturning(ctx, query, message, severity, active)
+}
+
+// Delete удаляет баннер сайта. Удаление отсутствующего баннера не является ошибкой.
+func (r *BannerRepository) Delete(ctx context.Context) error {
+	_, err := r.db.Execute(ctx, "DELETE FROM knowledge_base.site_banner WHERE id = 1")
+	return err
+}
@@ -125,3 +125,22 @@ func (r *BaseRepository) GetFiltered(ctx context.Context, conditions []string, p
 
 	return r.db.FetchAll(ctx, query, params...)
 }
+
+// likeEscaper экранирует символы, имеющие особый смысл в шаблонах LIKE/ILIKE.
+// Обратная косая черта заменяется первой, чтобы не экранировать уже добавленные escape-символы.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike экранирует `%`, `_` и `\` в пользовательском вводе, чтобы он сравнивался буквально.
+// Результат используется только вместе с `ESCAPE '\'` в запросе.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// ilikeContains строит условие поиска подстроки без учета регистра для колонки column.
+// Возвращает условие с плейсхолдером $paramIndex и значение параметра, в котором ввод экранирован,
+// так что поиск "100%" находит строку "100%", а не все строки, начинающиеся с "100".
+// Все репозиторные методы, фильтрующие через ILIKE, должны использовать этот хелпер.
+func ilikeContains(column string, paramIndex int, value string) (string, string) {
+	condition := fmt.Sprintf(`%s ILIKE $%d ESCAPE '\'`, column, paramIndex)
+	return condition, "%" + escapeLike(value) + "%"
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"adminPanel/testutil"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"plain":      "plain",
+		"100%":       `100\%`,
+		"snake_case": `snake\_case`,
+		`C:\path`:    `C:\\path`,
+		`\%_`:        `\\\%\_`,
+	}
+	for input, want := range tests {
+		if got := escapeLike(input); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestILikeContains(t *testing.T) {
+	condition, param := ilikeContains("title", 3, "100%")
+	if want := `title ILIKE $3 ESCAPE '\'`; condition != want {
+		t.Errorf("condition = %q, want %q", condition, want)
+	}
+	if want := `%100\%%`; param != want {
+		t.Errorf("param = %q, want %q", param, want)
+	}
+}
+
+func TestILikeContainsMatchesLiterally(t *testing.T) {
+	db := testutil.NewDB(t)
+
+	titles := []string{"100% Go", "1000 tips", "snake_case", "snakeXcase", `C:\path`}
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{search: "100%", want: []string{"100% Go"}},
+		{search: "e_c", want: []string{"snake_case"}},
+		{search: `:\p`, want: []string{`C:\path`}},
+		{search: "%", want: []string{"100% Go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			condition, param := ilikeContains("title", 2, tt.search)
+			rows, err := db.Pool.Query(context.Background(),
+				"SELECT title FROM unnest($1::text[]) AS t(title) WHERE "+condition+" ORDER BY title", titles, param)
+			if err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			defer rows.Close()
+
+			var got []string
+			for rows.Next() {
+				var title string
+				if err := rows.Scan(&title); err != nil {
+					t.Fatalf("scan error = %v", err)
+				}
+				got = append(got, title)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ILIKE %q matched %v, want %v", tt.search, got, tt.want)
+			}
+		})
+	}
+}
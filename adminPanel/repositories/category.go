@@ -20,17 +20,17 @@ func NewCategoryRepository(db *database.Database) *CategoryRepository {
 	}
 }
 
-// Create создает новую категорию с заданным заголовком.
+// Create создает новую категорию с заданными заголовком и slug.
 // Генерирует UUID и устанавливает время создания и обновления.
 // Возвращает созданную категорию.
-func (r *CategoryRepository) Create(ctx context.Context, title string) (map[string]interface{}, error) {
+func (r *CategoryRepository) Create(ctx context.Context, title, slug string) (map[string]interface{}, error) {
 	query := `
 		INSERT INTO knowledge_base.category_d 
-		(id, title, created_at, updated_at)
-		VALUES (gen_random_uuid(), $1, NOW(), NOW())
+		(id, title, slug, created_at, updated_at)
+		VALUES (gen_random_uuid(), $1, $2, NOW(), NOW())
 		RETURNING *
 	`
-	return r.db.ExecuteReturning(ctx, query, title)
+	return r.db.ExecuteReturning(ctx, query, title, slug)
 }
 
 // Update обновляет заголовок категории по ID.
@@ -79,9 +79,57 @@ func (r *CategoryRepository) GetAllWithCourses(ctx context.Context) ([]map[strin
 			c.*,
 			COUNT(cb.id) as course_count
 		FROM knowledge_base.category_d c
-		LEFT JOIN knowledge_base.course_b cb ON c.id = cb.category_id
+		LEFT JOIN knowledge_base.course_b cb ON c.id = cb.category_id AND cb.deleted_at IS NULL
 		GROUP BY c.id
 		ORDER BY c.title
 	`
 	return r.db.FetchAll(ctx, query)
 }
+
+// GetEmpty получает категории, в которых нет ни одного курса (включая мягко удаленные, которые
+// так же, как и в CountCoursesForCategory, препятствуют удалению категории).
+// Результат отсортирован по времени создания: сначала самые старые.
+func (r *CategoryRepository) GetEmpty(ctx context.Context) ([]map[string]interface{}, error) {
+	query := `
+		SELECT c.*
+		FROM knowledge_base.category_d c
+		LEFT JOIN knowledge_base.course_b cb ON c.id = cb.category_id
+		WHERE cb.id IS NULL
+		ORDER BY c.created_at, c.title
+	`
+	return r.db.FetchAll(ctx, query)
+}
+
+// SlugExists проверяет, занят ли slug другой категорией.
+// Категория с ID excludeID (если задан) не учитывается, что позволяет проверять slug при редактировании.
+func (r *CategoryRepository) SlugExists(ctx context.Context, slug, excludeID string) (bool, error) {
+	query := `
+		SELECT 1 FROM knowledge_base.category_d
+		WHERE slug = $1 AND ($2 = '' OR id::text <> $2)
+		LIMIT 1
+	`
+	result, err := r.db.FetchOne(ctx, query, slug, excludeID)
+	if err != nil {
+		return false, err
+	}
+	return result != nil, nil
+}
+
+// GetStats получает все категории с количеством курсов и общим количеством уроков в них.
+// Категории без курсов возвращаются с нулевыми значениями.
+// Результат отсортирован по количеству курсов по убыванию.
+func (r *CategoryRepository) GetStats(ctx context.Context) ([]map[string]interface{}, error) {
+	query := `
+		SELECT
+			c.id,
+			c.title,
+			COUNT(DISTINCT cb.id) as course_count,
+			COUNT(l.id) as lesson_count
+		FROM knowledge_base.category_d c
+		LEFT JOIN knowledge_base.course_b cb ON c.id = cb.category_id AND cb.deleted_at IS NULL
+		LEFT JOIN knowledge_base.lesson_d l ON cb.id = l.course_id
+		GROUP BY c.id, c.title
+		ORDER BY course_count DESC, c.title
+	`
+	return r.db.FetchAll(ctx, query)
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"adminPanel/database"
+)
+
+// ChangeEventRepository отправляет уведомления об изменениях контента через PostgreSQL NOTIFY.
+type ChangeEventRepository struct {
+	db *database.Database
+}
+
+// NewChangeEventRepository создает новый экземпляр ChangeEventRepository.
+func NewChangeEventRepository(db *database.Database) *ChangeEventRepository {
+	return &ChangeEventRepository{db: db}
+}
+
+// Notify отправляет payload подписчикам канала channel (LISTEN channel).
+// Уведомление доставляется всем соединениям, подписанным на канал в момент отправки.
+func (r *ChangeEventRepository) Notify(ctx context.Context, channel, payload string) error {
+	_, err := r.db.Execute(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+)
+
+// gzipMagic первые байты gzip-потока, по которым сжатый контент отличается от обычного.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// contentCodec кодирует контент урока перед записью в БД и декодирует его при чтении.
+// При включенном сжатии контент размером не меньше minSize сжимается gzip;
+// при чтении сжатый и обычный контент различаются по magic bytes, поэтому
+// записи, сохраненные до включения сжатия, читаются без изменений.
+type contentCodec struct {
+	compress bool
+	minSize  int
+	debug    bool
+}
+
+// encode подготавливает контент к записи. Возвращает сжатые данные, если сжатие включено
+// и действительно уменьшает размер, иначе - исходный текст в UTF-8.
+func (c contentCodec) encode(content string) ([]byte, error) {
+	plain := []byte(content)
+	if !c.compress || len(plain) < c.minSize {
+		return plain, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(plain); err != nil {
+		return nil, fmt.Errorf("failed to compress content: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress content: %w", err)
+	}
+
+	if c.debug {
+		log.Printf("🗜️  Lesson content compressed: %d -> %d bytes (ratio %.2f)",
+			len(plain), buf.Len(), float64(buf.Len())/float64(len(plain)))
+	}
+
+	if buf.Len() >= len(plain) {
+		return plain, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// decode восстанавливает текст контента из данных, прочитанных из БД.
+// NULL возвращается как пустая строка.
+func (c contentCodec) decode(raw []byte) (string, error) {
+	if !bytes.HasPrefix(raw, gzipMagic) {
+		return string(raw), nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress content: %w", err)
+	}
+	defer zr.Close()
+
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress content: %w", err)
+	}
+	return string(plain), nil
+}
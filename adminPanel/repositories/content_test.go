@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestContentCodecRoundTrip(t *testing.T) {
+	contents := map[string]string{
+		"empty":  "",
+		"blocks": `[{"type":"text","data":{"text":"<b>bold</b>\r\n  spaced"}}]`,
+		"large":  strings.Repeat("<p>Урок &amp; пример</p>\n", 200),
+	}
+	codecs := map[string]contentCodec{
+		"plain":      {},
+		"compressed": {compress: true, minSize: 1},
+	}
+
+	for codecName, codec := range codecs {
+		for name, content := range contents {
+			t.Run(codecName+"/"+name, func(t *testing.T) {
+				encoded, err := codec.encode(content)
+				if err != nil {
+					t.Fatalf("encode() error = %v", err)
+				}
+				decoded, err := codec.decode(encoded)
+				if err != nil {
+					t.Fatalf("decode() error = %v", err)
+				}
+				if decoded != content {
+					t.Errorf("decode(encode(x)) = %q, want %q", decoded, content)
+				}
+			})
+		}
+	}
+
+	encoded, _ := codecs["compressed"].encode(contents["large"])
+	if !bytes.HasPrefix(encoded, gzipMagic) || len(encoded) >= len(contents["large"]) {
+		t.Errorf("encode() did not compress large content (%d bytes)", len(encoded))
+	}
+}
@@ -2,11 +2,15 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"adminPanel/database"
 	"adminPanel/handlers/dto/request"
+
+	"github.com/jackc/pgx/v5"
 )
 
 // CourseRepository предоставляет методы для работы с курсами.
@@ -23,30 +27,35 @@ func NewCourseRepository(db *database.Database) *CourseRepository {
 	}
 }
 
-// Create создает новый курс на основе данных из request.CourseCreate.
+// Create создает новый курс на основе данных из request.CourseCreate и заданного slug.
 // Генерирует UUID и устанавливает время создания и обновления.
 // Возвращает созданный курс.
-func (r *CourseRepository) Create(ctx context.Context, course request.CourseCreate) (map[string]interface{}, error) {
+func (r *CourseRepository) Create(ctx context.Context, course request.CourseCreate, slug string) (map[string]interface{}, error) {
 	query := `
 		INSERT INTO knowledge_base.course_b 
-		(id, title, description, level, category_id, visibility, image_key, created_at, updated_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, NOW(), NOW())
+		(id, title, slug, description, level, category_id, visibility, image_key, content_policy, created_at, updated_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 		RETURNING *
 	`
 
 	return r.db.ExecuteReturning(ctx, query,
 		course.Title,
+		slug,
 		course.Description,
 		course.Level,
 		course.CategoryID,
 		course.Visibility,
 		course.ImageKey,
+		course.ContentPolicy,
 	)
 }
 
 // Update обновляет курс по ID на основе данных из request.CourseUpdate.
-// Использует COALESCE для обновления только переданных полей.
-// Возвращает обновленный курс.
+// Использует COALESCE для обновления только переданных полей. Пустой image_key сохраняет
+// текущее изображение: удалить его можно только частичным обновлением (Patch).
+// Если задан course.Version, курс обновляется, только пока updated_at равен ему, а если задан
+// course.UnmodifiedSince - только если курс не изменялся позже этого момента (с точностью до секунды).
+// Возвращает обновленный курс или nil, если курс не найден или условие не выполнено.
 func (r *CourseRepository) Update(ctx context.Context, id string, course request.CourseUpdate) (map[string]interface{}, error) {
 	query := `
 		UPDATE knowledge_base.course_b 
@@ -55,9 +64,12 @@ func (r *CourseRepository) Update(ctx context.Context, id string, course request
 			level = COALESCE($3, level),
 			category_id = COALESCE($4, category_id),
 			visibility = COALESCE($5, visibility),
-			image_key = COALESCE($6, image_key),
+			image_key = COALESCE(NULLIF($6, ''), image_key),
+			content_policy = COALESCE(NULLIF($8, ''), content_policy),
 			updated_at = NOW()
 		WHERE id = $7
+			AND ($9::timestamp IS NULL OR updated_at = $9::timestamp)
+			AND ($10::timestamp IS NULL OR date_trunc('second', updated_at) <= $10::timestamp)
 		RETURNING *
 	`
 
@@ -69,19 +81,73 @@ func (r *CourseRepository) Update(ctx context.Context, id string, course request
 		course.Visibility,
 		course.ImageKey,
 		id,
+		course.ContentPolicy,
+		utcTime(course.Version),
+		utcTime(course.UnmodifiedSince),
 	)
 }
 
-// GetFiltered получает курсы с фильтрами из request.CourseFilter.
-// Возвращает список курсов, общее количество и ошибку.
-func (r *CourseRepository) GetFiltered(ctx context.Context, filter request.CourseFilter) ([]map[string]interface{}, int, error) {
+// utcTime переводит время в UTC: колонки TIMESTAMP хранят время UTC без часового пояса,
+// а pgx передает в них время по местным часам значения. nil остается nil.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// Patch частично обновляет курс по ID на основе request.CoursePatch.
+// Записываются только переданные поля: nil передается в запрос как NULL и сохраняет текущее значение
+// через COALESCE. Пустой image_key очищает изображение (NULLIF), пустое описание сохраняется как есть.
+// Возвращает обновленный курс или nil, если курс не найден.
+func (r *CourseRepository) Patch(ctx context.Context, id string, patch request.CoursePatch) (map[string]interface{}, error) {
+	query := `
+		UPDATE knowledge_base.course_b
+		SET title = COALESCE($2, title),
+			description = COALESCE($3, description),
+			level = COALESCE($4, level),
+			visibility = COALESCE($5, visibility),
+			image_key = CASE WHEN $6::text IS NULL THEN image_key ELSE NULLIF($6::text, '') END,
+			content_policy = COALESCE($7, content_policy),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING *
+	`
+
+	return r.db.ExecuteReturning(ctx, query,
+		id,
+		patch.Title,
+		patch.Description,
+		patch.Level,
+		patch.Visibility,
+		patch.ImageKey,
+		patch.ContentPolicy,
+	)
+}
+
+// Move переносит не удаленный курс из категории fromCategoryID в toCategoryID с заданным slug.
+// Возвращает перенесенный курс или nil, если курс не найден в исходной категории.
+func (r *CourseRepository) Move(ctx context.Context, id, fromCategoryID, toCategoryID, slug string) (map[string]interface{}, error) {
+	query := `
+		UPDATE knowledge_base.course_b
+		SET category_id = $3, slug = $4, updated_at = NOW()
+		WHERE id = $1 AND category_id = $2 AND deleted_at IS NULL
+		RETURNING *
+	`
+	return r.db.ExecuteReturning(ctx, query, id, fromCategoryID, toCategoryID, slug)
+}
+
+// filterConditions строит условия WHERE и параметры для фильтров из request.CourseFilter.
+// Возвращает условия, параметры и номер следующего плейсхолдера.
+func (r *CourseRepository) filterConditions(filter request.CourseFilter) ([]string, []interface{}, int) {
 	var conditions []string
 	var params []interface{}
 	paramCounter := 1
 
-	if filter.Level != "" {
-		conditions = append(conditions, fmt.Sprintf("level = $%d", paramCounter))
-		params = append(params, filter.Level)
+	if len(filter.Levels) > 0 {
+		conditions = append(conditions, fmt.Sprintf("level = ANY($%d)", paramCounter))
+		params = append(params, filter.Levels)
 		paramCounter++
 	}
 
@@ -97,6 +163,44 @@ func (r *CourseRepository) GetFiltered(ctx context.Context, filter request.Cours
 		paramCounter++
 	}
 
+	if filter.Title != "" {
+		condition, pattern := ilikeContains("title", paramCounter, filter.Title)
+		conditions = append(conditions, condition)
+		params = append(params, pattern)
+		paramCounter++
+	}
+
+	dateBounds := []struct {
+		condition string
+		value     *time.Time
+	}{
+		{"created_at >= $%d", filter.CreatedAfter},
+		{"created_at < $%d", filter.CreatedBefore},
+		{"updated_at >= $%d", filter.UpdatedAfter},
+		{"updated_at < $%d", filter.UpdatedBefore},
+	}
+	for _, bound := range dateBounds {
+		if bound.value == nil {
+			continue
+		}
+		// Колонки хранят время без часового пояса в UTC, поэтому граница приводится к UTC.
+		conditions = append(conditions, fmt.Sprintf(bound.condition, paramCounter))
+		params = append(params, bound.value.UTC())
+		paramCounter++
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	return conditions, params, paramCounter
+}
+
+// GetFiltered получает курсы с фильтрами из request.CourseFilter.
+// Возвращает список курсов, общее количество и ошибку.
+func (r *CourseRepository) GetFiltered(ctx context.Context, filter request.CourseFilter) ([]map[string]interface{}, int, error) {
+	conditions, params, paramCounter := r.filterConditions(filter)
+
 	countQuery := "SELECT COUNT(*) as count FROM knowledge_base.course_b"
 	if len(conditions) > 0 {
 		countQuery += " WHERE " + strings.Join(conditions, " AND ")
@@ -130,18 +234,330 @@ func (r *CourseRepository) GetFiltered(ctx context.Context, filter request.Cours
 	return data, total, nil
 }
 
-// GetByCategory получает все курсы для заданной категории.
+// courseSearchVector выражение полнотекстового индекса курса по заголовку и описанию.
+// Должно совпадать с выражением индекса idx_course_search.
+const courseSearchVector = "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, ''))"
+
+// Search выполняет полнотекстовый поиск курсов по заголовку и описанию с учетом фильтров.
+// Результаты отсортированы по релевантности (ts_rank), затем по времени создания.
+// Возвращает список курсов, общее количество совпадений и ошибку.
+func (r *CourseRepository) Search(ctx context.Context, search string, filter request.CourseFilter) ([]map[string]interface{}, int, error) {
+	conditions, params, paramCounter := r.filterConditions(filter)
+
+	queryParam := paramCounter
+	conditions = append(conditions, fmt.Sprintf("%s @@ plainto_tsquery('simple', $%d)", courseSearchVector, queryParam))
+	params = append(params, search)
+	paramCounter++
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	countResult, err := r.db.FetchOne(ctx, "SELECT COUNT(*) as count FROM knowledge_base.course_b"+where, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	if count, ok := countResult["count"].(int64); ok {
+		total = int(count)
+	}
+
+	query := "SELECT * FROM knowledge_base.course_b" + where
+	query += fmt.Sprintf(" ORDER BY ts_rank(%s, plainto_tsquery('simple', $%d)) DESC, created_at DESC", courseSearchVector, queryParam)
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramCounter, paramCounter+1)
+
+	params = append(params, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	data, err := r.db.FetchAll(ctx, query, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// GetFilteredWithCategory получает курсы всех категорий с фильтрами из request.CourseFilter.
+// Категория учитывается, только если задан filter.CategoryID. Каждая строка дополняется
+// названием категории (category_title). Если задан search, курсы дополнительно отбираются
+// полнотекстовым поиском и сортируются по релевантности, иначе - по времени создания.
+// Возвращает список курсов, общее количество и ошибку.
+func (r *CourseRepository) GetFilteredWithCategory(ctx context.Context, filter request.CourseFilter, search string) ([]map[string]interface{}, int, error) {
+	conditions, params, paramCounter := r.filterConditions(filter)
+
+	orderBy := "created_at DESC"
+	if search != "" {
+		conditions = append(conditions, fmt.Sprintf("%s @@ plainto_tsquery('simple', $%d)", courseSearchVector, paramCounter))
+		orderBy = fmt.Sprintf("ts_rank(%s, plainto_tsquery('simple', $%d)) DESC, created_at DESC", courseSearchVector, paramCounter)
+		params = append(params, search)
+		paramCounter++
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	countResult, err := r.db.FetchOne(ctx, "SELECT COUNT(*) as count FROM knowledge_base.course_b"+where, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	if count, ok := countResult["count"].(int64); ok {
+		total = int(count)
+	}
+
+	// LATERAL-подзапрос возвращает только category_title, поэтому условия фильтров
+	// без префикса таблицы по-прежнему относятся к колонкам course_b.
+	query := `
+		SELECT course_b.*, category.category_title
+		FROM knowledge_base.course_b
+		JOIN LATERAL (
+			SELECT title AS category_title FROM knowledge_base.category_d WHERE id = course_b.category_id
+		) AS category ON TRUE` + where
+	query += " ORDER BY " + orderBy
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramCounter, paramCounter+1)
+
+	params = append(params, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	data, err := r.db.FetchAll(ctx, query, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// GetByID получает курс по ID, исключая мягко удаленные.
+// Возвращает nil, если курс не найден или удален.
+func (r *CourseRepository) GetByID(ctx context.Context, id string) (map[string]interface{}, error) {
+	query := "SELECT * FROM knowledge_base.course_b WHERE id = $1 AND deleted_at IS NULL"
+	return r.db.FetchOne(ctx, query, id)
+}
+
+// GetByIDIncludingDeleted получает курс по ID независимо от того, удален ли он мягко.
+func (r *CourseRepository) GetByIDIncludingDeleted(ctx context.Context, id string) (map[string]interface{}, error) {
+	return r.BaseRepository.GetByID(ctx, id)
+}
+
+// SoftDelete помечает курс как удаленный, заполняя deleted_at.
+// Возвращает true, если курс был помечен, false - если не найден или уже удален.
+func (r *CourseRepository) SoftDelete(ctx context.Context, id string) (bool, error) {
+	query := `
+		UPDATE knowledge_base.course_b
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	affected, err := r.db.Execute(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Restore снимает пометку мягкого удаления с курса.
+// Возвращает восстановленный курс или nil, если курс не найден или не был удален.
+func (r *CourseRepository) Restore(ctx context.Context, id string) (map[string]interface{}, error) {
+	query := `
+		UPDATE knowledge_base.course_b
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING *
+	`
+	return r.db.ExecuteReturning(ctx, query, id)
+}
+
+// deleteCourseLessonsQuery удаляет уроки курса $1, если курс принадлежит категории $2.
+// Выполняется перед физическим удалением курса, чтобы не оставлять уроков без курса
+// независимо от того, настроен ли в базе каскадный внешний ключ.
+const deleteCourseLessonsQuery = `
+	DELETE FROM knowledge_base.lesson_d
+	WHERE course_id = $1
+	  AND EXISTS (SELECT 1 FROM knowledge_base.course_b WHERE id = $1 AND category_id = $2)
+`
+
+// DeleteWithLessons физически удаляет курс категории вместе с его уроками в одной транзакции:
+// сначала уроки, затем курс. Возвращает признак удаления курса, количество удаленных уроков
+// и image_key удаленного курса (пустой, если изображения не было).
+func (r *CourseRepository) DeleteWithLessons(ctx context.Context, categoryID, id string) (bool, int64, string, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, 0, "", err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, deleteCourseLessonsQuery, id, categoryID)
+	if err != nil {
+		return false, 0, "", err
+	}
+
+	var imageKey *string
+	err = tx.QueryRow(ctx, `
+		DELETE FROM knowledge_base.course_b
+		WHERE id = $1 AND category_id = $2
+		RETURNING image_key
+	`, id, categoryID).Scan(&imageKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, "", nil
+	}
+	if err != nil {
+		return false, 0, "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, 0, "", err
+	}
+
+	if imageKey == nil {
+		return true, tag.RowsAffected(), "", nil
+	}
+	return true, tag.RowsAffected(), *imageKey, nil
+}
+
+// BulkDeleteResult описывает результат удаления одного курса в SoftDeleteMany.
+// Deleted равно false и Err равно nil, если курс не найден в категории.
+type BulkDeleteResult struct {
+	ID      string
+	Deleted bool
+	Err     error
+}
+
+// SoftDeleteMany помечает курсы категории удаленными через deleted_at в одной транзакции.
+// Каждый курс обрабатывается в собственной точке сохранения, поэтому ошибка для одного ID
+// не отменяет удаление остальных. Возвращает результаты в порядке переданных ID.
+func (r *CourseRepository) SoftDeleteMany(ctx context.Context, categoryID string, ids []string) ([]BulkDeleteResult, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	results := make([]BulkDeleteResult, 0, len(ids))
+	for _, id := range ids {
+		result := BulkDeleteResult{ID: id}
+
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := savepoint.Exec(ctx, `
+			UPDATE knowledge_base.course_b
+			SET deleted_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND category_id = $2 AND deleted_at IS NULL
+		`, id, categoryID)
+		if err != nil {
+			_ = savepoint.Rollback(ctx)
+			result.Err = err
+		} else if err := savepoint.Commit(ctx); err != nil {
+			result.Err = err
+		} else {
+			result.Deleted = tag.RowsAffected() > 0
+		}
+
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Clone создает копию курса sourceID с заданными заголовком и slug вместе со всеми его уроками
+// в одной транзакции. Копия создается в той же категории в статусе draft и использует тот же image_key.
+// Возвращает ID нового курса и количество скопированных уроков.
+func (r *CourseRepository) Clone(ctx context.Context, sourceID, title, slug string) (string, int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var newID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO knowledge_base.course_b
+		(id, title, slug, description, level, category_id, visibility, image_key, content_policy, created_at, updated_at)
+		SELECT gen_random_uuid(), $2, $3, description, level, category_id, 'draft', image_key, content_policy, NOW(), NOW()
+		FROM knowledge_base.course_b
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id::text
+	`, sourceID, title, slug).Scan(&newID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	copied, err := copyLessons(ctx, tx, sourceID, newID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", 0, err
+	}
+
+	return newID, copied, nil
+}
+
+// ImageKeyInUse проверяет, ссылается ли хотя бы один курс (включая мягко удаленные) на image_key.
+// Копии курсов переиспользуют изображение оригинала, поэтому объект нельзя удалять, пока он используется.
+func (r *CourseRepository) ImageKeyInUse(ctx context.Context, imageKey string) (bool, error) {
+	query := "SELECT 1 FROM knowledge_base.course_b WHERE image_key = $1 LIMIT 1"
+	result, err := r.db.FetchOne(ctx, query, imageKey)
+	if err != nil {
+		return false, err
+	}
+	return result != nil, nil
+}
+
+// GetDeleted получает мягко удаленные курсы всех категорий, начиная с последних удаленных.
+// Возвращает список курсов, общее количество удаленных курсов и ошибку.
+func (r *CourseRepository) GetDeleted(ctx context.Context, limit, offset int) ([]map[string]interface{}, int, error) {
+	total, err := r.Count(ctx, "deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT * FROM knowledge_base.course_b
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	data, err := r.db.FetchAll(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// GetByCategory получает все не удаленные курсы для заданной категории.
 // Сортирует по времени создания в порядке убывания.
 func (r *CourseRepository) GetByCategory(ctx context.Context, categoryID string) ([]map[string]interface{}, error) {
 	query := `
 		SELECT * FROM knowledge_base.course_b
-		WHERE category_id = $1
+		WHERE category_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
 	return r.db.FetchAll(ctx, query, categoryID)
 }
 
+// GetOutlineByCategory получает не более limit не удаленных курсов категории (сначала новые)
+// с колонкой total - общим количеством не удаленных курсов категории.
+func (r *CourseRepository) GetOutlineByCategory(ctx context.Context, categoryID string, limit int) ([]map[string]interface{}, error) {
+	query := `
+		SELECT id, title, slug, level, visibility, COUNT(*) OVER () AS total
+		FROM knowledge_base.course_b
+		WHERE category_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	return r.db.FetchAll(ctx, query, categoryID, limit)
+}
+
 // ExistsByCategory проверяет существование категории по ID.
 // Возвращает true, если категория существует.
 func (r *CourseRepository) ExistsByCategory(ctx context.Context, categoryID string) (bool, error) {
@@ -152,3 +568,18 @@ func (r *CourseRepository) ExistsByCategory(ctx context.Context, categoryID stri
 	}
 	return result != nil, nil
 }
+
+// SlugExists проверяет, занят ли slug другим курсом в заданной категории.
+// Курс с ID excludeID (если задан) не учитывается, что позволяет проверять slug при редактировании.
+func (r *CourseRepository) SlugExists(ctx context.Context, categoryID, slug, excludeID string) (bool, error) {
+	query := `
+		SELECT 1 FROM knowledge_base.course_b
+		WHERE category_id = $1 AND slug = $2 AND ($3 = '' OR id::text <> $3)
+		LIMIT 1
+	`
+	result, err := r.db.FetchOne(ctx, query, categoryID, slug, excludeID)
+	if err != nil {
+		return false, err
+	}
+	return result != nil, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"adminPanel/handlers/dto/request"
+	"adminPanel/testutil"
+)
+
+func TestCourseSearchTreatsWildcardsLiterally(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCourseRepository(db)
+
+	categoryID := testutil.CreateCategory(t, db)
+	testutil.CreateCourse(t, db, categoryID, "100% Go", "easy", "public")
+	testutil.CreateCourse(t, db, categoryID, "Python basics", "easy", "public")
+	testutil.CreateCourse(t, db, categoryID, "snake_case naming", "medium", "public")
+
+	tests := []struct {
+		search string
+		want   int
+	}{
+		{search: "%", want: 0},
+		{search: "%%", want: 0},
+		{search: "_", want: 0},
+		{search: "%' OR '1'='1", want: 0},
+		{search: "100%", want: 1},
+		{search: "python", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			filter := request.CourseFilter{CategoryID: categoryID, Page: 1, Limit: 20}
+			data, total, err := repo.Search(context.Background(), tt.search, filter)
+			if err != nil {
+				t.Fatalf("Search(%q) error = %v", tt.search, err)
+			}
+			if total != tt.want || len(data) != tt.want {
+				t.Errorf("Search(%q) = %d rows, total %d, want %d", tt.search, len(data), total, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourseTitleFilterTreatsWildcardsLiterally(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCourseRepository(db)
+
+	categoryID := testutil.CreateCategory(t, db)
+	testutil.CreateCourse(t, db, categoryID, "100% Go", "easy", "public")
+	testutil.CreateCourse(t, db, categoryID, "1000 Go tips", "easy", "public")
+	testutil.CreateCourse(t, db, categoryID, "snake_case naming", "medium", "public")
+	testutil.CreateCourse(t, db, categoryID, `C:\path basics`, "medium", "public")
+
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{title: "100%", want: 1},
+		{title: "%", want: 1},
+		{title: "_", want: 1},
+		{title: "0_G", want: 0},
+		{title: `\`, want: 1},
+		{title: "GO", want: 2},
+		{title: "1000", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			filter := request.CourseFilter{CategoryID: categoryID, Title: tt.title, Page: 1, Limit: 20}
+			data, total, err := repo.GetFiltered(context.Background(), filter)
+			if err != nil {
+				t.Fatalf("GetFiltered(title %q) error = %v", tt.title, err)
+			}
+			if total != tt.want || len(data) != tt.want {
+				t.Errorf("GetFiltered(title %q) = %d rows, total %d, want %d", tt.title, len(data), total, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourseUpdateWithoutImageKeyKeepsImage(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCourseRepository(db)
+	ctx := context.Background()
+
+	categoryID := testutil.CreateCategory(t, db)
+	courseID := testutil.CreateCourse(t, db, categoryID, "old title", "easy", "draft")
+	const imageKey = "courses/cover.png"
+	if _, err := db.Pool.Exec(ctx, `UPDATE knowledge_base.course_b SET image_key = $1 WHERE id = $2`, imageKey, courseID); err != nil {
+		t.Fatalf("set image key: %v", err)
+	}
+
+	updated, err := repo.Update(ctx, courseID, request.CourseUpdate{Title: "new title"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated["title"] != "new title" {
+		t.Errorf("title = %v, want %q", updated["title"], "new title")
+	}
+	if updated["image_key"] != imageKey {
+		t.Errorf("image_key = %v, want %q kept", updated["image_key"], imageKey)
+	}
+}
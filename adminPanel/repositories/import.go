@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"adminPanel/config"
+	"adminPanel/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CourseImport содержит данные курса для импорта вместе с его уроками.
+// Slug используется как предпочтительный: при занятости подбирается свободный вариант.
+type CourseImport struct {
+	Title         string
+	Slug          string
+	Description   string
+	Level         string
+	Visibility    string
+	ImageKey      string
+	ContentPolicy string
+	Lessons       []LessonImport
+}
+
+// LessonImport содержит данные урока для импорта.
+// Slug используется как предпочтительный: при занятости в курсе подбирается свободный вариант.
+type LessonImport struct {
+	Title      string
+	Slug       string
+	Content    string
+	OrderIndex int
+}
+
+// CategoryImportResult содержит итоги импорта категории.
+type CategoryImportResult struct {
+	CategoryID      string
+	CategoryCreated bool
+	Courses         int
+	Lessons         int
+}
+
+// ImportRepository восстанавливает категории с курсами и уроками из выгрузки.
+// Содержит ссылку на базу данных и кодек контента уроков, совпадающий с LessonRepository.
+type ImportRepository struct {
+	db    *database.Database
+	codec contentCodec
+}
+
+// NewImportRepository создает новый экземпляр ImportRepository.
+// Принимает соединение с базой данных, настройки хранения контента и флаг отладки.
+func NewImportRepository(db *database.Database, cfg config.ContentConfig, debug bool) *ImportRepository {
+	return &ImportRepository{
+		db: db,
+		codec: contentCodec{
+			compress: cfg.Compress,
+			minSize:  cfg.CompressMinSize,
+			debug:    debug,
+		},
+	}
+}
+
+// ImportCategory в одной транзакции находит категорию по заголовку (или создает ее со slug,
+// полученным от generate) и создает в ней курсы и уроки с новыми UUID.
+// Занятость slug проверяется внутри транзакции, поэтому учитываются и только что созданные курсы и уроки.
+// При любой ошибке транзакция откатывается и в базе не остается частично импортированных данных.
+func (r *ImportRepository) ImportCategory(ctx context.Context, title, slug string, courses []CourseImport, generate SlugGenerator) (CategoryImportResult, error) {
+	var result CategoryImportResult
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx,
+		"SELECT id::text FROM knowledge_base.category_d WHERE title = $1 FOR UPDATE", title,
+	).Scan(&result.CategoryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		categorySlug, slugErr := generate(slug, func(candidate string) (bool, error) {
+			return rowExists(ctx, tx, "SELECT 1 FROM knowledge_base.category_d WHERE slug = $1", candidate)
+		})
+		if slugErr != nil {
+			return result, slugErr
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO knowledge_base.category_d (id, title, slug, created_at, updated_at)
+			VALUES (gen_random_uuid(), $1, $2, NOW(), NOW())
+			RETURNING id::text
+		`, title, categorySlug).Scan(&result.CategoryID)
+		result.CategoryCreated = true
+	}
+	if err != nil {
+		return result, err
+	}
+
+	for _, course := range courses {
+		courseSlug, err := generate(course.Slug, func(candidate string) (bool, error) {
+			return rowExists(ctx, tx,
+				"SELECT 1 FROM knowledge_base.course_b WHERE category_id = $1 AND slug = $2",
+				result.CategoryID, candidate)
+		})
+		if err != nil {
+			return result, err
+		}
+
+		var courseID string
+		err = tx.QueryRow(ctx, `
+			INSERT INTO knowledge_base.course_b
+			(id, title, slug, description, level, category_id, visibility, image_key, content_policy, created_at, updated_at)
+			VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NOW(), NOW())
+			RETURNING id::text
+		`, course.Title, courseSlug, course.Description, course.Level, result.CategoryID, course.Visibility, course.ImageKey,
+			course.ContentPolicy,
+		).Scan(&courseID)
+		if err != nil {
+			return result, err
+		}
+		result.Courses++
+
+		for _, lesson := range course.Lessons {
+			lessonSlug, err := generate(lesson.Slug, func(candidate string) (bool, error) {
+				return rowExists(ctx, tx,
+					"SELECT 1 FROM knowledge_base.lesson_d WHERE course_id = $1 AND slug = $2",
+					courseID, candidate)
+			})
+			if err != nil {
+				return result, err
+			}
+
+			encoded, err := r.codec.encode(lesson.Content)
+			if err != nil {
+				return result, err
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO knowledge_base.lesson_d
+				(id, title, slug, content, course_id, order_index, created_at, updated_at)
+				VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW(), NOW())
+			`, lesson.Title, lessonSlug, encoded, courseID, lesson.OrderIndex)
+			if err != nil {
+				return result, err
+			}
+			result.Lessons++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// rowExists выполняет запрос вида SELECT 1 ... в транзакции tx и сообщает, вернул ли он строку.
+func rowExists(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) (bool, error) {
+	var one int
+	err := tx.QueryRow(ctx, query, args...).Scan(&one)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"adminPanel/config"
 	"adminPanel/database"
 	"adminPanel/handlers/dto/request"
 	"adminPanel/models"
@@ -12,37 +14,49 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// ErrLessonOrderMismatch возвращается ReorderLessons, если переданный список ID
+// не совпадает с набором уроков курса.
+var ErrLessonOrderMismatch = errors.New("lesson ids do not match lessons of the course")
+
 // LessonRepository предоставляет методы для работы с уроками.
-// Содержит ссылку на базу данных для выполнения запросов.
+// Содержит ссылку на базу данных для выполнения запросов и кодек контента уроков.
 type LessonRepository struct {
-	db *database.Database
+	db    *database.Database
+	codec contentCodec
 }
 
 // NewLessonRepository создает новый экземпляр LessonRepository.
-// Принимает соединение с базой данных.
-func NewLessonRepository(db *database.Database) *LessonRepository {
+// Принимает соединение с базой данных, настройки хранения контента и флаг отладки
+// (в режиме отладки логируется степень сжатия контента).
+func NewLessonRepository(db *database.Database, cfg config.ContentConfig, debug bool) *LessonRepository {
 	return &LessonRepository{
 		db: db,
+		codec: contentCodec{
+			compress: cfg.Compress,
+			minSize:  cfg.CompressMinSize,
+			debug:    debug,
+		},
 	}
 }
 
 // GetAllByCourseID получает все уроки для заданного курса с пагинацией и сортировкой.
-// Принимает courseID, limit, offset, sortBy (title, created_at, updated_at), sortOrder (ASC/DESC).
+// Принимает courseID, limit, offset, sortBy (order_index, title, created_at, updated_at), sortOrder (ASC/DESC).
+// При равных значениях поля сортировки уроки упорядочиваются по времени создания.
 // Возвращает список уроков.
 func (r *LessonRepository) GetAllByCourseID(ctx context.Context, courseID string, limit, offset int, sortBy, sortOrder string) ([]models.Lesson, error) {
-	allowedSortBy := map[string]bool{"title": true, "created_at": true, "updated_at": true}
+	allowedSortBy := map[string]bool{"order_index": true, "title": true, "created_at": true, "updated_at": true}
 	if !allowedSortBy[sortBy] {
-		sortBy = "created_at"
+		sortBy = "order_index"
 	}
 	if !(strings.EqualFold(sortOrder, "ASC") || strings.EqualFold(sortOrder, "DESC")) {
 		sortOrder = "ASC"
 	}
 
 	query := fmt.Sprintf(`
-	       SELECT id, title, course_id, content, created_at, updated_at
+	       SELECT id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
 	       FROM knowledge_base.lesson_d
 	       WHERE course_id = $1
-	       ORDER BY %s %s
+	       ORDER BY %s %s, created_at ASC
 	       LIMIT $2 OFFSET $3
        `, sortBy, sortOrder)
 
@@ -55,12 +69,12 @@ func (r *LessonRepository) GetAllByCourseID(ctx context.Context, courseID string
 	var lessons []models.Lesson
 	for rows.Next() {
 		var lesson models.Lesson
-		var content *string
-		if err := rows.Scan(&lesson.ID, &lesson.Title, &lesson.CourseID, &content, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
+		var content []byte
+		if err := rows.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
 			return nil, err
 		}
-		if content != nil {
-			lesson.Content = *content
+		if lesson.Content, err = r.codec.decode(content); err != nil {
+			return nil, err
 		}
 		lessons = append(lessons, lesson)
 	}
@@ -68,6 +82,40 @@ func (r *LessonRepository) GetAllByCourseID(ctx context.Context, courseID string
 	return lessons, nil
 }
 
+// ForEachByCourseID построчно читает все уроки курса в порядке order_index и вызывает fn для каждого.
+// В отличие от GetAllByCourseID не накапливает уроки в памяти, что важно для экспорта больших курсов.
+// Ошибка, возвращенная fn, прерывает чтение и возвращается вызывающему.
+func (r *LessonRepository) ForEachByCourseID(ctx context.Context, courseID string, fn func(models.Lesson) error) error {
+	query := `
+		SELECT id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
+		FROM knowledge_base.lesson_d
+		WHERE course_id = $1
+		ORDER BY order_index ASC, created_at ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, courseID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lesson models.Lesson
+		var content []byte
+		if err := rows.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
+			return err
+		}
+		if lesson.Content, err = r.codec.decode(content); err != nil {
+			return err
+		}
+		if err := fn(lesson); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // CountByCourseID подсчитывает количество уроков для заданного курса.
 // Возвращает количество уроков.
 func (r *LessonRepository) CountByCourseID(ctx context.Context, courseID string) (int, error) {
@@ -80,17 +128,110 @@ func (r *LessonRepository) CountByCourseID(ctx context.Context, courseID string)
 	return count, nil
 }
 
+// CountLessonsByCourseIDs подсчитывает количество уроков для нескольких курсов одним запросом.
+// Возвращает карту courseID -> количество уроков с ключами в том виде, в каком ID переданы;
+// курсы без уроков получают 0. PostgreSQL возвращает UUID в нижнем регистре, поэтому
+// ID сопоставляются без учета регистра.
+func (r *LessonRepository) CountLessonsByCourseIDs(ctx context.Context, courseIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return counts, nil
+	}
+	keysByID := make(map[string][]string, len(courseIDs))
+	for _, id := range courseIDs {
+		counts[id] = 0
+		normalized := strings.ToLower(id)
+		keysByID[normalized] = append(keysByID[normalized], id)
+	}
+
+	query := `
+	       SELECT course_id::text, COUNT(*)
+	       FROM knowledge_base.lesson_d
+	       WHERE course_id = ANY($1::uuid[])
+	       GROUP BY course_id
+       `
+
+	rows, err := r.db.Pool.Query(ctx, query, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var courseID string
+		var count int
+		if err := rows.Scan(&courseID, &count); err != nil {
+			return nil, err
+		}
+		for _, key := range keysByID[courseID] {
+			counts[key] = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetOutlinesByCourseIDs получает оглавления уроков нескольких курсов одним запросом:
+// для каждого курса не более perCourseLimit уроков (без контента) в порядке order_index.
+// Возвращает карты courseID -> уроки и courseID -> общее количество уроков курса;
+// курсы без уроков в картах отсутствуют.
+func (r *LessonRepository) GetOutlinesByCourseIDs(ctx context.Context, courseIDs []string, perCourseLimit int) (map[string][]models.LessonOutline, map[string]int, error) {
+	outlines := make(map[string][]models.LessonOutline, len(courseIDs))
+	counts := make(map[string]int, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return outlines, counts, nil
+	}
+
+	query := `
+	       SELECT id::text, course_id::text, title, order_index, lesson_count
+	       FROM (
+		       SELECT id, course_id, title, order_index,
+			       ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY order_index, created_at, id) AS position,
+			       COUNT(*) OVER (PARTITION BY course_id) AS lesson_count
+		       FROM knowledge_base.lesson_d
+		       WHERE course_id = ANY($1::uuid[])
+	       ) AS l
+	       WHERE position <= $2
+	       ORDER BY course_id, position
+       `
+
+	rows, err := r.db.Pool.Query(ctx, query, courseIDs, perCourseLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lesson models.LessonOutline
+		var courseID string
+		var count int
+		if err := rows.Scan(&lesson.ID, &courseID, &lesson.Title, &lesson.OrderIndex, &count); err != nil {
+			return nil, nil, err
+		}
+		outlines[courseID] = append(outlines[courseID], lesson)
+		counts[courseID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return outlines, counts, nil
+}
+
 // GetByID получает урок по ID.
 // Возвращает урок или nil, если не найден.
 func (r *LessonRepository) GetByID(ctx context.Context, lessonID string) (*models.Lesson, error) {
-	query := `SELECT id, title, course_id, content, created_at, updated_at FROM knowledge_base.lesson_d WHERE id = $1`
+	query := `SELECT id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at FROM knowledge_base.lesson_d WHERE id = $1`
 
 	row := r.db.Pool.QueryRow(ctx, query, lessonID)
 
 	var lesson models.Lesson
-	var content *string
+	var content []byte
 
-	err := row.Scan(&lesson.ID, &lesson.Title, &lesson.CourseID, &content, &lesson.CreatedAt, &lesson.UpdatedAt)
+	err := row.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -98,39 +239,155 @@ func (r *LessonRepository) GetByID(ctx context.Context, lessonID string) (*model
 		return nil, err
 	}
 
-	if content != nil {
-		lesson.Content = *content
+	if lesson.Content, err = r.codec.decode(content); err != nil {
+		return nil, err
 	}
 
 	return &lesson, nil
 }
 
-// Create создает новый урок для заданного курса на основе данных из request.LessonCreate.
+// Create создает новый урок для заданного курса на основе данных из request.LessonCreate и заданного slug.
+// Урок добавляется в конец курса: order_index равен максимальному в курсе плюс один.
+// Время создания и обновления задается базой данных (NOW()), как и в остальных репозиториях.
 // Возвращает созданный урок.
-func (r *LessonRepository) Create(ctx context.Context, courseID string, lesson request.LessonCreate) (*models.Lesson, error) {
+func (r *LessonRepository) Create(ctx context.Context, courseID string, lesson request.LessonCreate, slug string) (*models.Lesson, error) {
 	query := `
-	       INSERT INTO knowledge_base.lesson_d (title, course_id, content)
-	       VALUES ($1, $2, $3)
-	       RETURNING id, title, course_id, content, created_at, updated_at
+	       INSERT INTO knowledge_base.lesson_d (title, slug, course_id, content, order_index, created_at, updated_at)
+	       VALUES ($1, $4, $2, $3, (
+		       SELECT COALESCE(MAX(order_index), 0) + 1
+		       FROM knowledge_base.lesson_d
+		       WHERE course_id = $2
+	       ), NOW(), NOW())
+	       RETURNING id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
        `
 
-	row := r.db.Pool.QueryRow(ctx, query, lesson.Title, courseID, lesson.Content)
+	encoded, err := r.codec.encode(lesson.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.Pool.QueryRow(ctx, query, lesson.Title, courseID, encoded, slug)
 
 	var newLesson models.Lesson
-	var content *string
+	var content []byte
 
-	err := row.Scan(&newLesson.ID, &newLesson.Title, &newLesson.CourseID, &content, &newLesson.CreatedAt, &newLesson.UpdatedAt)
+	err = row.Scan(&newLesson.ID, &newLesson.Title, &newLesson.Slug, &newLesson.CourseID, &content, &newLesson.OrderIndex, &newLesson.CreatedAt, &newLesson.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	if content != nil {
-		newLesson.Content = *content
+	if newLesson.Content, err = r.codec.decode(content); err != nil {
+		return nil, err
 	}
 
 	return &newLesson, nil
 }
 
+// BulkLessonError сообщает, на каком уроке пакета (индекс во входном списке) прервалось создание.
+type BulkLessonError struct {
+	Index int
+	Err   error
+}
+
+// Error реализует интерфейс error.
+func (e *BulkLessonError) Error() string {
+	return fmt.Sprintf("lesson %d: %v", e.Index, e.Err)
+}
+
+// Unwrap возвращает исходную ошибку.
+func (e *BulkLessonError) Unwrap() error {
+	return e.Err
+}
+
+// CreateMany создает уроки курса в одной транзакции в порядке списка lessons.
+// Уроки добавляются в конец курса с последовательными order_index; slug каждого урока
+// подбирается generate с проверкой занятости внутри транзакции, поэтому учитываются и уроки пакета.
+// При ошибке транзакция откатывается целиком, а ошибка оборачивается в BulkLessonError с индексом урока.
+// Возвращает созданные уроки в порядке входного списка.
+func (r *LessonRepository) CreateMany(ctx context.Context, courseID string, lessons []request.LessonCreate, generate SlugGenerator) ([]models.Lesson, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Блокируем строку курса, а не его уроки: у курса без уроков блокировать было бы нечего,
+	// и параллельные пакеты получили бы те же позиции.
+	if _, err := tx.Exec(ctx, `
+		SELECT 1 FROM knowledge_base.course_b WHERE id = $1 FOR UPDATE
+	`, courseID); err != nil {
+		return nil, err
+	}
+
+	var lastIndex int
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(order_index), 0) FROM knowledge_base.lesson_d WHERE course_id = $1
+	`, courseID).Scan(&lastIndex); err != nil {
+		return nil, err
+	}
+
+	created := make([]models.Lesson, 0, len(lessons))
+	for i, input := range lessons {
+		lesson, err := r.createInTx(ctx, tx, courseID, input, lastIndex+i+1, generate)
+		if err != nil {
+			return nil, &BulkLessonError{Index: i, Err: err}
+		}
+		created = append(created, *lesson)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// createInTx создает один урок пакета CreateMany на позиции orderIndex.
+func (r *LessonRepository) createInTx(ctx context.Context, tx pgx.Tx, courseID string, input request.LessonCreate, orderIndex int, generate SlugGenerator) (*models.Lesson, error) {
+	slug, err := generate(input.Title, func(candidate string) (bool, error) {
+		return rowExists(ctx, tx,
+			"SELECT 1 FROM knowledge_base.lesson_d WHERE course_id = $1 AND slug = $2",
+			courseID, candidate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := r.codec.encode(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRow(ctx, `
+		INSERT INTO knowledge_base.lesson_d (title, slug, course_id, content, order_index, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
+	`, input.Title, slug, courseID, encoded, orderIndex)
+
+	var lesson models.Lesson
+	var content []byte
+	if err := row.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if lesson.Content, err = r.codec.decode(content); err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+// SlugExists проверяет, занят ли slug другим уроком заданного курса.
+func (r *LessonRepository) SlugExists(ctx context.Context, courseID, slug string) (bool, error) {
+	query := `
+		SELECT 1 FROM knowledge_base.lesson_d
+		WHERE course_id = $1 AND slug = $2
+		LIMIT 1
+	`
+	result, err := r.db.FetchOne(ctx, query, courseID, slug)
+	if err != nil {
+		return false, err
+	}
+	return result != nil, nil
+}
+
 // Update обновляет урок по ID на основе данных из request.LessonUpdate.
 // Возвращает обновленный урок.
 func (r *LessonRepository) Update(ctx context.Context, lessonID string, lesson request.LessonUpdate) (*models.Lesson, error) {
@@ -141,20 +398,25 @@ func (r *LessonRepository) Update(ctx context.Context, lessonID string, lesson r
 		       content = $2,
 		       updated_at = NOW()
 	       WHERE id = $3
-	       RETURNING id, title, course_id, content, created_at, updated_at
+	       RETURNING id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
        `
-	row := r.db.Pool.QueryRow(ctx, query, lesson.Title, lesson.Content, lessonID)
+	encoded, err := r.codec.encode(lesson.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	row := r.db.Pool.QueryRow(ctx, query, lesson.Title, encoded, lessonID)
 
 	var updatedLesson models.Lesson
-	var content *string
+	var content []byte
 
-	err := row.Scan(&updatedLesson.ID, &updatedLesson.Title, &updatedLesson.CourseID, &content, &updatedLesson.CreatedAt, &updatedLesson.UpdatedAt)
+	err = row.Scan(&updatedLesson.ID, &updatedLesson.Title, &updatedLesson.Slug, &updatedLesson.CourseID, &content, &updatedLesson.OrderIndex, &updatedLesson.CreatedAt, &updatedLesson.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	if content != nil {
-		updatedLesson.Content = *content
+	if updatedLesson.Content, err = r.codec.decode(content); err != nil {
+		return nil, err
 	}
 
 	return &updatedLesson, nil
@@ -172,3 +434,203 @@ func (r *LessonRepository) Delete(ctx context.Context, lessonID string) (bool, e
 
 	return result.RowsAffected() > 0, nil
 }
+
+// orphanedLessonsCondition условие отбора уроков, курс которых больше не существует.
+// Мягко удаленные курсы сохраняют строку в course_b, поэтому их уроки сиротами не считаются.
+const orphanedLessonsCondition = `NOT EXISTS (
+	SELECT 1 FROM knowledge_base.course_b c WHERE c.id = l.course_id
+)`
+
+// FindOrphanedLessons возвращает уроки, чей course_id не соответствует ни одному курсу.
+// Такие уроки могут остаться после физического удаления курса, если в базе нет каскадного внешнего ключа.
+// Уроки упорядочены по course_id и order_index.
+func (r *LessonRepository) FindOrphanedLessons(ctx context.Context) ([]models.Lesson, error) {
+	query := `
+		SELECT l.id, l.title, COALESCE(l.slug, ''), l.course_id, l.content, l.order_index, l.created_at, l.updated_at
+		FROM knowledge_base.lesson_d l
+		WHERE ` + orphanedLessonsCondition + `
+		ORDER BY l.course_id, l.order_index, l.created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lessons := []models.Lesson{}
+	for rows.Next() {
+		var lesson models.Lesson
+		var content []byte
+		if err := rows.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if lesson.Content, err = r.codec.decode(content); err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, lesson)
+	}
+
+	return lessons, rows.Err()
+}
+
+// CountOrphanedLessons подсчитывает уроки, чей курс не существует.
+func (r *LessonRepository) CountOrphanedLessons(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM knowledge_base.lesson_d l WHERE ` + orphanedLessonsCondition
+
+	var count int
+	if err := r.db.Pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// DeleteOrphanedLessons удаляет все уроки, чей курс не существует.
+// Возвращает количество удаленных уроков.
+func (r *LessonRepository) DeleteOrphanedLessons(ctx context.Context) (int64, error) {
+	query := `DELETE FROM knowledge_base.lesson_d l WHERE ` + orphanedLessonsCondition
+
+	result, err := r.db.Pool.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// ReorderLessons задает новый порядок уроков курса в одной транзакции.
+// orderedIDs должен содержать ID всех уроков курса ровно по одному разу;
+// урок на позиции i получает order_index = i + 1. Иначе возвращается ErrLessonOrderMismatch.
+func (r *LessonRepository) ReorderLessons(ctx context.Context, courseID string, orderedIDs []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+	       SELECT id::text
+	       FROM knowledge_base.lesson_d
+	       WHERE course_id = $1
+	       FOR UPDATE
+       `, courseID)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(existing) != len(orderedIDs) {
+		return ErrLessonOrderMismatch
+	}
+	for _, id := range orderedIDs {
+		if !existing[id] {
+			return ErrLessonOrderMismatch
+		}
+		delete(existing, id)
+	}
+
+	_, err = tx.Exec(ctx, `
+	       UPDATE knowledge_base.lesson_d AS l
+	       SET order_index = o.position, updated_at = NOW()
+	       FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+	       WHERE l.id = o.id AND l.course_id = $1
+       `, courseID, orderedIDs)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Duplicate создает копию урока lessonID курса courseID с заголовком title и slug в одной транзакции.
+// Копия встает сразу после исходного урока: уроки курса с большим order_index сдвигаются на одну позицию.
+// Контент копируется в том виде, в котором хранится (в том числе сжатым).
+// Возвращает созданный урок или nil, если исходный урок не найден в курсе.
+func (r *LessonRepository) Duplicate(ctx context.Context, courseID, lessonID, title, slug string) (*models.Lesson, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Блокируем уроки курса, чтобы параллельные вставки и перестановки не получили ту же позицию.
+	if _, err := tx.Exec(ctx, `
+	       SELECT 1 FROM knowledge_base.lesson_d WHERE course_id = $1 FOR UPDATE
+       `, courseID); err != nil {
+		return nil, err
+	}
+
+	var sourceIndex int
+	err = tx.QueryRow(ctx, `
+	       SELECT order_index FROM knowledge_base.lesson_d WHERE id = $1 AND course_id = $2
+       `, lessonID, courseID).Scan(&sourceIndex)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+	       UPDATE knowledge_base.lesson_d
+	       SET order_index = order_index + 1, updated_at = NOW()
+	       WHERE course_id = $1 AND order_index > $2
+       `, courseID, sourceIndex); err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRow(ctx, `
+	       INSERT INTO knowledge_base.lesson_d
+	       (id, title, slug, content, course_id, order_index, created_at, updated_at)
+	       SELECT gen_random_uuid(), $2, $3, content, course_id, order_index + 1, NOW(), NOW()
+	       FROM knowledge_base.lesson_d
+	       WHERE id = $1
+	       RETURNING id, title, COALESCE(slug, ''), course_id, content, order_index, created_at, updated_at
+       `, lessonID, title, slug)
+
+	var lesson models.Lesson
+	var content []byte
+	if err := row.Scan(&lesson.ID, &lesson.Title, &lesson.Slug, &lesson.CourseID, &content, &lesson.OrderIndex, &lesson.CreatedAt, &lesson.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if lesson.Content, err = r.codec.decode(content); err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+// copyLessons копирует все уроки курса sourceCourseID в курс targetCourseID в рамках транзакции tx.
+// Контент копируется в том виде, в котором хранится (в том числе сжатым), порядок уроков и их slug сохраняются:
+// slug уникален в пределах курса, поэтому в новом курсе конфликтов не возникает.
+// Возвращает количество скопированных уроков.
+func copyLessons(ctx context.Context, tx pgx.Tx, sourceCourseID, targetCourseID string) (int64, error) {
+	query := `
+		INSERT INTO knowledge_base.lesson_d
+		(id, title, slug, content, course_id, order_index, created_at, updated_at)
+		SELECT gen_random_uuid(), title, slug, content, $2, order_index, NOW(), NOW()
+		FROM knowledge_base.lesson_d
+		WHERE course_id = $1
+	`
+	tag, err := tx.Exec(ctx, query, sourceCourseID, targetCourseID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/testutil"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestCountLessonsByCourseIDs(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewLessonRepository(db, config.ContentConfig{}, false)
+
+	categoryID := testutil.CreateCategory(t, db)
+	empty := testutil.CreateCourse(t, db, categoryID, "test course", "easy", "draft")
+	one := testutil.CreateCourse(t, db, categoryID, "test course", "easy", "draft")
+	three := testutil.CreateCourse(t, db, categoryID, "test course", "hard", "public")
+	testutil.CreateLessons(t, db, one, 1)
+	testutil.CreateLessons(t, db, three, 3)
+
+	upper := strings.ToUpper(three)
+	counts, err := repo.CountLessonsByCourseIDs(context.Background(), []string{empty, one, upper})
+	if err != nil {
+		t.Fatalf("CountLessonsByCourseIDs() error = %v", err)
+	}
+
+	want := map[string]int{empty: 0, one: 1, upper: 3}
+	if len(counts) != len(want) {
+		t.Errorf("CountLessonsByCourseIDs() = %v, want %v", counts, want)
+	}
+	for id, n := range want {
+		if got, ok := counts[id]; !ok || got != n {
+			t.Errorf("counts[%s] = %d (present %v), want %d", id, got, ok, n)
+		}
+	}
+}
+
+func TestCountLessonsByCourseIDsEmpty(t *testing.T) {
+	repo := NewLessonRepository(nil, config.ContentConfig{}, false)
+
+	counts, err := repo.CountLessonsByCourseIDs(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("CountLessonsByCourseIDs() error = %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("CountLessonsByCourseIDs() = %v, want empty", counts)
+	}
+}
+
+func TestCreateManyConcurrentBatchesGetDistinctPositions(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewLessonRepository(db, config.ContentConfig{}, false)
+
+	categoryID := testutil.CreateCategory(t, db)
+	// У курса нет уроков, поэтому параллельные пакеты сериализуются только блокировкой курса.
+	courseID := testutil.CreateCourse(t, db, categoryID, "test course", "easy", "draft")
+	slug := func(title string, _ func(string) (bool, error)) (string, error) { return title, nil }
+
+	const batches, perBatch = 4, 3
+	var wg sync.WaitGroup
+	errs := make(chan error, batches)
+	for b := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lessons := make([]request.LessonCreate, perBatch)
+			for i := range lessons {
+				lessons[i] = request.LessonCreate{Title: fmt.Sprintf("lesson-%d-%d", b, i)}
+			}
+			_, err := repo.CreateMany(context.Background(), courseID, lessons, slug)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateMany() error = %v", err)
+		}
+	}
+
+	rows, err := db.Pool.Query(context.Background(),
+		`SELECT order_index FROM knowledge_base.lesson_d WHERE course_id = $1 ORDER BY order_index`, courseID)
+	if err != nil {
+		t.Fatalf("query positions: %v", err)
+	}
+	positions, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		t.Fatalf("collect positions: %v", err)
+	}
+	for i, position := range positions {
+		if position != i+1 {
+			t.Fatalf("positions = %v, want 1..%d without gaps or repeats", positions, batches*perBatch)
+		}
+	}
+	if len(positions) != batches*perBatch {
+		t.Errorf("created %d lessons, want %d", len(positions), batches*perBatch)
+	}
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SlugGenerator строит уникальный slug для заголовка title.
+// Функция exists проверяет занятость кандидата в рамках транзакции текущего пакета.
+type SlugGenerator func(title string, exists func(slug string) (bool, error)) (string, error)
+
+// backfillSlugsBatch заполняет slug у не более чем batchSize строк таблицы table, у которых он отсутствует.
+// Строки обрабатываются в порядке created_at, id, поэтому при коллизиях суффиксы -2, -3 распределяются
+// детерминированно: более ранняя запись получает базовый slug. scopeColumn (если задан) ограничивает
+// уникальность slug значением этой колонки, как у курсов внутри категории.
+// Весь пакет выполняется в одной транзакции; возвращается количество обновленных строк.
+func backfillSlugsBatch(ctx context.Context, pool *pgxpool.Pool, table, scopeColumn string, batchSize int, generate SlugGenerator) (int, error) {
+	scopeSelect := "''"
+	if scopeColumn != "" {
+		scopeSelect = scopeColumn + "::text"
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT id::text, title, %s
+		FROM %s
+		WHERE slug IS NULL
+		ORDER BY created_at, id
+		LIMIT $1
+		FOR UPDATE
+	`, scopeSelect, table), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct{ id, title, scope string }
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.title, &p.scope); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	existsQuery := fmt.Sprintf("SELECT 1 FROM %s WHERE slug = $1 LIMIT 1", table)
+	if scopeColumn != "" {
+		existsQuery = fmt.Sprintf("SELECT 1 FROM %s WHERE slug = $1 AND %s::text = $2 LIMIT 1", table, scopeColumn)
+	}
+	updateQuery := fmt.Sprintf("UPDATE %s SET slug = $1 WHERE id::text = $2", table)
+
+	for _, p := range batch {
+		slug, err := generate(p.title, func(candidate string) (bool, error) {
+			args := []interface{}{candidate}
+			if scopeColumn != "" {
+				args = append(args, p.scope)
+			}
+			var found int
+			err := tx.QueryRow(ctx, existsQuery, args...).Scan(&found)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, nil
+			}
+			return err == nil, err
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.Exec(ctx, updateQuery, slug, p.id); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(batch), nil
+}
+
+// BackfillSlugsBatch заполняет slug у очередного пакета категорий без slug.
+// Возвращает количество обновленных категорий; 0 означает, что заполнять больше нечего.
+func (r *CategoryRepository) BackfillSlugsBatch(ctx context.Context, batchSize int, generate SlugGenerator) (int, error) {
+	return backfillSlugsBatch(ctx, r.db.Pool, "knowledge_base.category_d", "", batchSize, generate)
+}
+
+// BackfillSlugsBatch заполняет slug у очередного пакета курсов без slug, включая мягко удаленные.
+// Уникальность проверяется в пределах категории курса.
+// Возвращает количество обновленных курсов; 0 означает, что заполнять больше нечего.
+func (r *CourseRepository) BackfillSlugsBatch(ctx context.Context, batchSize int, generate SlugGenerator) (int, error) {
+	return backfillSlugsBatch(ctx, r.db.Pool, "knowledge_base.course_b", "category_id", batchSize, generate)
+}
+
+// BackfillSlugsBatch заполняет slug у очередного пакета уроков без slug.
+// Уникальность проверяется в пределах курса урока.
+// Возвращает количество обновленных уроков; 0 означает, что заполнять больше нечего.
+func (r *LessonRepository) BackfillSlugsBatch(ctx context.Context, batchSize int, generate SlugGenerator) (int, error) {
+	return backfillSlugsBatch(ctx, r.db.Pool, "knowledge_base.lesson_d", "course_id", batchSize, generate)
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"adminPanel/database"
+)
+
+// StatsRepository предоставляет агрегирующие запросы для сводной статистики базы знаний.
+// Каждый метод выполняет один запрос и возвращает одну строку с итогами по сущности.
+type StatsRepository struct {
+	db *database.Database
+}
+
+// NewStatsRepository создает новый экземпляр StatsRepository.
+func NewStatsRepository(db *database.Database) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// CategoryTotals возвращает количество категорий (total) и время последнего изменения категории (last_updated_at).
+func (r *StatsRepository) CategoryTotals(ctx context.Context) (map[string]interface{}, error) {
+	query := `
+		SELECT COUNT(*) AS total, MAX(updated_at) AS last_updated_at
+		FROM knowledge_base.category_d
+	`
+	return r.db.FetchOne(ctx, query)
+}
+
+// CourseTotals возвращает количество неудаленных курсов: всего (total), по видимости (draft, public)
+// и по уровню сложности (easy, medium, hard), а также время последнего изменения курса (last_updated_at).
+func (r *StatsRepository) CourseTotals(ctx context.Context) (map[string]interface{}, error) {
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE visibility = 'draft') AS draft,
+			COUNT(*) FILTER (WHERE visibility = 'public') AS public,
+			COUNT(*) FILTER (WHERE level = 'easy') AS easy,
+			COUNT(*) FILTER (WHERE level = 'medium') AS medium,
+			COUNT(*) FILTER (WHERE level = 'hard') AS hard,
+			MAX(updated_at) AS last_updated_at
+		FROM knowledge_base.course_b
+		WHERE deleted_at IS NULL
+	`
+	return r.db.FetchOne(ctx, query)
+}
+
+// LessonTotals возвращает количество уроков неудаленных курсов (total)
+// и время последнего изменения урока (last_updated_at).
+func (r *StatsRepository) LessonTotals(ctx context.Context) (map[string]interface{}, error) {
+	query := `
+		SELECT COUNT(*) AS total, MAX(l.updated_at) AS last_updated_at
+		FROM knowledge_base.lesson_d l
+		JOIN knowledge_base.course_b c ON c.id = l.course_id AND c.deleted_at IS NULL
+	`
+	return r.db.FetchOne(ctx, query)
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/testutil"
+)
+
+func TestInsertsUseDatabaseTimestamps(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+
+	var before time.Time
+	if err := db.Pool.QueryRow(ctx, `SELECT LOCALTIMESTAMP`).Scan(&before); err != nil {
+		t.Fatalf("read database clock: %v", err)
+	}
+
+	category, err := NewCategoryRepository(db).Create(ctx, "timestamps", fmt.Sprintf("timestamps-%d", before.UnixNano()))
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	categoryID := fmt.Sprint(category["id"])
+	t.Cleanup(func() {
+		_, _ = db.Pool.Exec(context.Background(), `DELETE FROM knowledge_base.course_b WHERE category_id = $1`, categoryID)
+		_, _ = db.Pool.Exec(context.Background(), `DELETE FROM knowledge_base.category_d WHERE id = $1`, categoryID)
+	})
+
+	course, err := NewCourseRepository(db).Create(ctx, request.CourseCreate{
+		Title:         "timestamps",
+		Level:         "easy",
+		CategoryID:    categoryID,
+		Visibility:    "draft",
+		ContentPolicy: "strict",
+	}, "timestamps")
+	if err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	courseID := fmt.Sprint(course["id"])
+
+	lesson, err := NewLessonRepository(db, config.ContentConfig{}, false).Create(ctx, courseID, request.LessonCreate{Title: "timestamps"}, "timestamps")
+	if err != nil {
+		t.Fatalf("create lesson: %v", err)
+	}
+
+	rows := []struct {
+		table string
+		id    string
+	}{
+		{table: "knowledge_base.category_d", id: categoryID},
+		{table: "knowledge_base.course_b", id: courseID},
+		{table: "knowledge_base.lesson_d", id: lesson.ID},
+	}
+	for _, row := range rows {
+		t.Run(row.table, func(t *testing.T) {
+			var fromDB, equal bool
+			err := db.Pool.QueryRow(ctx, `
+				SELECT created_at BETWEEN $2 AND LOCALTIMESTAMP, created_at = updated_at
+				FROM `+row.table+` WHERE id = $1
+			`, row.id, before).Scan(&fromDB, &equal)
+			if err != nil {
+				t.Fatalf("read timestamps: %v", err)
+			}
+			if !fromDB {
+				t.Error("created_at is outside the database clock window, want it set by NOW()")
+			}
+			if !equal {
+				t.Error("created_at != updated_at for a new row, want both from the same NOW()")
+			}
+		})
+	}
+}
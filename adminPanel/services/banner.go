@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"adminPanel/handlers/dto/request"
+	"adminPanel/middleware"
+	"adminPanel/models"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// MaxBannerMessageLength - максимальная длина текста баннера в символах.
+const MaxBannerMessageLength = 1000
+
+// bannerTracer трассировщик для сервиса баннера сайта.
+var bannerTracer = otel.Tracer("admin-panel/banner-service")
+
+// BannerService предоставляет бизнес-логику для баннера, который показывается на всех страницах публичного сайта.
+type BannerService struct {
+	bannerRepo *repositories.BannerRepository
+}
+
+// NewBannerService создает новый экземпляр BannerService.
+func NewBannerService(bannerRepo *repositories.BannerRepository) *BannerService {
+	return &BannerService{bannerRepo: bannerRepo}
+}
+
+// GetBanner получает текущий баннер сайта.
+// Если баннер не задан, возвращает неактивный баннер с пустым текстом.
+func (s *BannerService) GetBanner(ctx context.Context) (*models.SiteBanner, error) {
+	ctx, span := bannerTracer.Start(ctx, "BannerService.GetBanner")
+	defer span.End()
+
+	data, err := s.bannerRepo.Get(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get banner: %v", err))
+	}
+
+	if data == nil {
+		return &models.SiteBanner{Severity: models.BannerSeverityInfo}, nil
+	}
+	return bannerFromRow(data), nil
+}
+
+// SetBanner проверяет и сохраняет баннер сайта, заменяя предыдущий.
+func (s *BannerService) SetBanner(ctx context.Context, input request.BannerUpdate) (*models.SiteBanner, error) {
+	ctx, span := bannerTracer.Start(ctx, "BannerService.SetBanner")
+	defer span.End()
+
+	message := strings.TrimSpace(input.Message)
+	if message == "" {
+		return nil, middleware.ValidationError("Banner message is required")
+	}
+	if utf8.RuneCountInString(message) > MaxBannerMessageLength {
+		return nil, middleware.ValidationError(fmt.Sprintf("Banner message must not exceed %d characters", MaxBannerMessageLength))
+	}
+
+	severity := input.Severity
+	if severity == "" {
+		severity = models.BannerSeverityInfo
+	}
+	switch severity {
+	case models.BannerSeverityInfo, models.BannerSeverityWarning, models.BannerSeverityCritical:
+	default:
+		return nil, middleware.ValidationError("Banner severity must be one of: info, warning, critical")
+	}
+
+	active := true
+	if input.Active != nil {
+		active = *input.Active
+	}
+	span.SetAttributes(
+		attribute.String("banner.severity", severity),
+		attribute.Bool("banner.active", active),
+	)
+
+	data, err := s.bannerRepo.Upsert(ctx, message, severity, active)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to save banner: %v", err))
+	}
+
+	return bannerFromRow(data), nil
+}
+
+// ClearBanner удаляет баннер сайта.
+func (s *BannerService) ClearBanner(ctx context.Context) error {
+	ctx, span := bannerTracer.Start(ctx, "BannerService.ClearBanner")
+	defer span.End()
+
+	if err := s.bannerRepo.Delete(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return middleware.InternalError(fmt.Sprintf("Failed to clear banner: %v", err))
+	}
+	return nil
+}
+
+// bannerFromRow преобразует строку таблицы site_banner в модель SiteBanner.
+func bannerFromRow(data map[string]interface{}) *models.SiteBanner {
+	active, _ := data["active"].(bool)
+	updatedAt := parseTime(data["updated_at"])
+	return &models.SiteBanner{
+		Message:   toString(data["message"]),
+		Severity:  toString(data["severity"]),
+		Active:    active,
+		UpdatedAt: &updatedAt,
+	}
+}
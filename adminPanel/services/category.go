@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"strings"
 
+	"adminPanel/config"
 	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
 	"adminPanel/models"
 	"adminPanel/repositories"
@@ -21,6 +23,8 @@ import (
 // Содержит репозиторий для доступа к данным и методы для CRUD операций.
 type CategoryService struct {
 	categoryRepo *repositories.CategoryRepository
+	cache        *categoryCache
+	events       EventPublisher
 }
 
 // categoryTracer трассировщик для сервиса категорий.
@@ -28,19 +32,46 @@ type CategoryService struct {
 var categoryTracer = otel.Tracer("admin-panel/category-service")
 
 // NewCategoryService создает новый экземпляр CategoryService.
-// Принимает репозиторий категорий.
-func NewCategoryService(categoryRepo *repositories.CategoryRepository) *CategoryService {
+// Принимает репозиторий категорий, настройки категорий (время жизни кэша; 0 отключает кэш)
+// и шину доменных событий (nil - события не публикуются).
+func NewCategoryService(categoryRepo *repositories.CategoryRepository, cfg config.CategoryConfig, events EventPublisher) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		cache:        newCategoryCache(cfg.CacheTTL),
+		events:       events,
 	}
 }
 
+// InvalidateCache сбрасывает кэш категорий. Вызывается после изменений категорий в обход
+// CreateCategory, UpdateCategory и DeleteCategory (например, при импорте).
+func (s *CategoryService) InvalidateCache() {
+	s.cache.invalidate()
+}
+
+// PublishChange публикует событие изменения категории для изменений в обход методов сервиса.
+// action - одно из EventActionCreated, EventActionUpdated, EventActionDeleted.
+func (s *CategoryService) PublishChange(ctx context.Context, action, id string) {
+	publishEvent(ctx, s.events, NewDomainEvent(EventEntityCategory, action, id))
+}
+
+// categoryEvent создает доменное событие категории с ее состоянием после изменения.
+func categoryEvent(action string, category models.Category) DomainEvent {
+	event := NewDomainEvent(EventEntityCategory, action, category.ID)
+	event.Data = category
+	return event
+}
+
 // GetCategories получает все категории, отсортированные по заголовку.
-// Возвращает список моделей Category.
+// Возвращает список моделей Category; при включенном кэше список берется из него.
 func (s *CategoryService) GetCategories(ctx context.Context) ([]models.Category, error) {
 	ctx, span := categoryTracer.Start(ctx, "CategoryService.GetCategories")
 	defer span.End()
 
+	if categories, ok := s.cache.getList(); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return categories, nil
+	}
+
 	data, err := s.categoryRepo.GetAll(ctx, 100, 0, "title", "ASC")
 	if err != nil {
 		span.RecordError(err)
@@ -48,6 +79,73 @@ func (s *CategoryService) GetCategories(ctx context.Context) ([]models.Category,
 		return nil, middleware.InternalError(fmt.Sprintf("Failed to get categories: %v", err))
 	}
 
+	categories := categoriesFromRows(data)
+
+	s.cache.setList(categories)
+	return categories, nil
+}
+
+// ListCategories получает страницу page категорий размером limit, отсортированных по заголовку,
+// и пагинацию с общим числом категорий. page меньше 1 заменяется на 1, а limit вне диапазона 1..100 - на 20.
+// В отличие от GetCategories, кэш не используется.
+func (s *CategoryService) ListCategories(ctx context.Context, page, limit int) ([]models.Category, models.Pagination, error) {
+	ctx, span := categoryTracer.Start(ctx, "CategoryService.ListCategories")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	span.SetAttributes(attribute.Int("page", page), attribute.Int("limit", limit))
+
+	total, err := s.categoryRepo.Count(ctx, "")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, models.Pagination{}, middleware.InternalError(fmt.Sprintf("Failed to count categories: %v", err))
+	}
+
+	data, err := s.categoryRepo.GetAll(ctx, limit, (page-1)*limit, "title", "ASC")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, models.Pagination{}, middleware.InternalError(fmt.Sprintf("Failed to get categories: %v", err))
+	}
+
+	pages := (total + limit - 1) / limit
+	if pages == 0 {
+		pages = 1
+	}
+
+	return categoriesFromRows(data), models.Pagination{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+		Pages: pages,
+	}, nil
+}
+
+// GetEmptyCategories получает категории без курсов одним запросом, сначала самые старые.
+// Такие категории можно удалить через DeleteCategory.
+func (s *CategoryService) GetEmptyCategories(ctx context.Context) ([]models.Category, error) {
+	ctx, span := categoryTracer.Start(ctx, "CategoryService.GetEmptyCategories")
+	defer span.End()
+
+	data, err := s.categoryRepo.GetEmpty(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get empty categories: %v", err))
+	}
+
+	span.SetAttributes(attribute.Int("categories.count", len(data)))
+	return categoriesFromRows(data), nil
+}
+
+// categoriesFromRows преобразует строки таблицы категорий в модели Category.
+func categoriesFromRows(data []map[string]interface{}) []models.Category {
 	categories := make([]models.Category, 0, len(data))
 	for _, item := range data {
 		category := models.Category{
@@ -57,20 +155,25 @@ func (s *CategoryService) GetCategories(ctx context.Context) ([]models.Category,
 				UpdatedAt: parseTime(item["updated_at"]),
 			},
 			Title: toString(item["title"]),
+			Slug:  toString(item["slug"]),
 		}
 		categories = append(categories, category)
 	}
-
-	return categories, nil
+	return categories
 }
 
 // GetCategory получает категорию по ID.
-// Возвращает модель Category или ошибку, если не найдена.
+// Возвращает модель Category или ошибку, если не найдена; при включенном кэше категория берется из него.
 func (s *CategoryService) GetCategory(ctx context.Context, id string) (*models.Category, error) {
 	ctx, span := categoryTracer.Start(ctx, "CategoryService.GetCategory")
 	span.SetAttributes(attribute.String("category.id", id))
 	defer span.End()
 
+	if category, ok := s.cache.get(id); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return category, nil
+	}
+
 	data, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		span.RecordError(err)
@@ -89,13 +192,15 @@ func (s *CategoryService) GetCategory(ctx context.Context, id string) (*models.C
 			UpdatedAt: parseTime(data["updated_at"]),
 		},
 		Title: toString(data["title"]),
+		Slug:  toString(data["slug"]),
 	}
 
+	s.cache.set(category)
 	return category, nil
 }
 
 // CreateCategory создает новую категорию на основе данных из request.CategoryCreate.
-// Проверяет уникальность заголовка и возвращает созданную категорию.
+// Проверяет уникальность заголовка, генерирует из него уникальный slug и возвращает созданную категорию.
 func (s *CategoryService) CreateCategory(ctx context.Context, input request.CategoryCreate) (*models.Category, error) {
 	existing, err := s.categoryRepo.GetByTitle(ctx, input.Title)
 	if err != nil {
@@ -106,7 +211,20 @@ func (s *CategoryService) CreateCategory(ctx context.Context, input request.Cate
 		return nil, middleware.ConflictError(fmt.Sprintf("Category with title '%s' already exists", input.Title))
 	}
 
-	data, err := s.categoryRepo.Create(ctx, input.Title)
+	var data map[string]interface{}
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(input.Title, "category", func(slug string) (bool, error) {
+			return s.categoryRepo.SlugExists(ctx, slug, "")
+		})
+		if slugErr != nil {
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate category slug: %v", slugErr))
+		}
+
+		data, err = s.categoryRepo.Create(ctx, input.Title, slug)
+		if !isSlugConflict(err, "idx_category_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			return nil, middleware.ConflictError("Category with this title already exists")
@@ -121,8 +239,11 @@ func (s *CategoryService) CreateCategory(ctx context.Context, input request.Cate
 			UpdatedAt: parseTime(data["updated_at"]),
 		},
 		Title: toString(data["title"]),
+		Slug:  toString(data["slug"]),
 	}
 
+	s.cache.invalidate()
+	publishEvent(ctx, s.events, categoryEvent(EventActionCreated, *category))
 	return category, nil
 }
 
@@ -173,8 +294,11 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, id string, input r
 			UpdatedAt: parseTime(data["updated_at"]),
 		},
 		Title: toString(data["title"]),
+		Slug:  toString(data["slug"]),
 	}
 
+	s.cache.invalidate()
+	publishEvent(ctx, s.events, categoryEvent(EventActionUpdated, *category))
 	return category, nil
 }
 
@@ -218,5 +342,55 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, id string) error {
 		return middleware.InternalError("Failed to delete category")
 	}
 
+	s.cache.invalidate()
+	publishEvent(ctx, s.events, NewDomainEvent(EventEntityCategory, EventActionDeleted, id))
 	return nil
 }
+
+// IsSlugAvailable нормализует slug так же, как генератор, и проверяет, свободен ли он среди категорий.
+// Возвращает нормализованный slug и признак доступности.
+func (s *CategoryService) IsSlugAvailable(ctx context.Context, slug, excludeID string) (string, bool, error) {
+	ctx, span := categoryTracer.Start(ctx, "CategoryService.IsSlugAvailable")
+	defer span.End()
+
+	normalized := normalizeSlug(slug)
+	span.SetAttributes(attribute.String("category.slug", normalized))
+	if normalized == "" {
+		return "", false, middleware.ValidationError("Slug must contain at least one letter or digit")
+	}
+
+	exists, err := s.categoryRepo.SlugExists(ctx, normalized, excludeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", false, middleware.InternalError(fmt.Sprintf("Failed to check category slug: %v", err))
+	}
+
+	return normalized, !exists, nil
+}
+
+// GetCategoryStats получает статистику по всем категориям: количество курсов и уроков.
+// Категории без курсов включаются с нулевыми значениями.
+func (s *CategoryService) GetCategoryStats(ctx context.Context) ([]response.CategoryStatsDTO, error) {
+	ctx, span := categoryTracer.Start(ctx, "CategoryService.GetCategoryStats")
+	defer span.End()
+
+	data, err := s.categoryRepo.GetStats(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get category stats: %v", err))
+	}
+
+	stats := make([]response.CategoryStatsDTO, 0, len(data))
+	for _, item := range data {
+		stats = append(stats, response.CategoryStatsDTO{
+			ID:          toString(item["id"]),
+			Title:       toString(item["title"]),
+			CourseCount: toInt(item["course_count"]),
+			LessonCount: toInt(item["lesson_count"]),
+		})
+	}
+
+	return stats, nil
+}
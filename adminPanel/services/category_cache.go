@@ -0,0 +1,105 @@
+package services
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"adminPanel/models"
+)
+
+// categoryCache хранит список категорий и отдельные категории в памяти на время ttl.
+// Потокобезопасен; нулевой указатель означает отключенный кэш.
+type categoryCache struct {
+	ttl time.Duration
+
+	mu          sync.RWMutex
+	list        []models.Category
+	listExpires time.Time
+	items       map[string]categoryCacheItem
+}
+
+// categoryCacheItem - закэшированная категория со временем истечения.
+type categoryCacheItem struct {
+	category  models.Category
+	expiresAt time.Time
+}
+
+// newCategoryCache создает кэш категорий. При ttl <= 0 возвращает nil (кэш отключен).
+func newCategoryCache(ttl time.Duration) *categoryCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &categoryCache{ttl: ttl, items: make(map[string]categoryCacheItem)}
+}
+
+// getList возвращает копию закэшированного списка категорий, если он не истек.
+func (c *categoryCache) getList() ([]models.Category, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.list == nil || time.Now().After(c.listExpires) {
+		return nil, false
+	}
+	return slices.Clone(c.list), true
+}
+
+// setList сохраняет копию списка категорий.
+func (c *categoryCache) setList(categories []models.Category) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.list = slices.Clone(categories)
+	c.listExpires = time.Now().Add(c.ttl)
+}
+
+// get возвращает копию закэшированной категории, если она не истекла.
+func (c *categoryCache) get(id string) (*models.Category, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, ok := c.items[id]
+	if !ok || time.Now().After(item.expiresAt) {
+		return nil, false
+	}
+	category := item.category
+	return &category, true
+}
+
+// set сохраняет копию категории. Истекшие записи удаляются, чтобы кэш не рос бесконечно.
+func (c *categoryCache) set(category *models.Category) {
+	if c == nil || category == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, item := range c.items {
+		if now.After(item.expiresAt) {
+			delete(c.items, id)
+		}
+	}
+	c.items[category.ID] = categoryCacheItem{category: *category, expiresAt: now.Add(c.ttl)}
+}
+
+// invalidate очищает и список, и отдельные категории.
+func (c *categoryCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.list = nil
+	c.items = make(map[string]categoryCacheItem)
+}
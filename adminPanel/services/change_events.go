@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"adminPanel/config"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Сущности, об изменении которых публикуются события для publicSide.
+const (
+	ChangeEntityCategory = EventEntityCategory
+	ChangeEntityCourse   = EventEntityCourse
+)
+
+// Действия над сущностями в событиях изменения.
+const (
+	ChangeActionCreate = "create"
+	ChangeActionUpdate = "update"
+	ChangeActionDelete = "delete"
+)
+
+// ChangeEvent - событие изменения контента, по которому публичная часть сбрасывает свой кэш.
+// Формат JSON совпадает с тем, что ожидает publicSide.
+type ChangeEvent struct {
+	Entity     string `json:"entity"`                // category или course.
+	Action     string `json:"action"`                // create, update или delete.
+	ID         string `json:"id"`                    // ID измененной сущности.
+	CategoryID string `json:"category_id,omitempty"` // Категория курса (для перемещения - новая категория).
+}
+
+// changeEventsTracer трассировщик для публикации событий изменения.
+var changeEventsTracer = otel.Tracer("admin-panel/change-events")
+
+// changeActions сопоставляет действия доменных событий с действиями в формате publicSide.
+var changeActions = map[string]string{
+	EventActionCreated: ChangeActionCreate,
+	EventActionUpdated: ChangeActionUpdate,
+	EventActionDeleted: ChangeActionDelete,
+}
+
+// ChangePublisher публикует события изменения контента в канал PostgreSQL NOTIFY.
+// Подключается к шине доменных событий как подписчик (см. NewEventPublisher).
+// Нулевой указатель означает, что канал не настроен: публичная часть полагается только на TTL своего кэша.
+type ChangePublisher struct {
+	repo    *repositories.ChangeEventRepository
+	channel string
+}
+
+// NewChangePublisher создает публикатор событий. Если канал в настройках не задан, возвращает nil.
+func NewChangePublisher(repo *repositories.ChangeEventRepository, cfg config.EventsConfig) *ChangePublisher {
+	if cfg.Channel == "" {
+		return nil
+	}
+	return &ChangePublisher{repo: repo, channel: cfg.Channel}
+}
+
+// Subscriber возвращает публикатор как подписчика шины доменных событий или nil, если канал не настроен.
+// Нулевой *ChangePublisher нельзя передавать в интерфейс напрямую: интерфейс с нулевым указателем не равен nil.
+func (p *ChangePublisher) Subscriber() EventPublisher {
+	if p == nil {
+		return nil
+	}
+	return p
+}
+
+// Publish отправляет доменное событие категории или курса в формате ChangeEvent.
+// События уроков пропускаются: publicSide на них не подписан и сбросил бы по ним весь кэш.
+// Ошибка отправки не отменяет изменение: устаревшие данные в кэше публичной части истекут по TTL.
+func (p *ChangePublisher) Publish(ctx context.Context, event DomainEvent) error {
+	if p == nil || (event.Entity != ChangeEntityCategory && event.Entity != ChangeEntityCourse) {
+		return nil
+	}
+
+	ctx, span := changeEventsTracer.Start(ctx, "ChangePublisher.Publish")
+	span.SetAttributes(
+		attribute.String("event.entity", event.Entity),
+		attribute.String("event.action", event.Action),
+		attribute.String("event.id", event.EntityID),
+	)
+	defer span.End()
+
+	payload, err := json.Marshal(ChangeEvent{
+		Entity:     event.Entity,
+		Action:     changeActions[event.Action],
+		ID:         event.EntityID,
+		CategoryID: event.CategoryID,
+	})
+	if err == nil {
+		err = p.repo.Notify(ctx, p.channel, string(payload))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("notify %s: %w", p.channel, err)
+	}
+	return nil
+}
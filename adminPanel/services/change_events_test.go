@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"adminPanel/config"
+)
+
+func TestChangePublisherWithoutChannel(t *testing.T) {
+	p := NewChangePublisher(nil, config.EventsConfig{})
+	if p != nil {
+		t.Fatalf("NewChangePublisher() = %v, want nil without a channel", p)
+	}
+	if sub := p.Subscriber(); sub != nil {
+		t.Errorf("Subscriber() = %v, want nil interface", sub)
+	}
+	if err := p.Publish(context.Background(), DomainEvent{Entity: ChangeEntityCourse, EntityID: "course"}); err != nil {
+		t.Errorf("Publish() error = %v, want nil", err)
+	}
+}
+
+func TestChangePublisherSkipsLessonEvents(t *testing.T) {
+	// Репозиторий не задан: событие урока должно быть пропущено до обращения к БД.
+	p := NewChangePublisher(nil, config.EventsConfig{Channel: "content_changes"})
+	if err := p.Publish(context.Background(), DomainEvent{Entity: "lesson", Action: EventActionUpdated, EntityID: "lesson"}); err != nil {
+		t.Errorf("Publish() error = %v, want nil", err)
+	}
+}
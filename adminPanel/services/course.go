@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
+	"adminPanel/config"
 	"adminPanel/handlers/dto/request"
 	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
@@ -14,6 +16,15 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Режимы удаления курса.
+const (
+	// CourseDeleteModeHard физически удаляет строку курса вместе с уроками.
+	CourseDeleteModeHard = "hard"
+	// CourseDeleteModeSoft помечает курс удаленным через deleted_at с возможностью восстановления.
+	CourseDeleteModeSoft = "soft"
 )
 
 // CourseService предоставляет бизнес-логику для работы с курсами.
@@ -21,6 +32,9 @@ import (
 type CourseService struct {
 	courseRepo   *repositories.CourseRepository
 	categoryRepo *repositories.CategoryRepository
+	config       config.CourseConfig
+	searchConfig config.SearchConfig
+	events       EventPublisher
 }
 
 // courseTracer трассировщик для сервиса курсов.
@@ -28,17 +42,71 @@ type CourseService struct {
 var courseTracer = otel.Tracer("admin-panel/course-service")
 
 // NewCourseService создает новый экземпляр CourseService.
-// Принимает репозитории для курсов и категорий.
+// Принимает репозитории для курсов и категорий, настройки курсов, ограничения поиска
+// и шину доменных событий (nil - события не публикуются).
 func NewCourseService(
 	courseRepo *repositories.CourseRepository,
 	categoryRepo *repositories.CategoryRepository,
+	cfg config.CourseConfig,
+	searchCfg config.SearchConfig,
+	events EventPublisher,
 ) *CourseService {
 	return &CourseService{
 		courseRepo:   courseRepo,
 		categoryRepo: categoryRepo,
+		config:       cfg,
+		searchConfig: searchCfg,
+		events:       events,
 	}
 }
 
+// courseEvent создает доменное событие курса с его состоянием после изменения.
+func courseEvent(action string, course models.Course) DomainEvent {
+	event := NewDomainEvent(EventEntityCourse, action, course.ID)
+	event.CategoryID = course.CategoryID
+	event.Data = course
+	return event
+}
+
+// courseDeletedEvent создает доменное событие удаления курса из категории.
+func courseDeletedEvent(id, categoryID string) DomainEvent {
+	event := NewDomainEvent(EventEntityCourse, EventActionDeleted, id)
+	event.CategoryID = categoryID
+	return event
+}
+
+// toCourseModel преобразует строку результата запроса в models.Course.
+func toCourseModel(data map[string]interface{}) models.Course {
+	return models.Course{
+		BaseModel: models.BaseModel{
+			ID:        toString(data["id"]),
+			CreatedAt: parseTime(data["created_at"]),
+			UpdatedAt: parseTime(data["updated_at"]),
+		},
+		Title:         toString(data["title"]),
+		Slug:          toString(data["slug"]),
+		Description:   toString(data["description"]),
+		Level:         toString(data["level"]),
+		CategoryID:    toString(data["category_id"]),
+		CategoryTitle: toString(data["category_title"]),
+		Visibility:    toString(data["visibility"]),
+		ImageKey:      toString(data["image_key"]),
+		ContentPolicy: toString(data["content_policy"]),
+		DeletedAt:     parseNullableTime(data["deleted_at"]),
+	}
+}
+
+// applyLevelFilter разбирает список уровней сложности filter.Level в filter.Levels.
+// Недопустимый уровень дает ошибку 400 VALIDATION_ERROR с его значением.
+func applyLevelFilter(filter *request.CourseFilter) error {
+	levels, err := request.ParseLevels(filter.Level)
+	if err != nil {
+		return middleware.NewAppError(err.Error(), 400, "VALIDATION_ERROR")
+	}
+	filter.Levels = levels
+	return nil
+}
+
 // GetCourses получает курсы с фильтрами и пагинацией из request.CourseFilter.
 // Возвращает пагинированный ответ с курсами.
 func (s *CourseService) GetCourses(ctx context.Context, filter request.CourseFilter) (*response.PaginatedCoursesResponse, error) {
@@ -47,6 +115,7 @@ func (s *CourseService) GetCourses(ctx context.Context, filter request.CourseFil
 		attribute.String("filter.level", filter.Level),
 		attribute.String("filter.visibility", filter.Visibility),
 		attribute.String("filter.category_id", filter.CategoryID),
+		attribute.Bool("filter.include_deleted", filter.IncludeDeleted),
 		attribute.Int("filter.page", filter.Page),
 		attribute.Int("filter.limit", filter.Limit),
 	)
@@ -59,6 +128,10 @@ func (s *CourseService) GetCourses(ctx context.Context, filter request.CourseFil
 		filter.Limit = 20
 	}
 
+	if err := applyLevelFilter(&filter); err != nil {
+		return nil, err
+	}
+
 	categoryExists, err := s.categoryRepo.Exists(ctx, filter.CategoryID)
 	if err != nil {
 		span.RecordError(err)
@@ -76,22 +149,167 @@ func (s *CourseService) GetCourses(ctx context.Context, filter request.CourseFil
 		return nil, middleware.InternalError(fmt.Sprintf("Failed to get courses: %v", err))
 	}
 
+	return paginatedCourses(data, total, filter), nil
+}
+
+// SearchCourses выполняет полнотекстовый поиск курсов категории по заголовку и описанию.
+// Поддерживает те же фильтры и пагинацию, что и GetCourses; результаты отсортированы по релевантности.
+// Запрос короче SearchConfig.MinQueryLength возвращает ошибку валидации, а по всем страницам
+// доступно не больше SearchConfig.MaxResults результатов.
+func (s *CourseService) SearchCourses(ctx context.Context, query string, filter request.CourseFilter) (*response.PaginatedCoursesResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.SearchCourses")
+	span.SetAttributes(
+		attribute.String("search.query", query),
+		attribute.String("filter.category_id", filter.CategoryID),
+		attribute.Int("filter.page", filter.Page),
+		attribute.Int("filter.limit", filter.Limit),
+	)
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, middleware.NewAppError("Search query 'q' must not be empty", 400, "VALIDATION_ERROR")
+	}
+	if minLength := s.searchConfig.MinQueryLength; utf8.RuneCountInString(query) < minLength {
+		return nil, middleware.NewAppError(
+			fmt.Sprintf("Search query 'q' must be at least %d characters long", minLength),
+			400,
+			"VALIDATION_ERROR",
+		)
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 20
+	}
+	maxResults := s.searchConfig.MaxResults
+	if maxResults > 0 && filter.Limit > maxResults {
+		filter.Limit = maxResults
+	}
+	span.SetAttributes(attribute.Int("search.max_results", maxResults))
+
+	if err := applyLevelFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	categoryExists, err := s.categoryRepo.Exists(ctx, filter.CategoryID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to check category: %v", err))
+	}
+	if !categoryExists {
+		return nil, middleware.NotFoundError("Category", filter.CategoryID)
+	}
+
+	data, total, err := s.courseRepo.Search(ctx, query, filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to search courses: %v", err))
+	}
+
+	data, total = capSearchResults(data, total, filter, maxResults)
+	return paginatedCourses(data, total, filter), nil
+}
+
+// GetAllCourses получает курсы всех категорий с фильтрами и пагинацией из request.CourseFilter.
+// CategoryID необязателен: если он задан, категория должна существовать. Непустой filter.Search
+// включает полнотекстовый поиск с теми же ограничениями, что и SearchCourses.
+// Каждый курс в ответе содержит название своей категории.
+func (s *CourseService) GetAllCourses(ctx context.Context, filter request.CourseFilter) (*response.PaginatedCoursesResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.GetAllCourses")
+	span.SetAttributes(
+		attribute.String("filter.level", filter.Level),
+		attribute.String("filter.visibility", filter.Visibility),
+		attribute.String("filter.category_id", filter.CategoryID),
+		attribute.String("search.query", filter.Search),
+		attribute.Bool("filter.include_deleted", filter.IncludeDeleted),
+		attribute.Int("filter.page", filter.Page),
+		attribute.Int("filter.limit", filter.Limit),
+	)
+	defer span.End()
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 20
+	}
+
+	search := strings.TrimSpace(filter.Search)
+	maxResults := 0
+	if search != "" {
+		if minLength := s.searchConfig.MinQueryLength; utf8.RuneCountInString(search) < minLength {
+			return nil, middleware.NewAppError(
+				fmt.Sprintf("Search query 'q' must be at least %d characters long", minLength),
+				400,
+				"VALIDATION_ERROR",
+			)
+		}
+		maxResults = s.searchConfig.MaxResults
+		if maxResults > 0 && filter.Limit > maxResults {
+			filter.Limit = maxResults
+		}
+	}
+
+	if err := applyLevelFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Visibility != "" && !request.IsValidCourseVisibility(filter.Visibility) {
+		return nil, middleware.NewAppError("Visibility must be one of: draft, public", 400, "VALIDATION_ERROR")
+	}
+
+	if filter.CategoryID != "" {
+		categoryExists, err := s.categoryRepo.Exists(ctx, filter.CategoryID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to check category: %v", err))
+		}
+		if !categoryExists {
+			return nil, middleware.NotFoundError("Category", filter.CategoryID)
+		}
+	}
+
+	data, total, err := s.courseRepo.GetFilteredWithCategory(ctx, filter, search)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get courses: %v", err))
+	}
+
+	data, total = capSearchResults(data, total, filter, maxResults)
+	return paginatedCourses(data, total, filter), nil
+}
+
+// capSearchResults ограничивает страницу результатов поиска и их общее количество значением maxResults,
+// чтобы по всем страницам было доступно не больше maxResults курсов. При maxResults <= 0 ограничения нет.
+func capSearchResults(data []map[string]interface{}, total int, filter request.CourseFilter, maxResults int) ([]map[string]interface{}, int) {
+	if maxResults <= 0 {
+		return data, total
+	}
+	offset := (filter.Page - 1) * filter.Limit
+	switch {
+	case offset >= maxResults:
+		data = nil
+	case offset+len(data) > maxResults:
+		data = data[:maxResults-offset]
+	}
+	if total > maxResults {
+		total = maxResults
+	}
+	return data, total
+}
+
+// paginatedCourses формирует пагинированный ответ со списком курсов.
+func paginatedCourses(data []map[string]interface{}, total int, filter request.CourseFilter) *response.PaginatedCoursesResponse {
 	courses := make([]models.Course, 0, len(data))
 	for _, item := range data {
-		course := models.Course{
-			BaseModel: models.BaseModel{
-				ID:        toString(item["id"]),
-				CreatedAt: parseTime(item["created_at"]),
-				UpdatedAt: parseTime(item["updated_at"]),
-			},
-			Title:       toString(item["title"]),
-			Description: toString(item["description"]),
-			Level:       toString(item["level"]),
-			CategoryID:  toString(item["category_id"]),
-			Visibility:  toString(item["visibility"]),
-			ImageKey:    toString(item["image_key"]),
-		}
-		courses = append(courses, course)
+		courses = append(courses, toCourseModel(item))
 	}
 
 	pages := (total + filter.Limit - 1) / filter.Limit
@@ -113,17 +331,38 @@ func (s *CourseService) GetCourses(ctx context.Context, filter request.CourseFil
 				Pages: pages,
 			},
 		},
-	}, nil
+	}
 }
 
 // GetCourse получает курс по ID в заданной категории.
+// Мягко удаленные курсы считаются отсутствующими.
 // Возвращает ответ с курсом или ошибку, если не найден.
 func (s *CourseService) GetCourse(ctx context.Context, categoryID, id string) (*response.CourseResponse, error) {
+	return s.getCourse(ctx, categoryID, id, false)
+}
+
+// GetCourseIncludingDeleted получает курс по ID в заданной категории, включая мягко удаленные.
+func (s *CourseService) GetCourseIncludingDeleted(ctx context.Context, categoryID, id string) (*response.CourseResponse, error) {
+	return s.getCourse(ctx, categoryID, id, true)
+}
+
+// getCourse получает курс по ID в заданной категории.
+// includeDeleted определяет, учитываются ли мягко удаленные курсы.
+func (s *CourseService) getCourse(ctx context.Context, categoryID, id string, includeDeleted bool) (*response.CourseResponse, error) {
 	ctx, span := courseTracer.Start(ctx, "CourseService.GetCourse")
-	span.SetAttributes(attribute.String("course.id", id))
+	span.SetAttributes(
+		attribute.String("course.id", id),
+		attribute.Bool("course.include_deleted", includeDeleted),
+	)
 	defer span.End()
 
-	data, err := s.courseRepo.GetByID(ctx, id)
+	var data map[string]interface{}
+	var err error
+	if includeDeleted {
+		data, err = s.courseRepo.GetByIDIncludingDeleted(ctx, id)
+	} else {
+		data, err = s.courseRepo.GetByID(ctx, id)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -140,26 +379,14 @@ func (s *CourseService) GetCourse(ctx context.Context, categoryID, id string) (*
 
 	course := &response.CourseResponse{
 		Status: "success",
-		Data: models.Course{
-			BaseModel: models.BaseModel{
-				ID:        toString(data["id"]),
-				CreatedAt: parseTime(data["created_at"]),
-				UpdatedAt: parseTime(data["updated_at"]),
-			},
-			Title:       toString(data["title"]),
-			Description: toString(data["description"]),
-			Level:       toString(data["level"]),
-			CategoryID:  toString(data["category_id"]),
-			Visibility:  toString(data["visibility"]),
-			ImageKey:    toString(data["image_key"]),
-		},
+		Data:   toCourseModel(data),
 	}
 
 	return course, nil
 }
 
 // CreateCourse создает новый курс на основе данных из request.CourseCreate.
-// Проверяет существование категории и устанавливает значения по умолчанию.
+// Проверяет существование категории, устанавливает значения по умолчанию и генерирует уникальный в категории slug.
 // Возвращает ответ с созданным курсом.
 func (s *CourseService) CreateCourse(ctx context.Context, input request.CourseCreate) (*response.CourseResponse, error) {
 	ctx, span := courseTracer.Start(ctx, "CourseService.CreateCourse")
@@ -188,8 +415,29 @@ func (s *CourseService) CreateCourse(ctx context.Context, input request.CourseCr
 	if strings.TrimSpace(input.Visibility) == "" {
 		input.Visibility = "draft"
 	}
+	if input.ContentPolicy == "" {
+		input.ContentPolicy = models.ContentPolicyStrict
+	}
+	if !request.IsValidContentPolicy(input.ContentPolicy) {
+		return nil, middleware.ValidationError(fmt.Sprintf("Unknown content policy '%s' (allowed: strict, relaxed)", input.ContentPolicy))
+	}
 
-	data, err := s.courseRepo.Create(ctx, input)
+	var data map[string]interface{}
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(input.Title, "course", func(slug string) (bool, error) {
+			return s.courseRepo.SlugExists(ctx, input.CategoryID, slug, "")
+		})
+		if slugErr != nil {
+			span.RecordError(slugErr)
+			span.SetStatus(codes.Error, slugErr.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate course slug: %v", slugErr))
+		}
+
+		data, err = s.courseRepo.Create(ctx, input, slug)
+		if !isSlugConflict(err, "idx_course_category_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -198,26 +446,16 @@ func (s *CourseService) CreateCourse(ctx context.Context, input request.CourseCr
 
 	course := &response.CourseResponse{
 		Status: "success",
-		Data: models.Course{
-			BaseModel: models.BaseModel{
-				ID:        toString(data["id"]),
-				CreatedAt: parseTime(data["created_at"]),
-				UpdatedAt: parseTime(data["updated_at"]),
-			},
-			Title:       toString(data["title"]),
-			Description: toString(data["description"]),
-			Level:       toString(data["level"]),
-			CategoryID:  toString(data["category_id"]),
-			Visibility:  toString(data["visibility"]),
-			ImageKey:    toString(data["image_key"]),
-		},
+		Data:   toCourseModel(data),
 	}
 
+	publishEvent(ctx, s.events, courseEvent(EventActionCreated, course.Data))
 	return course, nil
 }
 
 // UpdateCourse обновляет курс по ID в категории на основе данных из request.CourseUpdate.
-// Проверяет существование и возвращает ответ с обновленным курсом.
+// Проверяет существование и возвращает ответ с обновленным курсом. Если задана ожидаемая версия
+// (input.Version или input.UnmodifiedSince), а курс с тех пор изменен, возвращает ошибку 409 CONFLICT.
 func (s *CourseService) UpdateCourse(ctx context.Context, categoryID, id string, input request.CourseUpdate) (*response.CourseResponse, error) {
 	ctx, span := courseTracer.Start(ctx, "CourseService.UpdateCourse")
 	span.SetAttributes(
@@ -240,7 +478,12 @@ func (s *CourseService) UpdateCourse(ctx context.Context, categoryID, id string,
 		return nil, middleware.NotFoundError("Course", id)
 	}
 
+	if input.ContentPolicy != "" && !request.IsValidContentPolicy(input.ContentPolicy) {
+		return nil, middleware.ValidationError(fmt.Sprintf("Unknown content policy '%s' (allowed: strict, relaxed)", input.ContentPolicy))
+	}
+
 	input.CategoryID = categoryID
+	span.SetAttributes(attribute.Bool("course.conditional", input.Version != nil || input.UnmodifiedSince != nil))
 
 	data, err := s.courseRepo.Update(ctx, id, input)
 	if err != nil {
@@ -248,57 +491,458 @@ func (s *CourseService) UpdateCourse(ctx context.Context, categoryID, id string,
 		span.SetStatus(codes.Error, err.Error())
 		return nil, middleware.InternalError(fmt.Sprintf("Failed to update course: %v", err))
 	}
+	if data == nil {
+		// Курс существовал при проверке выше, значит, его успели изменить (или удалить) после
+		// версии, на основе которой клиент сделал правку.
+		return nil, middleware.NewAppError("Course was modified by another request; reload it and retry the update", 409, "CONFLICT")
+	}
+
+	course := &response.CourseResponse{
+		Status: "success",
+		Data:   toCourseModel(data),
+	}
+
+	publishEvent(ctx, s.events, courseEvent(EventActionUpdated, course.Data))
+	return course, nil
+}
+
+// PatchCourse частично обновляет курс по ID в категории: изменяются только поля, переданные в patch.
+// Проверяет значения переданных полей и существование курса в категории.
+// Возвращает ответ с обновленным курсом.
+func (s *CourseService) PatchCourse(ctx context.Context, categoryID, id string, patch request.CoursePatch) (*response.CourseResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.PatchCourse")
+	span.SetAttributes(
+		attribute.String("course.id", id),
+		attribute.String("course.category_id", categoryID),
+	)
+	defer span.End()
+
+	if patch.IsEmpty() {
+		return nil, middleware.ValidationError("At least one field must be provided")
+	}
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" || utf8.RuneCountInString(title) > maxTitleLength {
+			return nil, middleware.ValidationError("Title must be between 1 and 255 characters")
+		}
+		patch.Title = &title
+	}
+	if patch.Level != nil && !request.IsValidCourseLevel(*patch.Level) {
+		return nil, middleware.ValidationError("Level must be one of: hard, medium, easy")
+	}
+	if patch.Visibility != nil && !request.IsValidCourseVisibility(*patch.Visibility) {
+		return nil, middleware.ValidationError("Visibility must be one of: draft, public")
+	}
+	if patch.ContentPolicy != nil && !request.IsValidContentPolicy(*patch.ContentPolicy) {
+		return nil, middleware.ValidationError(fmt.Sprintf("Unknown content policy '%s' (allowed: strict, relaxed)", *patch.ContentPolicy))
+	}
+
+	existing, err := s.courseRepo.GetByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to check course: %v", err))
+	}
+
+	if existing == nil || toString(existing["category_id"]) != categoryID {
+		return nil, middleware.NotFoundError("Course", id)
+	}
+
+	data, err := s.courseRepo.Patch(ctx, id, patch)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to update course: %v", err))
+	}
+	if data == nil {
+		return nil, middleware.NotFoundError("Course", id)
+	}
 
 	course := &response.CourseResponse{
 		Status: "success",
-		Data: models.Course{
-			BaseModel: models.BaseModel{
-				ID:        toString(data["id"]),
-				CreatedAt: parseTime(data["created_at"]),
-				UpdatedAt: parseTime(data["updated_at"]),
-			},
-			Title:       toString(data["title"]),
-			Description: toString(data["description"]),
-			Level:       toString(data["level"]),
-			CategoryID:  toString(data["category_id"]),
-			Visibility:  toString(data["visibility"]),
-			ImageKey:    toString(data["image_key"]),
-		},
+		Data:   toCourseModel(data),
 	}
 
+	publishEvent(ctx, s.events, courseEvent(EventActionUpdated, course.Data))
 	return course, nil
 }
 
 // DeleteCourse удаляет курс по ID в заданной категории.
-// Проверяет существование перед удалением.
-func (s *CourseService) DeleteCourse(ctx context.Context, categoryID, id string) error {
+// mode выбирает режим удаления (CourseDeleteModeHard или CourseDeleteModeSoft);
+// пустое значение означает режим по умолчанию из настроек курсов.
+// Жесткое удаление допускается и для мягко удаленного курса; уроки курса удаляются вместе с ним.
+// Возвращает ключи изображений S3, которые после жесткого удаления больше не используются
+// и могут быть удалены вызывающим; при мягком удалении изображения сохраняются для восстановления.
+func (s *CourseService) DeleteCourse(ctx context.Context, categoryID, id, mode string) ([]string, error) {
 	ctx, span := courseTracer.Start(ctx, "CourseService.DeleteCourse")
-	span.SetAttributes(attribute.String("course.id", id))
 	defer span.End()
 
-	existing, err := s.courseRepo.GetByID(ctx, id)
+	if mode == "" {
+		mode = CourseDeleteModeHard
+		if s.config.SoftDelete {
+			mode = CourseDeleteModeSoft
+		}
+	}
+	span.SetAttributes(
+		attribute.String("course.id", id),
+		attribute.String("course.delete_mode", mode),
+	)
+
+	if mode != CourseDeleteModeHard && mode != CourseDeleteModeSoft {
+		return nil, middleware.ValidationError("Mode must be one of: hard, soft")
+	}
+
+	var existing map[string]interface{}
+	var err error
+	if mode == CourseDeleteModeSoft {
+		existing, err = s.courseRepo.GetByID(ctx, id)
+	} else {
+		existing, err = s.courseRepo.GetByIDIncludingDeleted(ctx, id)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return middleware.InternalError(fmt.Sprintf("Failed to check course: %v", err))
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to check course: %v", err))
 	}
 
 	if existing == nil || toString(existing["category_id"]) != categoryID {
-		return middleware.NotFoundError("Course", id)
+		return nil, middleware.NotFoundError("Course", id)
 	}
 
-	deleted, err := s.courseRepo.Delete(ctx, id)
+	var deleted bool
+	var imageKeys []string
+	if mode == CourseDeleteModeSoft {
+		deleted, err = s.courseRepo.SoftDelete(ctx, id)
+	} else {
+		deleted, imageKeys, err = s.deleteCourseWithLessons(ctx, categoryID, id)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return middleware.InternalError(fmt.Sprintf("Failed to delete course: %v", err))
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to delete course: %v", err))
 	}
 
 	if !deleted {
-		return middleware.InternalError("Failed to delete course")
+		return nil, middleware.InternalError("Failed to delete course")
 	}
 
-	return nil
+	publishEvent(ctx, s.events, courseDeletedEvent(id, categoryID))
+	return imageKeys, nil
+}
+
+// deleteCourseWithLessons физически удаляет курс и его уроки в одной транзакции, поэтому уроки
+// без курса не остаются даже без каскадного внешнего ключа в базе. Возвращает признак удаления
+// и ключ изображения курса, если на него больше не ссылается ни один курс (копии используют
+// то же изображение).
+func (s *CourseService) deleteCourseWithLessons(ctx context.Context, categoryID, id string) (bool, []string, error) {
+	span := trace.SpanFromContext(ctx)
+
+	deleted, lessons, imageKey, err := s.courseRepo.DeleteWithLessons(ctx, categoryID, id)
+	if err != nil || !deleted {
+		return deleted, nil, err
+	}
+	span.SetAttributes(attribute.Int64("course.deleted_lessons", lessons))
+
+	if imageKey == "" {
+		return true, nil, nil
+	}
+	inUse, err := s.courseRepo.ImageKeyInUse(ctx, imageKey)
+	if err != nil {
+		// Курс уже удален; неиспользуемое изображение лишь останется в хранилище.
+		span.RecordError(err)
+		return true, nil, nil
+	}
+	if inUse {
+		return true, nil, nil
+	}
+	return true, []string{imageKey}, nil
+}
+
+// BulkDeleteCourses удаляет несколько курсов категории.
+// Режим удаления выбирается так же, как в DeleteCourse. Мягкое удаление выполняется одной
+// транзакцией, жесткое — для каждого курса отдельно тем же путем, что и в DeleteCourse.
+// Ошибка удаления одного курса не прерывает обработку остальных; результат содержит статус
+// для каждого ID. Также возвращает ключи изображений S3, которые после жесткого удаления
+// больше не используются и могут быть удалены вызывающим.
+func (s *CourseService) BulkDeleteCourses(ctx context.Context, categoryID string, ids []string, mode string) ([]response.BulkDeleteItem, []string, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.BulkDeleteCourses")
+	defer span.End()
+
+	if mode == "" {
+		mode = CourseDeleteModeHard
+		if s.config.SoftDelete {
+			mode = CourseDeleteModeSoft
+		}
+	}
+	span.SetAttributes(
+		attribute.String("course.category_id", categoryID),
+		attribute.String("course.delete_mode", mode),
+		attribute.Int("courses.requested", len(ids)),
+	)
+
+	if mode != CourseDeleteModeHard && mode != CourseDeleteModeSoft {
+		return nil, nil, middleware.ValidationError("Mode must be one of: hard, soft")
+	}
+
+	categoryExists, err := s.categoryRepo.Exists(ctx, categoryID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, middleware.InternalError(fmt.Sprintf("Failed to check category: %v", err))
+	}
+	if !categoryExists {
+		return nil, nil, middleware.NotFoundError("Category", categoryID)
+	}
+
+	var results []repositories.BulkDeleteResult
+	var imageKeys []string
+	if mode == CourseDeleteModeSoft {
+		results, err = s.courseRepo.SoftDeleteMany(ctx, categoryID, ids)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, nil, middleware.InternalError(fmt.Sprintf("Failed to delete courses: %v", err))
+		}
+	} else {
+		results = make([]repositories.BulkDeleteResult, 0, len(ids))
+		for _, id := range ids {
+			deleted, keys, err := s.deleteCourseWithLessons(ctx, categoryID, id)
+			results = append(results, repositories.BulkDeleteResult{ID: id, Deleted: deleted, Err: err})
+			imageKeys = append(imageKeys, keys...)
+		}
+	}
+
+	items := make([]response.BulkDeleteItem, 0, len(results))
+	deleted := 0
+	for _, result := range results {
+		item := response.BulkDeleteItem{ID: result.ID}
+		switch {
+		case result.Err != nil:
+			span.RecordError(result.Err)
+			item.Status = response.BulkDeleteStatusError
+			item.Error = result.Err.Error()
+		case result.Deleted:
+			item.Status = response.BulkDeleteStatusDeleted
+			deleted++
+			publishEvent(ctx, s.events, courseDeletedEvent(result.ID, categoryID))
+		default:
+			item.Status = response.BulkDeleteStatusNotFound
+		}
+		items = append(items, item)
+	}
+	span.SetAttributes(attribute.Int("courses.deleted", deleted))
+
+	return items, imageKeys, nil
+}
+
+// RestoreCourse отменяет мягкое удаление курса по ID в заданной категории.
+// Возвращает ошибку NotFound, если курс не существует, и Conflict, если курс не был удален.
+func (s *CourseService) RestoreCourse(ctx context.Context, categoryID, id string) (*response.CourseResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.RestoreCourse")
+	span.SetAttributes(attribute.String("course.id", id))
+	defer span.End()
+
+	existing, err := s.courseRepo.GetByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to check course: %v", err))
+	}
+
+	if existing == nil || toString(existing["category_id"]) != categoryID {
+		return nil, middleware.NotFoundError("Course", id)
+	}
+
+	if existing["deleted_at"] == nil {
+		return nil, middleware.NewAppError("Course is not deleted", 409, "COURSE_NOT_DELETED")
+	}
+
+	data, err := s.courseRepo.Restore(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to restore course: %v", err))
+	}
+
+	if data == nil {
+		return nil, middleware.NewAppError("Course is not deleted", 409, "COURSE_NOT_DELETED")
+	}
+
+	restored := toCourseModel(data)
+	publishEvent(ctx, s.events, courseEvent(EventActionUpdated, restored))
+	return &response.CourseResponse{
+		Status: "success",
+		Data:   restored,
+	}, nil
+}
+
+// cloneTitleSuffix добавляется к заголовку копии курса или урока.
+// maxTitleLength ограничивает длину заголовка размером колонки title (в символах).
+const (
+	cloneTitleSuffix = " (копия)"
+	maxTitleLength   = 255
+)
+
+// CloneCourse создает копию курса в той же категории вместе со всеми уроками.
+// Копия получает заголовок с суффиксом " (копия)", собственный slug и статус draft; image_key переиспользуется.
+// Возвращает ответ с созданным курсом.
+func (s *CourseService) CloneCourse(ctx context.Context, categoryID, courseID string) (*response.CourseResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.CloneCourse")
+	span.SetAttributes(
+		attribute.String("course.id", courseID),
+		attribute.String("course.category_id", categoryID),
+	)
+	defer span.End()
+
+	source, err := s.getCourse(ctx, categoryID, courseID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	title := source.Data.Title
+	if maxRunes := maxTitleLength - utf8.RuneCountInString(cloneTitleSuffix); utf8.RuneCountInString(title) > maxRunes {
+		title = string([]rune(title)[:maxRunes])
+	}
+	title += cloneTitleSuffix
+
+	var newID string
+	var copied int64
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(title, "course", func(slug string) (bool, error) {
+			return s.courseRepo.SlugExists(ctx, categoryID, slug, "")
+		})
+		if slugErr != nil {
+			span.RecordError(slugErr)
+			span.SetStatus(codes.Error, slugErr.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate course slug: %v", slugErr))
+		}
+
+		newID, copied, err = s.courseRepo.Clone(ctx, courseID, title, slug)
+		if !isSlugConflict(err, "idx_course_category_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to clone course: %v", err))
+	}
+
+	span.AddEvent("course cloned", trace.WithAttributes(
+		attribute.String("course.source_id", courseID),
+		attribute.String("course.destination_id", newID),
+		attribute.Int64("lessons.copied", copied),
+	))
+
+	course, err := s.getCourse(ctx, categoryID, newID, false)
+	if err != nil {
+		return nil, err
+	}
+	publishEvent(ctx, s.events, courseEvent(EventActionCreated, course.Data))
+	return course, nil
+}
+
+// MoveCourse переносит курс из категории fromCategoryID в toCategoryID.
+// Проверяет, что категории различаются и существуют, а курс принадлежит исходной категории.
+// Slug курса сохраняется, если он свободен в целевой категории, иначе подбирается свободный вариант.
+// Возвращает ответ с перенесенным курсом.
+func (s *CourseService) MoveCourse(ctx context.Context, fromCategoryID, courseID, toCategoryID string) (*response.CourseResponse, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.MoveCourse")
+	span.SetAttributes(
+		attribute.String("course.id", courseID),
+		attribute.String("course.category_id", fromCategoryID),
+		attribute.String("course.target_category_id", toCategoryID),
+	)
+	defer span.End()
+
+	if strings.EqualFold(fromCategoryID, toCategoryID) {
+		return nil, middleware.NewAppError("Course already belongs to the target category", 400, "SAME_CATEGORY")
+	}
+
+	for _, categoryID := range []string{fromCategoryID, toCategoryID} {
+		exists, err := s.courseRepo.ExistsByCategory(ctx, categoryID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to check category: %v", err))
+		}
+		if !exists {
+			return nil, middleware.NotFoundError("Category", categoryID)
+		}
+	}
+
+	source, err := s.getCourse(ctx, fromCategoryID, courseID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(source.Data.Slug, "course", func(slug string) (bool, error) {
+			return s.courseRepo.SlugExists(ctx, toCategoryID, slug, courseID)
+		})
+		if slugErr != nil {
+			span.RecordError(slugErr)
+			span.SetStatus(codes.Error, slugErr.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate course slug: %v", slugErr))
+		}
+
+		data, err = s.courseRepo.Move(ctx, courseID, fromCategoryID, toCategoryID, slug)
+		if !isSlugConflict(err, "idx_course_category_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to move course: %v", err))
+	}
+
+	if data == nil {
+		return nil, middleware.NotFoundError("Course", courseID)
+	}
+
+	moved := toCourseModel(data)
+	publishEvent(ctx, s.events, courseEvent(EventActionUpdated, moved))
+	return &response.CourseResponse{
+		Status: "success",
+		Data:   moved,
+	}, nil
+}
+
+// IsImageKeyInUse сообщает, используется ли изображение с ключом imageKey каким-либо курсом.
+func (s *CourseService) IsImageKeyInUse(ctx context.Context, imageKey string) (bool, error) {
+	inUse, err := s.courseRepo.ImageKeyInUse(ctx, imageKey)
+	if err != nil {
+		return false, middleware.InternalError(fmt.Sprintf("Failed to check image usage: %v", err))
+	}
+	return inUse, nil
+}
+
+// GetDeletedCourses получает мягко удаленные курсы всех категорий с пагинацией,
+// отсортированные по времени удаления (сначала последние). Используется для отмены удаления.
+// Возвращает курсы и их общее количество.
+func (s *CourseService) GetDeletedCourses(ctx context.Context, page, limit int) ([]models.Course, int, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.GetDeletedCourses")
+	span.SetAttributes(
+		attribute.Int("filter.page", page),
+		attribute.Int("filter.limit", limit),
+	)
+	defer span.End()
+
+	data, total, err := s.courseRepo.GetDeleted(ctx, limit, (page-1)*limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, middleware.InternalError(fmt.Sprintf("Failed to get deleted courses: %v", err))
+	}
+
+	courses := make([]models.Course, 0, len(data))
+	for _, item := range data {
+		courses = append(courses, toCourseModel(item))
+	}
+
+	return courses, total, nil
 }
 
 // GetCategoryCourses получает все курсы для заданной категории.
@@ -328,20 +972,31 @@ func (s *CourseService) GetCategoryCourses(ctx context.Context, categoryID strin
 
 	courses := make([]models.Course, 0, len(data))
 	for _, item := range data {
-		course := models.Course{
-			BaseModel: models.BaseModel{
-				ID:        toString(item["id"]),
-				CreatedAt: parseTime(item["created_at"]),
-				UpdatedAt: parseTime(item["updated_at"]),
-			},
-			Title:       toString(item["title"]),
-			Description: toString(item["description"]),
-			Level:       toString(item["level"]),
-			CategoryID:  toString(item["category_id"]),
-			Visibility:  toString(item["visibility"]),
-		}
-		courses = append(courses, course)
+		courses = append(courses, toCourseModel(item))
 	}
 
 	return courses, nil
 }
+
+// IsSlugAvailable нормализует slug так же, как генератор, и проверяет, свободен ли он среди курсов категории.
+// Возвращает нормализованный slug и признак доступности.
+func (s *CourseService) IsSlugAvailable(ctx context.Context, categoryID, slug, excludeID string) (string, bool, error) {
+	ctx, span := courseTracer.Start(ctx, "CourseService.IsSlugAvailable")
+	span.SetAttributes(attribute.String("course.category_id", categoryID))
+	defer span.End()
+
+	normalized := normalizeSlug(slug)
+	span.SetAttributes(attribute.String("course.slug", normalized))
+	if normalized == "" {
+		return "", false, middleware.ValidationError("Slug must contain at least one letter or digit")
+	}
+
+	exists, err := s.courseRepo.SlugExists(ctx, categoryID, normalized, excludeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", false, middleware.InternalError(fmt.Sprintf("Failed to check course slug: %v", err))
+	}
+
+	return normalized, !exists, nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/repositories"
+	"adminPanel/testutil"
+)
+
+func TestSearchCoursesRejectsShortQuery(t *testing.T) {
+	s := NewCourseService(nil, nil, config.CourseConfig{}, config.SearchConfig{MinQueryLength: 2, MaxResults: 200}, nil)
+
+	for _, query := range []string{"", "   ", "a", " я "} {
+		_, err := s.SearchCourses(context.Background(), query, request.CourseFilter{CategoryID: "category"})
+
+		var appErr *middleware.AppError
+		if !errors.As(err, &appErr) || appErr.StatusCode != 400 || appErr.Code != "VALIDATION_ERROR" {
+			t.Errorf("SearchCourses(%q) error = %v, want 400 VALIDATION_ERROR", query, err)
+		}
+	}
+}
+
+func TestCapSearchResults(t *testing.T) {
+	rows := func(n int) []map[string]interface{} {
+		return make([]map[string]interface{}, n)
+	}
+
+	tests := []struct {
+		name       string
+		data       int
+		total      int
+		page       int
+		limit      int
+		maxResults int
+		wantData   int
+		wantTotal  int
+	}{
+		{name: "no cap", data: 20, total: 500, page: 3, limit: 20, maxResults: 0, wantData: 20, wantTotal: 500},
+		{name: "under cap", data: 5, total: 5, page: 1, limit: 20, maxResults: 50, wantData: 5, wantTotal: 5},
+		{name: "page crosses cap", data: 20, total: 500, page: 3, limit: 20, maxResults: 50, wantData: 10, wantTotal: 50},
+		{name: "page past cap", data: 20, total: 500, page: 4, limit: 20, maxResults: 50, wantData: 0, wantTotal: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := request.CourseFilter{Page: tt.page, Limit: tt.limit}
+			data, total := capSearchResults(rows(tt.data), tt.total, filter, tt.maxResults)
+			if len(data) != tt.wantData || total != tt.wantTotal {
+				t.Errorf("capSearchResults() = %d rows, total %d, want %d rows, total %d", len(data), total, tt.wantData, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestDeleteCourseRemovesLessons(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	s := NewCourseService(repositories.NewCourseRepository(db), repositories.NewCategoryRepository(db),
+		config.CourseConfig{}, config.SearchConfig{}, nil)
+
+	categoryID := testutil.CreateCategory(t, db)
+	shared := testutil.CreateCourse(t, db, categoryID, "shared image", "easy", "draft")
+	copied := testutil.CreateCourse(t, db, categoryID, "shared image copy", "easy", "draft")
+	own := testutil.CreateCourse(t, db, categoryID, "own image", "easy", "draft")
+	for id, key := range map[string]string{shared: "courses/shared.png", copied: "courses/shared.png", own: "courses/own.png"} {
+		if _, err := db.Pool.Exec(ctx, `UPDATE knowledge_base.course_b SET image_key = $1 WHERE id = $2`, key, id); err != nil {
+			t.Fatalf("set image key: %v", err)
+		}
+		testutil.CreateLessons(t, db, id, 3)
+	}
+
+	tests := []struct {
+		name     string
+		courseID string
+		wantKeys []string
+	}{
+		// Изображение еще используется копией курса, поэтому его ключ не возвращается.
+		{name: "image still in use", courseID: shared, wantKeys: nil},
+		{name: "last course with image", courseID: copied, wantKeys: []string{"courses/shared.png"}},
+		{name: "own image", courseID: own, wantKeys: []string{"courses/own.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := s.DeleteCourse(ctx, categoryID, tt.courseID, CourseDeleteModeHard)
+			if err != nil {
+				t.Fatalf("DeleteCourse() error = %v", err)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("DeleteCourse() image keys = %v, want %v", keys, tt.wantKeys)
+			}
+
+			var courses, lessons int
+			err = db.Pool.QueryRow(ctx, `
+				SELECT (SELECT count(*) FROM knowledge_base.course_b WHERE id = $1),
+				       (SELECT count(*) FROM knowledge_base.lesson_d WHERE course_id = $1)
+			`, tt.courseID).Scan(&courses, &lessons)
+			if err != nil {
+				t.Fatalf("count rows: %v", err)
+			}
+			if courses != 0 || lessons != 0 {
+				t.Errorf("after delete: %d courses, %d lessons, want none", courses, lessons)
+			}
+		})
+	}
+}
+
+func TestBulkDeleteCoursesReturnsUnusedImageKeys(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	s := NewCourseService(repositories.NewCourseRepository(db), repositories.NewCategoryRepository(db),
+		config.CourseConfig{}, config.SearchConfig{}, nil)
+
+	categoryID := testutil.CreateCategory(t, db)
+	shared := testutil.CreateCourse(t, db, categoryID, "shared image", "easy", "draft")
+	copied := testutil.CreateCourse(t, db, categoryID, "shared image copy", "easy", "draft")
+	kept := testutil.CreateCourse(t, db, categoryID, "kept copy", "easy", "draft")
+	for _, id := range []string{shared, copied, kept} {
+		if _, err := db.Pool.Exec(ctx, `UPDATE knowledge_base.course_b SET image_key = $1 WHERE id = $2`, "courses/bulk.png", id); err != nil {
+			t.Fatalf("set image key: %v", err)
+		}
+		testutil.CreateLessons(t, db, id, 2)
+	}
+
+	items, keys, err := s.BulkDeleteCourses(ctx, categoryID, []string{shared, copied}, CourseDeleteModeHard)
+	if err != nil {
+		t.Fatalf("BulkDeleteCourses() error = %v", err)
+	}
+	for _, item := range items {
+		if item.Status != response.BulkDeleteStatusDeleted {
+			t.Errorf("course %s status = %q, want deleted", item.ID, item.Status)
+		}
+	}
+	// Изображение еще используется третьим курсом.
+	if len(keys) != 0 {
+		t.Errorf("image keys = %v, want none", keys)
+	}
+
+	var lessons int
+	if err := db.Pool.QueryRow(ctx, `SELECT count(*) FROM knowledge_base.lesson_d WHERE course_id = ANY($1)`,
+		[]string{shared, copied}).Scan(&lessons); err != nil {
+		t.Fatalf("count lessons: %v", err)
+	}
+	if lessons != 0 {
+		t.Errorf("%d lessons left after bulk delete, want none", lessons)
+	}
+
+	_, keys, err = s.BulkDeleteCourses(ctx, categoryID, []string{kept}, CourseDeleteModeHard)
+	if err != nil {
+		t.Fatalf("BulkDeleteCourses() error = %v", err)
+	}
+	if !slices.Equal(keys, []string{"courses/bulk.png"}) {
+		t.Errorf("image keys = %v, want [courses/bulk.png]", keys)
+	}
+}
+
+func TestUpdateCourseRejectsStaleEdit(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	s := NewCourseService(repositories.NewCourseRepository(db), repositories.NewCategoryRepository(db),
+		config.CourseConfig{}, config.SearchConfig{}, nil)
+
+	categoryID := testutil.CreateCategory(t, db)
+	courseID := testutil.CreateCourse(t, db, categoryID, "original", "easy", "draft")
+	loaded, err := s.GetCourse(ctx, categoryID, courseID)
+	if err != nil {
+		t.Fatalf("GetCourse() error = %v", err)
+	}
+	version := loaded.Data.UpdatedAt
+	// Заголовок If-Unmodified-Since передает время с точностью до секунды.
+	lastModified := version.Truncate(time.Second)
+
+	// Первый администратор сохраняет правку на основе загруженной версии.
+	updated, err := s.UpdateCourse(ctx, categoryID, courseID, request.CourseUpdate{Title: "first edit", Version: &version})
+	if err != nil {
+		t.Fatalf("UpdateCourse() with current version error = %v", err)
+	}
+	if updated.Data.Title != "first edit" {
+		t.Errorf("title = %q, want %q", updated.Data.Title, "first edit")
+	}
+
+	// Время правки сдвигается на час вперед, чтобы она была позже загруженной версии
+	// и при сравнении с точностью до секунды.
+	if _, err := db.Pool.Exec(ctx, `UPDATE knowledge_base.course_b SET updated_at = $1 WHERE id = $2`,
+		version.Add(time.Hour).UTC(), courseID); err != nil {
+		t.Fatalf("shift updated_at: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input request.CourseUpdate
+	}{
+		{name: "stale version", input: request.CourseUpdate{Title: "second edit", Version: &version}},
+		{name: "stale If-Unmodified-Since", input: request.CourseUpdate{Title: "second edit", UnmodifiedSince: &lastModified}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.UpdateCourse(ctx, categoryID, courseID, tt.input)
+
+			var appErr *middleware.AppError
+			if !errors.As(err, &appErr) || appErr.StatusCode != 409 || appErr.Code != "CONFLICT" {
+				t.Fatalf("UpdateCourse() error = %v, want 409 CONFLICT", err)
+			}
+		})
+	}
+
+	current, err := s.GetCourse(ctx, categoryID, courseID)
+	if err != nil {
+		t.Fatalf("GetCourse() error = %v", err)
+	}
+	if current.Data.Title != "first edit" {
+		t.Errorf("title after stale edits = %q, want %q", current.Data.Title, "first edit")
+	}
+
+	// Без версии правка применяется как раньше.
+	if _, err := s.UpdateCourse(ctx, categoryID, courseID, request.CourseUpdate{Title: "forced edit"}); err != nil {
+		t.Errorf("UpdateCourse() without version error = %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"adminPanel/config"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Сущности, о которых публикуются доменные события.
+const (
+	EventEntityCategory = "category"
+	EventEntityCourse   = "course"
+	EventEntityLesson   = "lesson"
+)
+
+// Действия в доменных событиях. Тип события составляется как "<сущность>.<действие>", например course.updated.
+const (
+	EventActionCreated = "created"
+	EventActionUpdated = "updated"
+	EventActionDeleted = "deleted"
+)
+
+// DomainEvent - единый конверт доменного события, публикуемого сервисами после успешного изменения.
+// Data содержит состояние сущности после изменения; для удаления остается пустым.
+type DomainEvent struct {
+	ID         string    `json:"id"`                    // Уникальный ID события (UUID).
+	Type       string    `json:"type"`                  // <entity>.<action>, например lesson.deleted.
+	Entity     string    `json:"entity"`                // category, course или lesson.
+	Action     string    `json:"action"`                // created, updated или deleted.
+	EntityID   string    `json:"entity_id"`             // ID измененной сущности.
+	CategoryID string    `json:"category_id,omitempty"` // Категория курса (для перемещения - новая категория).
+	CourseID   string    `json:"course_id,omitempty"`   // Курс урока.
+	OccurredAt time.Time `json:"occurred_at"`           // Время изменения (UTC).
+	Data       any       `json:"data,omitempty"`        // Состояние сущности после изменения.
+}
+
+// NewDomainEvent создает событие с новым ID и текущим временем.
+func NewDomainEvent(entity, action, entityID string) DomainEvent {
+	return DomainEvent{
+		ID:         uuid.New().String(),
+		Type:       entity + "." + action,
+		Entity:     entity,
+		Action:     action,
+		EntityID:   entityID,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// EventPublisher публикует доменные события. Через него сервисы сообщают об изменениях,
+// не зная о побочных эффектах (сброс кэша, вебхуки, индексация), которые на них подписаны.
+// Реализация должна быть безопасна для одновременного использования.
+type EventPublisher interface {
+	Publish(ctx context.Context, event DomainEvent) error
+}
+
+// NoopEventPublisher отбрасывает все события. Используется, когда шина событий не настроена.
+type NoopEventPublisher struct{}
+
+// Publish ничего не делает.
+func (NoopEventPublisher) Publish(context.Context, DomainEvent) error {
+	return nil
+}
+
+// LoggingEventPublisher записывает каждое событие в лог в формате JSON.
+type LoggingEventPublisher struct{}
+
+// Publish записывает событие в лог.
+func (LoggingEventPublisher) Publish(_ context.Context, event DomainEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("📣 Domain event %s: %s", event.Type, payload)
+	return nil
+}
+
+// multiEventPublisher передает событие каждому из публикаторов по очереди.
+// Ошибка одного публикатора не мешает остальным; все ошибки объединяются.
+type multiEventPublisher []EventPublisher
+
+// Publish передает событие всем публикаторам.
+func (m multiEventPublisher) Publish(ctx context.Context, event DomainEvent) error {
+	var errs []error
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewEventPublisher собирает шину доменных событий из настроек: основной публикатор по
+// DOMAIN_EVENTS_PUBLISHER (noop или log) и дополнительные подписчики, например ChangePublisher.
+// Нулевые подписчики пропускаются. Будущие реализации (NATS, Kafka) подключаются здесь же.
+func NewEventPublisher(cfg config.EventsConfig, subscribers ...EventPublisher) EventPublisher {
+	var publishers multiEventPublisher
+	if cfg.Publisher == "log" {
+		publishers = append(publishers, LoggingEventPublisher{})
+	}
+	for _, subscriber := range subscribers {
+		if subscriber != nil {
+			publishers = append(publishers, subscriber)
+		}
+	}
+
+	switch len(publishers) {
+	case 0:
+		return NoopEventPublisher{}
+	case 1:
+		return publishers[0]
+	default:
+		return publishers
+	}
+}
+
+// domainEventsTracer трассировщик для публикации доменных событий.
+var domainEventsTracer = otel.Tracer("admin-panel/domain-events")
+
+// publishEvent публикует событие после успешного изменения.
+// Ошибка публикации не отменяет изменение: она записывается в лог и в span.
+// Нулевой publisher равнозначен NoopEventPublisher.
+func publishEvent(ctx context.Context, publisher EventPublisher, event DomainEvent) {
+	if publisher == nil {
+		return
+	}
+
+	ctx, span := domainEventsTracer.Start(ctx, "EventPublisher.Publish")
+	span.SetAttributes(
+		attribute.String("event.id", event.ID),
+		attribute.String("event.type", event.Type),
+		attribute.String("event.entity_id", event.EntityID),
+	)
+	defer span.End()
+
+	if err := publisher.Publish(ctx, event); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		log.Printf("⚠️  Failed to publish %s event for %s: %v", event.Type, event.EntityID, err)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/request"
+	"adminPanel/repositories"
+	"adminPanel/testutil"
+
+	"github.com/google/uuid"
+)
+
+// recordingPublisher запоминает опубликованные события и возвращает err на каждую публикацию.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []DomainEvent
+	err    error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event DomainEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return p.err
+}
+
+// take возвращает накопленные события и очищает список.
+func (p *recordingPublisher) take() []DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := p.events
+	p.events = nil
+	return events
+}
+
+func TestNewEventPublisher(t *testing.T) {
+	sub := &recordingPublisher{}
+
+	if _, ok := NewEventPublisher(config.EventsConfig{}).(NoopEventPublisher); !ok {
+		t.Error("NewEventPublisher() without publishers is not NoopEventPublisher")
+	}
+	if _, ok := NewEventPublisher(config.EventsConfig{Publisher: "log"}, nil).(LoggingEventPublisher); !ok {
+		t.Error("NewEventPublisher(log) is not LoggingEventPublisher")
+	}
+	if got := NewEventPublisher(config.EventsConfig{Publisher: "noop"}, sub); got != EventPublisher(sub) {
+		t.Errorf("NewEventPublisher() with one subscriber = %T, want the subscriber", got)
+	}
+
+	bus := NewEventPublisher(config.EventsConfig{Publisher: "log"}, sub)
+	event := NewDomainEvent(EventEntityCourse, EventActionCreated, "course")
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if events := sub.take(); len(events) != 1 || events[0].ID != event.ID {
+		t.Errorf("subscriber got %v, want the published event", events)
+	}
+}
+
+func TestMultiEventPublisherDeliversToAll(t *testing.T) {
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	first := &recordingPublisher{err: errFirst}
+	ok := &recordingPublisher{}
+	second := &recordingPublisher{err: errSecond}
+
+	err := multiEventPublisher{first, ok, second}.Publish(context.Background(), NewDomainEvent(EventEntityLesson, EventActionDeleted, "lesson"))
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Publish() error = %v, want both errors joined", err)
+	}
+	for i, p := range []*recordingPublisher{first, ok, second} {
+		if n := len(p.take()); n != 1 {
+			t.Errorf("publisher %d got %d events, want 1", i, n)
+		}
+	}
+}
+
+func TestNewDomainEvent(t *testing.T) {
+	event := NewDomainEvent(EventEntityCategory, EventActionUpdated, "category")
+	if event.Type != "category.updated" || event.Entity != EventEntityCategory || event.Action != EventActionUpdated {
+		t.Errorf("NewDomainEvent() = %+v, want type category.updated", event)
+	}
+	if _, err := uuid.Parse(event.ID); err != nil {
+		t.Errorf("event ID %q is not a UUID", event.ID)
+	}
+	if event.OccurredAt.IsZero() || event.OccurredAt.Location().String() != "UTC" {
+		t.Errorf("OccurredAt = %v, want current UTC time", event.OccurredAt)
+	}
+
+	// Ошибка публикации не должна прерывать изменение.
+	publishEvent(context.Background(), &recordingPublisher{err: errors.New("broker down")}, event)
+	publishEvent(context.Background(), nil, event)
+}
+
+func TestServicesPublishDomainEvents(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx := context.Background()
+	events := &recordingPublisher{}
+	contentCfg := config.ContentConfig{}
+	categoryRepo := repositories.NewCategoryRepository(db)
+	courseRepo := repositories.NewCourseRepository(db)
+	categories := NewCategoryService(categoryRepo, config.CategoryConfig{}, events)
+	courses := NewCourseService(courseRepo, categoryRepo, config.CourseConfig{}, config.SearchConfig{}, events)
+	lessons := NewLessonService(repositories.NewLessonRepository(db, contentCfg, false), courseRepo, contentCfg, events)
+
+	// expect проверяет события, опубликованные после предыдущего вызова.
+	expect := func(t *testing.T, entityID string, wantTypes ...string) {
+		t.Helper()
+		got := events.take()
+		if len(got) != len(wantTypes) {
+			t.Fatalf("published %d events %v, want %v", len(got), got, wantTypes)
+		}
+		for i, event := range got {
+			if event.Type != wantTypes[i] || event.EntityID != entityID {
+				t.Errorf("event %d = %s for %s, want %s for %s", i, event.Type, event.EntityID, wantTypes[i], entityID)
+			}
+		}
+	}
+
+	t.Run("category", func(t *testing.T) {
+		category, err := categories.CreateCategory(ctx, request.CategoryCreate{Title: "events " + uuid.NewString()})
+		if err != nil {
+			t.Fatalf("CreateCategory() error = %v", err)
+		}
+		t.Cleanup(func() {
+			_, _ = db.Pool.Exec(context.Background(), `DELETE FROM knowledge_base.category_d WHERE id = $1`, category.ID)
+		})
+		expect(t, category.ID, "category.created")
+
+		if _, err := categories.UpdateCategory(ctx, category.ID, request.CategoryUpdate{Title: "events " + uuid.NewString()}); err != nil {
+			t.Fatalf("UpdateCategory() error = %v", err)
+		}
+		expect(t, category.ID, "category.updated")
+
+		if err := categories.DeleteCategory(ctx, category.ID); err != nil {
+			t.Fatalf("DeleteCategory() error = %v", err)
+		}
+		expect(t, category.ID, "category.deleted")
+	})
+
+	categoryID := testutil.CreateCategory(t, db)
+	var courseID string
+
+	t.Run("course", func(t *testing.T) {
+		created, err := courses.CreateCourse(ctx, request.CourseCreate{Title: "events course", CategoryID: categoryID})
+		if err != nil {
+			t.Fatalf("CreateCourse() error = %v", err)
+		}
+		courseID = created.Data.ID
+		expect(t, courseID, "course.created")
+
+		if _, err := courses.UpdateCourse(ctx, categoryID, courseID, request.CourseUpdate{Title: "events course v2"}); err != nil {
+			t.Fatalf("UpdateCourse() error = %v", err)
+		}
+		expect(t, courseID, "course.updated")
+
+		// Неудачное изменение не публикует событий.
+		missing := uuid.NewString()
+		if _, err := courses.UpdateCourse(ctx, categoryID, missing, request.CourseUpdate{Title: "missing"}); err == nil {
+			t.Fatal("UpdateCourse() of a missing course error = nil")
+		}
+		expect(t, missing)
+	})
+	if courseID == "" {
+		t.Fatal("course was not created")
+	}
+
+	t.Run("lesson", func(t *testing.T) {
+		created, err := lessons.CreateLesson(ctx, courseID, request.LessonCreate{Title: "events lesson", Content: "<p>v1</p>"})
+		if err != nil {
+			t.Fatalf("CreateLesson() error = %v", err)
+		}
+		lessonID := created.Data.ID
+		expect(t, lessonID, "lesson.created")
+
+		if _, err := lessons.UpdateLesson(ctx, lessonID, courseID, request.LessonUpdate{Content: "<p>v2</p>"}); err != nil {
+			t.Fatalf("UpdateLesson() error = %v", err)
+		}
+		expect(t, lessonID, "lesson.updated")
+
+		if err := lessons.DeleteLesson(ctx, lessonID, courseID); err != nil {
+			t.Fatalf("DeleteLesson() error = %v", err)
+		}
+		expect(t, lessonID, "lesson.deleted")
+	})
+
+	if _, err := courses.DeleteCourse(ctx, categoryID, courseID, CourseDeleteModeHard); err != nil {
+		t.Fatalf("DeleteCourse() error = %v", err)
+	}
+	expect(t, courseID, "course.deleted")
+}
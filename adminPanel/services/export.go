@@ -0,0 +1,417 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"adminPanel/handlers/dto/request"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/models"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Поддерживаемые форматы экспорта категории.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// exportTracer трассировщик для сервиса экспорта.
+var exportTracer = otel.Tracer("admin-panel/export-service")
+
+// categoryCSVHeader заголовок CSV-экспорта: один урок на строку, данные категории и курса повторяются.
+var categoryCSVHeader = []string{
+	"category_id", "category_title",
+	"course_id", "course_title", "course_slug", "course_description", "course_level", "course_visibility", "course_image_key",
+	"lesson_id", "lesson_title", "lesson_order_index", "lesson_content",
+}
+
+// ExportService выгружает категории вместе с курсами и уроками в JSON или CSV
+// и восстанавливает их из JSON-выгрузки.
+type ExportService struct {
+	categoryService *CategoryService
+	courseRepo      *repositories.CourseRepository
+	lessonRepo      *repositories.LessonRepository
+	importRepo      *repositories.ImportRepository
+}
+
+// NewExportService создает новый экземпляр ExportService.
+// Принимает сервис категорий и репозитории курсов, уроков и импорта.
+func NewExportService(
+	categoryService *CategoryService,
+	courseRepo *repositories.CourseRepository,
+	lessonRepo *repositories.LessonRepository,
+	importRepo *repositories.ImportRepository,
+) *ExportService {
+	return &ExportService{
+		categoryService: categoryService,
+		courseRepo:      courseRepo,
+		lessonRepo:      lessonRepo,
+		importRepo:      importRepo,
+	}
+}
+
+// ValidateExportFormat проверяет формат экспорта. Пустое значение означает JSON.
+func ValidateExportFormat(format string) (string, error) {
+	switch format {
+	case "", ExportFormatJSON:
+		return ExportFormatJSON, nil
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	default:
+		return "", middleware.NewAppError(
+			fmt.Sprintf("Unsupported export format '%s'. Allowed: json, csv", format),
+			400,
+			"VALIDATION_ERROR",
+		)
+	}
+}
+
+// ExportCategory сериализует категорию, ее курсы (без мягко удаленных) и уроки с контентом
+// в JSON или CSV и возвращает результат целиком. Для больших категорий следует использовать
+// StreamCategoryJSON и StreamCategoryCSV, которые не буферизуют выгрузку.
+func (s *ExportService) ExportCategory(ctx context.Context, categoryID string, format string) ([]byte, error) {
+	ctx, span := exportTracer.Start(ctx, "ExportService.ExportCategory")
+	span.SetAttributes(
+		attribute.String("category.id", categoryID),
+		attribute.String("export.format", format),
+	)
+	defer span.End()
+
+	format, err := ValidateExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if format == ExportFormatCSV {
+		err = s.StreamCategoryCSV(ctx, categoryID, &buf)
+	} else {
+		err = s.StreamCategoryJSON(ctx, categoryID, &buf)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StreamCategoryJSON записывает JSON-выгрузку категории (response.CategoryExport) в w по мере
+// чтения из базы данных: сначала заголовок с категорией, затем курсы, а уроки каждого курса
+// по одному, так что в памяти одновременно находится не больше одного урока.
+// Существование категории проверяется до записи, чтобы ошибку можно было вернуть статусом ответа.
+func (s *ExportService) StreamCategoryJSON(ctx context.Context, categoryID string, w io.Writer) error {
+	category, err := s.categoryService.GetCategory(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	// Encoder завершает каждое значение переводом строки, поэтому каждый урок занимает отдельную строку.
+	enc := json.NewEncoder(w)
+	write := func(raw string) error {
+		_, err := io.WriteString(w, raw)
+		return err
+	}
+
+	if err := write(fmt.Sprintf(`{"schema_version":%d,"exported_at":`, response.CategoryExportSchemaVersion)); err != nil {
+		return err
+	}
+	if err := enc.Encode(time.Now().UTC()); err != nil {
+		return err
+	}
+	if err := write(`,"category":`); err != nil {
+		return err
+	}
+	if err := enc.Encode(category); err != nil {
+		return err
+	}
+	if err := write(`,"courses":[` + "\n"); err != nil {
+		return err
+	}
+
+	courses := 0
+	err = s.forEachCourse(ctx, categoryID, func(course models.Course) error {
+		// Поля курса встраиваются в объект CourseExport, поэтому закрывающая скобка курса
+		// заменяется массивом уроков.
+		courseJSON, err := json.Marshal(course)
+		if err != nil {
+			return err
+		}
+		open := strings.TrimSuffix(string(courseJSON), "}")
+		if open != "{" {
+			open += ","
+		}
+		if courses > 0 {
+			open = "," + open
+		}
+		courses++
+		if err := write(open + `"lessons":[` + "\n"); err != nil {
+			return err
+		}
+
+		lessons := 0
+		err = s.lessonRepo.ForEachByCourseID(ctx, course.ID, func(lesson models.Lesson) error {
+			if lessons > 0 {
+				if err := write(","); err != nil {
+					return err
+				}
+			}
+			lessons++
+			return enc.Encode(lesson)
+		})
+		if err != nil {
+			return err
+		}
+		return write("]}\n")
+	})
+	if err != nil {
+		return middleware.InternalError(fmt.Sprintf("Failed to export category: %v", err))
+	}
+
+	return write("]}\n")
+}
+
+// StreamCategoryCSV записывает CSV-выгрузку категории в w по мере чтения из базы данных:
+// по одной строке на урок, данные категории и курса повторяются в каждой строке.
+// Курс без уроков выгружается одной строкой с пустыми полями урока.
+// Существование категории проверяется до записи первой строки, чтобы ошибку можно было вернуть статусом ответа.
+func (s *ExportService) StreamCategoryCSV(ctx context.Context, categoryID string, w io.Writer) error {
+	category, err := s.categoryService.GetCategory(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(categoryCSVHeader); err != nil {
+		return err
+	}
+
+	err = s.forEachCourse(ctx, categoryID, func(course models.Course) error {
+		courseFields := []string{
+			category.ID, category.Title,
+			course.ID, course.Title, course.Slug, course.Description, course.Level, course.Visibility, course.ImageKey,
+		}
+
+		lessons := 0
+		err := s.lessonRepo.ForEachByCourseID(ctx, course.ID, func(lesson models.Lesson) error {
+			lessons++
+			return writer.Write(append(courseFields[:len(courseFields):len(courseFields)],
+				lesson.ID, lesson.Title, strconv.Itoa(lesson.OrderIndex), lesson.Content,
+			))
+		})
+		if err != nil {
+			return err
+		}
+		if lessons == 0 {
+			if err := writer.Write(append(courseFields, "", "", "", "")); err != nil {
+				return err
+			}
+		}
+
+		// Сбрасываем буфер после каждого курса, чтобы клиент получал данные по мере выгрузки.
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return middleware.InternalError(fmt.Sprintf("Failed to export category: %v", err))
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportFilename возвращает имя файла выгрузки категории для заголовка Content-Disposition.
+// Используется и как предварительная проверка существования категории перед потоковой выгрузкой.
+func (s *ExportService) ExportFilename(ctx context.Context, categoryID, format string) (string, error) {
+	category, err := s.categoryService.GetCategory(ctx, categoryID)
+	if err != nil {
+		return "", err
+	}
+	name := category.Slug
+	if name == "" {
+		name = category.ID
+	}
+	return fmt.Sprintf("category-%s.%s", name, format), nil
+}
+
+// ImportCategory восстанавливает категорию из JSON, созданного ExportCategory.
+// Категория с тем же заголовком переиспользуется, иначе создается новая; курсы и уроки
+// всегда создаются заново с новыми UUID. Slug из выгрузки сохраняются, если свободны.
+// Импорт выполняется в одной транзакции: при ошибке ничего не создается.
+func (s *ExportService) ImportCategory(ctx context.Context, data []byte) (*response.CategoryImportResult, error) {
+	ctx, span := exportTracer.Start(ctx, "ExportService.ImportCategory")
+	span.SetAttributes(attribute.Int("import.size", len(data)))
+	defer span.End()
+
+	var export response.CategoryExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, middleware.ValidationError(fmt.Sprintf("Invalid export file: %v", err))
+	}
+
+	courses, err := validateCategoryExport(export)
+	if err != nil {
+		return nil, err
+	}
+
+	slug := export.Category.Slug
+	if slug == "" {
+		slug = export.Category.Title
+	}
+
+	result, err := s.importRepo.ImportCategory(ctx, export.Category.Title, slug, courses,
+		func(title string, exists func(string) (bool, error)) (string, error) {
+			return uniqueSlug(title, "imported", exists)
+		})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, middleware.ConflictError("Import conflicts with concurrently created data, please retry")
+		}
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to import category: %v", err))
+	}
+
+	// Импортированные курсы меняют состав категории и в уже существующей категории,
+	// поэтому событие публикуется всегда; локальный кэш хранит только сами категории.
+	action := EventActionUpdated
+	if result.CategoryCreated {
+		s.categoryService.InvalidateCache()
+		action = EventActionCreated
+	}
+	s.categoryService.PublishChange(ctx, action, result.CategoryID)
+
+	span.SetAttributes(
+		attribute.String("category.id", result.CategoryID),
+		attribute.Bool("import.category_created", result.CategoryCreated),
+		attribute.Int("import.courses", result.Courses),
+		attribute.Int("import.lessons", result.Lessons),
+	)
+
+	category, err := s.categoryService.GetCategory(ctx, result.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := 0
+	if result.CategoryCreated {
+		categories = 1
+	}
+
+	return &response.CategoryImportResult{
+		Category:        *category,
+		CategoryCreated: result.CategoryCreated,
+		Categories:      categories,
+		Courses:         result.Courses,
+		Lessons:         result.Lessons,
+	}, nil
+}
+
+// validateCategoryExport проверяет версию схемы и содержимое выгрузки и преобразует курсы
+// в данные для импорта. Возвращает ошибку валидации со списком всех найденных проблем.
+// Пустая видимость курса импортируется как draft.
+func validateCategoryExport(export response.CategoryExport) ([]repositories.CourseImport, error) {
+	if export.SchemaVersion != response.CategoryExportSchemaVersion {
+		return nil, middleware.ValidationError(fmt.Sprintf(
+			"Unsupported export schema version %d, expected %d",
+			export.SchemaVersion, response.CategoryExportSchemaVersion,
+		))
+	}
+
+	var problems []string
+	checkTitle := func(field, title string) {
+		if strings.TrimSpace(title) == "" {
+			problems = append(problems, field+" is required")
+		} else if utf8.RuneCountInString(title) > maxTitleLength {
+			problems = append(problems, fmt.Sprintf("%s must not exceed %d characters", field, maxTitleLength))
+		}
+	}
+
+	checkTitle("category.title", export.Category.Title)
+
+	courses := make([]repositories.CourseImport, 0, len(export.Courses))
+	for i, course := range export.Courses {
+		field := fmt.Sprintf("courses[%d]", i)
+		checkTitle(field+".title", course.Title)
+
+		if !request.IsValidCourseLevel(course.Level) {
+			problems = append(problems, fmt.Sprintf("%s.level has unknown value '%s'", field, course.Level))
+		}
+		visibility := course.Visibility
+		if visibility == "" {
+			visibility = "draft"
+		}
+		if !request.IsValidCourseVisibility(visibility) {
+			problems = append(problems, fmt.Sprintf("%s.visibility has unknown value '%s'", field, course.Visibility))
+		}
+		// Выгрузки, сделанные до появления политик разметки, не содержат content_policy.
+		contentPolicy := course.ContentPolicy
+		if contentPolicy == "" {
+			contentPolicy = models.ContentPolicyStrict
+		}
+		if !request.IsValidContentPolicy(contentPolicy) {
+			problems = append(problems, fmt.Sprintf("%s.content_policy has unknown value '%s'", field, course.ContentPolicy))
+		}
+
+		item := repositories.CourseImport{
+			Title:         course.Title,
+			Slug:          course.Slug,
+			Description:   course.Description,
+			Level:         course.Level,
+			Visibility:    visibility,
+			ImageKey:      course.ImageKey,
+			ContentPolicy: contentPolicy,
+			Lessons:       make([]repositories.LessonImport, 0, len(course.Lessons)),
+		}
+		if item.Slug == "" {
+			item.Slug = course.Title
+		}
+		for j, lesson := range course.Lessons {
+			checkTitle(fmt.Sprintf("%s.lessons[%d].title", field, j), lesson.Title)
+			// Выгрузки, сделанные до появления slug уроков, не содержат slug.
+			lessonSlug := lesson.Slug
+			if lessonSlug == "" {
+				lessonSlug = lesson.Title
+			}
+			item.Lessons = append(item.Lessons, repositories.LessonImport{
+				Title:      lesson.Title,
+				Slug:       lessonSlug,
+				Content:    lesson.Content,
+				OrderIndex: lesson.OrderIndex,
+			})
+		}
+		courses = append(courses, item)
+	}
+
+	if len(problems) > 0 {
+		return nil, middleware.ValidationError("Invalid export file: " + strings.Join(problems, "; "))
+	}
+	return courses, nil
+}
+
+// forEachCourse вызывает fn для каждого не удаленного курса категории.
+func (s *ExportService) forEachCourse(ctx context.Context, categoryID string, fn func(models.Course) error) error {
+	data, err := s.courseRepo.GetByCategory(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	for _, item := range data {
+		if err := fn(toCourseModel(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
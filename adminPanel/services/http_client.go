@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// newHTTPClient создает HTTP-клиент для исходящих запросов с заданными таймаутами.
+// connectTimeout ограничивает установку соединения и TLS-рукопожатие,
+// readTimeout — общее время запроса, включая чтение тела ответа.
+func newHTTPClient(connectTimeout, readTimeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   readTimeout,
+	}
+}
+
+// isTimeoutError проверяет, вызвана ли ошибка истечением таймаута или дедлайна контекста.
+func isTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
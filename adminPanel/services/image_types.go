@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"adminPanel/middleware"
+)
+
+// svgContentType MIME-тип SVG, для которого перед загрузкой выполняется очистка.
+const svgContentType = "image/svg+xml"
+
+// allowedImageTypes содержит допустимые MIME-типы изображений и расширения,
+// используемые, если расширение нельзя взять из имени файла или URL.
+// Общий список для загрузки через сервер (multipart и по URL), где SVG очищается prepareImageBody.
+// Presigned-загрузка в обход сервера принимает те же типы, кроме SVG.
+var allowedImageTypes = map[string]string{
+	"image/jpeg":   ".jpg",
+	"image/jpg":    ".jpg",
+	"image/png":    ".png",
+	"image/gif":    ".gif",
+	"image/webp":   ".webp",
+	"image/avif":   ".avif",
+	svgContentType: ".svg",
+}
+
+// allowedImageTypesText перечисляет допустимые форматы для сообщений об ошибках.
+const allowedImageTypesText = "JPEG, PNG, GIF, WEBP, AVIF, and SVG"
+
+// normalizeImageType приводит Content-Type к виду без параметров и в нижнем регистре
+// (например, "image/svg+xml; charset=utf-8" -> "image/svg+xml").
+func normalizeImageType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
+
+// isValidImageType проверяет, является ли contentType допустимым типом изображения.
+// Поддерживает JPEG, PNG, GIF, WEBP, AVIF и SVG.
+func isValidImageType(contentType string) bool {
+	_, ok := allowedImageTypes[normalizeImageType(contentType)]
+	return ok
+}
+
+// imageExtension возвращает расширение файла для допустимого типа изображения или ".jpg".
+func imageExtension(contentType string) string {
+	if ext, ok := allowedImageTypes[normalizeImageType(contentType)]; ok {
+		return ext
+	}
+	return ".jpg"
+}
+
+// invalidImageTypeError создает ошибку 400 для недопустимого типа изображения.
+func invalidImageTypeError(contentType string) *middleware.AppError {
+	return middleware.NewAppError(
+		fmt.Sprintf("Invalid image type: %s. Only %s are allowed", contentType, allowedImageTypesText),
+		400,
+		"INVALID_IMAGE_TYPE",
+	)
+}
+
+// prepareImageBody возвращает содержимое изображения для загрузки в S3 и его размер.
+// Для SVG содержимое читается целиком (не более maxSize байт) и очищается sanitizeSVG,
+// поэтому размер может измениться; остальные типы передаются без изменений.
+func prepareImageBody(contentType string, src io.Reader, size, maxSize int64) (io.Reader, int64, error) {
+	if normalizeImageType(contentType) != svgContentType {
+		return src, size, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, 0, middleware.NewAppError(
+			fmt.Sprintf("Failed to read SVG image: %v", err),
+			400,
+			"FILE_READ_ERROR",
+		)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, 0, middleware.NewAppError(
+			fmt.Sprintf("Image size exceeds maximum allowed size of %d bytes", maxSize),
+			400,
+			"IMAGE_TOO_LARGE",
+		)
+	}
+
+	clean, err := sanitizeSVG(data)
+	if err != nil {
+		return nil, 0, middleware.NewAppError(
+			fmt.Sprintf("Failed to process SVG image: %v", err),
+			400,
+			"INVALID_SVG",
+		)
+	}
+	return bytes.NewReader(clean), int64(len(clean)), nil
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
+	"adminPanel/config"
 	"adminPanel/handlers/dto/request"
 	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
@@ -12,31 +15,48 @@ import (
 	"adminPanel/repositories"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // LessonService предоставляет бизнес-логику для работы с уроками.
 // Содержит репозитории для уроков и курсов, методы для CRUD операций.
+// requireBlocks требует, чтобы контент уроков был в формате структурированных блоков.
 type LessonService struct {
-	lessonRepo   *repositories.LessonRepository
-	courseRepo   *repositories.CourseRepository
-	lessonTracer trace.Tracer
+	lessonRepo    *repositories.LessonRepository
+	courseRepo    *repositories.CourseRepository
+	requireBlocks bool
+	events        EventPublisher
+	lessonTracer  trace.Tracer
 }
 
 // NewLessonService создает новый экземпляр LessonService.
-// Принимает репозитории для уроков и курсов, инициализирует трассировщик.
+// Принимает репозитории для уроков и курсов, настройки контента и шину доменных событий
+// (nil - события не публикуются), инициализирует трассировщик.
 func NewLessonService(
 	lessonRepo *repositories.LessonRepository,
 	courseRepo *repositories.CourseRepository,
+	contentCfg config.ContentConfig,
+	events EventPublisher,
 ) *LessonService {
 	return &LessonService{
-		lessonRepo:   lessonRepo,
-		courseRepo:   courseRepo,
-		lessonTracer: otel.Tracer("admin-panel/lesson-service"),
+		lessonRepo:    lessonRepo,
+		courseRepo:    courseRepo,
+		requireBlocks: contentCfg.RequireBlocks,
+		events:        events,
+		lessonTracer:  otel.Tracer("admin-panel/lesson-service"),
 	}
 }
 
+// lessonEvent создает доменное событие урока с его состоянием после изменения.
+func lessonEvent(action string, lesson models.Lesson) DomainEvent {
+	event := NewDomainEvent(EventEntityLesson, action, lesson.ID)
+	event.CourseID = lesson.CourseID
+	event.Data = lesson
+	return event
+}
+
 // GetLessons получает уроки для заданного курса с пагинацией и сортировкой из models.QueryList.
 // Проверяет существование курса и возвращает пагинированный ответ с уроками.
 func (s *LessonService) GetLessons(ctx context.Context, courseID string, queryParams models.QueryList) (*response.LessonListResponse, error) {
@@ -50,6 +70,11 @@ func (s *LessonService) GetLessons(ctx context.Context, courseID string, queryPa
 		queryParams.Limit = 20
 	}
 
+	sortBy, sortOrder, err := ParseSort(queryParams.Sort, lessonSortFields, "order_index", "ASC")
+	if err != nil {
+		return nil, err
+	}
+
 	courseExists, err := s.courseRepo.Exists(ctx, courseID)
 	if err != nil {
 		span.RecordError(err)
@@ -60,7 +85,6 @@ func (s *LessonService) GetLessons(ctx context.Context, courseID string, queryPa
 		return nil, middleware.NotFoundError("Course", courseID)
 	}
 
-	sortBy, sortOrder := parseSortParameter(queryParams.Sort)
 	offset := (queryParams.Page - 1) * queryParams.Limit
 
 	total, err := s.lessonRepo.CountByCourseID(ctx, courseID)
@@ -99,6 +123,22 @@ func (s *LessonService) GetLessons(ctx context.Context, courseID string, queryPa
 	}, nil
 }
 
+// GetLessonCounts возвращает количество уроков для каждого из переданных курсов.
+// Курсы без уроков присутствуют в результате со значением 0.
+func (s *LessonService) GetLessonCounts(ctx context.Context, courseIDs []string) (map[string]int, error) {
+	ctx, span := s.lessonTracer.Start(ctx, "LessonService.GetLessonCounts")
+	defer span.End()
+
+	counts, err := s.lessonRepo.CountLessonsByCourseIDs(ctx, courseIDs)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to count lessons: %v", err))
+	}
+
+	return counts, nil
+}
+
 // GetLesson получает урок по ID в заданном курсе.
 // Возвращает ответ с уроком или ошибку, если не найден.
 func (s *LessonService) GetLesson(ctx context.Context, lessonID, courseID string) (*response.LessonResponse, error) {
@@ -124,12 +164,39 @@ func (s *LessonService) GetLesson(ctx context.Context, lessonID, courseID string
 	}, nil
 }
 
+// GetLessonRawContent получает контент урока в том виде, в каком он сохранен (после распаковки при сжатии),
+// без какой-либо обработки для отображения. Используется формой редактирования, чтобы правка
+// не меняла содержимое при сохранении.
+func (s *LessonService) GetLessonRawContent(ctx context.Context, lessonID, courseID string) (string, error) {
+	ctx, span := s.lessonTracer.Start(ctx, "LessonService.GetLessonRawContent")
+	span.SetAttributes(attribute.String("lesson.id", lessonID))
+	defer span.End()
+
+	lesson, err := s.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		span.RecordError(err)
+		return "", middleware.InternalError(fmt.Sprintf("Failed to get lesson: %v", err))
+	}
+
+	if lesson == nil || lesson.CourseID != courseID {
+		return "", middleware.NotFoundError("Lesson", lessonID)
+	}
+
+	span.SetAttributes(attribute.Int("lesson.content_length", len(lesson.Content)))
+	return lesson.Content, nil
+}
+
 // CreateLesson создает новый урок для заданного курса на основе данных из request.LessonCreate.
-// Проверяет существование курса и возвращает ответ с созданным уроком.
+// Проверяет существование курса и формат контента, генерирует уникальный в курсе slug
+// и возвращает ответ с созданным уроком.
 func (s *LessonService) CreateLesson(ctx context.Context, courseID string, input request.LessonCreate) (*response.LessonResponse, error) {
 	ctx, span := s.lessonTracer.Start(ctx, "LessonService.CreateLesson")
 	defer span.End()
 
+	if err := ValidateLessonContent(input.Content, s.requireBlocks); err != nil {
+		return nil, err
+	}
+
 	courseExists, err := s.courseRepo.Exists(ctx, courseID)
 	if err != nil {
 		span.RecordError(err)
@@ -139,11 +206,26 @@ func (s *LessonService) CreateLesson(ctx context.Context, courseID string, input
 		return nil, middleware.NotFoundError("Course", courseID)
 	}
 
-	lesson, err := s.lessonRepo.Create(ctx, courseID, input)
+	var lesson *models.Lesson
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(input.Title, "lesson", func(slug string) (bool, error) {
+			return s.lessonRepo.SlugExists(ctx, courseID, slug)
+		})
+		if slugErr != nil {
+			span.RecordError(slugErr)
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate lesson slug: %v", slugErr))
+		}
+
+		lesson, err = s.lessonRepo.Create(ctx, courseID, input, slug)
+		if !isSlugConflict(err, "idx_lesson_course_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
 	if err != nil {
 		span.RecordError(err)
 		return nil, middleware.InternalError(fmt.Sprintf("Failed to create lesson: %v", err))
 	}
+	publishEvent(ctx, s.events, lessonEvent(EventActionCreated, *lesson))
 
 	return &response.LessonResponse{
 		Status: "success",
@@ -151,12 +233,93 @@ func (s *LessonService) CreateLesson(ctx context.Context, courseID string, input
 	}, nil
 }
 
+// MaxBulkLessons ограничивает количество уроков в одном запросе пакетного создания.
+const MaxBulkLessons = 100
+
+// CreateLessons создает несколько уроков курса в одной транзакции в порядке inputs:
+// уроки добавляются в конец курса с последовательными order_index и получают уникальные в курсе slug.
+// Заголовок и контент каждого урока проверяются до начала вставки; ошибки перечисляются с индексами уроков.
+// При сбое вставки не создается ни один урок, а в ошибке указывается индекс урока, на котором она произошла.
+// Возвращает ответ с созданными уроками в порядке inputs.
+func (s *LessonService) CreateLessons(ctx context.Context, courseID string, inputs []request.LessonCreate) (*response.LessonBulkCreateResponse, error) {
+	ctx, span := s.lessonTracer.Start(ctx, "LessonService.CreateLessons")
+	span.SetAttributes(
+		attribute.String("course.id", courseID),
+		attribute.Int("lessons.count", len(inputs)),
+	)
+	defer span.End()
+
+	if len(inputs) == 0 {
+		return nil, middleware.ValidationError("Lessons list must not be empty")
+	}
+	if len(inputs) > MaxBulkLessons {
+		return nil, middleware.ValidationError(fmt.Sprintf("Too many lessons, maximum is %d", MaxBulkLessons))
+	}
+
+	var problems []string
+	for i := range inputs {
+		inputs[i].Title = strings.TrimSpace(inputs[i].Title)
+		if inputs[i].Title == "" || utf8.RuneCountInString(inputs[i].Title) > maxTitleLength {
+			problems = append(problems, fmt.Sprintf("lessons[%d]: Title must be between 1 and 255 characters", i))
+		}
+		if err := ValidateLessonContent(inputs[i].Content, s.requireBlocks); err != nil {
+			problems = append(problems, fmt.Sprintf("lessons[%d]: %v", i, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, middleware.ValidationError("Invalid lessons: " + strings.Join(problems, "; "))
+	}
+
+	courseExists, err := s.courseRepo.Exists(ctx, courseID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to check course existence: %v", err))
+	}
+	if !courseExists {
+		return nil, middleware.NotFoundError("Course", courseID)
+	}
+
+	generate := func(title string, exists func(slug string) (bool, error)) (string, error) {
+		return uniqueSlug(title, "lesson", exists)
+	}
+
+	var lessons []models.Lesson
+	for attempt := 1; ; attempt++ {
+		lessons, err = s.lessonRepo.CreateMany(ctx, courseID, inputs, generate)
+		if !isSlugConflict(err, "idx_lesson_course_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		var bulkErr *repositories.BulkLessonError
+		if errors.As(err, &bulkErr) {
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to create lesson at index %d, no lessons were created: %v", bulkErr.Index, bulkErr.Err))
+		}
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to create lessons: %v", err))
+	}
+
+	for _, lesson := range lessons {
+		publishEvent(ctx, s.events, lessonEvent(EventActionCreated, lesson))
+	}
+
+	return &response.LessonBulkCreateResponse{
+		Status: "success",
+		Data:   lessons,
+	}, nil
+}
+
 // UpdateLesson обновляет урок по ID в курсе на основе данных из request.LessonUpdate.
-// Проверяет существование и возвращает ответ с обновленным уроком.
+// Проверяет существование и формат контента, возвращает ответ с обновленным уроком.
 func (s *LessonService) UpdateLesson(ctx context.Context, lessonID, courseID string, input request.LessonUpdate) (*response.LessonResponse, error) {
 	ctx, span := s.lessonTracer.Start(ctx, "LessonService.UpdateLesson")
 	defer span.End()
 
+	if err := ValidateLessonContent(input.Content, s.requireBlocks); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.lessonRepo.GetByID(ctx, lessonID)
 	if err != nil {
 		span.RecordError(err)
@@ -171,6 +334,7 @@ func (s *LessonService) UpdateLesson(ctx context.Context, lessonID, courseID str
 		span.RecordError(err)
 		return nil, middleware.InternalError(fmt.Sprintf("Failed to update lesson: %v", err))
 	}
+	publishEvent(ctx, s.events, lessonEvent(EventActionUpdated, *lesson))
 
 	return &response.LessonResponse{
 		Status: "success",
@@ -202,17 +366,113 @@ func (s *LessonService) DeleteLesson(ctx context.Context, lessonID, courseID str
 		return middleware.InternalError("Failed to delete lesson for an unknown reason")
 	}
 
+	event := NewDomainEvent(EventEntityLesson, EventActionDeleted, lessonID)
+	event.CourseID = courseID
+	publishEvent(ctx, s.events, event)
 	return nil
 }
 
-// parseSortParameter разбирает параметр сортировки.
-// Если начинается с "-", то DESC, иначе ASC. По умолчанию "created_at ASC".
-func parseSortParameter(sort string) (sortBy, sortOrder string) {
-	if sort == "" {
-		return "created_at", "ASC"
+// DuplicateLesson создает копию урока курса с заголовком, дополненным суффиксом " (копия)",
+// тем же контентом и в том же курсе. Копия встает сразу после исходного урока.
+// Возвращает ответ с созданным уроком или ошибку NotFound, если урок не принадлежит курсу.
+func (s *LessonService) DuplicateLesson(ctx context.Context, lessonID, courseID string) (*response.LessonResponse, error) {
+	ctx, span := s.lessonTracer.Start(ctx, "LessonService.DuplicateLesson")
+	span.SetAttributes(
+		attribute.String("lesson.source_id", lessonID),
+		attribute.String("course.id", courseID),
+	)
+	defer span.End()
+
+	source, err := s.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get lesson: %v", err))
+	}
+	if source == nil || source.CourseID != courseID {
+		return nil, middleware.NotFoundError("Lesson", lessonID)
+	}
+
+	title := source.Title
+	if maxRunes := maxTitleLength - utf8.RuneCountInString(cloneTitleSuffix); utf8.RuneCountInString(title) > maxRunes {
+		title = string([]rune(title)[:maxRunes])
+	}
+	title += cloneTitleSuffix
+
+	var lesson *models.Lesson
+	for attempt := 1; ; attempt++ {
+		slug, slugErr := uniqueSlug(title, "lesson", func(slug string) (bool, error) {
+			return s.lessonRepo.SlugExists(ctx, courseID, slug)
+		})
+		if slugErr != nil {
+			span.RecordError(slugErr)
+			span.SetStatus(codes.Error, slugErr.Error())
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to generate lesson slug: %v", slugErr))
+		}
+
+		lesson, err = s.lessonRepo.Duplicate(ctx, courseID, lessonID, title, slug)
+		if !isSlugConflict(err, "idx_lesson_course_slug") || attempt == slugInsertAttempts {
+			break
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to duplicate lesson: %v", err))
+	}
+	if lesson == nil {
+		return nil, middleware.NotFoundError("Lesson", lessonID)
+	}
+	span.SetAttributes(
+		attribute.String("lesson.id", lesson.ID),
+		attribute.Int("lesson.order_index", lesson.OrderIndex),
+	)
+	publishEvent(ctx, s.events, lessonEvent(EventActionCreated, *lesson))
+
+	return &response.LessonResponse{
+		Status: "success",
+		Data:   *lesson,
+	}, nil
+}
+
+// ReorderLessons задает новый порядок уроков курса.
+// orderedIDs должен содержать ID всех уроков курса ровно по одному разу.
+func (s *LessonService) ReorderLessons(ctx context.Context, courseID string, orderedIDs []string) error {
+	ctx, span := s.lessonTracer.Start(ctx, "LessonService.ReorderLessons")
+	defer span.End()
+
+	courseExists, err := s.courseRepo.Exists(ctx, courseID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return middleware.InternalError(fmt.Sprintf("Failed to check course existence: %v", err))
 	}
-	if strings.HasPrefix(sort, "-") {
-		return strings.TrimPrefix(sort, "-"), "DESC"
+	if !courseExists {
+		return middleware.NotFoundError("Course", courseID)
 	}
-	return sort, "ASC"
+
+	if err := s.lessonRepo.ReorderLessons(ctx, courseID, orderedIDs); err != nil {
+		if errors.Is(err, repositories.ErrLessonOrderMismatch) {
+			return middleware.ValidationError("Lesson IDs must list every lesson of the course exactly once")
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return middleware.InternalError(fmt.Sprintf("Failed to reorder lessons: %v", err))
+	}
+
+	// Порядок меняется у всех уроков курса сразу, поэтому событие публикуется для каждого.
+	for _, lessonID := range orderedIDs {
+		event := NewDomainEvent(EventEntityLesson, EventActionUpdated, lessonID)
+		event.CourseID = courseID
+		publishEvent(ctx, s.events, event)
+	}
+	return nil
+}
+
+// lessonSortFields поля, по которым можно сортировать список уроков.
+var lessonSortFields = map[string]string{
+	"order_index": "order_index",
+	"title":       "title",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
 }
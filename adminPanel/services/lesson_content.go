@@ -0,0 +1,49 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"adminPanel/middleware"
+	"adminPanel/models"
+)
+
+// ValidateLessonContent проверяет контент урока в формате структурированных блоков:
+// JSON-массив models.ContentBlock, где content_type каждого блока входит в models.KnownContentTypes.
+// Если requireBlocks выключен, контент, не являющийся JSON-массивом (HTML или обычный текст),
+// принимается как раньше, а JSON-массив все равно проверяется. Пустой контент допустим всегда.
+// Возвращает ошибку 422 с индексами блоков неизвестного типа.
+func ValidateLessonContent(content string, requireBlocks bool) error {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return nil
+	}
+
+	isArray := strings.HasPrefix(trimmed, "[") && json.Valid([]byte(trimmed))
+	if !isArray {
+		if requireBlocks {
+			return middleware.ValidationError("Lesson content must be a JSON array of content blocks")
+		}
+		return nil
+	}
+
+	var blocks []models.ContentBlock
+	if err := json.Unmarshal([]byte(trimmed), &blocks); err != nil {
+		return middleware.ValidationError(fmt.Sprintf("Lesson content blocks are malformed: %v", err))
+	}
+
+	var problems []string
+	for i, block := range blocks {
+		if !slices.Contains(models.KnownContentTypes, block.ContentType) {
+			problems = append(problems, fmt.Sprintf("block %d has unknown content_type %q", i, block.ContentType))
+		}
+	}
+	if len(problems) > 0 {
+		return middleware.ValidationError(fmt.Sprintf("Invalid lesson content: %s (allowed: %s)",
+			strings.Join(problems, "; "), strings.Join(models.KnownContentTypes, ", ")))
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Размер пакета при заполнении slug.
+const (
+	DefaultSlugBackfillBatchSize = 100
+	MaxSlugBackfillBatchSize     = 1000
+)
+
+// maintenanceTracer трассировщик для сервисных операций обслуживания данных.
+var maintenanceTracer = otel.Tracer("admin-panel/maintenance-service")
+
+// MaintenanceService предоставляет операции обслуживания данных, которые запускаются администратором вручную.
+type MaintenanceService struct {
+	categoryRepo *repositories.CategoryRepository
+	courseRepo   *repositories.CourseRepository
+	lessonRepo   *repositories.LessonRepository
+}
+
+// NewMaintenanceService создает новый экземпляр MaintenanceService.
+// Принимает репозитории категорий, курсов и уроков.
+func NewMaintenanceService(categoryRepo *repositories.CategoryRepository, courseRepo *repositories.CourseRepository, lessonRepo *repositories.LessonRepository) *MaintenanceService {
+	return &MaintenanceService{
+		categoryRepo: categoryRepo,
+		courseRepo:   courseRepo,
+		lessonRepo:   lessonRepo,
+	}
+}
+
+// BackfillSlugs генерирует slug для всех категорий, курсов и уроков, у которых его нет, пакетами по batchSize строк.
+// Каждый пакет выполняется в отдельной транзакции. Операция идемпотентна: записи со slug не затрагиваются,
+// поэтому повторный запуск после сбоя продолжает с того места, где остановился предыдущий.
+func (s *MaintenanceService) BackfillSlugs(ctx context.Context, batchSize int) (*response.SlugBackfillResult, error) {
+	ctx, span := maintenanceTracer.Start(ctx, "MaintenanceService.BackfillSlugs")
+	defer span.End()
+
+	if batchSize <= 0 {
+		batchSize = DefaultSlugBackfillBatchSize
+	}
+	if batchSize > MaxSlugBackfillBatchSize {
+		return nil, middleware.ValidationError(fmt.Sprintf("Batch size must not exceed %d", MaxSlugBackfillBatchSize))
+	}
+	span.SetAttributes(attribute.Int("backfill.batch_size", batchSize))
+
+	result := &response.SlugBackfillResult{BatchSize: batchSize}
+
+	categories, batches, err := backfillAll(ctx, batchSize, s.categoryRepo.BackfillSlugsBatch, "category")
+	result.Categories, result.Batches = categories, batches
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to backfill category slugs: %v", err))
+	}
+
+	courses, batches, err := backfillAll(ctx, batchSize, s.courseRepo.BackfillSlugsBatch, "course")
+	result.Courses, result.Batches = courses, result.Batches+batches
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to backfill course slugs: %v", err))
+	}
+
+	lessons, batches, err := backfillAll(ctx, batchSize, s.lessonRepo.BackfillSlugsBatch, "lesson")
+	result.Lessons, result.Batches = lessons, result.Batches+batches
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to backfill lesson slugs: %v", err))
+	}
+
+	span.SetAttributes(
+		attribute.Int("backfill.categories", result.Categories),
+		attribute.Int("backfill.courses", result.Courses),
+		attribute.Int("backfill.lessons", result.Lessons),
+		attribute.Int("backfill.batches", result.Batches),
+	)
+
+	return result, nil
+}
+
+// FindOrphanedLessons возвращает уроки, чей курс не существует (остались после физического
+// удаления курса без каскадного внешнего ключа), и их количество.
+func (s *MaintenanceService) FindOrphanedLessons(ctx context.Context) (*response.OrphanedLessonsResult, error) {
+	ctx, span := maintenanceTracer.Start(ctx, "MaintenanceService.FindOrphanedLessons")
+	defer span.End()
+
+	lessons, err := s.lessonRepo.FindOrphanedLessons(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to find orphaned lessons: %v", err))
+	}
+
+	span.SetAttributes(attribute.Int("orphaned_lessons.count", len(lessons)))
+	return &response.OrphanedLessonsResult{Count: len(lessons), Items: lessons}, nil
+}
+
+// DeleteOrphanedLessons удаляет все уроки, чей курс не существует, и возвращает их количество.
+// Операция идемпотентна: повторный вызов удаляет только появившиеся с тех пор уроки без курса.
+func (s *MaintenanceService) DeleteOrphanedLessons(ctx context.Context) (*response.OrphanedLessonsDeleteResult, error) {
+	ctx, span := maintenanceTracer.Start(ctx, "MaintenanceService.DeleteOrphanedLessons")
+	defer span.End()
+
+	deleted, err := s.lessonRepo.DeleteOrphanedLessons(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to delete orphaned lessons: %v", err))
+	}
+
+	span.SetAttributes(attribute.Int64("orphaned_lessons.deleted", deleted))
+	return &response.OrphanedLessonsDeleteResult{Deleted: int(deleted)}, nil
+}
+
+// backfillAll вызывает batchFn, пока очередной пакет не окажется неполным.
+// Возвращает общее количество обновленных строк и число выполненных пакетов.
+func backfillAll(
+	ctx context.Context,
+	batchSize int,
+	batchFn func(context.Context, int, repositories.SlugGenerator) (int, error),
+	fallback string,
+) (int, int, error) {
+	generate := func(title string, exists func(slug string) (bool, error)) (string, error) {
+		return uniqueSlug(title, fallback, exists)
+	}
+
+	total, batches := 0, 0
+	for {
+		updated, err := batchFn(ctx, batchSize, generate)
+		if err != nil {
+			return total, batches, err
+		}
+		if updated == 0 {
+			return total, batches, nil
+		}
+		total += updated
+		batches++
+		if updated < batchSize {
+			return total, batches, nil
+		}
+	}
+}
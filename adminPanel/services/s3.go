@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"adminPanel/config"
+	"adminPanel/handlers/dto/response"
 	"adminPanel/middleware"
 
 	"github.com/google/uuid"
@@ -18,6 +21,8 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -28,10 +33,17 @@ var tracer = otel.Tracer("adminPanel/services")
 // S3Service предоставляет методы для работы с MinIO/S3 хранилищем.
 // Позволяет загружать, удалять и получать URL изображений.
 type S3Service struct {
-	client    *minio.Client
-	bucket    string
-	useSSL    bool
-	publicURL string
+	client        *minio.Client
+	httpClient    *http.Client
+	bucket        string
+	useSSL        bool
+	publicURL     string
+	presignExpiry time.Duration
+	maxImageSize  int64
+	healthTimeout time.Duration
+	maxBatchFiles int
+	uploadWorkers int
+	uploadBytes   metric.Int64Counter
 }
 
 // NewS3Service создает новый экземпляр S3Service на основе конфигурации MinIO.
@@ -45,14 +57,60 @@ func NewS3Service(cfg config.MinioConfig) (*S3Service, error) {
 		return nil, fmt.Errorf("failed to initialize MinIO client: %w", err)
 	}
 
+	uploadBytes, err := otel.Meter("adminPanel/services").Int64Counter("s3.upload.size",
+		metric.WithDescription("Bytes uploaded to S3"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 upload bytes counter: %w", err)
+	}
+
 	return &S3Service{
-		client:    minioClient,
-		bucket:    cfg.Bucket,
-		useSSL:    cfg.UseSSL,
-		publicURL: cfg.PublicURL,
+		client:        minioClient,
+		httpClient:    newHTTPClient(cfg.DownloadConnectTimeout, cfg.DownloadReadTimeout),
+		bucket:        cfg.Bucket,
+		useSSL:        cfg.UseSSL,
+		publicURL:     cfg.PublicURL,
+		presignExpiry: cfg.PresignExpiry,
+		maxImageSize:  cfg.MaxImageSizeBytes,
+		healthTimeout: cfg.HealthCheckTimeout,
+		maxBatchFiles: cfg.BatchMaxFiles,
+		uploadWorkers: cfg.UploadConcurrency,
+		uploadBytes:   uploadBytes,
 	}, nil
 }
 
+// Bucket возвращает имя bucket, с которым работает сервис.
+func (s *S3Service) Bucket() string {
+	return s.bucket
+}
+
+// HealthCheck проверяет доступность хранилища вызовом BucketExists с таймаутом из MINIO_HEALTH_CHECK_TIMEOUT.
+// Возвращает ошибку, если MinIO не отвечает или bucket отсутствует.
+func (s *S3Service) HealthCheck(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "S3Service.HealthCheck",
+		trace.WithAttributes(attribute.String("s3.bucket", s.bucket)))
+	defer span.End()
+
+	if s.healthTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.healthTimeout)
+		defer cancel()
+	}
+
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to check bucket existence: %w", err)
+	}
+	if !exists {
+		err = fmt.Errorf("bucket %q does not exist", s.bucket)
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
 // EnsureBucketExists проверяет существование bucket и создает его, если необходимо.
 // Устанавливает публичную политику доступа для чтения объектов.
 func (s *S3Service) EnsureBucketExists(ctx context.Context) error {
@@ -108,19 +166,72 @@ func (s *S3Service) UploadImage(ctx context.Context, file *multipart.FileHeader)
 		attribute.Int64("file.size", file.Size),
 	)
 
-	contentType := file.Header.Get("Content-Type")
+	objectName, _, err := s.putUploadedImage(ctx, file)
+	if err != nil {
+		return "", err
+	}
+
+	imageURL := s.GetImageURL(objectName)
+
+	span.AddEvent("image uploaded", trace.WithAttributes(
+		attribute.String("image.url", imageURL),
+	))
+
+	return imageURL, nil
+}
+
+// UploadImageKey загружает изображение из multipart.FileHeader в S3.
+// Проверяет тип и размер файла, генерирует уникальное имя и возвращает ключ объекта.
+// Дополнительно сохраняет миниатюру под ключом ThumbnailKey; при ошибке остается только оригинал.
+func (s *S3Service) UploadImageKey(ctx context.Context, file *multipart.FileHeader) (string, error) {
+	ctx, span := tracer.Start(ctx, "S3Service.UploadImageKey")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("file.name", file.Filename),
+		attribute.Int64("file.size", file.Size),
+	)
+
+	objectName, contentType, err := s.putUploadedImage(ctx, file)
+	if err != nil {
+		return "", err
+	}
+
+	span.AddEvent("image uploaded", trace.WithAttributes(
+		attribute.String("object.key", objectName),
+	))
+
+	// SVG масштабируется без потерь, миниатюра для него не нужна.
+	if contentType == svgContentType {
+		return objectName, nil
+	}
+
+	// Исходный поток уже прочитан PutObject, поэтому для миниатюры файл открывается заново.
+	thumbSrc, err := file.Open()
+	if err != nil {
+		log.Printf("⚠️  Skipping thumbnail for %s: failed to reopen uploaded file: %v", objectName, err)
+	} else {
+		defer thumbSrc.Close()
+		s.uploadThumbnail(ctx, thumbSrc, objectName)
+	}
+
+	return objectName, nil
+}
+
+// putUploadedImage проверяет тип и размер изображения из multipart.FileHeader, загружает его в S3
+// под уникальным ключом и возвращает ключ объекта и нормализованный Content-Type.
+// Ошибки записываются в текущий спан из ctx.
+func (s *S3Service) putUploadedImage(ctx context.Context, file *multipart.FileHeader) (string, string, error) {
+	span := trace.SpanFromContext(ctx)
+
+	contentType := normalizeImageType(file.Header.Get("Content-Type"))
 	if !isValidImageType(contentType) {
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Invalid image type: %s. Only JPEG, PNG, GIF, and WEBP are allowed", contentType),
-			400,
-			"INVALID_IMAGE_TYPE",
-		)
+		return "", "", invalidImageTypeError(contentType)
 	}
 
-	maxSize := int64(10 * 1024 * 1024)
-	if file.Size > maxSize {
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Image size exceeds maximum allowed size of %d bytes", maxSize),
+	if file.Size > s.maxImageSize {
+		return "", "", middleware.NewAppError(
+			fmt.Sprintf("Image size exceeds maximum allowed size of %d bytes", s.maxImageSize),
 			400,
 			"IMAGE_TOO_LARGE",
 		)
@@ -129,7 +240,7 @@ func (s *S3Service) UploadImage(ctx context.Context, file *multipart.FileHeader)
 	src, err := file.Open()
 	if err != nil {
 		span.RecordError(err)
-		return "", middleware.NewAppError(
+		return "", "", middleware.NewAppError(
 			fmt.Sprintf("Failed to open uploaded file: %v", err),
 			500,
 			"FILE_OPEN_ERROR",
@@ -146,93 +257,206 @@ func (s *S3Service) UploadImage(ctx context.Context, file *multipart.FileHeader)
 
 	span.SetAttributes(attribute.String("object.name", objectName))
 
-	_, err = s.client.PutObject(ctx, s.bucket, objectName, src, file.Size, minio.PutObjectOptions{
+	body, size, err := prepareImageBody(contentType, src, file.Size, s.maxImageSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, objectName, body, size, minio.PutObjectOptions{
 		ContentType: contentType,
 	})
 	if err != nil {
 		span.RecordError(err)
-		return "", middleware.NewAppError(
+		return "", "", middleware.NewAppError(
 			fmt.Sprintf("Failed to upload image to S3: %v", err),
 			500,
 			"S3_UPLOAD_ERROR",
 		)
 	}
+	s.recordUploadBytes(ctx, "image", info.Size)
 
-	imageURL := s.GetImageURL(objectName)
+	return objectName, contentType, nil
+}
 
-	span.AddEvent("image uploaded", trace.WithAttributes(
-		attribute.String("image.url", imageURL),
+// recordUploadBytes учитывает объем загруженных в S3 данных в счетчике s3.upload.size
+// и в метриках Prometheus (если они включены). kind различает исходные изображения и миниатюры.
+func (s *S3Service) recordUploadBytes(ctx context.Context, kind string, size int64) {
+	middleware.RecordS3Upload(s.bucket, kind, size)
+	if s.uploadBytes == nil {
+		return
+	}
+	s.uploadBytes.Add(ctx, size, metric.WithAttributes(
+		attribute.String("s3.bucket", s.bucket),
+		attribute.String("upload.kind", kind),
 	))
-
-	return imageURL, nil
 }
 
-// UploadImageKey загружает изображение из multipart.FileHeader в S3.
-// Проверяет тип и размер файла, генерирует уникальное имя и возвращает ключ объекта.
-func (s *S3Service) UploadImageKey(ctx context.Context, file *multipart.FileHeader) (string, error) {
-	ctx, span := tracer.Start(ctx, "S3Service.UploadImageKey")
+// UploadImages загружает несколько изображений из multipart-формы параллельно, не более
+// MINIO_UPLOAD_CONCURRENCY загрузок одновременно. Каждый файл проверяется и загружается независимо:
+// ошибка одного файла записывается в его результат и не прерывает остальные.
+// Результаты возвращаются в порядке входных файлов. Возвращает ошибку, только если файлов нет
+// или их больше MINIO_BATCH_MAX_FILES.
+func (s *S3Service) UploadImages(ctx context.Context, files []*multipart.FileHeader) ([]response.ImageUploadResult, error) {
+	ctx, span := tracer.Start(ctx, "S3Service.UploadImages")
 	defer span.End()
 
+	if len(files) == 0 {
+		return nil, middleware.NewAppError("At least one file is required in 'files[]'", 400, "MISSING_FILE")
+	}
+	if len(files) > s.maxBatchFiles {
+		return nil, middleware.NewAppError(
+			fmt.Sprintf("Too many files: at most %d files can be uploaded in one request", s.maxBatchFiles),
+			400,
+			"TOO_MANY_FILES",
+		)
+	}
+
+	workers := min(s.uploadWorkers, len(files))
 	span.SetAttributes(
-		attribute.String("file.name", file.Filename),
-		attribute.Int64("file.size", file.Size),
+		attribute.Int("upload.files_total", len(files)),
+		attribute.Int("upload.workers", workers),
 	)
 
-	contentType := file.Header.Get("Content-Type")
-	if !isValidImageType(contentType) {
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Invalid image type: %s. Only JPEG, PNG, GIF, and WEBP are allowed", contentType),
-			400,
-			"INVALID_IMAGE_TYPE",
-		)
+	results := make([]response.ImageUploadResult, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.uploadBatchItem(ctx, files[i])
+			}
+		}()
 	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
-	maxSize := int64(10 * 1024 * 1024)
-	if file.Size > maxSize {
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Image size exceeds maximum allowed size of %d bytes", maxSize),
-			400,
-			"IMAGE_TOO_LARGE",
-		)
+	succeeded := 0
+	for _, result := range results {
+		if result.Error == "" {
+			succeeded++
+		}
 	}
+	span.SetAttributes(attribute.Int("upload.files_succeeded", succeeded))
 
-	src, err := file.Open()
+	return results, nil
+}
+
+// uploadBatchItem загружает один файл пакета в собственном спане и формирует его результат.
+func (s *S3Service) uploadBatchItem(ctx context.Context, file *multipart.FileHeader) response.ImageUploadResult {
+	ctx, span := tracer.Start(ctx, "S3Service.uploadBatchItem")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("file.name", file.Filename),
+		attribute.Int64("file.size", file.Size),
+	)
+
+	result := response.ImageUploadResult{Filename: file.Filename}
+	objectName, _, err := s.putUploadedImage(ctx, file)
 	if err != nil {
 		span.RecordError(err)
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Failed to open uploaded file: %v", err),
-			500,
-			"FILE_OPEN_ERROR",
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Key = objectName
+	result.URL = s.GetImageURL(objectName)
+	return result
+}
+
+// defaultPresignExpiry используется, если срок действия presigned URL не задан.
+// maxPresignExpiry - максимальный срок, который допускает подпись S3 (7 дней).
+const (
+	defaultPresignExpiry = 15 * time.Minute
+	maxPresignExpiry     = 7 * 24 * time.Hour
+)
+
+// PresignedPost содержит данные для прямой загрузки изображения в S3 POST-запросом
+// (multipart/form-data): URL, поля формы с подписанной политикой и ключ будущего объекта.
+type PresignedPost struct {
+	URL       string
+	Fields    map[string]string
+	ObjectKey string
+}
+
+// GeneratePresignedPost выдает подписанную политику POST-загрузки, по которой клиент загружает
+// изображение напрямую в S3, минуя память сервера. Политика фиксирует ключ объекта, тип контента
+// и размер (от 1 до maxImageSize байт), поэтому хранилище отклонит файл другого типа или размера.
+// Расширение ключа выводится из типа контента, а не из имени файла клиента.
+// SVG не допускается: при загрузке в обход сервиса его нельзя очистить от скриптов.
+// После загрузки клиент передает ObjectKey в image_key курса.
+func (s *S3Service) GeneratePresignedPost(ctx context.Context, contentType string) (*PresignedPost, error) {
+	ctx, span := tracer.Start(ctx, "S3Service.GeneratePresignedPost")
+	defer span.End()
+
+	expiry := s.PresignExpiry()
+	span.SetAttributes(
+		attribute.String("content.type", contentType),
+		attribute.String("presign.expiry", expiry.String()),
+	)
+
+	if !isValidImageType(contentType) {
+		return nil, invalidImageTypeError(contentType)
+	}
+	contentType = normalizeImageType(contentType)
+	if contentType == svgContentType {
+		return nil, middleware.NewAppError(
+			"SVG images cannot be uploaded via presigned URL, use POST /upload/image instead",
+			400,
+			"INVALID_IMAGE_TYPE",
 		)
 	}
-	defer src.Close()
 
-	ext := filepath.Ext(file.Filename)
 	objectName := fmt.Sprintf("go/%s/%s%s",
 		time.Now().Format("2006/01/02"),
 		uuid.New().String(),
-		ext,
+		imageExtension(contentType),
 	)
-
 	span.SetAttributes(attribute.String("object.name", objectName))
 
-	_, err = s.client.PutObject(ctx, s.bucket, objectName, src, file.Size, minio.PutObjectOptions{
-		ContentType: contentType,
-	})
+	policy := minio.NewPostPolicy()
+	for _, err := range []error{
+		policy.SetBucket(s.bucket),
+		policy.SetKey(objectName),
+		policy.SetContentType(contentType),
+		policy.SetContentLengthRange(1, s.maxImageSize),
+		policy.SetExpires(time.Now().UTC().Add(expiry)),
+	} {
+		if err != nil {
+			span.RecordError(err)
+			return nil, middleware.InternalError(fmt.Sprintf("Failed to build upload policy: %v", err))
+		}
+	}
+
+	uploadURL, fields, err := s.client.PresignedPostPolicy(ctx, policy)
 	if err != nil {
 		span.RecordError(err)
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Failed to upload image to S3: %v", err),
+		return nil, middleware.NewAppError(
+			fmt.Sprintf("Failed to generate presigned upload URL: %v", err),
 			500,
-			"S3_UPLOAD_ERROR",
+			"S3_PRESIGN_ERROR",
 		)
 	}
 
-	span.AddEvent("image uploaded", trace.WithAttributes(
-		attribute.String("object.key", objectName),
-	))
+	span.AddEvent("presigned upload policy generated")
 
-	return objectName, nil
+	return &PresignedPost{URL: uploadURL.String(), Fields: fields, ObjectKey: objectName}, nil
+}
+
+// PresignExpiry возвращает срок действия presigned URL с учетом значения по умолчанию и ограничения S3.
+func (s *S3Service) PresignExpiry() time.Duration {
+	switch {
+	case s.presignExpiry <= 0:
+		return defaultPresignExpiry
+	case s.presignExpiry > maxPresignExpiry:
+		return maxPresignExpiry
+	}
+	return s.presignExpiry
 }
 
 // DeleteImage удаляет изображение из S3 по публичному URL.
@@ -254,7 +478,26 @@ func (s *S3Service) DeleteImage(ctx context.Context, imageURL string) error {
 
 	span.SetAttributes(attribute.String("object.name", objectName))
 
-	err := s.client.RemoveObject(ctx, s.bucket, objectName, minio.RemoveObjectOptions{})
+	return s.DeleteImageByKey(ctx, objectName)
+}
+
+// DeleteImageByKey удаляет изображение из S3 по ключу объекта (значению image_key),
+// чтобы вызывающему коду не приходилось восстанавливать публичный URL.
+func (s *S3Service) DeleteImageByKey(ctx context.Context, objectKey string) error {
+	ctx, span := tracer.Start(ctx, "S3Service.DeleteImageByKey")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("object.key", objectKey))
+
+	if objectKey == "" {
+		return middleware.NewAppError(
+			"Image key must not be empty",
+			400,
+			"INVALID_IMAGE_KEY",
+		)
+	}
+
+	err := s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{})
 	if err != nil {
 		span.RecordError(err)
 		return middleware.NewAppError(
@@ -264,6 +507,11 @@ func (s *S3Service) DeleteImage(ctx context.Context, imageURL string) error {
 		)
 	}
 
+	// Миниатюры может не быть (старые изображения, ошибка генерации), поэтому ошибка не возвращается.
+	if err := s.client.RemoveObject(ctx, s.bucket, ThumbnailKey(objectKey), minio.RemoveObjectOptions{}); err != nil {
+		span.RecordError(err)
+	}
+
 	span.AddEvent("image deleted")
 
 	return nil
@@ -285,25 +533,6 @@ func (s *S3Service) extractObjectNameFromURL(imageURL string) string {
 	return ""
 }
 
-// isValidImageType проверяет, является ли contentType допустимым типом изображения.
-// Поддерживает JPEG, PNG, GIF, WEBP.
-func isValidImageType(contentType string) bool {
-	validTypes := []string{
-		"image/jpeg",
-		"image/jpg",
-		"image/png",
-		"image/gif",
-		"image/webp",
-	}
-
-	for _, validType := range validTypes {
-		if contentType == validType {
-			return true
-		}
-	}
-	return false
-}
-
 // UploadImageFromReader загружает изображение из io.Reader в S3.
 // Принимает reader, имя файла, размер и тип контента, возвращает публичный URL.
 func (s *S3Service) UploadImageFromReader(ctx context.Context, reader io.Reader, filename string, size int64, contentType string) (string, error) {
@@ -316,12 +545,9 @@ func (s *S3Service) UploadImageFromReader(ctx context.Context, reader io.Reader,
 		attribute.String("content.type", contentType),
 	)
 
+	contentType = normalizeImageType(contentType)
 	if !isValidImageType(contentType) {
-		return "", middleware.NewAppError(
-			fmt.Sprintf("Invalid image type: %s. Only JPEG, PNG, GIF, and WEBP are allowed", contentType),
-			400,
-			"INVALID_IMAGE_TYPE",
-		)
+		return "", invalidImageTypeError(contentType)
 	}
 
 	ext := filepath.Ext(filename)
@@ -333,7 +559,12 @@ func (s *S3Service) UploadImageFromReader(ctx context.Context, reader io.Reader,
 
 	span.SetAttributes(attribute.String("object.name", objectName))
 
-	_, err := s.client.PutObject(ctx, s.bucket, objectName, reader, size, minio.PutObjectOptions{
+	body, size, err := prepareImageBody(contentType, reader, size, s.maxImageSize)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, objectName, body, size, minio.PutObjectOptions{
 		ContentType: contentType,
 	})
 	if err != nil {
@@ -344,6 +575,7 @@ func (s *S3Service) UploadImageFromReader(ctx context.Context, reader io.Reader,
 			"S3_UPLOAD_ERROR",
 		)
 	}
+	s.recordUploadBytes(ctx, "image", info.Size)
 
 	imageURL := s.GetImageURL(objectName)
 
@@ -356,15 +588,29 @@ func (s *S3Service) UploadImageFromReader(ctx context.Context, reader io.Reader,
 
 // UploadImageFromURL скачивает изображение по URL и загружает в S3.
 // Проверяет тип контента, генерирует имя и возвращает публичный URL.
+// Скачивание ограничено таймаутами HTTP-клиента и отменяется вместе с контекстом запроса.
 func (s *S3Service) UploadImageFromURL(ctx context.Context, imageURL string) (string, error) {
 	ctx, span := tracer.Start(ctx, "S3Service.UploadImageFromURL")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("source.url", imageURL))
 
-	resp, err := http.Get(imageURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
 	if err != nil {
 		span.RecordError(err)
+		return "", middleware.NewAppError(
+			fmt.Sprintf("Invalid image URL: %v", err),
+			400,
+			"IMAGE_DOWNLOAD_ERROR",
+		)
+	}
+
+	resp, err := s.doOutboundRequest(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		if isTimeoutError(err) {
+			return "", imageDownloadTimeoutError(imageURL)
+		}
 		return "", middleware.NewAppError(
 			fmt.Sprintf("Failed to download image from URL: %v", err),
 			400,
@@ -381,7 +627,7 @@ func (s *S3Service) UploadImageFromURL(ctx context.Context, imageURL string) (st
 		)
 	}
 
-	contentType := resp.Header.Get("Content-Type")
+	contentType := normalizeImageType(resp.Header.Get("Content-Type"))
 	if !isValidImageType(contentType) {
 		return "", middleware.NewAppError(
 			fmt.Sprintf("Invalid image type from URL: %s", contentType),
@@ -392,18 +638,7 @@ func (s *S3Service) UploadImageFromURL(ctx context.Context, imageURL string) (st
 
 	ext := filepath.Ext(imageURL)
 	if ext == "" || len(ext) > 5 {
-		switch contentType {
-		case "image/jpeg", "image/jpg":
-			ext = ".jpg"
-		case "image/png":
-			ext = ".png"
-		case "image/gif":
-			ext = ".gif"
-		case "image/webp":
-			ext = ".webp"
-		default:
-			ext = ".jpg"
-		}
+		ext = imageExtension(contentType)
 	}
 
 	objectName := fmt.Sprintf("go/%s/%s%s",
@@ -414,17 +649,26 @@ func (s *S3Service) UploadImageFromURL(ctx context.Context, imageURL string) (st
 
 	span.SetAttributes(attribute.String("object.name", objectName))
 
-	_, err = s.client.PutObject(ctx, s.bucket, objectName, resp.Body, resp.ContentLength, minio.PutObjectOptions{
+	body, size, err := prepareImageBody(contentType, resp.Body, resp.ContentLength, s.maxImageSize)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, objectName, body, size, minio.PutObjectOptions{
 		ContentType: contentType,
 	})
 	if err != nil {
 		span.RecordError(err)
+		if isTimeoutError(err) {
+			return "", imageDownloadTimeoutError(imageURL)
+		}
 		return "", middleware.NewAppError(
 			fmt.Sprintf("Failed to upload image to S3: %v", err),
 			500,
 			"S3_UPLOAD_ERROR",
 		)
 	}
+	s.recordUploadBytes(ctx, "image", info.Size)
 
 	s3URL := s.GetImageURL(objectName)
 
@@ -434,3 +678,41 @@ func (s *S3Service) UploadImageFromURL(ctx context.Context, imageURL string) (st
 
 	return s3URL, nil
 }
+
+// doOutboundRequest выполняет исходящий HTTP-запрос через клиент с таймаутами скачивания
+// в отдельном span клиента. В span записываются метод, URL, статус ответа и длительность
+// до получения заголовков ответа; ошибка и статус 4xx/5xx помечают span как ошибочный.
+func (s *S3Service) doOutboundRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+	_, span := tracer.Start(ctx, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	span.SetAttributes(attribute.Int64("http.duration_ms", time.Since(start).Milliseconds()))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// imageDownloadTimeoutError возвращает ошибку истечения таймаута при скачивании изображения.
+func imageDownloadTimeoutError(imageURL string) *middleware.AppError {
+	return middleware.NewAppError(
+		fmt.Sprintf("Timed out downloading image from URL: %s", imageURL),
+		504,
+		"IMAGE_DOWNLOAD_TIMEOUT",
+	)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"adminPanel/middleware"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func TestUploadImageFromURLTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tests := []struct {
+		name        string
+		readTimeout time.Duration
+		ctxTimeout  time.Duration
+	}{
+		{name: "read timeout", readTimeout: 50 * time.Millisecond, ctxTimeout: 5 * time.Second},
+		{name: "request context deadline", readTimeout: 5 * time.Second, ctxTimeout: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &S3Service{httpClient: newHTTPClient(time.Second, tt.readTimeout)}
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			_, err := s.UploadImageFromURL(ctx, server.URL+"/image.png")
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("UploadImageFromURL() took %v, want it to stop at the timeout", elapsed)
+			}
+
+			var appErr *middleware.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("UploadImageFromURL() error = %v, want *middleware.AppError", err)
+			}
+			if appErr.Code != "IMAGE_DOWNLOAD_TIMEOUT" || appErr.StatusCode != http.StatusGatewayTimeout {
+				t.Errorf("UploadImageFromURL() error = %d %s, want 504 IMAGE_DOWNLOAD_TIMEOUT", appErr.StatusCode, appErr.Code)
+			}
+		})
+	}
+}
+
+func TestGeneratePresignedPostBindsTypeAndSize(t *testing.T) {
+	client, err := minio.New("minio.test:9000", &minio.Options{
+		Creds:  credentials.NewStaticV4("access", "secret", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("minio.New() error = %v", err)
+	}
+	s := &S3Service{client: client, bucket: "images", maxImageSize: 5 << 20}
+
+	post, err := s.GeneratePresignedPost(context.Background(), "image/PNG; charset=binary")
+	if err != nil {
+		t.Fatalf("GeneratePresignedPost() error = %v", err)
+	}
+	if !strings.HasPrefix(post.ObjectKey, "go/") || !strings.HasSuffix(post.ObjectKey, ".png") {
+		t.Errorf("ObjectKey = %q, want go/.../<uuid>.png", post.ObjectKey)
+	}
+	if post.Fields["key"] != post.ObjectKey || post.Fields["Content-Type"] != "image/png" {
+		t.Errorf("Fields = %v, want key %s and Content-Type image/png", post.Fields, post.ObjectKey)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(post.Fields["policy"])
+	if err != nil {
+		t.Fatalf("decode policy: %v", err)
+	}
+	var policy struct {
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		t.Fatalf("unmarshal policy %s: %v", raw, err)
+	}
+	conditions := make([]string, len(policy.Conditions))
+	for i, c := range policy.Conditions {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, c); err != nil {
+			t.Fatalf("compact condition %s: %v", c, err)
+		}
+		conditions[i] = compact.String()
+	}
+	for _, want := range []string{
+		`["eq","$key","` + post.ObjectKey + `"]`,
+		`["eq","$Content-Type","image/png"]`,
+		`["content-length-range",1,5242880]`,
+	} {
+		if !slices.Contains(conditions, want) {
+			t.Errorf("policy conditions %v do not contain %s", conditions, want)
+		}
+	}
+}
+
+func TestGeneratePresignedPostRejectsUnsafeTypes(t *testing.T) {
+	s := &S3Service{maxImageSize: 5 << 20}
+	for _, contentType := range []string{"image/svg+xml", "text/html", "application/octet-stream"} {
+		_, err := s.GeneratePresignedPost(context.Background(), contentType)
+
+		var appErr *middleware.AppError
+		if !errors.As(err, &appErr) || appErr.StatusCode != 400 || appErr.Code != "INVALID_IMAGE_TYPE" {
+			t.Errorf("GeneratePresignedPost(%q) error = %v, want 400 INVALID_IMAGE_TYPE", contentType, err)
+		}
+	}
+}
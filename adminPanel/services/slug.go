@@ -0,0 +1,92 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength ограничивает длину slug размером колонки в базе данных.
+const maxSlugLength = 255
+
+// cyrillicToLatin содержит таблицу транслитерации кириллицы для генерации slug.
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// normalizeSlug приводит произвольную строку к виду URL slug:
+// нижний регистр, транслитерация кириллицы, латинские буквы и цифры,
+// разделенные одиночными дефисами. Используется как для генерации slug
+// из заголовка, так и для нормализации slug, введенного пользователем.
+func normalizeSlug(value string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(value) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case cyrillicToLatin[r] != "":
+			part = cyrillicToLatin[r]
+		case r == 'ъ' || r == 'ь':
+			continue
+		default:
+			pendingDash = b.Len() > 0
+			continue
+		}
+
+		if pendingDash {
+			b.WriteByte('-')
+			pendingDash = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// slugInsertAttempts ограничивает число попыток вставки записи, если сгенерированный slug
+// успели занять параллельным запросом между проверкой и INSERT.
+const slugInsertAttempts = 3
+
+// uniqueSlug строит slug из заголовка и подбирает свободный вариант, добавляя суффиксы -2, -3 и т.д.
+// Если заголовок не содержит ни одной буквы или цифры, используется fallback.
+// Функция exists сообщает, занят ли кандидат.
+func uniqueSlug(title, fallback string, exists func(slug string) (bool, error)) (string, error) {
+	base := normalizeSlug(title)
+	if base == "" {
+		base = fallback
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+
+		suffix := fmt.Sprintf("-%d", n)
+		trimmed := base
+		if len(trimmed)+len(suffix) > maxSlugLength {
+			trimmed = strings.TrimRight(trimmed[:maxSlugLength-len(suffix)], "-")
+		}
+		candidate = trimmed + suffix
+	}
+}
+
+// isSlugConflict сообщает, вызвана ли ошибка нарушением уникального индекса slug с заданным именем.
+func isSlugConflict(err error, index string) bool {
+	return err != nil && strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), index)
+}
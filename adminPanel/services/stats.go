@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"adminPanel/config"
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// statsTracer трассировщик для сервиса сводной статистики.
+var statsTracer = otel.Tracer("admin-panel/stats-service")
+
+// StatsService предоставляет сводную статистику базы знаний для панели управления.
+// Подсчет требует полного прохода по таблицам, поэтому результат кэшируется на cfg.CacheTTL.
+type StatsService struct {
+	statsRepo *repositories.StatsRepository
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	overview  *response.StatsOverview
+	expiresAt time.Time
+}
+
+// NewStatsService создает новый экземпляр StatsService.
+// При cfg.CacheTTL <= 0 статистика подсчитывается при каждом запросе.
+func NewStatsService(statsRepo *repositories.StatsRepository, cfg config.StatsConfig) *StatsService {
+	return &StatsService{statsRepo: statsRepo, ttl: cfg.CacheTTL}
+}
+
+// GetOverview возвращает количество категорий, курсов (всего, по видимости и по уровню) и уроков,
+// а также время последнего изменения любой из этих сущностей.
+// Пока не истек кэш, возвращается ранее подсчитанный результат; его время указано в GeneratedAt.
+func (s *StatsService) GetOverview(ctx context.Context) (*response.StatsOverview, error) {
+	ctx, span := statsTracer.Start(ctx, "StatsService.GetOverview")
+	defer span.End()
+
+	if overview, ok := s.cached(); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return overview, nil
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	overview, err := s.computeOverview(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get stats overview: %v", err))
+	}
+
+	s.store(overview)
+	return overview, nil
+}
+
+// computeOverview выполняет по одному агрегирующему запросу на сущность и собирает из них сводку.
+func (s *StatsService) computeOverview(ctx context.Context) (*response.StatsOverview, error) {
+	categories, err := s.statsRepo.CategoryTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count categories: %w", err)
+	}
+	courses, err := s.statsRepo.CourseTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count courses: %w", err)
+	}
+	lessons, err := s.statsRepo.LessonTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count lessons: %w", err)
+	}
+
+	overview := &response.StatsOverview{
+		Categories: toInt(categories["total"]),
+		Courses: response.CourseStatsOverview{
+			Total: toInt(courses["total"]),
+			ByVisibility: response.CourseVisibilityCounts{
+				Draft:  toInt(courses["draft"]),
+				Public: toInt(courses["public"]),
+			},
+			ByLevel: response.CourseLevelCounts{
+				Easy:   toInt(courses["easy"]),
+				Medium: toInt(courses["medium"]),
+				Hard:   toInt(courses["hard"]),
+			},
+		},
+		Lessons:     toInt(lessons["total"]),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	for _, row := range []map[string]interface{}{categories, courses, lessons} {
+		updatedAt := parseNullableTime(row["last_updated_at"])
+		if updatedAt != nil && (overview.LastUpdatedAt == nil || updatedAt.After(*overview.LastUpdatedAt)) {
+			overview.LastUpdatedAt = updatedAt
+		}
+	}
+
+	return overview, nil
+}
+
+// cached возвращает копию закэшированной сводки, если она не истекла.
+func (s *StatsService) cached() (*response.StatsOverview, bool) {
+	if s.ttl <= 0 {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.overview == nil || time.Now().After(s.expiresAt) {
+		return nil, false
+	}
+	overview := *s.overview
+	return &overview, true
+}
+
+// store сохраняет копию сводки на время ttl.
+func (s *StatsService) store(overview *response.StatsOverview) {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *overview
+	s.overview = &copied
+	s.expiresAt = time.Now().Add(s.ttl)
+}
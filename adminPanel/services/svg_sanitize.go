@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// svgNamespace пространство имен SVG. Элементы из других пространств (XHTML внутри
+// foreignObject, собственные префиксы) при очистке отбрасываются.
+const svgNamespace = "http://www.w3.org/2000/svg"
+
+// xlinkNamespace пространство имен XLink, из которого сохраняется только xlink:href.
+const xlinkNamespace = "http://www.w3.org/1999/xlink"
+
+// svgAllowedElements содержит элементы SVG, которые сохраняются при очистке.
+// Сюда не входят script, style, foreignObject, image, a, animate* и set: они могут
+// выполнять код, загружать внешние ресурсы или подменять атрибуты во время показа.
+var svgAllowedElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "use": true, "title": true, "desc": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true, "line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true, "textPath": true,
+	"linearGradient": true, "radialGradient": true, "stop": true,
+	"clipPath": true, "mask": true, "pattern": true, "marker": true,
+	"filter": true, "feGaussianBlur": true, "feOffset": true, "feBlend": true, "feColorMatrix": true,
+	"feFlood": true, "feComposite": true, "feMerge": true, "feMergeNode": true, "feDropShadow": true,
+}
+
+// svgAllowedAttributes содержит атрибуты без пространства имен, которые сохраняются при очистке.
+// Обработчики событий on* и style в список не входят.
+var svgAllowedAttributes = map[string]bool{
+	"id": true, "class": true, "version": true, "viewBox": true, "preserveAspectRatio": true,
+	"width": true, "height": true, "x": true, "y": true, "x1": true, "y1": true, "x2": true, "y2": true,
+	"cx": true, "cy": true, "r": true, "rx": true, "ry": true, "fx": true, "fy": true, "dx": true, "dy": true,
+	"d": true, "points": true, "transform": true, "pathLength": true,
+	"fill": true, "fill-opacity": true, "fill-rule": true, "stroke": true, "stroke-width": true,
+	"stroke-linecap": true, "stroke-linejoin": true, "stroke-dasharray": true, "stroke-dashoffset": true,
+	"stroke-miterlimit": true, "stroke-opacity": true, "opacity": true, "color": true,
+	"visibility": true, "display": true, "clip-path": true, "clip-rule": true, "mask": true, "filter": true,
+	"offset": true, "stop-color": true, "stop-opacity": true,
+	"gradientUnits": true, "gradientTransform": true, "spreadMethod": true,
+	"patternUnits": true, "patternContentUnits": true, "patternTransform": true,
+	"clipPathUnits": true, "maskUnits": true, "maskContentUnits": true,
+	"markerWidth": true, "markerHeight": true, "markerUnits": true, "refX": true, "refY": true, "orient": true,
+	"font-family": true, "font-size": true, "font-weight": true, "font-style": true,
+	"text-anchor": true, "dominant-baseline": true, "letter-spacing": true, "startOffset": true,
+	"stdDeviation": true, "in": true, "in2": true, "result": true, "mode": true, "type": true, "values": true,
+	"operator": true, "k1": true, "k2": true, "k3": true, "k4": true, "flood-color": true, "flood-opacity": true,
+	"href": true,
+}
+
+// errInvalidSVG возвращается sanitizeSVG, если содержимое не является корректным SVG-документом.
+var errInvalidSVG = errors.New("invalid SVG document")
+
+// sanitizeSVG разбирает SVG XML-парсером и заново записывает только разрешенные элементы
+// и атрибуты (svgAllowedElements, svgAllowedAttributes), так как bucket раздается публично
+// и SVG открывается браузером как документ. Запрещенные элементы удаляются вместе с содержимым;
+// комментарии, инструкции обработки и DOCTYPE отбрасываются. Ссылки href допускаются только
+// на фрагменты внутри документа (#id), а url(...) в значениях атрибутов - только вида url(#id).
+// Проверяются значения после раскрытия сущностей, поэтому кодирование вида jav&#x61;script: не помогает.
+// Возвращает errInvalidSVG, если документ не разбирается или корневой элемент не svg.
+func sanitizeSVG(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var out bytes.Buffer
+	depth := 0
+	rootSeen := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidSVG, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if rootSeen || !isSVGElement(t.Name, "svg") {
+					return nil, fmt.Errorf("%w: root element must be svg", errInvalidSVG)
+				}
+				rootSeen = true
+			}
+			if !isSVGElement(t.Name, t.Name.Local) || !svgAllowedElements[t.Name.Local] {
+				if err := decoder.Skip(); err != nil {
+					return nil, fmt.Errorf("%w: %v", errInvalidSVG, err)
+				}
+				continue
+			}
+			writeSVGStartElement(&out, t, depth == 0)
+			depth++
+		case xml.EndElement:
+			if depth > 0 {
+				depth--
+				out.WriteString("</" + t.Name.Local + ">")
+			}
+		case xml.CharData:
+			if depth > 0 {
+				xml.EscapeText(&out, t)
+			}
+		}
+	}
+
+	if !rootSeen {
+		return nil, fmt.Errorf("%w: root element must be svg", errInvalidSVG)
+	}
+	return out.Bytes(), nil
+}
+
+// isSVGElement проверяет, что элемент local принадлежит пространству имен SVG.
+// Элемент без пространства имен допускается: браузер не исполняет такой документ как SVG.
+func isSVGElement(name xml.Name, local string) bool {
+	return name.Local == local && (name.Space == svgNamespace || name.Space == "")
+}
+
+// writeSVGStartElement записывает открывающий тег с разрешенными атрибутами.
+// Корневому элементу добавляется объявление пространства имен SVG; xlink:href
+// записывается как href (SVG 2), поэтому пространство имен XLink не нужно.
+func writeSVGStartElement(out *bytes.Buffer, element xml.StartElement, root bool) {
+	out.WriteString("<" + element.Name.Local)
+	if root {
+		out.WriteString(` xmlns="` + svgNamespace + `"`)
+	}
+	for _, attr := range element.Attr {
+		name, ok := svgAttributeName(attr.Name)
+		if !ok || !safeSVGAttributeValue(name, attr.Value) {
+			continue
+		}
+		out.WriteString(" " + name + `="`)
+		xml.EscapeText(out, []byte(attr.Value))
+		out.WriteString(`"`)
+	}
+	out.WriteString(">")
+}
+
+// svgAttributeName возвращает имя атрибута для записи или false, если атрибут не разрешен.
+func svgAttributeName(name xml.Name) (string, bool) {
+	switch name.Space {
+	case "":
+		return name.Local, svgAllowedAttributes[name.Local]
+	case xlinkNamespace:
+		return "href", name.Local == "href"
+	}
+	return "", false
+}
+
+// safeSVGAttributeValue проверяет значение атрибута: href - только ссылка на фрагмент документа,
+// url(...) в любом атрибуте - только на фрагмент документа.
+func safeSVGAttributeValue(name, value string) bool {
+	if name == "href" {
+		return strings.HasPrefix(strings.TrimSpace(value), "#")
+	}
+
+	lower := strings.ToLower(value)
+	for {
+		i := strings.Index(lower, "url(")
+		if i < 0 {
+			return true
+		}
+		lower = strings.TrimLeft(lower[i+len("url("):], " \t\r\n'\"")
+		if !strings.HasPrefix(lower, "#") {
+			return false
+		}
+	}
+}
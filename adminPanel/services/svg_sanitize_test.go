@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSVGRemovesActiveContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		forbidden []string
+	}{
+		{
+			name:      "script element",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><rect width="1"/></svg>`,
+			forbidden: []string{"script", "alert"},
+		},
+		{
+			name:      "namespaced script element",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><svg:script xmlns:svg="http://www.w3.org/2000/svg">alert(1)</svg:script></svg>`,
+			forbidden: []string{"script", "alert"},
+		},
+		{
+			name:      "script in foreign namespace",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><h:script xmlns:h="http://www.w3.org/1999/xhtml">alert(1)</h:script></svg>`,
+			forbidden: []string{"script", "alert"},
+		},
+		{
+			name:      "event handler",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><circle r="1" onclick='alert(2)'/></svg>`,
+			forbidden: []string{"onload", "onclick", "alert"},
+		},
+		{
+			name:      "entity encoded javascript href",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><use href="jav&#x61;script:alert(1)"/></svg>`,
+			forbidden: []string{"javascript", "alert"},
+		},
+		{
+			name:      "xlink javascript href",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="javascript:alert(1)"/></svg>`,
+			forbidden: []string{"javascript", "alert"},
+		},
+		{
+			name:      "foreignObject with html",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><foreignObject><iframe xmlns="http://www.w3.org/1999/xhtml" src="javascript:alert(1)"/></foreignObject></svg>`,
+			forbidden: []string{"foreignObject", "iframe", "alert"},
+		},
+		{
+			name:      "anchor and animation",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><a href="javascript:alert(1)"><set attributeName="href" to="javascript:alert(2)"/></a></svg>`,
+			forbidden: []string{"<a", "<set", "alert"},
+		},
+		{
+			name:      "style element and attribute",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><style>@import url(https://evil.example/x.css);</style><rect style="fill:red"/></svg>`,
+			forbidden: []string{"style", "evil"},
+		},
+		{
+			name:      "external url in presentation attribute",
+			input:     `<svg xmlns="http://www.w3.org/2000/svg"><rect fill="url(https://evil.example/#g)"/></svg>`,
+			forbidden: []string{"evil"},
+		},
+		{
+			name:      "comments and processing instructions",
+			input:     `<?xml version="1.0"?><?xml-stylesheet href="https://evil.example/x.xsl"?><svg xmlns="http://www.w3.org/2000/svg"><!-- evil --></svg>`,
+			forbidden: []string{"evil", "<?", "<!--"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := sanitizeSVG([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("sanitizeSVG() error = %v", err)
+			}
+			for _, s := range tt.forbidden {
+				if strings.Contains(string(out), s) {
+					t.Errorf("sanitizeSVG() = %q, must not contain %q", out, s)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeSVGKeepsDrawing(t *testing.T) {
+	input := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10">` +
+		`<defs><linearGradient id="g"><stop offset="0" stop-color="#fff"/></linearGradient></defs>` +
+		`<path d="M0 0L10 10" fill="url(#g)"/><use xlink:href="#g"/><text x="1">a &amp; b</text></svg>`
+	want := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">` +
+		`<defs><linearGradient id="g"><stop offset="0" stop-color="#fff"></stop></linearGradient></defs>` +
+		`<path d="M0 0L10 10" fill="url(#g)"></path><use href="#g"></use><text x="1">a &amp; b</text></svg>`
+
+	out, err := sanitizeSVG([]byte(input))
+	if err != nil {
+		t.Fatalf("sanitizeSVG() error = %v", err)
+	}
+	if string(out) != want {
+		t.Errorf("sanitizeSVG() =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestSanitizeSVGRejectsInvalidDocuments(t *testing.T) {
+	inputs := map[string]string{
+		"not xml":          `<svg><rect></svg>`,
+		"html root":        `<html><body><svg xmlns="http://www.w3.org/2000/svg"/></body></html>`,
+		"empty":            ``,
+		"unknown entity":   `<!DOCTYPE svg [<!ENTITY x "y">]><svg xmlns="http://www.w3.org/2000/svg">&x;</svg>`,
+		"two root element": `<svg xmlns="http://www.w3.org/2000/svg"/><svg xmlns="http://www.w3.org/2000/svg"/>`,
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := sanitizeSVG([]byte(input)); !errors.Is(err, errInvalidSVG) {
+				t.Errorf("sanitizeSVG() error = %v, want errInvalidSVG", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+
+	"github.com/minio/minio-go/v7"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// thumbnailWidth ширина миниатюры изображения курса в пикселях.
+const thumbnailWidth = 320
+
+// maxThumbnailSourcePixels наибольшее число пикселей исходного изображения, для которого
+// создается миниатюра. Декодированное изображение занимает около 4 байт на пиксель, поэтому
+// небольшой файл с заявленными размерами вроде 50000×50000 без этой проверки занял бы гигабайты памяти.
+const maxThumbnailSourcePixels = 40_000_000
+
+// thumbnailPrefix префикс ключей объектов с миниатюрами.
+const thumbnailPrefix = "thumb/"
+
+// ThumbnailKey возвращает ключ объекта миниатюры для ключа исходного изображения.
+func ThumbnailKey(objectKey string) string {
+	if objectKey == "" {
+		return ""
+	}
+	return thumbnailPrefix + objectKey
+}
+
+// GetThumbnailURL формирует публичный URL миниатюры для ключа исходного изображения.
+func (s *S3Service) GetThumbnailURL(objectKey string) string {
+	return s.GetImageURL(ThumbnailKey(objectKey))
+}
+
+// uploadThumbnail декодирует изображение (JPEG, PNG, WEBP), уменьшает его до thumbnailWidth по ширине
+// и загружает под ключом ThumbnailKey(objectKey). PNG сохраняется в PNG ради прозрачности, остальные форматы в JPEG.
+// Ошибки не прерывают загрузку оригинала: они логируются как предупреждение, миниатюра просто не создается.
+func (s *S3Service) uploadThumbnail(ctx context.Context, src io.Reader, objectKey string) {
+	span := trace.SpanFromContext(ctx)
+
+	img, format, err := decodeThumbnailSource(src)
+	if err != nil {
+		log.Printf("⚠️  Skipping thumbnail for %s: failed to decode image: %v", objectKey, err)
+		span.AddEvent("thumbnail skipped", trace.WithAttributes(
+			attribute.String("object.key", objectKey),
+			attribute.String("reason", err.Error()),
+		))
+		return
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > thumbnailWidth {
+		height = height * thumbnailWidth / width
+		width = thumbnailWidth
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	if format == "png" {
+		contentType = "image/png"
+		err = png.Encode(&buf, thumb)
+	} else {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		log.Printf("⚠️  Skipping thumbnail for %s: failed to encode thumbnail: %v", objectKey, err)
+		span.RecordError(err)
+		return
+	}
+
+	thumbKey := ThumbnailKey(objectKey)
+	info, err := s.client.PutObject(ctx, s.bucket, thumbKey, &buf, int64(buf.Len()), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		log.Printf("⚠️  Skipping thumbnail for %s: failed to upload thumbnail: %v", objectKey, err)
+		span.RecordError(err)
+		return
+	}
+	s.recordUploadBytes(ctx, "thumbnail", info.Size)
+
+	span.AddEvent("thumbnail uploaded", trace.WithAttributes(
+		attribute.String("thumbnail.key", thumbKey),
+		attribute.Int("thumbnail.width", width),
+		attribute.Int("thumbnail.height", height),
+		attribute.String("image.format", format),
+	))
+}
+
+// decodeThumbnailSource декодирует исходное изображение для миниатюры. Сначала читается только
+// заголовок с размерами, и изображения больше maxThumbnailSourcePixels отклоняются до выделения памяти
+// под пиксели; прочитанный заголовок затем передается декодеру вместе с остатком потока.
+func decodeThumbnailSource(src io.Reader) (image.Image, string, error) {
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(src, &header))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxThumbnailSourcePixels {
+		return nil, "", fmt.Errorf("image dimensions %dx%d exceed the limit of %d pixels", cfg.Width, cfg.Height, maxThumbnailSourcePixels)
+	}
+	return image.Decode(io.MultiReader(&header, src))
+}
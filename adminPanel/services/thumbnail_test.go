@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// pngHeader возвращает начало PNG-файла с заголовком IHDR, заявляющим размеры width×height.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	ihdr[8], ihdr[9] = 8, 6 // 8 бит на канал, RGBA.
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr...)
+	buf.Write(chunk)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return buf.Bytes()
+}
+
+func TestDecodeThumbnailSourceRejectsHugeDimensions(t *testing.T) {
+	_, _, err := decodeThumbnailSource(bytes.NewReader(pngHeader(50000, 50000)))
+	if err == nil || !strings.Contains(err.Error(), "exceed the limit") {
+		t.Fatalf("decodeThumbnailSource() error = %v, want dimension limit error", err)
+	}
+}
+
+func TestDecodeThumbnailSourceDecodesImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+
+	img, format, err := decodeThumbnailSource(&buf)
+	if err != nil {
+		t.Fatalf("decodeThumbnailSource() error = %v", err)
+	}
+	if format != "png" || img.Bounds().Dx() != 640 || img.Bounds().Dy() != 480 {
+		t.Errorf("decodeThumbnailSource() = %s %v, want png 640x480", format, img.Bounds())
+	}
+}
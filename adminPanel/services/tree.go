@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"adminPanel/handlers/dto/response"
+	"adminPanel/middleware"
+	"adminPanel/models"
+	"adminPanel/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Ограничения размера дерева категории: курсы и уроки сверх лимита не попадают в ответ,
+// а соответствующий уровень помечается как усеченный.
+const (
+	// MaxTreeCourses - максимальное количество курсов в дереве категории.
+	MaxTreeCourses = 200
+	// MaxTreeLessonsPerCourse - максимальное количество уроков в оглавлении одного курса.
+	MaxTreeLessonsPerCourse = 200
+)
+
+// treeTracer трассировщик для сервиса дерева категорий.
+var treeTracer = otel.Tracer("admin-panel/tree-service")
+
+// CategoryTreeService собирает дерево категории: курсы и оглавления их уроков без контента.
+type CategoryTreeService struct {
+	categoryService *CategoryService
+	courseRepo      *repositories.CourseRepository
+	lessonRepo      *repositories.LessonRepository
+}
+
+// NewCategoryTreeService создает новый экземпляр CategoryTreeService.
+// Принимает сервис категорий и репозитории курсов и уроков.
+func NewCategoryTreeService(
+	categoryService *CategoryService,
+	courseRepo *repositories.CourseRepository,
+	lessonRepo *repositories.LessonRepository,
+) *CategoryTreeService {
+	return &CategoryTreeService{
+		categoryService: categoryService,
+		courseRepo:      courseRepo,
+		lessonRepo:      lessonRepo,
+	}
+}
+
+// GetCategoryTree возвращает категорию с не удаленными курсами (сначала новые) и оглавлениями их уроков.
+// Дерево собирается фиксированным числом запросов независимо от размера категории: категория,
+// курсы и уроки всех курсов. Размер ограничен MaxTreeCourses и MaxTreeLessonsPerCourse;
+// количества на каждом уровне считаются по всем записям, а не только по попавшим в ответ.
+func (s *CategoryTreeService) GetCategoryTree(ctx context.Context, categoryID string) (*response.CategoryTreeDTO, error) {
+	ctx, span := treeTracer.Start(ctx, "CategoryTreeService.GetCategoryTree")
+	span.SetAttributes(attribute.String("category.id", categoryID))
+	defer span.End()
+
+	category, err := s.categoryService.GetCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := s.courseRepo.GetOutlineByCategory(ctx, categoryID, MaxTreeCourses)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get courses: %v", err))
+	}
+
+	courseIDs := make([]string, 0, len(courses))
+	for _, course := range courses {
+		courseIDs = append(courseIDs, toString(course["id"]))
+	}
+
+	outlines, counts, err := s.lessonRepo.GetOutlinesByCourseIDs(ctx, courseIDs, MaxTreeLessonsPerCourse)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, middleware.InternalError(fmt.Sprintf("Failed to get lesson outlines: %v", err))
+	}
+
+	tree := &response.CategoryTreeDTO{
+		ID:    category.ID,
+		Title: category.Title,
+		Slug:  category.Slug,
+		Courses: response.CourseTreeListDTO{
+			Items: make([]response.CourseTreeDTO, 0, len(courses)),
+		},
+	}
+	for i, course := range courses {
+		if i == 0 {
+			tree.Courses.Count = toInt(course["total"])
+		}
+
+		id := courseIDs[i]
+		lessons := outlines[id]
+		if lessons == nil {
+			lessons = []models.LessonOutline{}
+		}
+		tree.LessonCount += counts[id]
+		tree.Courses.Items = append(tree.Courses.Items, response.CourseTreeDTO{
+			ID:         id,
+			Title:      toString(course["title"]),
+			Slug:       toString(course["slug"]),
+			Level:      toString(course["level"]),
+			Visibility: toString(course["visibility"]),
+			Lessons: response.LessonOutlinesDTO{
+				Count:     counts[id],
+				Truncated: counts[id] > len(lessons),
+				Items:     lessons,
+			},
+		})
+	}
+	tree.Courses.Truncated = tree.Courses.Count > len(tree.Courses.Items)
+
+	span.SetAttributes(
+		attribute.Int("tree.courses", len(tree.Courses.Items)),
+		attribute.Int("tree.lessons", tree.LessonCount),
+	)
+	return tree, nil
+}
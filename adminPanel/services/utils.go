@@ -2,13 +2,17 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"adminPanel/middleware"
+
 	"github.com/google/uuid"
 )
 
 // toString преобразует значение в строку.
-// Обрабатывает []byte, [16]byte, uuid.UUID, string и другие типы.
+// Обрабатывает []byte, [16]byte, uuid.UUID, string и другие типы; nil (NULL в базе) дает пустую строку.
 func toString(v interface{}) string {
 	switch val := v.(type) {
 	case []byte:
@@ -26,6 +30,8 @@ func toString(v interface{}) string {
 		return val.String()
 	case string:
 		return val
+	case nil:
+		return ""
 	}
 	return fmt.Sprintf("%v", v)
 }
@@ -43,3 +49,57 @@ func parseTime(value interface{}) time.Time {
 	}
 	return time.Time{}
 }
+
+// parseNullableTime преобразует значение в *time.Time.
+// Возвращает nil для NULL и значений, которые не удалось разобрать.
+func parseNullableTime(value interface{}) *time.Time {
+	t := parseTime(value)
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// toInt преобразует числовое значение из результата запроса в int.
+// Обрабатывает int64, int32 и int, возвращает 0 для остальных типов.
+func toInt(v interface{}) int {
+	switch val := v.(type) {
+	case int64:
+		return int(val)
+	case int32:
+		return int(val)
+	case int:
+		return val
+	}
+	return 0
+}
+
+// ParseSort разбирает параметр сортировки вида "field" или "-field" (по убыванию).
+// allowed сопоставляет допустимые имена полей из запроса с колонками в SQL.
+// Пустой input дает defaultCol и defaultDir. Неизвестное поле отклоняется ошибкой 400
+// VALIDATION_ERROR с его именем и списком допустимых полей, а не заменяется сортировкой по умолчанию.
+func ParseSort(input string, allowed map[string]string, defaultCol, defaultDir string) (column, direction string, err error) {
+	if input == "" {
+		return defaultCol, defaultDir, nil
+	}
+
+	field, desc := strings.CutPrefix(input, "-")
+	column, ok := allowed[field]
+	if !ok {
+		fields := make([]string, 0, len(allowed))
+		for name := range allowed {
+			fields = append(fields, name)
+		}
+		slices.Sort(fields)
+		return "", "", middleware.NewAppError(
+			fmt.Sprintf("Unsupported sort field '%s'. Allowed: %s", field, strings.Join(fields, ", ")),
+			400,
+			"VALIDATION_ERROR",
+		)
+	}
+
+	if desc {
+		return column, "DESC", nil
+	}
+	return column, "ASC", nil
+}
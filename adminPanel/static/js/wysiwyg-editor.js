@@ -3,6 +3,16 @@
  * Интеграция с MinIO/S3 для загрузки изображений
  */
 
+// Загрузка изображений из редактора идет через веб-маршрут под защитой CSRF,
+// так как у страниц редактора нет Bearer-токена для /api/v1/upload.
+const UPLOAD_IMAGE_URL = '/admin/upload/image';
+
+// CSRF-токен страницы из скрытого поля _csrf формы
+function csrfToken() {
+    const input = document.querySelector('input[name="_csrf"]');
+    return input ? input.value : '';
+}
+
 // Инициализация TinyMCE с интеграцией загрузки изображений
 function initTinyMCE(selector) {
     tinymce.init({
@@ -48,9 +58,10 @@ function initTinyMCE(selector) {
             return new Promise((resolve, reject) => {
                 const formData = new FormData();
                 formData.append('image', blobInfo.blob(), blobInfo.filename());
+                formData.append('_csrf', csrfToken());
                 
                 const xhr = new XMLHttpRequest();
-                xhr.open('POST', '/admin/api/v1/upload/image', true);
+                xhr.open('POST', UPLOAD_IMAGE_URL, true);
                 
                 // Обработка прогресса загрузки
                 xhr.upload.onprogress = function(e) {
@@ -102,10 +113,10 @@ function initTinyMCE(selector) {
                     
                     if (file) {
                         // Проверка типа файла
-                        const validTypes = ['image/jpeg', 'image/png', 'image/gif', 'image/webp'];
+                        const validTypes = ['image/jpeg', 'image/png', 'image/gif', 'image/webp', 'image/avif', 'image/svg+xml'];
                         if (!validTypes.includes(file.type)) {
                             tinymce.activeEditor.notificationManager.open({
-                                text: 'Неверный тип файла. Разрешены только: JPEG, PNG, GIF, WEBP',
+                                text: 'Неверный тип файла. Разрешены только: JPEG, PNG, GIF, WEBP, AVIF, SVG',
                                 type: 'error',
                                 timeout: 5000
                             });
@@ -125,6 +136,7 @@ function initTinyMCE(selector) {
                         // Загружаем файл
                         const formData = new FormData();
                         formData.append('image', file);
+                        formData.append('_csrf', csrfToken());
                         
                         const notification = tinymce.activeEditor.notificationManager.open({
                             text: 'Загрузка изображения...',
@@ -133,7 +145,7 @@ function initTinyMCE(selector) {
                             progressBar: true
                         });
                         
-                        fetch('/admin/api/v1/upload/image', {
+                        fetch(UPLOAD_IMAGE_URL, {
                             method: 'POST',
                             body: formData
                         })
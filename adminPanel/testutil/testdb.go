@@ -0,0 +1,80 @@
+// Пакет testutil содержит вспомогательные функции для тестов, работающих с базой данных.
+package testutil
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"adminPanel/config"
+	"adminPanel/database"
+)
+
+// NewDB подключается к базе из TEST_DATABASE_URL со схемой из init-sql/knowledge-base-db.
+// Без переменной тест пропускается.
+func NewDB(t *testing.T) *database.Database {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	t.Setenv("DATABASE_URL", url)
+
+	db, err := database.InitDB(config.NewSettings())
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	t.Cleanup(db.Pool.Close)
+	return db
+}
+
+// CreateCategory создает категорию и удаляет ее вместе с курсами и уроками после теста.
+func CreateCategory(t *testing.T, db *database.Database) string {
+	t.Helper()
+	ctx := context.Background()
+
+	var id string
+	err := db.Pool.QueryRow(ctx, `
+		INSERT INTO knowledge_base.category_d (title) VALUES ('test category') RETURNING id::text
+	`).Scan(&id)
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_, _ = db.Pool.Exec(ctx, `DELETE FROM knowledge_base.course_b WHERE category_id = $1`, id)
+		_, _ = db.Pool.Exec(ctx, `DELETE FROM knowledge_base.category_d WHERE id = $1`, id)
+	})
+	return id
+}
+
+// CreateCourse создает курс в категории и возвращает его ID.
+func CreateCourse(t *testing.T, db *database.Database, categoryID, title, level, visibility string) string {
+	t.Helper()
+
+	var id string
+	err := db.Pool.QueryRow(context.Background(), `
+		INSERT INTO knowledge_base.course_b (title, level, visibility, category_id)
+		VALUES ($1, $2, $3, $4) RETURNING id::text
+	`, title, level, visibility, categoryID).Scan(&id)
+	if err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	return id
+}
+
+// CreateLessons создает n уроков курса.
+func CreateLessons(t *testing.T, db *database.Database, courseID string, n int) {
+	t.Helper()
+
+	for i := 1; i <= n; i++ {
+		_, err := db.Pool.Exec(context.Background(), `
+			INSERT INTO knowledge_base.lesson_d (title, course_id, order_index) VALUES ('test lesson', $1, $2)
+		`, courseID, i)
+		if err != nil {
+			t.Fatalf("create lesson: %v", err)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+-- URL slugs for categories and courses.
+-- Category slugs are unique globally, course slugs are unique within a category.
+ALTER TABLE knowledge_base.category_d ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE knowledge_base.course_b ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_slug ON knowledge_base.category_d (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_course_category_slug ON knowledge_base.course_b (category_id, slug);
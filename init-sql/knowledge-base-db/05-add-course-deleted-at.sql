@@ -0,0 +1,5 @@
+-- Soft delete for courses: a non-NULL deleted_at marks the course as deleted.
+-- Soft-deleted courses are hidden from listings and can be restored from the admin panel.
+ALTER TABLE knowledge_base.course_b ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_course_not_deleted ON knowledge_base.course_b (category_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- Explicit lesson ordering within a course.
+-- Existing lessons are numbered by creation time; new lessons are appended to the end (max + 1).
+ALTER TABLE knowledge_base.lesson_d ADD COLUMN IF NOT EXISTS order_index INTEGER NOT NULL DEFAULT 0;
+
+UPDATE knowledge_base.lesson_d AS l
+SET order_index = numbered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY created_at, id) AS position
+    FROM knowledge_base.lesson_d
+) AS numbered
+WHERE l.id = numbered.id AND l.order_index = 0;
+
+CREATE INDEX IF NOT EXISTS idx_lesson_course_order ON knowledge_base.lesson_d (course_id, order_index);
@@ -0,0 +1,22 @@
+-- Lesson content is stored as bytes so that it can be gzip-compressed by the admin panel.
+-- Plain content is kept as UTF-8; compressed content is recognised on read by the gzip magic bytes (1f 8b).
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = 'knowledge_base'
+          AND table_name = 'lesson_d'
+          AND column_name = 'content'
+          AND data_type = 'text'
+    ) THEN
+        -- The text default cannot be cast to BYTEA automatically, so it is dropped before
+        -- the type change and restored as a BYTEA value afterwards.
+        ALTER TABLE knowledge_base.lesson_d
+            ALTER COLUMN content DROP DEFAULT;
+        ALTER TABLE knowledge_base.lesson_d
+            ALTER COLUMN content TYPE BYTEA USING convert_to(content, 'UTF8');
+        ALTER TABLE knowledge_base.lesson_d
+            ALTER COLUMN content SET DEFAULT ''::bytea;
+    END IF;
+END
+$$;
@@ -0,0 +1,4 @@
+-- Full-text search over course title and description.
+-- The expression must match courseSearchVector in adminPanel/repositories/course.go.
+CREATE INDEX IF NOT EXISTS idx_course_search ON knowledge_base.course_b
+    USING GIN (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(description, '')));
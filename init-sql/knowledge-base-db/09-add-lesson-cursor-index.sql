@@ -0,0 +1,3 @@
+-- Keyset (cursor) pagination of lessons within a course on (created_at, id).
+-- id is the tiebreaker for lessons created within the same timestamp.
+CREATE INDEX IF NOT EXISTS idx_lesson_course_created_id ON knowledge_base.lesson_d (course_id, created_at, id);
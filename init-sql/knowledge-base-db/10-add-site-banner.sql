@@ -0,0 +1,9 @@
+-- Site-wide banner (e.g. scheduled maintenance notice) shown on every public page while active.
+-- The table holds at most one row (id = 1); clearing the banner deletes it.
+CREATE TABLE IF NOT EXISTS knowledge_base.site_banner (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    message TEXT NOT NULL,
+    severity VARCHAR(20) NOT NULL DEFAULT 'info' CHECK (severity IN ('info', 'warning', 'critical')),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
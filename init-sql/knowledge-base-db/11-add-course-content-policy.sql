@@ -0,0 +1,6 @@
+-- Per-course markup policy for HTML lesson content rendered on the public side.
+-- 'strict' allows only basic formatting; 'relaxed' additionally allows styling classes,
+-- data attributes and a few extra elements for courses that need richer markup.
+ALTER TABLE knowledge_base.course_b
+    ADD COLUMN IF NOT EXISTS content_policy VARCHAR(20) NOT NULL DEFAULT 'strict'
+    CHECK (content_policy IN ('strict', 'relaxed'));
@@ -0,0 +1,5 @@
+-- URL slugs for lessons, unique within a course.
+-- Existing rows keep NULL until the admin slug backfill fills them in.
+ALTER TABLE knowledge_base.lesson_d ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lesson_course_slug ON knowledge_base.lesson_d (course_id, slug);
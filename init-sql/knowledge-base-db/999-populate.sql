@@ -90,4 +90,13 @@ INSERT INTO knowledge_base.category_d (id, title, created_at, updated_at) VALUES
 INSERT INTO knowledge_base.course_b (id, category_id, title, description, level, visibility, created_at, updated_at) VALUES ('b6000001-0000-4000-8000-000000000006', 'a6000000-0000-4000-8000-000000000006', 'SOLID Principles in Practice', 'Understand and apply the five SOLID principles of object-oriented design to write more maintainable, flexible, and scalable code.', 'medium', 'draft', '2024-05-02 11:00:00', '2024-05-02 11:00:00');
 INSERT INTO knowledge_base.lesson_d (id, course_id, title, content, created_at, updated_at) VALUES ('c6000001-0001-4000-8000-000000000006', 'b6000001-0000-4000-8000-000000000006', 'Single Responsibility Principle', '<p>A class should have only one reason to change. This principle helps to keep classes focused and small.</p>', '2024-05-03 12:00:00', '2024-05-03 12:00:00');
 
+-- Number seeded lessons within each course by creation time.
+UPDATE knowledge_base.lesson_d AS l
+SET order_index = numbered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY course_id ORDER BY created_at, id) AS position
+    FROM knowledge_base.lesson_d
+) AS numbered
+WHERE l.id = numbered.id;
+
 COMMIT;
\ No newline at end of file
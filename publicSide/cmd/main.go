@@ -9,19 +9,28 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/clients/testing"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
 	v1 "github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler/api/v1"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler/web"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/middleware"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/repository"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/router"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/cache"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/database"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/httpclient"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/lessoncontent"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/logger"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/template"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/tracing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
@@ -42,21 +51,36 @@ func main() {
 		config.WithDBFromEnv(),
 		config.WithCORSFromEnv(),
 		config.WithPortFromEnv(),
+		config.WithShutdownFromEnv(),
 		config.WithTracingFromEnv(),
 		config.WithLogLevelFromEnv(),
 		config.WithDevFromEnv(),
+		config.WithStrictUUIDFromEnv(),
 		config.WithOIDCFromEnv(),
 		config.WithMinioFromEnv(),
 		config.WithTestingFromEnv(),
+		config.WithPreviewFromEnv(),
+		config.WithCategoriesFromEnv(),
+		config.WithSessionFromEnv(),
+		config.WithAPIKeysFromEnv(),
+		config.WithSwaggerFromEnv(),
+		config.WithContentFromEnv(),
+		config.WithCacheFromEnv(),
 	)
 	if err != nil {
 		slog.Error("Failed to initialize config", "error", err)
 		os.Exit(1)
 	}
+	if err := cfg.Validate(); err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// --- Логирование и Трассировка ---
 	slog.SetDefault(logger.Setup(cfg.Log.Level))
-	slog.Info("Application starting", "DEV_MODE", cfg.App.Dev)
+	slog.Info("Application starting", "DEV_MODE", cfg.App.Dev, "API_KEYS", len(cfg.APIKeys.Keys))
+
+	utils.SetStrictUUIDValidation(cfg.App.StrictUUID)
 
 	tracer, err := tracing.New(&cfg.Otel)
 	if err != nil {
@@ -66,7 +90,7 @@ func main() {
 	defer tracer.Close()
 
 	// --- Аутентификация (OIDC) ---
-	provider, err := oidc.NewProvider(context.Background(), cfg.OIDC.IssuerURL)
+	provider, err := newOIDCProvider(cfg.OIDC)
 	if err != nil {
 		slog.Error("Failed to initialize OIDC provider", "error", err)
 		os.Exit(1)
@@ -77,11 +101,17 @@ func main() {
 		ClientSecret: cfg.OIDC.ClientSecret,
 		RedirectURL:  cfg.OIDC.RedirectURL,
 		Endpoint:     provider.Endpoint(),
-		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		Scopes:       cfg.OIDC.Scopes,
+	}
+
+	sessionStore, err := web.NewSessionStore(cfg.Session, cfg.OIDC.ClientSecret)
+	if err != nil {
+		slog.Error("Failed to initialize session store", "error", err)
+		os.Exit(1)
 	}
 
-	authHandler := web.NewAuthHandler(provider, oauth2Config)
-	authMiddleware := web.NewAuthMiddleware(provider, cfg.OIDC.ClientID)
+	authHandler := web.NewAuthHandler(provider, oauth2Config, sessionStore, cfg.OIDC.PostLogoutRedirectURL, cfg.OIDC.Claims)
+	authMiddleware := web.NewAuthMiddleware(provider, oauth2Config, sessionStore, cfg.Preview, cfg.OIDC.Claims)
 
 	// --- Инициализация зависимостей (DI) ---
 	dbPool, err := database.NewConnection(&cfg.Database)
@@ -99,7 +129,13 @@ func main() {
 	}
 	slog.Info("S3 service initialized")
 
-	testingClient, err := testing.NewClient(cfg.TestingService.BaseURL, "./doc/schemas/external/testing/get_test_response.json")
+	testingHTTPClient := httpclient.New(cfg.TestingService.ConnectTimeout, cfg.TestingService.ReadTimeout)
+	testingClient, err := testing.NewClient(
+		cfg.TestingService.BaseURL,
+		"./doc/schemas/external/testing/get_test_response.json",
+		"./doc/schemas/external/testing/get_test_stats_response.json",
+		testingHTTPClient,
+	)
 	if err != nil {
 		slog.Error("Failed to initialize testing client", "error", err)
 		os.Exit(1)
@@ -110,22 +146,65 @@ func main() {
 	lessonRepo := repository.NewLessonRepository(dbPool)
 	categoryRepo := repository.NewCategoryRepository(dbPool)
 	courseRepo := repository.NewCourseRepository(dbPool)
+	bannerRepo := repository.NewBannerRepository(dbPool)
 
 	// Сервисы
 	lessonService := service.NewLessonService(lessonRepo)
-	categoryService := service.NewCategoryService(categoryRepo)
+	categoryService := service.NewCategoryService(categoryRepo, cfg.Categories)
 	courseService := service.NewCourseService(courseRepo, categoryRepo, s3Service)
+
+	// Кэш списков категорий и отдельных курсов; при CACHE_TTL=0 кэши равны nil и не используются.
+	categoryCache := cache.New[service.CategoryPage]("categories", cfg.Cache.MaxEntries, cfg.Cache.TTL)
+	courseCache := cache.New[response.CourseDTO]("courses", cfg.Cache.MaxEntries, cfg.Cache.TTL)
+	categoryService = service.NewCachedCategoryService(categoryService, categoryCache)
+	courseService = service.NewCachedCourseService(courseService, courseCache)
+	if cfg.Cache.TTL > 0 && cfg.Cache.StatsInterval > 0 {
+		go cache.LogStats(context.Background(), cfg.Cache.StatsInterval, categoryCache, courseCache)
+	}
+	// События изменения от adminPanel сбрасывают записи сразу; без канала записи устаревают до CACHE_TTL.
+	if cfg.Cache.TTL > 0 && cfg.Cache.EventsChannel != "" {
+		invalidator := service.NewCacheInvalidator(categoryCache, courseCache)
+		go database.Listen(context.Background(), dbPool, cfg.Cache.EventsChannel, invalidator.HandlePayload, invalidator.InvalidateAll)
+	}
 	testService := service.NewTestService(testingClient)
+	// Баннер запрашивается на каждой странице и кэшируется ненадолго; при BANNER_CACHE_TTL=0 кэш отключен.
+	bannerService := service.NewBannerService(bannerRepo, cache.New[domain.Banner]("banner", 1, cfg.Cache.BannerTTL))
 	slog.Info("All services initialized")
 
+	// Подсветка синтаксиса блоков кода в контенте уроков
+	highlighter, err := lessoncontent.NewHighlighter(cfg.Content.CodeTheme)
+	if err != nil {
+		slog.Error("Failed to initialize code highlighter", "error", err)
+		os.Exit(1)
+	}
+	lessoncontent.RegisterRenderer(lessoncontent.BlockCode, highlighter.Render)
+
+	// Встраивание видео только с разрешенных хостов
+	videoEmbedder, err := lessoncontent.NewVideoEmbedder(cfg.Content.VideoEmbedHosts)
+	if err != nil {
+		slog.Error("Failed to initialize video embedder", "error", err)
+		os.Exit(1)
+	}
+	lessoncontent.RegisterRenderer(lessoncontent.BlockVideo, videoEmbedder.Render)
+	codeHighlightCSS, err := highlighter.CSS()
+	if err != nil {
+		slog.Error("Failed to generate code highlight CSS", "error", err)
+		os.Exit(1)
+	}
+
 	// --- Настройка Fiber ---
 	engine := template.NewEngine(&cfg.App)
+	// Маршрутизация нестрогая и регистронезависимая; завершающий слэш дополнительно
+	// удаляется NormalizeTrailingSlash, чтобы все промежуточные обработчики видели канонический путь.
 	app := fiber.New(fiber.Config{
-		Views:        engine,
-		ErrorHandler: middleware.CommonErrorHandler,
+		Views:         engine,
+		ErrorHandler:  middleware.CommonErrorHandler,
+		StrictRouting: false,
+		CaseSensitive: false,
 	})
 
 	// Middleware
+	app.Use(middleware.NormalizeTrailingSlash())
 	app.Use(recover.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
@@ -139,12 +218,14 @@ func main() {
 	// --- Роутинг ---
 	webRouter := &router.WebRouter{
 		Config:              &cfg.App,
-		HomeHandler:         web.NewHomeHandler(categoryService, courseService),
-		CategoryPageHandler: web.NewCategoryHandler(categoryService, courseService),
+		HomeHandler:         web.NewHomeHandler(categoryService, courseService, cfg.Database.MaxParallelQueries),
+		CategoryPageHandler: web.NewCategoryHandler(categoryService, courseService, cfg.Database.MaxParallelQueries),
 		CoursesHandler:      web.NewCoursesHandler(courseService, categoryService, lessonService, testService, cfg.TestingService),
 		WebLessonHandler:    web.NewLessonHandler(lessonService, courseService, categoryService),
 		AuthHandler:         authHandler,
 		AuthMiddleware:      authMiddleware,
+		BannerMiddleware:    web.NewBannerMiddleware(bannerService),
+		CodeHighlightCSS:    codeHighlightCSS,
 	}
 	webRouter.Setup(app)
 
@@ -152,13 +233,88 @@ func main() {
 		APICategoryHandler: v1.NewCategoryHandler(categoryService),
 		APICourseHandler:   v1.NewCourseHandler(courseService),
 		APILessonHandler:   v1.NewLessonHandler(lessonService),
+		APIKeyMiddleware:   middleware.APIKeyAuth(cfg.APIKeys),
+		Swagger:            &cfg.Swagger,
 	}
 	apiRouter.Setup(app)
 
 	// --- Запуск сервера ---
+	// Отложенные закрытие пула БД и трассировщика выполняются после возврата из serve.
 	slog.Info("Starting server", "address", cfg.Server.Port)
-	if err := app.Listen(fmt.Sprintf(":%s", cfg.Server.Port)); err != nil {
+	if err := serve(app, fmt.Sprintf(":%s", cfg.Server.Port), cfg.Server.ShutdownTimeout); err != nil {
 		slog.Error("Server failed to start", "error", err)
 		os.Exit(1)
 	}
 }
+
+// oidcDiscoveryMaxDelay ограничивает паузу между попытками discovery OIDC-провайдера.
+const oidcDiscoveryMaxDelay = 30 * time.Second
+
+// oidcDiscoveryTimeout ограничивает одну попытку получения discovery-документа.
+const oidcDiscoveryTimeout = 10 * time.Second
+
+// newOIDCProvider получает discovery-документ OIDC-провайдера, повторяя попытки с экспоненциальной
+// паузой (OIDC_DISCOVERY_ATTEMPTS, OIDC_DISCOVERY_DELAY), пока провайдер (например, Keycloak
+// в docker-compose) запускается. Каждая неудачная попытка логируется; ошибка возвращается только
+// после исчерпания попыток. SIGINT или SIGTERM прерывают ожидание.
+func newOIDCProvider(cfg config.OIDCConfig) (*oidc.Provider, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	delay := cfg.DiscoveryDelay
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, oidcDiscoveryTimeout)
+		provider, err := oidc.NewProvider(attemptCtx, cfg.IssuerURL)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				slog.Info("OIDC provider discovered", "issuer", cfg.IssuerURL, "attempt", attempt)
+			}
+			return provider, nil
+		}
+		if attempt >= cfg.DiscoveryAttempts {
+			return nil, fmt.Errorf("OIDC discovery failed after %d attempts: %w", attempt, err)
+		}
+
+		slog.Warn("OIDC provider is not available, retrying",
+			"issuer", cfg.IssuerURL, "attempt", attempt, "max_attempts", cfg.DiscoveryAttempts,
+			"retry_in", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("OIDC discovery interrupted: %w", err)
+		}
+		delay = min(delay*2, oidcDiscoveryMaxDelay)
+	}
+}
+
+// serve запускает HTTP-сервер и блокируется до его остановки. По SIGINT или SIGTERM сервер
+// перестает принимать соединения и ждет завершения обрабатываемых запросов не дольше timeout;
+// повторный сигнал завершает процесс немедленно. Возвращает только ошибку запуска сервера.
+func serve(app *fiber.App, address string, timeout time.Duration) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- app.Listen(address)
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("Shutdown signal received, draining connections",
+		"open_connections", app.Server().GetOpenConnectionsCount(), "timeout", timeout)
+
+	start := time.Now()
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		slog.Warn("Server shutdown did not complete cleanly",
+			"error", err, "open_connections", app.Server().GetOpenConnectionsCount())
+	}
+	slog.Info("Server stopped", "drain_duration", time.Since(start).Round(time.Millisecond))
+	return nil
+}
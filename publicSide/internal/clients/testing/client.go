@@ -10,12 +10,19 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/httpclient"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	// TEST_API_PATH - путь для внутреннего API-взаимодействия для получения информации о тесте.
 	TEST_API_PATH = "/testing/internal/categories/%s/courses/%s/test"
+	// TEST_STATS_API_PATH - путь для внутреннего API-взаимодействия для получения статистики прохождения теста.
+	TEST_STATS_API_PATH = "/testing/internal/categories/%s/courses/%s/test/stats"
 	// TEST_UI_PATH - путь для пользовательского интерфейса для прохождения теста.
 	TEST_UI_PATH = "/testing/ui/categories/%s/courses/%s/test"
 
@@ -27,15 +34,18 @@ const (
 
 // Client инкапсулирует логику для отправки запросов к сервису тестирования.
 type Client struct {
-	baseURL    *url.URL
-	httpClient *http.Client
-	schema     *jsonschema.Schema
+	baseURL     *url.URL
+	httpClient  *http.Client
+	schema      *jsonschema.Schema
+	statsSchema *jsonschema.Schema
 }
 
 // NewClient создает новый экземпляр клиента для сервиса тестирования.
 // `baseURL` - это базовый URL сервиса (например, "http://localhost:8081").
-// `schemaPath` - путь к файлу JSON-схемы для валидации ответов.
-func NewClient(baseURL string, schemaPath string) (*Client, error) {
+// `schemaPath` - путь к файлу JSON-схемы для валидации ответов с тестом.
+// `statsSchemaPath` - путь к файлу JSON-схемы для валидации ответов со статистикой теста.
+// `httpClient` - HTTP-клиент с настроенными таймаутами (см. пакет httpclient).
+func NewClient(baseURL string, schemaPath string, statsSchemaPath string, httpClient *http.Client) (*Client, error) {
 	parsedBaseURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse base URL: %w", err)
@@ -46,21 +56,63 @@ func NewClient(baseURL string, schemaPath string) (*Client, error) {
 		return nil, fmt.Errorf("failed to compile schema: %w", err)
 	}
 
+	statsSchema, err := jsonschema.Compile(statsSchemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile stats schema: %w", err)
+	}
+
 	return &Client{
-		baseURL: parsedBaseURL,
-		httpClient: &http.Client{
-			Timeout: 3 * time.Second,
-		},
-		schema: schema,
+		baseURL:     parsedBaseURL,
+		httpClient:  httpClient,
+		schema:      schema,
+		statsSchema: statsSchema,
 	}, nil
 }
 
 // GetTest запрашивает информацию о тесте для конкретного курса.
 // Он выполняет GET-запрос, валидирует ответ по JSON-схеме и разбирает его.
 // Возвращает `ErrTestNotFound`, если тест не найден, `ErrServiceUnavailable` при проблемах с сетью
-// или `ErrInvalidResponse` при несоответствии ответа схеме.
+// (вместе с `ErrTimeout`, если истек таймаут) или `ErrInvalidResponse` при несоответствии ответа схеме.
 func (c *Client) GetTest(ctx context.Context, categoryID, courseID string) (*TestData, error) {
-	path := fmt.Sprintf(TEST_API_PATH, categoryID, courseID)
+	body, err := c.get(ctx, fmt.Sprintf(TEST_API_PATH, categoryID, courseID), c.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var testResponse TestResponse
+	if err := json.Unmarshal(body, &testResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response into DTO: %w", err)
+	}
+
+	if testResponse.Status == STATUS_NOT_FOUND {
+		return nil, ErrTestNotFound
+	}
+
+	return testResponse.Data, nil
+}
+
+// GetTestStats запрашивает статистику прохождения теста для конкретного курса.
+// Ошибки возвращаются так же, как в GetTest: `ErrTestNotFound`, если для курса нет теста.
+func (c *Client) GetTestStats(ctx context.Context, categoryID, courseID string) (*TestStatsData, error) {
+	body, err := c.get(ctx, fmt.Sprintf(TEST_STATS_API_PATH, categoryID, courseID), c.statsSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var statsResponse TestStatsResponse
+	if err := json.Unmarshal(body, &statsResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response into DTO: %w", err)
+	}
+
+	if statsResponse.Status == STATUS_NOT_FOUND {
+		return nil, ErrTestNotFound
+	}
+
+	return statsResponse.Data, nil
+}
+
+// get выполняет GET-запрос по пути path, валидирует ответ по схеме schema и возвращает его тело.
+func (c *Client) get(ctx context.Context, path string, schema *jsonschema.Schema) ([]byte, error) {
 	requestURL := c.baseURL.ResolveReference(&url.URL{Path: path})
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
@@ -68,14 +120,20 @@ func (c *Client) GetTest(ctx context.Context, categoryID, courseID string) (*Tes
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
+		if httpclient.IsTimeout(err) {
+			return nil, fmt.Errorf("%w: %w: %v", ErrServiceUnavailable, ErrTimeout, err)
+		}
 		return nil, fmt.Errorf("%w: %v", ErrServiceUnavailable, err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		if httpclient.IsTimeout(err) {
+			return nil, fmt.Errorf("%w: %w: %v", ErrServiceUnavailable, ErrTimeout, err)
+		}
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
@@ -83,20 +141,40 @@ func (c *Client) GetTest(ctx context.Context, categoryID, courseID string) (*Tes
 	if err := json.Unmarshal(body, &v); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response for validation: %w: %v", ErrInvalidResponse, err)
 	}
-	if err := c.schema.Validate(v); err != nil {
+	if err := schema.Validate(v); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
 	}
 
-	var testResponse TestResponse
-	if err := json.Unmarshal(body, &testResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response into DTO: %w", err)
-	}
+	return body, nil
+}
 
-	if testResponse.Status == STATUS_NOT_FOUND {
-		return nil, ErrTestNotFound
+// do выполняет запрос к сервису тестирования в отдельном span клиента. В span записываются
+// метод, URL, статус ответа и длительность до получения заголовков ответа; ошибка и статус
+// 4xx/5xx помечают span как ошибочный. Таймауты задает httpClient, отмену - контекст запроса.
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	_, span := otel.Tracer("testing-client").Start(ctx, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.String()),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	span.SetAttributes(attribute.Int64("http.duration_ms", time.Since(start).Milliseconds()))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
 
-	return testResponse.Data, nil
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
 }
 
 // GetUITestURL генерирует полный URL для страницы прохождения теста.
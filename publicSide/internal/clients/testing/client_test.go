@@ -0,0 +1,43 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/httpclient"
+)
+
+const (
+	testSchemaPath      = "../../../doc/schemas/external/testing/get_test_response.json"
+	testStatsSchemaPath = "../../../doc/schemas/external/testing/get_test_stats_response.json"
+)
+
+func TestGetTestTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient(server.URL, testSchemaPath, testStatsSchemaPath, httpclient.New(time.Second, 50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.GetTest(context.Background(), "category", "course")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetTest() took %v, want it to stop at the read timeout", elapsed)
+	}
+	if !errors.Is(err, ErrServiceUnavailable) || !errors.Is(err, ErrTimeout) {
+		t.Errorf("GetTest() error = %v, want ErrServiceUnavailable and ErrTimeout", err)
+	}
+}
@@ -15,3 +15,17 @@ type TestData struct {
 	MinPoint    int    `json:"min_point"`   // Минимальный балл для прохождения.
 	Description string `json:"description"` // Описание теста.
 }
+
+// TestStatsResponse представляет собой обертку ответа со статистикой теста.
+type TestStatsResponse struct {
+	Data   *TestStatsData `json:"data"`   // Статистика теста или null.
+	Status string         `json:"status"` // Статус ответа ("success", "not_found").
+}
+
+// TestStatsData содержит статистику прохождения теста по курсу.
+type TestStatsData struct {
+	TestID         string `json:"test_id"`         // ID теста.
+	QuestionCount  int    `json:"question_count"`  // Количество вопросов в тесте.
+	AttemptsTotal  int    `json:"attempts_total"`  // Общее количество попыток прохождения.
+	AttemptsPassed int    `json:"attempts_passed"` // Количество успешных попыток.
+}
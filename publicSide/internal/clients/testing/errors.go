@@ -10,6 +10,10 @@ var (
 	// ErrServiceUnavailable возникает при сетевых ошибках или таймаутах при обращении к сервису.
 	ErrServiceUnavailable = errors.New("testing service is unavailable")
 
+	// ErrTimeout возникает, если сервис тестирования не ответил за отведенное время.
+	// Всегда оборачивается вместе с ErrServiceUnavailable.
+	ErrTimeout = errors.New("testing service request timed out")
+
 	// ErrInvalidResponse возникает, если ответ от сервиса не соответствует JSON-схеме или не может быть разобран.
 	ErrInvalidResponse = errors.New("invalid response from testing service")
 )
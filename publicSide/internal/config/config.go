@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type (
@@ -20,21 +22,31 @@ type (
 		OIDC           OIDCConfig
 		Minio          MinioConfig
 		TestingService TestingServiceConfig
+		Preview        PreviewConfig
+		Categories     CategoriesConfig
+		Session        SessionConfig
+		APIKeys        APIKeysConfig
+		Swagger        SwaggerConfig
+		Content        ContentConfig
+		Cache          CacheConfig
 	}
 
 	// AppConfig содержит общие настройки приложения.
 	AppConfig struct {
-		Dev bool // Dev режим (true/false) - включает горячую перезагрузку шаблонов и заголовки no-cache.
+		Dev        bool // Dev режим (true/false) - включает горячую перезагрузку шаблонов и заголовки no-cache.
+		StrictUUID bool // Строгая проверка UUID в путях (только ненулевые UUID версии 4).
 	}
 
 	// ServerConfig содержит настройки HTTP-сервера.
 	ServerConfig struct {
-		Port string // Порт, на котором будет запущен веб-сервер.
+		Port            string        // Порт, на котором будет запущен веб-сервер.
+		ShutdownTimeout time.Duration // Время ожидания завершения обрабатываемых запросов при остановке.
 	}
 
 	// DatabaseConfig содержит настройки подключения к базе данных.
 	DatabaseConfig struct {
-		URL string // Полная строка подключения к PostgreSQL.
+		URL                string // Полная строка подключения к PostgreSQL.
+		MaxParallelQueries int    // Максимум одновременных запросов к БД, выполняемых параллельно в рамках одного HTTP-запроса.
 	}
 
 	// CORSConfig содержит настройки Cross-Origin Resource Sharing.
@@ -58,10 +70,28 @@ type (
 
 	// OIDCConfig содержит настройки OpenID Connect для аутентификации.
 	OIDCConfig struct {
-		ClientID     string // ID клиента OIDC.
-		ClientSecret string // Секрет клиента OIDC.
-		IssuerURL    string // URL издателя токенов OIDC.
-		RedirectURL  string // URL для перенаправления после аутентификации.
+		ClientID              string   // ID клиента OIDC.
+		ClientSecret          string   // Секрет клиента OIDC.
+		IssuerURL             string   // URL издателя токенов OIDC.
+		RedirectURL           string   // URL для перенаправления после аутентификации.
+		Scopes                []string // Запрашиваемые scopes OIDC; всегда должен содержать openid.
+		PostLogoutRedirectURL string   // URL возврата после выхода у провайдера; пустой - главная страница приложения.
+
+		DiscoveryAttempts int           // Число попыток получить discovery-документ провайдера при запуске.
+		DiscoveryDelay    time.Duration // Пауза перед второй попыткой; каждая следующая пауза удваивается (не более 30 секунд).
+
+		Claims ClaimsConfig // Расположение данных пользователя в claims ID Token'а.
+	}
+
+	// ClaimsConfig содержит пути к claims ID Token'а, из которых заполняется domain.UserClaims.
+	// Путь задается сегментами через точку, например realm_access.roles или resource_access.<client>.roles.
+	ClaimsConfig struct {
+		Subject  string   // Путь к идентификатору пользователя.
+		Username string   // Путь к имени пользователя (логину).
+		Name     string   // Путь к полному имени пользователя.
+		Email    string   // Путь к email пользователя.
+		Roles    []string // Пути к спискам ролей; роли по всем путям объединяются.
+		Required []string // Поля пользователя (subject, username, name, email, roles), без которых токен отклоняется.
 	}
 
 	// MinioConfig содержит настройки подключения к MinIO (S3-совместимое хранилище).
@@ -76,10 +106,76 @@ type (
 
 	// TestingServiceConfig содержит настройки для внешнего сервиса тестирования.
 	TestingServiceConfig struct {
-		BaseURL string // Базовый URL сервиса тестирования.
+		BaseURL        string        // Базовый URL сервиса тестирования.
+		ConnectTimeout time.Duration // Таймаут установки соединения с сервисом тестирования.
+		ReadTimeout    time.Duration // Таймаут получения ответа от сервиса тестирования.
 	}
+
+	// PreviewConfig содержит настройки предпросмотра непубличных курсов редакторами.
+	PreviewConfig struct {
+		Enabled    bool   // Разрешает редакторам просматривать непубличные курсы с параметром ?preview=true.
+		EditorRole string // Роль realm, дающая право на предпросмотр.
+	}
+
+	// CategoriesConfig содержит настройки отображения списков категорий.
+	CategoriesConfig struct {
+		ShowEmpty bool // Показывать ли по умолчанию категории без публичных курсов.
+	}
+
+	// SessionConfig содержит настройки пользовательской сессии OIDC.
+	SessionConfig struct {
+		Lifetime         time.Duration // Время жизни (max-age) cookie сессии и refresh-токена.
+		RefreshThreshold time.Duration // За сколько до истечения ID Token он обновляется по refresh-токену.
+		CookieDomain     string        // Домен cookie сессии; пустой - только текущий хост.
+		CookieSameSite   string        // Политика SameSite cookie сессии: Lax, Strict или None.
+		CookieSecure     bool          // Передавать cookie только по HTTPS.
+		CookieHTTPOnly   bool          // Запретить доступ к cookie из JavaScript.
+	}
+
+	// SwaggerConfig содержит настройки документации API.
+	SwaggerConfig struct {
+		Enabled      bool // Регистрирует Swagger UI и раздачу /doc; по умолчанию совпадает с DEV.
+		SpecWithAuth bool // При выключенном Swagger оставляет /doc/swagger.json доступным по API-ключу.
+	}
+
+	// ContentConfig содержит настройки отображения контента уроков.
+	ContentConfig struct {
+		CodeTheme       string   // Тема подсветки синтаксиса блоков кода (название темы chroma).
+		VideoEmbedHosts []string // Хосты, с которых разрешено встраивать видео через iframe.
+	}
+
+	// CacheConfig содержит настройки in-memory кэша списков категорий и курсов.
+	CacheConfig struct {
+		TTL           time.Duration // Время жизни записи; 0 отключает кэш.
+		MaxEntries    int           // Максимальное количество записей в каждом кэше.
+		StatsInterval time.Duration // Период записи статистики попаданий в лог; 0 отключает.
+		EventsChannel string        // Канал PostgreSQL NOTIFY с событиями изменения от adminPanel; пустой - только TTL.
+		BannerTTL     time.Duration // Время жизни закэшированного баннера сайта; 0 отключает кэш баннера.
+	}
+
+	// APIKeysConfig содержит ключи доступа к публичному API v1 для интеграций без OIDC.
+	// Пустой список означает, что API остается открытым.
+	APIKeysConfig struct {
+		Keys []APIKey // Зарегистрированные ключи.
+	}
+
+	// APIKey описывает один ключ доступа к API. Сам ключ не хранится, только его SHA-256.
+	APIKey struct {
+		ID    string // Идентификатор ключа, который пишется в логи вместо самого ключа.
+		Hash  string // SHA-256 ключа в шестнадцатеричном виде.
+		Scope string // Область доступа: read (только чтение) или full.
+	}
+)
+
+// Области доступа API-ключей.
+const (
+	APIKeyScopeRead = "read" // Только безопасные методы (GET, HEAD, OPTIONS).
+	APIKeyScopeFull = "full" // Любые методы.
 )
 
+// defaultOIDCScopes — набор scopes, запрашиваемый, если OIDC_SCOPES не задан.
+const defaultOIDCScopes = "openid,profile,email"
+
 // Option определяет тип функции, которая конфигурирует объект *Config.
 type Option func(*Config) error
 
@@ -98,6 +194,9 @@ func New(opts ...Option) (*Config, error) {
 }
 
 // WithOIDCFromEnv возвращает Option для конфигурации OIDC из переменных окружения.
+// По умолчанию discovery провайдера при запуске повторяется до 10 раз с паузой от 2 секунд,
+// а данные пользователя читаются из стандартных claims Keycloak (sub, preferred_username, name,
+// email, realm_access.roles); обязателен только sub.
 func WithOIDCFromEnv() Option {
 	return func(cfg *Config) error {
 		var err error
@@ -117,14 +216,36 @@ func WithOIDCFromEnv() Option {
 		if err != nil {
 			return err
 		}
+		cfg.OIDC.Scopes = parseList(getOptionalEnv("OIDC_SCOPES", defaultOIDCScopes))
+		cfg.OIDC.PostLogoutRedirectURL = getOptionalEnv("OIDC_POST_LOGOUT_REDIRECT_URL", "")
+		if cfg.OIDC.DiscoveryAttempts, err = getOptionalEnvAsInt("OIDC_DISCOVERY_ATTEMPTS", 10); err != nil {
+			return err
+		}
+		if cfg.OIDC.DiscoveryDelay, err = getOptionalEnvAsDuration("OIDC_DISCOVERY_DELAY", 2*time.Second); err != nil {
+			return err
+		}
+		cfg.OIDC.Claims = ClaimsConfig{
+			Subject:  getOptionalEnv("OIDC_CLAIM_SUBJECT", "sub"),
+			Username: getOptionalEnv("OIDC_CLAIM_USERNAME", "preferred_username"),
+			Name:     getOptionalEnv("OIDC_CLAIM_NAME", "name"),
+			Email:    getOptionalEnv("OIDC_CLAIM_EMAIL", "email"),
+			Roles:    parseList(getOptionalEnv("OIDC_ROLE_CLAIM_PATHS", "realm_access.roles")),
+			Required: parseList(getOptionalEnv("OIDC_REQUIRED_CLAIMS", "subject")),
+		}
 		return nil
 	}
 }
 
 // WithDBFromEnv возвращает Option для конфигурации базы данных из переменных окружения.
 // Поддерживает как полную строку `DATABASE_URL`, так и отдельные `DB_*` переменные.
+// Ограничение параллельных запросов задается `DB_MAX_PARALLEL_QUERIES`.
 func WithDBFromEnv() Option {
 	return func(cfg *Config) error {
+		var err error
+		if cfg.Database.MaxParallelQueries, err = getOptionalEnvAsInt("DB_MAX_PARALLEL_QUERIES", 4); err != nil {
+			return err
+		}
+
 		databaseURL := os.Getenv("DATABASE_URL")
 		if databaseURL != "" {
 			cfg.Database.URL = databaseURL
@@ -194,6 +315,16 @@ func WithPortFromEnv() Option {
 	}
 }
 
+// WithShutdownFromEnv возвращает Option для конфигурации времени ожидания обрабатываемых запросов
+// при остановке сервера из переменной `SHUTDOWN_TIMEOUT`.
+func WithShutdownFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.Server.ShutdownTimeout, err = getOptionalEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second)
+		return err
+	}
+}
+
 // WithTracingFromEnv возвращает Option для конфигурации OpenTelemetry из переменных окружения.
 func WithTracingFromEnv() Option {
 	return func(cfg *Config) error {
@@ -227,6 +358,18 @@ func WithDevFromEnv() Option {
 	}
 }
 
+// WithStrictUUIDFromEnv возвращает Option для конфигурации строгой проверки UUID из переменной `STRICT_UUID_VALIDATION`.
+func WithStrictUUIDFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.App.StrictUUID, err = getOptionalEnvAsBool("STRICT_UUID_VALIDATION", false)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
 // WithMinioFromEnv возвращает Option для конфигурации MinIO из переменных окружения.
 func WithMinioFromEnv() Option {
 	return func(cfg *Config) error {
@@ -252,10 +395,42 @@ func WithTestingFromEnv() Option {
 		if err != nil {
 			return err
 		}
+		cfg.TestingService.ConnectTimeout, err = getOptionalEnvAsDuration("TESTING_SERVICE_CONNECT_TIMEOUT", time.Second)
+		if err != nil {
+			return err
+		}
+		cfg.TestingService.ReadTimeout, err = getOptionalEnvAsDuration("TESTING_SERVICE_READ_TIMEOUT", 3*time.Second)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// WithPreviewFromEnv возвращает Option для конфигурации предпросмотра непубличных курсов.
+// По умолчанию предпросмотр выключен и публичная часть показывает только публичные курсы.
+func WithPreviewFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.Preview.Enabled, err = getOptionalEnvAsBool("PREVIEW_ENABLED", false)
+		if err != nil {
+			return err
+		}
+		cfg.Preview.EditorRole = getOptionalEnv("PREVIEW_EDITOR_ROLE", "editor")
 		return nil
 	}
 }
 
+// WithCategoriesFromEnv возвращает Option для конфигурации списков категорий.
+// По умолчанию категории без публичных курсов скрыты; запрос может переопределить это параметром show_empty.
+func WithCategoriesFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.Categories.ShowEmpty, err = getOptionalEnvAsBool("SHOW_EMPTY_CATEGORIES", false)
+		return err
+	}
+}
+
 // getRequiredEnv извлекает обязательную переменную окружения.
 // Возвращает ошибку, если переменная не установлена или пуста.
 func getRequiredEnv(key string) (string, error) {
@@ -283,3 +458,142 @@ func getOptionalEnvAsBool(key string, defaultValue bool) (bool, error) {
 	}
 	return value, nil
 }
+
+// getOptionalEnvAsInt извлекает необязательную переменную окружения как целое число.
+func getOptionalEnvAsInt(key string, defaultValue int) (int, error) {
+	valueStr := getOptionalEnv(key, strconv.Itoa(defaultValue))
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse environment variable '%s' as integer: %w", key, err)
+	}
+	return value, nil
+}
+
+// getOptionalEnvAsDuration извлекает необязательную переменную окружения как time.Duration (например, "5s").
+func getOptionalEnvAsDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	valueStr := getOptionalEnv(key, defaultValue.String())
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse environment variable '%s' as duration: %w", key, err)
+	}
+	return value, nil
+}
+
+// WithSessionFromEnv возвращает Option для конфигурации пользовательской сессии.
+// По умолчанию cookie сессии живут 24 часа, ID Token обновляется за минуту до истечения,
+// а cookie выдаются с HttpOnly, SameSite=Lax и Secure вне режима разработки.
+// Значение Secure по умолчанию зависит от DEV, поэтому опция применяется после WithDevFromEnv.
+func WithSessionFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.Session.Lifetime, err = getOptionalEnvAsDuration("SESSION_LIFETIME", 24*time.Hour)
+		if err != nil {
+			return err
+		}
+		cfg.Session.RefreshThreshold, err = getOptionalEnvAsDuration("SESSION_REFRESH_THRESHOLD", time.Minute)
+		if err != nil {
+			return err
+		}
+		cfg.Session.CookieDomain = getOptionalEnv("SESSION_COOKIE_DOMAIN", "")
+		cfg.Session.CookieSameSite = getOptionalEnv("SESSION_COOKIE_SAMESITE", "Lax")
+		cfg.Session.CookieSecure, err = getOptionalEnvAsBool("SESSION_COOKIE_SECURE", !cfg.App.Dev)
+		if err != nil {
+			return err
+		}
+		cfg.Session.CookieHTTPOnly, err = getOptionalEnvAsBool("SESSION_COOKIE_HTTPONLY", true)
+		return err
+	}
+}
+
+// WithAPIKeysFromEnv возвращает Option для конфигурации API-ключей из переменной `API_KEYS`.
+// Формат: список через запятую элементов вида id:sha256[:scope]; scope по умолчанию read.
+// Корректность идентификаторов, хешей и областей проверяется в Validate.
+func WithAPIKeysFromEnv() Option {
+	return func(cfg *Config) error {
+		cfg.APIKeys.Keys = nil
+		for _, entry := range parseList(getOptionalEnv("API_KEYS", "")) {
+			parts := strings.Split(entry, ":")
+			key := APIKey{ID: strings.TrimSpace(parts[0]), Scope: APIKeyScopeRead}
+			if len(parts) > 1 {
+				key.Hash = strings.ToLower(strings.TrimSpace(parts[1]))
+			}
+			if len(parts) > 2 {
+				key.Scope = strings.ToLower(strings.TrimSpace(parts[2]))
+			}
+			if len(parts) > 3 {
+				return fmt.Errorf("failed to parse API_KEYS entry for key %q: expected id:sha256[:scope]", key.ID)
+			}
+			cfg.APIKeys.Keys = append(cfg.APIKeys.Keys, key)
+		}
+		return nil
+	}
+}
+
+// WithSwaggerFromEnv возвращает Option для конфигурации документации API из переменных
+// `SWAGGER_ENABLED` и `SWAGGER_SPEC_WITH_AUTH`. По умолчанию Swagger включен только в режиме
+// разработки, поэтому опция применяется после WithDevFromEnv.
+func WithSwaggerFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		cfg.Swagger.Enabled, err = getOptionalEnvAsBool("SWAGGER_ENABLED", cfg.App.Dev)
+		if err != nil {
+			return err
+		}
+		cfg.Swagger.SpecWithAuth, err = getOptionalEnvAsBool("SWAGGER_SPEC_WITH_AUTH", false)
+		return err
+	}
+}
+
+// WithContentFromEnv возвращает Option для конфигурации отображения контента уроков.
+// По умолчанию блоки кода подсвечиваются темой github, а видео можно встраивать с YouTube и Vimeo.
+func WithContentFromEnv() Option {
+	return func(cfg *Config) error {
+		cfg.Content.CodeTheme = getOptionalEnv("CODE_HIGHLIGHT_THEME", "github")
+		// Пустое значение, в отличие от отсутствующей переменной, отключает встраивание видео.
+		embedHosts, ok := os.LookupEnv("VIDEO_EMBED_ALLOWED_HOSTS")
+		if !ok {
+			embedHosts = "www.youtube-nocookie.com,player.vimeo.com"
+		}
+		cfg.Content.VideoEmbedHosts = parseList(embedHosts)
+		return nil
+	}
+}
+
+// WithCacheFromEnv возвращает Option для конфигурации кэша публичных данных
+// из переменных `CACHE_TTL`, `CACHE_MAX_ENTRIES`, `CACHE_STATS_INTERVAL`, `CONTENT_EVENTS_CHANNEL`
+// и `BANNER_CACHE_TTL`.
+func WithCacheFromEnv() Option {
+	return func(cfg *Config) error {
+		var err error
+		if cfg.Cache.TTL, err = getOptionalEnvAsDuration("CACHE_TTL", 30*time.Second); err != nil {
+			return err
+		}
+		if cfg.Cache.MaxEntries, err = getOptionalEnvAsInt("CACHE_MAX_ENTRIES", 1000); err != nil {
+			return err
+		}
+		if cfg.Cache.StatsInterval, err = getOptionalEnvAsDuration("CACHE_STATS_INTERVAL", 5*time.Minute); err != nil {
+			return err
+		}
+		cfg.Cache.EventsChannel = getOptionalEnv("CONTENT_EVENTS_CHANNEL", "")
+		if cfg.Cache.BannerTTL, err = getOptionalEnvAsDuration("BANNER_CACHE_TTL", 15*time.Second); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// parseList разбирает список значений, разделенных запятыми,
+// отбрасывая пустые элементы и повторы с сохранением порядка.
+func parseList(raw string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,34 @@
+// Package domain определяет основные бизнес-сущности и модели данных,
+// которые используются во всем приложении.
+package domain
+
+import "context"
+
+// Уровни важности баннера сайта.
+const (
+	BannerSeverityInfo     = "info"
+	BannerSeverityWarning  = "warning"
+	BannerSeverityCritical = "critical"
+)
+
+// Banner представляет общее для всего сайта уведомление (например, о плановых работах),
+// которое задается в панели администратора.
+type Banner struct {
+	Message  string // Текст уведомления
+	Severity string // Уровень важности: info, warning или critical
+	Active   bool   // Показывается ли баннер
+}
+
+// bannerContextKey - ключ контекста с баннером сайта для текущего запроса.
+type bannerContextKey struct{}
+
+// WithBanner возвращает контекст с баннером сайта, который нужно показать на странице.
+func WithBanner(ctx context.Context, banner *Banner) context.Context {
+	return context.WithValue(ctx, bannerContextKey{}, banner)
+}
+
+// BannerFromContext возвращает баннер сайта из контекста или nil, если показывать нечего.
+func BannerFromContext(ctx context.Context) *Banner {
+	banner, _ := ctx.Value(bannerContextKey{}).(*Banner)
+	return banner
+}
@@ -2,17 +2,65 @@
 // которые используются во всем приложении.
 package domain
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Допустимые уровни сложности курса.
+const (
+	LevelEasy   = "easy"
+	LevelMedium = "medium"
+	LevelHard   = "hard"
+)
+
+// Levels перечисляет допустимые уровни сложности в порядке возрастания.
+var Levels = []string{LevelEasy, LevelMedium, LevelHard}
+
+// ParseLevels разбирает список уровней сложности, перечисленных через запятую (например, "easy,medium").
+// Пустая строка и значение "all" означают отсутствие фильтра и возвращают nil.
+// Возвращает ошибку, если хотя бы один уровень не входит в допустимый перечень.
+func ParseLevels(raw string) ([]string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "all" {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var levels []string
+	for _, level := range strings.Split(raw, ",") {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if level == "" || seen[level] {
+			continue
+		}
+		if !slices.Contains(Levels, level) {
+			return nil, fmt.Errorf("invalid level %q: allowed values are %s", level, strings.Join(Levels, ", "))
+		}
+		seen[level] = true
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
 
 // Course представляет собой учебный курс.
 type Course struct {
-	ID          string    `json:"id"`          // Уникальный идентификатор
-	Title       string    `json:"title"`       // Название курса
-	Description string    `json:"description"` // Описание курса
-	Level       string    `json:"level"`       // Уровень сложности (easy, medium, hard)
-	Visibility  string    `json:"visibility"`  // Видимость (draft, public)
-	CategoryID  string    `json:"category_id"` // ID категории, к которой относится курс
-	ImageKey    string    `json:"image_key"`   // Ключ изображения в S3/MinIO
-	CreatedAt   time.Time `json:"created_at"`  // Время создания
-	UpdatedAt   time.Time `json:"updated_at"`  // Время последнего обновления
+	ID          string `json:"id"`          // Уникальный идентификатор
+	Title       string `json:"title"`       // Название курса
+	Slug        string `json:"slug"`        // URL slug, уникальный в пределах категории
+	Description string `json:"description"` // Описание курса
+	Level       string `json:"level"`       // Уровень сложности (easy, medium, hard)
+	Visibility  string `json:"visibility"`  // Видимость (draft, public)
+	CategoryID  string `json:"category_id"` // ID категории, к которой относится курс
+	ImageKey    string `json:"image_key"`   // Ключ изображения в S3/MinIO
+	// ContentPolicy - политика разметки HTML-контента уроков (strict, relaxed).
+	ContentPolicy string `json:"content_policy"`
+	// CategoryTitle и CategorySlug - название и slug категории курса; заполняются только запросами,
+	// которые присоединяют категорию.
+	CategoryTitle string    `json:"category_title,omitempty"`
+	CategorySlug  string    `json:"category_slug,omitempty"`
+	CreatedAt     time.Time `json:"created_at"` // Время создания
+	UpdatedAt     time.Time `json:"updated_at"` // Время последнего обновления
 }
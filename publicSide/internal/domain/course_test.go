@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{raw: "", want: nil},
+		{raw: "all", want: nil},
+		{raw: "easy", want: []string{LevelEasy}},
+		{raw: " Easy , hard,easy,, ", want: []string{LevelEasy, LevelHard}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevels(tt.raw)
+		if err != nil {
+			t.Errorf("ParseLevels(%q) error = %v", tt.raw, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseLevels(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseLevelsRejectsUnknownLevel(t *testing.T) {
+	_, err := ParseLevels("easy,expert")
+	if err == nil {
+		t.Fatal("ParseLevels() error = nil, want error for unknown level")
+	}
+	if !strings.Contains(err.Error(), `"expert"`) {
+		t.Errorf("ParseLevels() error = %q, want it to name the rejected level", err)
+	}
+}
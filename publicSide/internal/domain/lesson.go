@@ -6,10 +6,12 @@ import "time"
 
 // Lesson представляет собой урок в рамках курса.
 type Lesson struct {
-	ID        string    `json:"id"`        // Уникальный идентификатор
-	Title     string    `json:"title"`     // Название урока
-	CourseID  string    `json:"course_id"` // ID курса, к которому относится урок
-	Content   string    `json:"content"`   // Содержимое урока (HTML/Markdown)
-	CreatedAt time.Time `json:"created_at"`// Время создания
-	UpdatedAt time.Time `json:"updated_at"`// Время последнего обновления
+	ID         string    `json:"id"`          // Уникальный идентификатор
+	Title      string    `json:"title"`       // Название урока
+	Slug       string    `json:"slug"`        // URL slug, уникальный в пределах курса
+	CourseID   string    `json:"course_id"`   // ID курса, к которому относится урок
+	Content    string    `json:"content"`     // Содержимое урока (HTML/Markdown)
+	OrderIndex int       `json:"order_index"` // Позиция урока в курсе
+	CreatedAt  time.Time `json:"created_at"`  // Время создания
+	UpdatedAt  time.Time `json:"updated_at"`  // Время последнего обновления
 }
@@ -0,0 +1,20 @@
+// Package domain определяет основные бизнес-сущности и модели данных,
+// которые используются во всем приложении.
+package domain
+
+import "context"
+
+// previewContextKey - ключ контекста, отмечающий запрос в режиме предпросмотра.
+type previewContextKey struct{}
+
+// WithPreview возвращает контекст, в котором запросы к курсам включают непубличные курсы.
+// Должен устанавливаться только для аутентифицированных редакторов.
+func WithPreview(ctx context.Context) context.Context {
+	return context.WithValue(ctx, previewContextKey{}, true)
+}
+
+// IsPreview сообщает, выполняется ли запрос в режиме предпросмотра.
+func IsPreview(ctx context.Context) bool {
+	preview, _ := ctx.Value(previewContextKey{}).(bool)
+	return preview
+}
@@ -10,3 +10,11 @@ type Test struct {
 	MinPoint    int    // Минимальный балл для прохождения
 	Description string // Описание теста
 }
+
+// TestStats представляет собой статистику прохождения итогового теста по курсу.
+type TestStats struct {
+	TestID         string // ID теста
+	QuestionCount  int    // Количество вопросов в тесте
+	AttemptsTotal  int    // Общее количество попыток прохождения
+	AttemptsPassed int    // Количество успешных попыток
+}
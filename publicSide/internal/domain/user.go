@@ -7,12 +7,26 @@ const (
 	UserContextKey = "user"
 	// SessionTokenCookie - имя cookie, в котором хранится сессионный токен (ID Token).
 	SessionTokenCookie = "session_token"
+	// RefreshTokenCookie - имя cookie, в котором хранится зашифрованный refresh-токен.
+	RefreshTokenCookie = "refresh_token"
 )
 
 // UserClaims представляет информацию о пользователе, извлеченную из ID Token'а.
+// Расположение полей в claims задается конфигурацией (см. config.ClaimsConfig).
 type UserClaims struct {
-	ID       string `json:"sub"`                // Уникальный идентификатор пользователя (Subject)
-	Email    string `json:"email"`              // Email пользователя
-	Name     string `json:"name"`               // Полное имя пользователя
-	Username string `json:"preferred_username"` // Предпочитаемое имя пользователя (логин)
+	ID       string   // Уникальный идентификатор пользователя (Subject)
+	Email    string   // Email пользователя
+	Name     string   // Полное имя пользователя
+	Username string   // Предпочитаемое имя пользователя (логин)
+	Roles    []string // Роли пользователя, собранные по всем настроенным путям
+}
+
+// HasRole проверяет, назначена ли пользователю роль `role`.
+func (u UserClaims) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,81 @@
+package dto_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	testingclient "github.com/TaurineMerge/LMS_Tages/publicSide/internal/clients/testing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/request"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+)
+
+// snakeCase описывает допустимое имя JSON-поля.
+var snakeCase = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// wireFields содержит имена полей, формат которых задает внешний сервис.
+var wireFields = map[string]bool{
+	"TestData.courseId": true,
+}
+
+func TestJSONTagsAreSnakeCase(t *testing.T) {
+	types := []any{
+		domain.Category{},
+		domain.Course{},
+		domain.Lesson{},
+
+		request.CategoriesQuery{},
+		request.CoursesBatchRequest{},
+		request.LessonsQuery{},
+		request.ListQuery{},
+		request.PaginationQuery{},
+
+		response.CategoryDTO{},
+		response.CourseDTO{},
+		response.ErrorDetail{},
+		response.ErrorResponse{},
+		response.LessonDTO{},
+		response.LessonDTODetailed{},
+		response.LessonPrefetch{},
+		response.PaginatedCategoriesData{},
+		response.PaginatedCoursesData{},
+		response.PaginatedLessonsData{},
+		response.CursorLessonsData{},
+		response.Pagination{},
+		response.SuccessResponse{},
+
+		testingclient.TestResponse{},
+		testingclient.TestStatsResponse{},
+	}
+
+	seen := make(map[reflect.Type]bool)
+	for _, v := range types {
+		checkJSONTags(t, reflect.TypeOf(v), seen)
+	}
+}
+
+// checkJSONTags проверяет теги json у полей структуры и вложенных в нее структур.
+func checkJSONTags(t *testing.T, typ reflect.Type, seen map[reflect.Type]bool) {
+	t.Helper()
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" && !snakeCase.MatchString(name) && !wireFields[typ.Name()+"."+name] {
+			t.Errorf("%s.%s: json tag %q is not snake_case", typ, field.Name, name)
+		}
+		checkJSONTags(t, field.Type, seen)
+	}
+}
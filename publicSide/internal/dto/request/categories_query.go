@@ -0,0 +1,24 @@
+// Package request содержит структуры данных для разбора входящих HTTP-запросов.
+package request
+
+import "strconv"
+
+// CategoriesQuery представляет собой параметры запроса для списков категорий:
+// пагинацию и необязательное переопределение показа пустых категорий.
+type CategoriesQuery struct {
+	PaginationQuery
+	ShowEmpty string `query:"show_empty"` // "true" или "false"; пустое значение означает настройку по умолчанию.
+}
+
+// ShowEmptyOverride разбирает параметр show_empty.
+// Возвращает nil, если параметр не задан, и ошибку, если значение не является булевым.
+func (q CategoriesQuery) ShowEmptyOverride() (*bool, error) {
+	if q.ShowEmpty == "" {
+		return nil, nil
+	}
+	showEmpty, err := strconv.ParseBool(q.ShowEmpty)
+	if err != nil {
+		return nil, err
+	}
+	return &showEmpty, nil
+}
@@ -0,0 +1,7 @@
+// Package request содержит структуры данных для разбора входящих HTTP-запросов.
+package request
+
+// CoursesBatchRequest представляет собой тело запроса на получение нескольких курсов по ID.
+type CoursesBatchRequest struct {
+	IDs []string `json:"ids"` // ID курсов из любых категорий.
+}
@@ -0,0 +1,10 @@
+// Package request содержит структуры данных для разбора входящих HTTP-запросов.
+package request
+
+// LessonsQuery представляет собой параметры запроса для списка уроков курса.
+// Помимо страничной пагинации поддерживает курсорную: при наличии параметра cursor
+// page и sort игнорируются, а уроки возвращаются в порядке (created_at, id).
+type LessonsQuery struct {
+	ListQuery
+	Cursor string `query:"cursor"` // Непрозрачный курсор из next_cursor; пустое значение означает первую страницу.
+}
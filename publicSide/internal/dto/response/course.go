@@ -6,12 +6,16 @@ import "time"
 // CourseDTO - это объект передачи данных (DTO) для курса.
 // Используется для отправки информации о курсе клиенту.
 type CourseDTO struct {
-	ID          string    `json:"id"`           // Уникальный идентификатор курса.
-	Title       string    `json:"title"`        // Название курса.
-	Description string    `json:"description"`  // Описание курса.
-	Level       string    `json:"level"`        // Уровень сложности.
-	CategoryID  string    `json:"category_id"`  // ID категории, к которой относится курс.
-	ImageURL    string    `json:"image_url"`    // URL изображения курса.
-	CreatedAt   time.Time `json:"created_at"`   // Время создания.
-	UpdatedAt   time.Time `json:"updated_at"`   // Время последнего обновления.
+	ID            string    `json:"id"`                       // Уникальный идентификатор курса.
+	Title         string    `json:"title"`                    // Название курса.
+	Slug          string    `json:"slug"`                     // Человекочитаемый идентификатор курса для URL.
+	Description   string    `json:"description"`              // Описание курса.
+	Level         string    `json:"level"`                    // Уровень сложности.
+	CategoryID    string    `json:"category_id"`              // ID категории, к которой относится курс.
+	CategoryTitle string    `json:"category_title,omitempty"` // Название категории (только в выборках курсов из разных категорий).
+	CategorySlug  string    `json:"category_slug,omitempty"`  // Slug категории (только в выборках курсов из разных категорий).
+	ImageURL      string    `json:"image_url"`                // URL изображения курса.
+	ContentPolicy string    `json:"content_policy"`           // Политика разметки HTML-контента уроков (strict, relaxed).
+	CreatedAt     time.Time `json:"created_at"`               // Время создания.
+	UpdatedAt     time.Time `json:"updated_at"`               // Время последнего обновления.
 }
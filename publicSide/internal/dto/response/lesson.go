@@ -6,9 +6,13 @@ import "time"
 // LessonDTO - это объект передачи данных (DTO) для урока (краткая версия).
 // Используется для отправки информации об уроке без его содержимого, например, в списках.
 type LessonDTO struct {
-	ID        string    `json:"id"`         // Уникальный идентификатор урока.
-	Title     string    `json:"title"`      // Название урока.
-	CourseID  string    `json:"course_id"`  // ID курса, к которому относится урок.
-	CreatedAt time.Time `json:"created_at"` // Время создания.
-	UpdatedAt time.Time `json:"updated_at"` // Время последнего обновления.
+	ID                 string    `json:"id"`                   // Уникальный идентификатор урока.
+	Title              string    `json:"title"`                // Название урока.
+	Slug               string    `json:"slug"`                 // Slug урока для URL (пустой, если еще не заполнен).
+	CourseID           string    `json:"course_id"`            // ID курса, к которому относится урок.
+	OrderIndex         int       `json:"order_index"`          // Позиция урока в курсе.
+	ContentLength      int       `json:"content_length"`       // Количество символов текста урока.
+	ReadingTimeMinutes int       `json:"reading_time_minutes"` // Оценка времени чтения в минутах (не меньше 1).
+	CreatedAt          time.Time `json:"created_at"`           // Время создания.
+	UpdatedAt          time.Time `json:"updated_at"`           // Время последнего обновления.
 }
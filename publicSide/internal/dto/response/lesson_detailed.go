@@ -8,10 +8,23 @@ import (
 // LessonDTODetailed - это объект передачи данных (DTO) для урока (детальная версия).
 // Используется для отправки полной информации об уроке, включая его содержимое.
 type LessonDTODetailed struct {
-	ID        string    `json:"id"`         // Уникальный идентификатор урока.
-	Title     string    `json:"title"`      // Название урока.
-	CourseID  string    `json:"course_id"`  // ID курса, к которому относится урок.
-	Content   string    `json:"content"`    // Содержимое урока (HTML/Markdown).
-	CreatedAt time.Time `json:"created_at"` // Время создания.
-	UpdatedAt time.Time `json:"updated_at"` // Время последнего обновления.
+	ID                 string    `json:"id"`                   // Уникальный идентификатор урока.
+	Title              string    `json:"title"`                // Название урока.
+	Slug               string    `json:"slug"`                 // Slug урока для URL (пустой, если еще не заполнен).
+	CourseID           string    `json:"course_id"`            // ID курса, к которому относится урок.
+	Content            string    `json:"content"`              // Содержимое урока (HTML/Markdown).
+	OrderIndex         int       `json:"order_index"`          // Позиция урока в курсе.
+	ContentLength      int       `json:"content_length"`       // Количество символов текста урока.
+	ReadingTimeMinutes int       `json:"reading_time_minutes"` // Оценка времени чтения в минутах (не меньше 1).
+	CreatedAt          time.Time `json:"created_at"`           // Время создания.
+	UpdatedAt          time.Time `json:"updated_at"`           // Время последнего обновления.
+	// Prefetch содержит ID соседних уроков для предзагрузки на клиенте.
+	Prefetch *LessonPrefetch `json:"prefetch,omitempty"`
+}
+
+// LessonPrefetch содержит ID уроков, окружающих текущий, в порядке следования курса.
+type LessonPrefetch struct {
+	Window   int      `json:"window"`   // Размер окна (количество уроков в каждую сторону).
+	Previous []string `json:"previous"` // ID предыдущих уроков.
+	Next     []string `json:"next"`     // ID следующих уроков.
 }
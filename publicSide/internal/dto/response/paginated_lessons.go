@@ -7,3 +7,10 @@ type PaginatedLessonsData struct {
 	Items      []LessonDTO `json:"items"`      // Срез (список) уроков на текущей странице.
 	Pagination Pagination  `json:"pagination"` // Информация о пагинации.
 }
+
+// CursorLessonsData представляет собой структуру данных для ответа со страницей уроков,
+// полученной по курсору (keyset-пагинация по created_at и id).
+type CursorLessonsData struct {
+	Items      []LessonDTO `json:"items"`       // Срез (список) уроков на текущей странице.
+	NextCursor *string     `json:"next_cursor"` // Курсор следующей страницы; null, если страница последняя.
+}
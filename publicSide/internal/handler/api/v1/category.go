@@ -4,11 +4,12 @@ package v1
 import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/request"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // CategoryHandler обрабатывает HTTP-запросы, связанные с категориями.
@@ -23,33 +24,36 @@ func NewCategoryHandler(s service.CategoryService) *CategoryHandler {
 
 // GetAllCategories обрабатывает запрос на получение списка всех категорий с пагинацией.
 // @Summary Получить список всех категорий
-// @Description Получает страницы списка всех категорий.
+// @Description Получает страницы списка категорий. По умолчанию пустые категории скрыты или показаны согласно настройке SHOW_EMPTY_CATEGORIES.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
+// @Param show_empty query bool false "Показывать категории без публичных курсов (переопределяет настройку по умолчанию)"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
 // @Success 200 {object} response.SuccessResponse{data=response.PaginatedCategoriesData} "Успешный ответ"
 // @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
 // @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories [get]
 func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
-	var query request.PaginationQuery
+	var query request.CategoriesQuery
 	if err := c.QueryParser(&query); err != nil {
 		return apperrors.NewInvalidRequest("Wrong query parameters")
 	}
+	showEmpty, err := query.ShowEmptyOverride()
+	if err != nil {
+		return apperrors.NewInvalidRequest("Query parameter 'show_empty' must be a boolean")
+	}
 
-	categories, pagination, err := h.service.GetAll(c.UserContext(), query.Page, query.Limit)
+	categories, pagination, err := h.service.List(c.UserContext(), query.Page, query.Limit, showEmpty)
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data: response.PaginatedCategoriesData{
-			Items:      categories,
-			Pagination: pagination,
-		},
+	return handler.SendSuccess(c, response.PaginatedCategoriesData{
+		Items:      categories,
+		Pagination: pagination,
 	})
 }
 
@@ -60,6 +64,7 @@ func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param category_id path string true "Уникальный идентификатор категории"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
 // @Success 200 {object} response.SuccessResponse{data=response.CategoryDTO} "Успешный ответ"
 // @Failure 400 {object} response.ErrorResponse "Неверный формат ID"
 // @Failure 404 {object} response.ErrorResponse "Категория не найдена"
@@ -67,7 +72,7 @@ func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
 // @Router /categories/{category_id} [get]
 func (h *CategoryHandler) GetCategoryByID(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 
@@ -76,8 +81,5 @@ func (h *CategoryHandler) GetCategoryByID(c *fiber.Ctx) error {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data:   category,
-	})
+	return handler.SendSuccess(c, category)
 }
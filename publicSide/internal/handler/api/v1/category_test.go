@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/middleware"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/gofiber/fiber/v2"
+)
+
+const testCategoryID = "6f1f3c2e-8a4b-4c1d-9e2f-3a5b7c9d1e0f"
+
+// stubCategoryService возвращает одну и ту же категорию на любой запрос GetByID.
+type stubCategoryService struct {
+	service.CategoryService
+}
+
+func (stubCategoryService) GetByID(_ context.Context, categoryID string) (response.CategoryDTO, error) {
+	return response.CategoryDTO{ID: categoryID, Title: "Go"}, nil
+}
+
+func TestGetCategoryByIDEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		accept   string
+		envelope bool
+	}{
+		{name: "default", envelope: true},
+		{name: "envelope query false", query: "?envelope=false", envelope: false},
+		{name: "envelope query true", query: "?envelope=true", envelope: true},
+		{name: "raw profile", accept: "application/json; profile=raw", envelope: false},
+		{name: "quoted raw profile among media types", accept: `text/html, application/json;q=0.9;profile="RAW"`, envelope: false},
+		{name: "other profile", accept: "application/json; profile=full", envelope: true},
+		{name: "query overrides profile", query: "?envelope=true", accept: "application/json; profile=raw", envelope: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := getCategory(t, tt.query, tt.accept)
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderVary); got != fiber.HeaderAccept {
+				t.Errorf("Vary = %q, want %q", got, fiber.HeaderAccept)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			var category response.CategoryDTO
+			if tt.envelope {
+				var wrapped struct {
+					Status string               `json:"status"`
+					Data   response.CategoryDTO `json:"data"`
+				}
+				if err := json.Unmarshal(body, &wrapped); err != nil {
+					t.Fatalf("unmarshal %s: %v", body, err)
+				}
+				if wrapped.Status != response.StatusSuccess {
+					t.Errorf("status field = %q, want %q", wrapped.Status, response.StatusSuccess)
+				}
+				category = wrapped.Data
+			} else {
+				var fields map[string]json.RawMessage
+				if err := json.Unmarshal(body, &fields); err != nil {
+					t.Fatalf("unmarshal %s: %v", body, err)
+				}
+				if _, ok := fields["data"]; ok {
+					t.Errorf("raw body %s must not be wrapped", body)
+				}
+				if err := json.Unmarshal(body, &category); err != nil {
+					t.Fatalf("unmarshal %s: %v", body, err)
+				}
+			}
+			if category.ID != testCategoryID || category.Title != "Go" {
+				t.Errorf("category = %+v, want id %s and title Go", category, testCategoryID)
+			}
+		})
+	}
+}
+
+func TestGetCategoryByIDRejectsInvalidEnvelope(t *testing.T) {
+	resp := getCategory(t, "?envelope=maybe", "application/json; profile=raw")
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+
+	// Ошибки возвращаются в обертке независимо от запрошенной формы ответа.
+	var body response.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != response.StatusError || body.Error.Code == "" {
+		t.Errorf("body = %+v, want enveloped error", body)
+	}
+}
+
+// getCategory выполняет GET /api/v1/categories/{id} с заданными строкой запроса и заголовком Accept.
+func getCategory(t *testing.T, query, accept string) *http.Response {
+	t.Helper()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.CommonErrorHandler})
+	app.Get("/api/v1"+routing.RouteCategory, NewCategoryHandler(stubCategoryService{}).GetCategoryByID)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/api/v1/categories/"+testCategoryID+query, nil)
+	if accept != "" {
+		req.Header.Set(fiber.HeaderAccept, accept)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	return resp
+}
@@ -4,11 +4,12 @@ package v1
 import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/request"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // CourseHandler обрабатывает HTTP-запросы, связанные с курсами.
@@ -32,14 +33,19 @@ func NewCourseHandler(courseService service.CourseService) *CourseHandler {
 // @Param category_id path string true "Уникальный идентификатор категории"
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
+// @Param level query string false "Уровни сложности через запятую (easy, medium, hard)"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
 // @Success 200 {object} response.SuccessResponse{data=response.PaginatedCoursesData} "Успешный ответ"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
 // @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
 // @Failure 404 {object} response.ErrorResponse "Категория не найдена"
 // @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories/{category_id}/courses [get]
 func (h *CourseHandler) GetCoursesByCategoryID(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 
@@ -48,18 +54,15 @@ func (h *CourseHandler) GetCoursesByCategoryID(c *fiber.Ctx) error {
 		return apperrors.NewInvalidRequest("Wrong query parameters")
 	}
 
-	// В API не используются фильтры по уровню и сортировка, передаем пустые строки.
-	courses, pagination, err := h.courseService.GetCoursesByCategoryID(c.UserContext(), categoryID, query.Page, query.Limit, "", "")
+	// В API не используется сортировка, передаем пустую строку.
+	courses, pagination, err := h.courseService.GetCoursesByCategoryID(c.UserContext(), categoryID, query.Page, query.Limit, c.Query("level"), "")
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data: response.PaginatedCoursesData{
-			Items:      courses,
-			Pagination: pagination,
-		},
+	return handler.SendSuccessWithETag(c, response.PaginatedCoursesData{
+		Items:      courses,
+		Pagination: pagination,
 	})
 }
 
@@ -71,19 +74,23 @@ func (h *CourseHandler) GetCoursesByCategoryID(c *fiber.Ctx) error {
 // @Produce json
 // @Param category_id path string true "Уникальный идентификатор категории"
 // @Param course_id path string true "Уникальный идентификатор курса"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
 // @Success 200 {object} response.SuccessResponse{data=response.CourseDTO} "Успешный ответ"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
 // @Failure 400 {object} response.ErrorResponse "Неверный формат ID"
 // @Failure 404 {object} response.ErrorResponse "Категория или курс не найдены"
 // @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories/{category_id}/courses/{course_id} [get]
 func (h *CourseHandler) GetCourseByID(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 
 	courseID := c.Params(routing.PathVariableCourseID)
-	if _, err := uuid.Parse(courseID); err != nil {
+	if err := utils.ValidateUUID(courseID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCourseID)
 	}
 
@@ -92,8 +99,68 @@ func (h *CourseHandler) GetCourseByID(c *fiber.Ctx) error {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data:   course,
-	})
+	return handler.SendSuccessWithETag(c, course)
+}
+
+// GetCourseBySlug обрабатывает запрос на получение одного курса по его slug.
+// @Summary Получить курс по slug
+// @Description Получает детали одного курса по человекочитаемому slug в рамках категории.
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Param category_id path string true "Уникальный идентификатор категории"
+// @Param slug path string true "Slug курса"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
+// @Success 200 {object} response.SuccessResponse{data=response.CourseDTO} "Успешный ответ"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} response.ErrorResponse "Неверный формат ID или пустой slug"
+// @Failure 404 {object} response.ErrorResponse "Категория или курс не найдены"
+// @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/courses/slug/{slug} [get]
+func (h *CourseHandler) GetCourseBySlug(c *fiber.Ctx) error {
+	categoryID := c.Params(routing.PathVariableCategoryID)
+	if err := utils.ValidateUUID(categoryID); err != nil {
+		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
+	}
+
+	course, err := h.courseService.GetCourseBySlug(c.UserContext(), categoryID, c.Params(routing.PathVariableCourseSlug))
+	if err != nil {
+		return err
+	}
+
+	return handler.SendSuccessWithETag(c, course)
+}
+
+// GetCoursesByIDs обрабатывает запрос на получение нескольких курсов по их ID из любых категорий.
+// @Summary Получить курсы по списку ID
+// @Description Получает публичные курсы по списку UUID независимо от категории. Неизвестные и непубличные курсы пропускаются.
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Param request body request.CoursesBatchRequest true "Список ID курсов (не более 100)"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
+// @Success 200 {object} response.SuccessResponse{data=[]response.CourseDTO} "Успешный ответ"
+// @Failure 400 {object} response.ErrorResponse "Неверное тело запроса, формат ID или слишком много ID"
+// @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /courses/batch [post]
+func (h *CourseHandler) GetCoursesByIDs(c *fiber.Ctx) error {
+	var req request.CoursesBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperrors.NewInvalidRequest("Request body must be a JSON object with an 'ids' array")
+	}
+
+	for _, id := range req.IDs {
+		if err := utils.ValidateUUID(id); err != nil {
+			return apperrors.NewInvalidUUID("ids")
+		}
+	}
+
+	courses, err := h.courseService.GetCoursesByIDs(c.UserContext(), req.IDs)
+	if err != nil {
+		return err
+	}
+
+	return handler.SendSuccess(c, courses)
 }
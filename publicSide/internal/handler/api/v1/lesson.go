@@ -2,13 +2,16 @@
 package v1
 
 import (
+	"strconv"
+
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/request"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // LessonHandler обрабатывает HTTP-запросы, связанные с уроками.
@@ -24,6 +27,9 @@ func NewLessonHandler(s service.LessonService) *LessonHandler {
 // GetLessonsByCourseID обрабатывает запрос на получение списка уроков для конкретного курса.
 // @Summary Получить список уроков курса
 // @Description Получает страницы списка уроков для указанного курса. Поддерживает пагинацию и сортировку.
+// @Description Если передан параметр cursor (в том числе пустой для первой страницы), используется курсорная пагинация:
+// @Description уроки упорядочены по (created_at, id), page и sort игнорируются, а ответ содержит next_cursor
+// @Description (null на последней странице). Курсор непрозрачен и передается без изменений.
 // @Tags Lessons
 // @Accept json
 // @Produce json
@@ -31,38 +37,54 @@ func NewLessonHandler(s service.LessonService) *LessonHandler {
 // @Param course_id path string true "Уникальный идентификатор курса"
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(20)
-// @Param sort query string false "Поле и порядок сортировки (например, -created_at)"
-// @Success 200 {object} response.SuccessResponse{data=response.PaginatedLessonsData} "Успешный ответ"
+// @Param sort query string false "Поле и порядок сортировки (например, -created_at): order_index, title, created_at, updated_at; неизвестное поле - 400 VALIDATION_ERROR"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor (включает курсорную пагинацию)"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
+// @Success 200 {object} response.SuccessResponse{data=response.PaginatedLessonsData} "Успешный ответ (страничная пагинация)"
+// @Success 200 {object} response.SuccessResponse{data=response.CursorLessonsData} "Успешный ответ (курсорная пагинация)"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
 // @Failure 400 {object} response.ErrorResponse "Неверные параметры запроса"
 // @Failure 404 {object} response.ErrorResponse "Категория или курс не найдены"
 // @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories/{category_id}/courses/{course_id}/lessons [get]
 func (h *LessonHandler) GetLessonsByCourseID(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 	courseID := c.Params(routing.PathVariableCourseID)
-	if _, err := uuid.Parse(courseID); err != nil {
+	if err := utils.ValidateUUID(courseID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCourseID)
 	}
 
-	var query request.ListQuery
+	var query request.LessonsQuery
 	if err := c.QueryParser(&query); err != nil {
 		return apperrors.NewInvalidRequest("Wrong query parameters")
 	}
 
+	if c.Context().QueryArgs().Has("cursor") {
+		lessons, nextCursor, err := h.service.GetPageByCursor(c.UserContext(), categoryID, courseID, query.Cursor, query.Limit)
+		if err != nil {
+			return err
+		}
+
+		data := response.CursorLessonsData{Items: lessons}
+		if nextCursor != "" {
+			data.NextCursor = &nextCursor
+		}
+		return handler.SendSuccessWithETag(c, data)
+	}
+
 	lessons, pagination, err := h.service.GetAllByCourseID(c.UserContext(), categoryID, courseID, query.Page, query.Limit, query.Sort)
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data: response.PaginatedLessonsData{
-			Items:      lessons,
-			Pagination: pagination,
-		},
+	return handler.SendSuccessWithETag(c, response.PaginatedLessonsData{
+		Items:      lessons,
+		Pagination: pagination,
 	})
 }
 
@@ -75,33 +97,82 @@ func (h *LessonHandler) GetLessonsByCourseID(c *fiber.Ctx) error {
 // @Param category_id path string true "Уникальный идентификатор категории"
 // @Param course_id path string true "Уникальный идентификатор курса"
 // @Param lesson_id path string true "Уникальный идентификатор урока"
+// @Param window query int false "Количество соседних уроков в каждую сторону для предзагрузки (0 — отключить)" default(2)
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
 // @Success 200 {object} response.SuccessResponse{data=response.LessonDTODetailed} "Успешный ответ"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
 // @Failure 400 {object} response.ErrorResponse "Неверный формат ID"
 // @Failure 404 {object} response.ErrorResponse "Категория, курс или урок не найдены"
 // @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories/{category_id}/courses/{course_id}/lessons/{lesson_id} [get]
 func (h *LessonHandler) GetLessonByID(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 	courseID := c.Params(routing.PathVariableCourseID)
-	if _, err := uuid.Parse(courseID); err != nil {
+	if err := utils.ValidateUUID(courseID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCourseID)
 	}
 
 	lessonID := c.Params(routing.PathVariableLessonID)
-	if _, err := uuid.Parse(lessonID); err != nil {
+	if err := utils.ValidateUUID(lessonID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableLessonID)
 	}
 
-	lesson, err := h.service.GetByID(c.UserContext(), categoryID, courseID, lessonID)
+	window := service.DefaultPrefetchWindow
+	if raw := c.Query("window"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return apperrors.NewInvalidRequest("Wrong query parameters")
+		}
+		window = parsed
+	}
+
+	lesson, err := h.service.GetByIDWithPrefetch(c.UserContext(), categoryID, courseID, lessonID, window)
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.SuccessResponse{
-		Status: response.StatusSuccess,
-		Data:   lesson,
-	})
+	return handler.SendSuccessWithETag(c, lesson)
+}
+
+// GetLessonBySlug обрабатывает запрос на получение опубликованного урока по slug курса и slug урока.
+// @Summary Получить урок по slug
+// @Description Получает детали опубликованного урока по человекочитаемым slug курса и урока в рамках категории.
+// @Description Возвращает 404, если не разрешается любое звено цепочки: категория, курс или урок.
+// @Tags Lessons
+// @Accept json
+// @Produce json
+// @Param category_id path string true "Уникальный идентификатор категории"
+// @Param slug path string true "Slug курса"
+// @Param lesson_slug path string true "Slug урока"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param envelope query bool false "false - вернуть данные без обертки {status, data} (то же, что Accept: application/json; profile=raw)" default(true)
+// @Success 200 {object} response.SuccessResponse{data=response.LessonDTODetailed} "Успешный ответ"
+// @Header 200 {string} ETag "Хэш содержимого ответа"
+// @Success 304 "Данные не изменились"
+// @Failure 400 {object} response.ErrorResponse "Неверный формат ID"
+// @Failure 404 {object} response.ErrorResponse "Категория, курс или урок не найдены"
+// @Failure 500 {object} response.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/courses/slug/{slug}/lessons/{lesson_slug} [get]
+func (h *LessonHandler) GetLessonBySlug(c *fiber.Ctx) error {
+	categoryID := c.Params(routing.PathVariableCategoryID)
+	if err := utils.ValidateUUID(categoryID); err != nil {
+		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
+	}
+
+	lesson, err := h.service.GetPublishedLessonBySlug(
+		c.UserContext(),
+		categoryID,
+		c.Params(routing.PathVariableCourseSlug),
+		c.Params(routing.PathVariableLessonSlug),
+	)
+	if err != nil {
+		return err
+	}
+
+	return handler.SendSuccessWithETag(c, lesson)
 }
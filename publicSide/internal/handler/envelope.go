@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EnvelopeQueryParam - параметр запроса, управляющий оберткой ответа: envelope=false возвращает
+// ресурс или список без {status, data}, envelope=true - с оберткой (по умолчанию).
+const EnvelopeQueryParam = "envelope"
+
+// RawProfile - значение параметра profile в заголовке Accept (application/json; profile=raw),
+// запрашивающее ответ без обертки. Параметр запроса envelope имеет приоритет над заголовком.
+const RawProfile = "raw"
+
+// SendSuccess отправляет успешный ответ со статусом 200 в обертке response.SuccessResponse
+// или без нее, если клиент запросил необработанный ответ (см. WantsEnvelope).
+func SendSuccess(c *fiber.Ctx, data interface{}) error {
+	body, err := successBody(c, data)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(body)
+}
+
+// SendSuccessWithETag отправляет успешный ответ так же, как SendSuccess, но через SendJSONWithETag.
+// Обертка входит в хэш, поэтому ответы в разных формах имеют разные ETag.
+func SendSuccessWithETag(c *fiber.Ctx, data interface{}) error {
+	body, err := successBody(c, data)
+	if err != nil {
+		return err
+	}
+	return SendJSONWithETag(c, body)
+}
+
+// successBody возвращает тело успешного ответа в форме, запрошенной клиентом.
+// Форма может зависеть от заголовка Accept, поэтому он добавляется в Vary.
+func successBody(c *fiber.Ctx, data interface{}) (interface{}, error) {
+	c.Vary(fiber.HeaderAccept)
+
+	envelope, err := WantsEnvelope(c)
+	if err != nil {
+		return nil, err
+	}
+	if !envelope {
+		return data, nil
+	}
+	return response.SuccessResponse{
+		Status: response.StatusSuccess,
+		Data:   data,
+	}, nil
+}
+
+// WantsEnvelope сообщает, нужно ли оборачивать успешный ответ в {status, data}.
+// Параметр запроса envelope имеет приоритет; без него ответ не оборачивается только при
+// profile=raw в одном из медиатипов заголовка Accept. Ошибки по-прежнему возвращаются в обертке.
+// Возвращает ошибку, если envelope не является булевым значением.
+func WantsEnvelope(c *fiber.Ctx) (bool, error) {
+	if raw := c.Query(EnvelopeQueryParam); raw != "" {
+		envelope, err := strconv.ParseBool(raw)
+		if err != nil {
+			return false, apperrors.NewInvalidRequest("Query parameter 'envelope' must be a boolean")
+		}
+		return envelope, nil
+	}
+	return !acceptsRawProfile(c.Get(fiber.HeaderAccept)), nil
+}
+
+// acceptsRawProfile проверяет, содержит ли заголовок Accept медиатип с параметром profile=raw.
+func acceptsRawProfile(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		for _, param := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "profile") {
+				continue
+			}
+			if strings.EqualFold(strings.Trim(strings.TrimSpace(value), `"`), RawProfile) {
+				return true
+			}
+		}
+	}
+	return false
+}
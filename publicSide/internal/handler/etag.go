@@ -0,0 +1,49 @@
+// Package handler содержит общие вспомогательные функции для HTTP-обработчиков.
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// APICacheMaxAge - время, в течение которого клиент может использовать ответ API без повторной проверки.
+// Значение небольшое: изменения из панели администратора должны быстро доходить до клиентов,
+// а после истечения клиент перепроверяет ответ по ETag и получает 304 без тела, если он не изменился.
+const APICacheMaxAge = 30 * time.Second
+
+// SendJSONWithETag сериализует body в JSON и отправляет его со статусом 200, заголовками ETag
+// и Cache-Control. ETag вычисляется как хэш сериализованного ответа, поэтому меняется при любом
+// изменении данных. Если запрос содержит совпадающий If-None-Match, отправляется 304 без тела.
+// Ответы в режиме предпросмотра не кэшируются, а ответы по API-ключу кэшируются только клиентом.
+func SendJSONWithETag(c *fiber.Ctx, body interface{}) error {
+	data, err := c.App().Config().JSONEncoder(body)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(sum[:16])+`"`)
+	switch {
+	case domain.IsPreview(c.UserContext()):
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+	case c.Locals(middleware.APIKeyIDLocal) != nil:
+		// Ответ выдан по API-ключу: общий кэш (прокси, CDN) не должен отдавать его клиентам без ключа.
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", int(APICacheMaxAge.Seconds())))
+		c.Vary(middleware.APIKeyHeader)
+	default:
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(APICacheMaxAge.Seconds())))
+	}
+
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(fiber.StatusOK).Send(data)
+}
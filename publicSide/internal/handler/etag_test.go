@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSendJSONWithETagCacheControl(t *testing.T) {
+	const apiKey = "secret-key"
+	sum := sha256.Sum256([]byte(apiKey))
+	keys := config.APIKeysConfig{Keys: []config.APIKey{
+		{ID: "integration", Hash: hex.EncodeToString(sum[:]), Scope: config.APIKeyScopeRead},
+	}}
+
+	tests := []struct {
+		name         string
+		keys         config.APIKeysConfig
+		header       string
+		cacheControl string
+		vary         string
+	}{
+		{name: "open api", cacheControl: "public, max-age=30"},
+		{name: "api key", keys: keys, header: apiKey, cacheControl: "private, max-age=30", vary: middleware.APIKeyHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/data", middleware.APIKeyAuth(tt.keys), func(c *fiber.Ctx) error {
+				return SendJSONWithETag(c, fiber.Map{"id": 1})
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/data", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderCacheControl); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+			if got := resp.Header.Get(fiber.HeaderVary); got != tt.vary {
+				t.Errorf("Vary = %q, want %q", got, tt.vary)
+			}
+		})
+	}
+}
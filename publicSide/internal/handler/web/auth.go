@@ -4,65 +4,106 @@ package web
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"log/slog"
+	"net/url"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/oauth2"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
 )
 
 // AuthHandler обрабатывает HTTP-запросы, связанные с аутентификацией через OIDC.
 type AuthHandler struct {
-	provider     *oidc.Provider
-	oauth2Config *oauth2.Config
+	provider              *oidc.Provider
+	oauth2Config          *oauth2.Config
+	sessions              *SessionStore
+	endSessionEndpoint    string
+	postLogoutRedirectURL string
+	claimsConfig          config.ClaimsConfig
 }
 
 // NewAuthHandler создает новый экземпляр AuthHandler.
-func NewAuthHandler(provider *oidc.Provider, oauth2Config *oauth2.Config) *AuthHandler {
+// end_session_endpoint провайдера определяется из его метаданных; если провайдер
+// его не публикует, выход выполняется только локально.
+// postLogoutRedirectURL задает адрес возврата после выхода (пустой - главная страница),
+// claimsConfig - расположение данных пользователя в claims ID Token'а.
+func NewAuthHandler(provider *oidc.Provider, oauth2Config *oauth2.Config, sessions *SessionStore, postLogoutRedirectURL string, claimsConfig config.ClaimsConfig) *AuthHandler {
+	var metadata struct {
+		EndSessionEndpoint string `json:"end_session_endpoint"`
+	}
+	if err := provider.Claims(&metadata); err != nil {
+		slog.Warn("Failed to read OIDC provider metadata, logout will be local only", "error", err)
+	}
+	if metadata.EndSessionEndpoint == "" {
+		slog.Info("OIDC provider does not expose end_session_endpoint, logout will be local only")
+	}
+
 	return &AuthHandler{
-		provider:     provider,
-		oauth2Config: oauth2Config,
+		provider:              provider,
+		oauth2Config:          oauth2Config,
+		sessions:              sessions,
+		endSessionEndpoint:    metadata.EndSessionEndpoint,
+		postLogoutRedirectURL: postLogoutRedirectURL,
+		claimsConfig:          claimsConfig,
 	}
 }
 
+// Имена cookie, в которых на время входа хранятся state и nonce OIDC.
+const (
+	stateCookie = "oidc_state"
+	nonceCookie = "oidc_nonce"
+)
+
+// loginCookieTTL ограничивает время, за которое пользователь должен завершить вход у провайдера.
+const loginCookieTTL = 10 * time.Minute
+
 // Login инициирует процесс аутентификации OIDC.
-// Он генерирует `state` для защиты от CSRF, сохраняет его в cookie
+// Он генерирует случайные `state` (защита от login CSRF) и `nonce` (защита от подмены ID Token),
+// сохраняет их в HTTP-only cookie, привязывая к браузеру пользователя,
 // и перенаправляет пользователя на страницу входа провайдера.
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
-	b := make([]byte, 16)
-	_, err := rand.Read(b)
+	state, err := randomToken()
 	if err != nil {
 		slog.Error("Failed to generate random state for OIDC", "error", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to initiate login")
 	}
-	state := hex.EncodeToString(b)
+	nonce, err := randomToken()
+	if err != nil {
+		slog.Error("Failed to generate random nonce for OIDC", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to initiate login")
+	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "oidc_state",
-		Value:    state,
-		Expires:  time.Now().Add(10 * time.Minute),
-		HTTPOnly: true,
-		Secure:   c.Protocol() == "https",
-		SameSite: "Lax",
-	})
+	h.sessions.setLoginCookie(c, stateCookie, state, time.Now().Add(loginCookieTTL))
+	h.sessions.setLoginCookie(c, nonceCookie, nonce, time.Now().Add(loginCookieTTL))
 
-	authURL := h.oauth2Config.AuthCodeURL(state)
+	authURL := h.oauth2Config.AuthCodeURL(state, oidc.Nonce(nonce))
 	return c.Redirect(authURL, fiber.StatusTemporaryRedirect)
 }
 
 // Callback обрабатывает обратный вызов от OIDC провайдера после аутентификации.
-// Он проверяет `state`, обменивает `code` на токены, верифицирует `id_token`
-// и сохраняет его в сессионной cookie.
+// Он проверяет `state` по cookie, выданной при входе, обменивает `code` на токены,
+// верифицирует `id_token` вместе с `nonce` и сохраняет его вместе с refresh-токеном
+// в сессионных cookie. Несовпадение state или nonce отклоняется с 403.
 func (h *AuthHandler) Callback(c *fiber.Ctx) error {
-	stateCookie := c.Cookies("oidc_state")
-	if stateCookie == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "Missing state cookie")
-	}
+	expectedState := c.Cookies(stateCookie)
+	expectedNonce := c.Cookies(nonceCookie)
+
+	// Cookie входа одноразовые: удаляем их независимо от результата проверки.
+	h.sessions.setLoginCookie(c, stateCookie, "", time.Now().Add(-1*time.Hour))
+	h.sessions.setLoginCookie(c, nonceCookie, "", time.Now().Add(-1*time.Hour))
 
-	if c.Query("state") != stateCookie {
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid OIDC state")
+	if expectedState == "" || expectedNonce == "" {
+		return fiber.NewError(fiber.StatusForbidden, "Missing login state, please start the login again")
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(expectedState)) != 1 {
+		slog.Warn("OIDC state mismatch on callback")
+		return fiber.NewError(fiber.StatusForbidden, "Invalid OIDC state")
 	}
 
 	ctx := context.Background()
@@ -83,48 +124,66 @@ func (h *AuthHandler) Callback(c *fiber.Ctx) error {
 		slog.Error("Failed to verify ID token", "error", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "Invalid session token")
 	}
-
-	var claims struct {
-		Email    string `json:"email"`
-		Name     string `json:"name"`
-		Username string `json:"preferred_username"`
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(expectedNonce)) != 1 {
+		slog.Warn("OIDC nonce mismatch on callback")
+		return fiber.NewError(fiber.StatusForbidden, "Invalid OIDC nonce")
 	}
-	if err := idToken.Claims(&claims); err != nil {
+
+	claims, err := userClaimsFromToken(idToken, h.claimsConfig)
+	if err != nil {
+		// Без обязательных claims сессия все равно считалась бы гостевой, поэтому вход отклоняется сразу.
 		slog.Error("Failed to extract claims from ID token", "error", err)
-		return fiber.NewError(fiber.StatusInternalServerError, "Could not process user information")
+		return fiber.NewError(fiber.StatusForbidden, "Could not process user information")
 	}
 	slog.Info("User logged in successfully", "user", claims.Username, "email", claims.Email)
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "session_token",
-		Value:    rawIDToken,
-		Expires:  time.Now().Add(24 * time.Hour),
-		HTTPOnly: true,
-		Secure:   c.Protocol() == "https",
-		SameSite: "Lax",
-	})
-
-	// Удаляем state cookie после успешного использования.
-	c.Cookie(&fiber.Cookie{
-		Name:     "oidc_state",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HTTPOnly: true,
-	})
+	if err := h.sessions.Save(c, rawIDToken, tokens.RefreshToken); err != nil {
+		slog.Error("Failed to save session", "error", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to log in")
+	}
 
 	return c.Redirect("/", fiber.StatusTemporaryRedirect)
 }
 
 // Logout выполняет выход пользователя из системы.
-// Он удаляет сессионную cookie и перенаправляет на главную страницу.
+// Он удаляет сессионные cookie и, если провайдер поддерживает RP-initiated logout,
+// перенаправляет на его end_session_endpoint с адресом возврата после выхода.
+// Иначе сразу перенаправляет на адрес возврата.
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
-	c.Cookie(&fiber.Cookie{
-		Name:     "session_token",
-		Value:    "",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HTTPOnly: true,
-		Secure:   c.Protocol() == "https",
-		SameSite: "Lax",
-	})
-	return c.Redirect("/", fiber.StatusTemporaryRedirect)
+	rawIDToken := c.Cookies(domain.SessionTokenCookie)
+	h.sessions.Clear(c)
+
+	redirectURL := h.postLogoutRedirectURL
+	if redirectURL == "" {
+		redirectURL = c.BaseURL() + "/"
+	}
+
+	if h.endSessionEndpoint == "" {
+		return c.Redirect(redirectURL, fiber.StatusTemporaryRedirect)
+	}
+
+	logoutURL, err := url.Parse(h.endSessionEndpoint)
+	if err != nil {
+		slog.Error("Invalid OIDC end_session_endpoint", "endpoint", h.endSessionEndpoint, "error", err)
+		return c.Redirect(redirectURL, fiber.StatusTemporaryRedirect)
+	}
+
+	query := logoutURL.Query()
+	query.Set("client_id", h.oauth2Config.ClientID)
+	query.Set("post_logout_redirect_uri", redirectURL)
+	if rawIDToken != "" {
+		query.Set("id_token_hint", rawIDToken)
+	}
+	logoutURL.RawQuery = query.Encode()
+
+	return c.Redirect(logoutURL.String(), fiber.StatusTemporaryRedirect)
+}
+
+// randomToken возвращает случайную строку из 16 байт в hex для state и nonce.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,41 @@
+// Package web содержит обработчики для рендеринга веб-страниц.
+package web
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BannerMiddleware загружает баннер сайта для страниц, которые рендерятся с макетом `layouts/main`.
+type BannerMiddleware struct {
+	bannerService service.BannerService
+}
+
+// NewBannerMiddleware создает новый экземпляр BannerMiddleware.
+func NewBannerMiddleware(bannerService service.BannerService) *BannerMiddleware {
+	return &BannerMiddleware{bannerService: bannerService}
+}
+
+// WithBanner является middleware, которое помещает активный баннер сайта в контекст запроса,
+// откуда его берет viewmodel.NewMain. Маршруты API пропускаются. Ошибка получения баннера
+// не мешает показу страницы: она логируется, и страница рендерится без баннера.
+func (m *BannerMiddleware) WithBanner(c *fiber.Ctx) error {
+	if strings.HasPrefix(c.Path(), routing.RouteAPIV1) {
+		return c.Next()
+	}
+
+	banner, err := m.bannerService.GetActive(c.UserContext())
+	if err != nil {
+		slog.Warn("Failed to get site banner", "error", err)
+		return c.Next()
+	}
+	if banner != nil {
+		c.SetUserContext(domain.WithBanner(c.UserContext(), banner))
+	}
+	return c.Next()
+}
@@ -2,6 +2,7 @@
 package web
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
@@ -10,20 +11,24 @@ import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/viewmodel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/parallel"
 	"github.com/gofiber/fiber/v2"
 )
 
 // CategoryHandler обрабатывает HTTP-запросы, связанные со страницами категорий.
 type CategoryHandler struct {
-	categoriesService service.CategoryService
-	coursesService    service.CourseService
+	categoriesService  service.CategoryService
+	coursesService     service.CourseService
+	maxParallelQueries int
 }
 
 // NewCategoryHandler создает новый экземпляр CategoryHandler.
-func NewCategoryHandler(categoriesService service.CategoryService, coursesService service.CourseService) *CategoryHandler {
+// maxParallelQueries ограничивает число превью курсов категорий, загружаемых одновременно.
+func NewCategoryHandler(categoriesService service.CategoryService, coursesService service.CourseService, maxParallelQueries int) *CategoryHandler {
 	return &CategoryHandler{
-		categoriesService: categoriesService,
-		coursesService:    coursesService,
+		categoriesService:  categoriesService,
+		coursesService:     coursesService,
+		maxParallelQueries: maxParallelQueries,
 	}
 }
 
@@ -32,36 +37,55 @@ func NewCategoryHandler(categoriesService service.CategoryService, coursesServic
 func (h *CategoryHandler) RenderCategories(c *fiber.Ctx) error {
 	const COURSE_LIMIT = 5
 
-	var query request.PaginationQuery
+	var query request.CategoriesQuery
 	if err := c.QueryParser(&query); err != nil {
 		return apperrors.NewInvalidRequest("Wrong query parameters")
 	}
+	showEmpty, err := query.ShowEmptyOverride()
+	if err != nil {
+		return apperrors.NewInvalidRequest("Query parameter 'show_empty' must be a boolean")
+	}
 	ctx := c.UserContext()
 
-	// Получаем только те категории, в которых есть курсы.
-	categoriesDTOs, pagination, err := h.categoriesService.GetAllNotEmpty(ctx, query.Page, query.Limit)
+	// Пустые категории показываются согласно настройке или параметру show_empty.
+	categoriesDTOs, pagination, err := h.categoriesService.List(ctx, query.Page, query.Limit, showEmpty)
 	if err != nil {
 		slog.Error("Failed to get categories for home page", "error", err)
 		categoriesDTOs = []response.CategoryDTO{}
 	}
 
-	// Для каждой категории загружаем превью из нескольких курсов.
-	categories := make([]viewmodel.CategoryViewModel, 0, len(categoriesDTOs))
-	for _, cat := range categoriesDTOs {
-		coursesDTOs, coursesPagination, err := h.coursesService.GetCoursesByCategoryID(ctx, cat.ID, 1, COURSE_LIMIT, "", "")
-		if err != nil {
-			slog.Error("Failed to get courses for category", "categoryID", cat.ID, "error", err)
-			coursesDTOs = []response.CourseDTO{}
-			coursesPagination = response.Pagination{}
-		}
-		categories = append(categories, viewmodel.NewCategoryViewModel(cat, coursesDTOs, coursesPagination, COURSE_LIMIT))
-	}
+	// Для каждой категории загружаем превью из нескольких курсов (параллельно, с ограничением числа одновременных запросов).
+	categories := loadCategoryPreviews(ctx, h.coursesService, categoriesDTOs, COURSE_LIMIT, h.maxParallelQueries)
 	vm := viewmodel.NewCategoriesPageViewMode(categories, pagination)
 
 	return c.Render("pages/categories", fiber.Map{
 		"Header":  viewmodel.NewHeader(),
 		"User":    viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":    viewmodel.NewMain("Categories"),
+		"Main":    viewmodel.NewMain(c.UserContext(), "Categories"),
 		"Context": vm,
 	}, "layouts/main")
 }
+
+// loadCategoryPreviews загружает для каждой категории превью из courseLimit курсов и собирает модели
+// представления в исходном порядке категорий. Одновременно выполняется не более maxParallelQueries загрузок.
+// Ошибка загрузки курсов одной категории не прерывает остальные: категория отображается без курсов.
+func loadCategoryPreviews(
+	ctx context.Context,
+	coursesService service.CourseService,
+	categoriesDTOs []response.CategoryDTO,
+	courseLimit, maxParallelQueries int,
+) []viewmodel.CategoryViewModel {
+	categories := make([]viewmodel.CategoryViewModel, len(categoriesDTOs))
+	_ = parallel.Run(ctx, len(categoriesDTOs), maxParallelQueries, func(ctx context.Context, i int) error {
+		cat := categoriesDTOs[i]
+		coursesDTOs, coursesPagination, err := coursesService.GetCoursesByCategoryID(ctx, cat.ID, 1, courseLimit, "", "")
+		if err != nil {
+			slog.Error("Failed to get courses for category", "categoryID", cat.ID, "error", err)
+			coursesDTOs = []response.CourseDTO{}
+			coursesPagination = response.Pagination{}
+		}
+		categories[i] = viewmodel.NewCategoryViewModel(cat, coursesDTOs, coursesPagination, courseLimit)
+		return nil
+	})
+	return categories
+}
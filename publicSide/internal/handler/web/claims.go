@@ -0,0 +1,102 @@
+// Package web содержит обработчики для рендеринга веб-страниц.
+package web
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+)
+
+// userClaimsFromToken заполняет domain.UserClaims из claims ID Token'а по путям из cfg.
+// Возвращает ошибку, если claims не удалось разобрать или в них нет полей из cfg.Required.
+func userClaimsFromToken(idToken *oidc.IDToken, cfg config.ClaimsConfig) (domain.UserClaims, error) {
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return domain.UserClaims{}, err
+	}
+	return extractUserClaims(raw, cfg)
+}
+
+// extractUserClaims заполняет domain.UserClaims из разобранных claims по путям из cfg.
+// Строковые поля, отсутствующие в claims или имеющие другой тип, остаются пустыми;
+// роли объединяются по всем путям cfg.Roles. Возвращает ошибку со списком полей из cfg.Required,
+// которые не удалось заполнить.
+func extractUserClaims(raw map[string]any, cfg config.ClaimsConfig) (domain.UserClaims, error) {
+	claims := domain.UserClaims{
+		ID:       claimString(raw, cfg.Subject),
+		Email:    claimString(raw, cfg.Email),
+		Name:     claimString(raw, cfg.Name),
+		Username: claimString(raw, cfg.Username),
+	}
+	hasRoles := false
+	for _, path := range cfg.Roles {
+		roles, ok := claimStrings(raw, path)
+		if ok {
+			hasRoles = true
+			claims.Roles = append(claims.Roles, roles...)
+		}
+	}
+
+	present := map[string]bool{
+		"subject":  claims.ID != "",
+		"username": claims.Username != "",
+		"name":     claims.Name != "",
+		"email":    claims.Email != "",
+		"roles":    hasRoles,
+	}
+	var missing []string
+	for _, field := range cfg.Required {
+		if !present[field] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return claims, fmt.Errorf("ID token is missing required claims: %s", strings.Join(missing, ", "))
+	}
+
+	return claims, nil
+}
+
+// claimValue возвращает значение по пути path (сегменты через точку) во вложенных claims.
+func claimValue(raw map[string]any, path string) (any, bool) {
+	var current any = raw
+	for _, segment := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// claimString возвращает строку по пути path или пустую строку, если путь не найден
+// или значение не является строкой.
+func claimString(raw map[string]any, path string) string {
+	value, _ := claimValue(raw, path)
+	s, _ := value.(string)
+	return s
+}
+
+// claimStrings возвращает строковые элементы списка по пути path.
+// Второй результат равен false, если путь не найден или значение не является списком.
+func claimStrings(raw map[string]any, path string) ([]string, bool) {
+	value, _ := claimValue(raw, path)
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,109 @@
+package web
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+)
+
+func TestExtractUserClaims(t *testing.T) {
+	keycloak := config.ClaimsConfig{
+		Subject:  "sub",
+		Username: "preferred_username",
+		Name:     "name",
+		Email:    "email",
+		Roles:    []string{"realm_access.roles"},
+		Required: []string{"subject"},
+	}
+
+	tests := []struct {
+		name    string
+		raw     map[string]any
+		cfg     config.ClaimsConfig
+		want    domain.UserClaims
+		wantErr bool
+	}{
+		{
+			name: "keycloak defaults",
+			raw: map[string]any{
+				"sub":                "user-1",
+				"preferred_username": "jdoe",
+				"name":               "John Doe",
+				"email":              "jdoe@example.com",
+				"realm_access":       map[string]any{"roles": []any{"student"}},
+			},
+			cfg:  keycloak,
+			want: domain.UserClaims{ID: "user-1", Username: "jdoe", Name: "John Doe", Email: "jdoe@example.com", Roles: []string{"student"}},
+		},
+		{
+			name: "realm and client roles",
+			raw: map[string]any{
+				"sub":             "user-2",
+				"realm_access":    map[string]any{"roles": []any{"student"}},
+				"resource_access": map[string]any{"public-side": map[string]any{"roles": []any{"mentor", true}}},
+			},
+			cfg: config.ClaimsConfig{
+				Subject: "sub",
+				Roles:   []string{"realm_access.roles", "resource_access.public-side.roles"},
+			},
+			want: domain.UserClaims{ID: "user-2", Roles: []string{"student", "mentor"}},
+		},
+		{
+			name: "nested custom claims",
+			raw: map[string]any{
+				"oid":     "user-3",
+				"upn":     "jane@corp.example",
+				"profile": map[string]any{"display_name": "Jane Roe", "mail": "jane@example.com"},
+				"groups":  []any{"lms-students"},
+			},
+			cfg: config.ClaimsConfig{
+				Subject:  "oid",
+				Username: "upn",
+				Name:     "profile.display_name",
+				Email:    "profile.mail",
+				Roles:    []string{"groups"},
+				Required: []string{"subject", "email", "roles"},
+			},
+			want: domain.UserClaims{ID: "user-3", Username: "jane@corp.example", Name: "Jane Roe", Email: "jane@example.com", Roles: []string{"lms-students"}},
+		},
+		{
+			name: "values of wrong type are ignored",
+			raw:  map[string]any{"sub": 12345, "email": []any{"a@example.com"}, "realm_access": "student"},
+			cfg:  config.ClaimsConfig{Subject: "sub", Email: "email", Roles: []string{"realm_access.roles"}},
+			want: domain.UserClaims{},
+		},
+		{
+			name:    "missing required subject",
+			raw:     map[string]any{"preferred_username": "jdoe"},
+			cfg:     keycloak,
+			wantErr: true,
+		},
+		{
+			name:    "missing required email and roles",
+			raw:     map[string]any{"sub": "user-4", "mail": "jdoe@example.com"},
+			cfg:     config.ClaimsConfig{Subject: "sub", Email: "email", Roles: []string{"realm_access.roles"}, Required: []string{"email", "roles"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractUserClaims(tt.raw, tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("extractUserClaims() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractUserClaims() error = %v", err)
+			}
+			if got.ID != tt.want.ID || got.Username != tt.want.Username || got.Name != tt.want.Name ||
+				got.Email != tt.want.Email || !slices.Equal(got.Roles, tt.want.Roles) {
+				t.Errorf("extractUserClaims() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,8 +12,8 @@ import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/viewmodel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // CoursesHandler инкапсулирует зависимости и логику для обработки HTTP-запросов,
@@ -48,7 +48,7 @@ func NewCoursesHandler(
 // по уровню и сортировку через query-параметры.
 func (h *CoursesHandler) RenderCourses(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 	page := c.QueryInt("page", 1)
@@ -68,21 +68,21 @@ func (h *CoursesHandler) RenderCourses(c *fiber.Ctx) error {
 		return err
 	}
 
-	// Для каждого курса получаем количество уроков.
-	lessonAmounts := make([]int, 0, len(coursesDTOs))
+	// Количество уроков всех курсов страницы получаем одним запросом.
+	courseIDs := make([]string, 0, len(coursesDTOs))
 	for _, course := range coursesDTOs {
-		_, pag, err := h.lessonService.GetAllByCourseID(c.UserContext(), categoryID, course.ID, 1, 1, "")
-		if err != nil {
-			return err
-		}
-		lessonAmounts = append(lessonAmounts, pag.Total)
+		courseIDs = append(courseIDs, course.ID)
+	}
+	lessonCounts, err := h.lessonService.GetLessonCounts(c.UserContext(), courseIDs)
+	if err != nil {
+		return err
 	}
 
 	vm := viewmodel.NewCoursesPageViewModel(
 		categoryDTO,
 		coursesDTOs,
 		coursesPagination,
-		lessonAmounts,
+		lessonCounts,
 		level,
 		sortBy,
 	)
@@ -92,22 +92,52 @@ func (h *CoursesHandler) RenderCourses(c *fiber.Ctx) error {
 	return c.Render("pages/courses", fiber.Map{
 		"Header":  viewmodel.NewHeader(),
 		"User":    viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":    viewmodel.NewMain("Courses"),
+		"Main":    viewmodel.NewMain(c.UserContext(), "Courses"),
+		"Context": vm,
+	}, "layouts/main")
+}
+
+// RenderRecent отображает страницу последних обновленных публичных курсов из всех категорий.
+// Количество курсов задается query-параметром limit (по умолчанию 10, не более 50).
+func (h *CoursesHandler) RenderRecent(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", service.DefaultRecentCoursesLimit)
+
+	coursesDTOs, err := h.courseService.GetRecentlyUpdated(c.UserContext(), limit)
+	if err != nil {
+		return err
+	}
+
+	courseIDs := make([]string, 0, len(coursesDTOs))
+	for _, course := range coursesDTOs {
+		courseIDs = append(courseIDs, course.ID)
+	}
+	lessonCounts, err := h.lessonService.GetLessonCounts(c.UserContext(), courseIDs)
+	if err != nil {
+		return err
+	}
+
+	vm := viewmodel.NewRecentCoursesPageViewModel(coursesDTOs, lessonCounts)
+	vm.Courses = russifyCoursesLevel(vm.Courses)
+
+	return c.Render("pages/recent", fiber.Map{
+		"Header":  viewmodel.NewHeader(),
+		"User":    viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
+		"Main":    viewmodel.NewMain(c.UserContext(), "Recent"),
 		"Context": vm,
 	}, "layouts/main")
 }
 
 // RenderCoursePage отображает детальную страницу одного курса.
 // Он извлекает ID категории и курса из URL, загружает всю необходимую информацию:
-// данные о курсе, категории, список уроков и информацию о тесте.
+// данные о курсе, категории, список уроков, информацию о тесте и статистику его прохождения.
 // Корректно обрабатывает случаи, когда тест не найден или сервис тестов недоступен.
 func (h *CoursesHandler) RenderCoursePage(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return err
 	}
 	courseID := c.Params(routing.PathVariableCourseID)
-	if _, err := uuid.Parse(courseID); err != nil {
+	if err := utils.ValidateUUID(courseID); err != nil {
 		return err
 	}
 
@@ -145,6 +175,29 @@ func (h *CoursesHandler) RenderCoursePage(c *fiber.Ctx) error {
 		testVM = viewmodel.NewTestViewModel(testData, testing.GetUITestURL(h.testingConfig.BaseURL, categoryID, courseID))
 	}
 
+	var testStatsVM *viewmodel.TestStatsViewModel
+	var testStatsIsUnavailable bool
+
+	// Статистика запрашивается только для существующего теста. Если статистики нет
+	// (например, тест удален между запросами), блок статистики не показывается.
+	if testVM != nil {
+		testStats, err := h.testService.GetTestStats(c.UserContext(), categoryID, courseID)
+		if err != nil {
+			var appErr *apperrors.AppError
+			var unavailableErr *apperrors.ServiceUnavailableError
+
+			isNotFound := errors.As(err, &appErr) && appErr.HTTPStatus == 404
+			if errors.As(err, &unavailableErr) {
+				testStatsIsUnavailable = true
+			} else if !isNotFound {
+				slog.Error("Unexpected error fetching test stats", "error", err, "courseID", courseID)
+				return err
+			}
+		} else {
+			testStatsVM = viewmodel.NewTestStatsViewModel(testStats)
+		}
+	}
+
 	vm := viewmodel.NewCoursePageViewModel(
 		categoryDTO,
 		courseDTO,
@@ -152,6 +205,8 @@ func (h *CoursesHandler) RenderCoursePage(c *fiber.Ctx) error {
 		testVM,
 		testIsNotFound,
 		testServiceIsUnavailable,
+		testStatsVM,
+		testStatsIsUnavailable,
 	)
 
 	russifyCourseDetailLevel(vm.Course)
@@ -159,7 +214,7 @@ func (h *CoursesHandler) RenderCoursePage(c *fiber.Ctx) error {
 	return c.Render("pages/course", fiber.Map{
 		"Header":  viewmodel.NewHeader(),
 		"User":    viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":    viewmodel.NewMain("Course"),
+		"Main":    viewmodel.NewMain(c.UserContext(), "Course"),
 		"Context": vm,
 	}, "layouts/main")
 }
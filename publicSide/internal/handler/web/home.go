@@ -5,56 +5,57 @@ import (
 	"log/slog"
 
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/request"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/service"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/viewmodel"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/gofiber/fiber/v2"
 )
 
 // HomeHandler обрабатывает HTTP-запросы для главной страницы.
 type HomeHandler struct {
-	categoriesService service.CategoryService
-	coursesService    service.CourseService
+	categoriesService  service.CategoryService
+	coursesService     service.CourseService
+	maxParallelQueries int
 }
 
 // NewHomeHandler создает новый экземпляр HomeHandler.
-func NewHomeHandler(categoriesService service.CategoryService, coursesService service.CourseService) *HomeHandler {
+// maxParallelQueries ограничивает число превью курсов категорий, загружаемых одновременно.
+func NewHomeHandler(categoriesService service.CategoryService, coursesService service.CourseService, maxParallelQueries int) *HomeHandler {
 	return &HomeHandler{
-		categoriesService: categoriesService,
-		coursesService:    coursesService,
+		categoriesService:  categoriesService,
+		coursesService:     coursesService,
+		maxParallelQueries: maxParallelQueries,
 	}
 }
 
 // RenderHome отображает главную страницу.
-// Он загружает несколько непустых категорий и для каждой из них —
+// Он загружает несколько категорий (пустые — согласно настройке SHOW_EMPTY_CATEGORIES) и для каждой из них —
 // небольшое превью курсов для отображения.
 func (h *HomeHandler) RenderHome(c *fiber.Ctx) error {
 	const COURSE_LIMIT = 5
 	ctx := c.UserContext()
 
-	// Получаем несколько категорий для отображения на главной.
-	categoriesDTOs, _, err := h.categoriesService.GetAllNotEmpty(ctx, 1, 5)
+	showEmpty, err := request.CategoriesQuery{ShowEmpty: c.Query("show_empty")}.ShowEmptyOverride()
+	if err != nil {
+		return apperrors.NewInvalidRequest("Query parameter 'show_empty' must be a boolean")
+	}
+
+	// Получаем несколько категорий для отображения на главной; пустые скрываются согласно настройке.
+	categoriesDTOs, _, err := h.categoriesService.List(ctx, 1, 5, showEmpty)
 	if err != nil {
 		slog.Error("Failed to get categories for home page", "error", err)
 		categoriesDTOs = []response.CategoryDTO{}
 	}
 
-	// Для каждой категории загружаем превью курсов.
-	categories := make([]viewmodel.CategoryViewModel, 0, len(categoriesDTOs))
-	for _, cat := range categoriesDTOs {
-		coursesDTOs, coursesPagination, err := h.coursesService.GetCoursesByCategoryID(ctx, cat.ID, 1, COURSE_LIMIT, "", "")
-		if err != nil {
-			slog.Error("Failed to get courses for category", "categoryID", cat.ID, "error", err)
-			coursesDTOs = []response.CourseDTO{}
-			coursesPagination = response.Pagination{}
-		}
-		categories = append(categories, viewmodel.NewCategoryViewModel(cat, coursesDTOs, coursesPagination, COURSE_LIMIT))
-	}
+	// Для каждой категории загружаем превью курсов (параллельно, с ограничением числа одновременных запросов).
+	categories := loadCategoryPreviews(ctx, h.coursesService, categoriesDTOs, COURSE_LIMIT, h.maxParallelQueries)
 
 	return c.Render("pages/home", fiber.Map{
 		"Header":  viewmodel.NewHeader(),
 		"User":    viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":    viewmodel.NewMain("Home"),
+		"Main":    viewmodel.NewMain(c.UserContext(), "Home"),
 		"Context": viewmodel.NewHomePageViewModel(categories),
 	}, "layouts/main")
 }
@@ -10,8 +10,8 @@ import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/viewmodel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // LessonHandler обрабатывает HTTP-запросы, связанные со страницей урока.
@@ -35,15 +35,15 @@ func NewLessonHandler(ls service.LessonService, cs service.CourseService, cats s
 // уроках для навигации.
 func (h *LessonHandler) RenderLesson(c *fiber.Ctx) error {
 	categoryID := c.Params(routing.PathVariableCategoryID)
-	if _, err := uuid.Parse(categoryID); err != nil {
+	if err := utils.ValidateUUID(categoryID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
 	}
 	courseID := c.Params(routing.PathVariableCourseID)
-	if _, err := uuid.Parse(courseID); err != nil {
+	if err := utils.ValidateUUID(courseID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableCourseID)
 	}
 	lessonID := c.Params(routing.PathVariableLessonID)
-	if _, err := uuid.Parse(lessonID); err != nil {
+	if err := utils.ValidateUUID(lessonID); err != nil {
 		return apperrors.NewInvalidUUID(routing.PathVariableLessonID)
 	}
 	ctx := c.UserContext()
@@ -54,6 +54,35 @@ func (h *LessonHandler) RenderLesson(c *fiber.Ctx) error {
 		return err
 	}
 
+	return h.renderLessonPage(c, categoryID, lessonDTODetailed, false)
+}
+
+// RenderLessonBySlug отображает страницу урока, открытую по slug курса и slug урока.
+// Если любое звено цепочки категория -> курс -> урок не найдено, возвращается 404.
+// Ссылки навигации на странице тоже строятся по slug.
+func (h *LessonHandler) RenderLessonBySlug(c *fiber.Ctx) error {
+	categoryID := c.Params(routing.PathVariableCategoryID)
+	if err := utils.ValidateUUID(categoryID); err != nil {
+		return apperrors.NewInvalidUUID(routing.PathVariableCategoryID)
+	}
+	courseSlug := c.Params(routing.PathVariableCourseSlug)
+	lessonSlug := c.Params(routing.PathVariableLessonSlug)
+
+	lessonDTODetailed, err := h.lessonsService.GetPublishedLessonBySlug(c.UserContext(), categoryID, courseSlug, lessonSlug)
+	if err != nil {
+		slog.Error("Failed to get lesson by slug", "courseSlug", courseSlug, "lessonSlug", lessonSlug, "error", err)
+		return err
+	}
+
+	return h.renderLessonPage(c, categoryID, lessonDTODetailed, true)
+}
+
+// renderLessonPage загружает курс, категорию, соседние уроки и список уроков для боковой панели
+// и отображает страницу урока. bySlug передается в модель представления для построения ссылок.
+func (h *LessonHandler) renderLessonPage(c *fiber.Ctx, categoryID string, lessonDTODetailed response.LessonDTODetailed, bySlug bool) error {
+	ctx := c.UserContext()
+	courseID, lessonID := lessonDTODetailed.CourseID, lessonDTODetailed.ID
+
 	prevLessonDTO, nextLessonDTO, err := h.lessonsService.GetNeighboringLessons(ctx, categoryID, courseID, lessonID)
 	if err != nil {
 		slog.Error("Failed to get neighboring lessons", "lessonID", lessonID, "error", err)
@@ -83,7 +112,7 @@ func (h *LessonHandler) RenderLesson(c *fiber.Ctx) error {
 	return c.Render("pages/lesson", fiber.Map{
 		"Header": viewmodel.NewHeader(),
 		"User":   viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":   viewmodel.NewMain("Lesson"),
+		"Main":   viewmodel.NewMain(c.UserContext(), "Lesson"),
 		"Context": viewmodel.NewLessonPageViewModel(
 			lessonDTODetailed,
 			courseDTO,
@@ -91,6 +120,7 @@ func (h *LessonHandler) RenderLesson(c *fiber.Ctx) error {
 			nextLessonDTO,
 			prevLessonDTO,
 			lessonsDTOs,
+			bySlug,
 		),
 	}, "layouts/main")
 }
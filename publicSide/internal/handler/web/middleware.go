@@ -2,33 +2,57 @@
 package web
 
 import (
-	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gofiber/fiber/v2"
+	"golang.org/x/oauth2"
 
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
 )
 
+// errNoRefreshToken возвращается refresh, если в сессии нет refresh-токена.
+var errNoRefreshToken = errors.New("no refresh token in session")
+
 // AuthMiddleware предоставляет middleware для аутентификации.
 type AuthMiddleware struct {
-	provider *oidc.Provider
-	clientID string
+	provider      *oidc.Provider
+	oauth2Config  *oauth2.Config
+	sessions      *SessionStore
+	previewConfig config.PreviewConfig
+	claimsConfig  config.ClaimsConfig
 }
 
 // NewAuthMiddleware создает новый экземпляр AuthMiddleware.
-func NewAuthMiddleware(provider *oidc.Provider, clientID string) *AuthMiddleware {
+// claimsConfig задает, из каких claims ID Token'а заполняется информация о пользователе.
+func NewAuthMiddleware(provider *oidc.Provider, oauth2Config *oauth2.Config, sessions *SessionStore, previewConfig config.PreviewConfig, claimsConfig config.ClaimsConfig) *AuthMiddleware {
 	return &AuthMiddleware{
-		provider: provider,
-		clientID: clientID,
+		provider:      provider,
+		oauth2Config:  oauth2Config,
+		sessions:      sessions,
+		previewConfig: previewConfig,
+		claimsConfig:  claimsConfig,
 	}
 }
 
 // WithUser является middleware, которое проверяет сессионную cookie,
 // верифицирует JWT (ID Token) и помещает информацию о пользователе (claims)
 // в `c.Locals` для дальнейшего использования в обработчиках и шаблонах.
+// Если до истечения ID Token осталось меньше порога обновления, он прозрачно
+// обновляется по refresh-токену с перезаписью cookie сессии. Если обновить
+// истекший токен не удалось, сессия удаляется: веб-страницы перенаправляют на вход,
+// а запросы к API продолжаются от имени гостя, поскольку клиенты API не следуют на страницу входа.
 // Если токен отсутствует или невалиден, он просто передает управление дальше,
 // оставляя в `c.Locals` пустую структуру UserClaims (гостевой пользователь).
+// Так же обрабатывается токен без claims, обязательных по OIDC_REQUIRED_CLAIMS.
+// Если предпросмотр включен и пользователь с ролью редактора передал `?preview=true`,
+// запрос переводится в режим предпросмотра, в котором доступны непубличные курсы.
 func (m *AuthMiddleware) WithUser(c *fiber.Ctx) error {
 	// Инициализируем пустыми данными на случай, если пользователь гость.
 	c.Locals(domain.UserContextKey, domain.UserClaims{})
@@ -38,23 +62,99 @@ func (m *AuthMiddleware) WithUser(c *fiber.Ctx) error {
 		return c.Next()
 	}
 
-	ctx := context.Background()
-	verifier := m.provider.Verifier(&oidc.Config{ClientID: m.clientID})
+	// Срок действия проверяется отдельно, чтобы истекший, но подлинный токен можно было обновить.
+	verifier := m.provider.Verifier(&oidc.Config{ClientID: m.oauth2Config.ClientID, SkipExpiryCheck: true})
 
-	idToken, err := verifier.Verify(ctx, rawIDToken)
+	idToken, err := verifier.Verify(c.UserContext(), rawIDToken)
 	if err != nil {
-		// Если токен невалиден (например, истек), считаем пользователя гостем.
+		// Если токен невалиден, считаем пользователя гостем.
 		return c.Next()
 	}
 
-	var claims domain.UserClaims
-	if err := idToken.Claims(&claims); err != nil {
-		// Если не удалось извлечь claims, считаем пользователя гостем.
+	if time.Until(idToken.Expiry) <= m.sessions.RefreshThreshold() {
+		expired := !time.Now().Before(idToken.Expiry)
+
+		refreshed, err := m.refresh(c)
+		switch {
+		case err == nil:
+			idToken = refreshed
+		case errors.Is(err, errNoRefreshToken):
+			if expired {
+				// Без refresh-токена истекшая сессия означает гостя, как и раньше.
+				return c.Next()
+			}
+		default:
+			slog.Warn("Failed to refresh session", "error", err, "expired", expired)
+			if expired {
+				m.sessions.Clear(c)
+				if isAPIRequest(c) {
+					return c.Next()
+				}
+				return c.Redirect(routing.RouteLogin, fiber.StatusTemporaryRedirect)
+			}
+			// Токен еще действителен: продолжаем с ним, обновление повторится на следующем запросе.
+		}
+	}
+
+	claims, err := userClaimsFromToken(idToken, m.claimsConfig)
+	if err != nil {
+		// Если не удалось извлечь claims или в них нет обязательных полей, считаем пользователя гостем.
+		slog.Warn("Failed to extract user claims from session token", "error", err)
 		return c.Next()
 	}
 
 	// Сохраняем claims в контексте для доступа в последующих обработчиках.
 	c.Locals(domain.UserContextKey, claims)
 
+	// Редакторы могут запросить предпросмотр непубличных курсов.
+	if m.previewConfig.Enabled && c.QueryBool("preview") && claims.HasRole(m.previewConfig.EditorRole) {
+		c.SetUserContext(domain.WithPreview(c.UserContext()))
+	}
+
 	return c.Next()
 }
+
+// isAPIRequest сообщает, относится ли запрос к JSON API, а не к веб-страницам.
+func isAPIRequest(c *fiber.Ctx) bool {
+	path := c.Path()
+	return path == routing.RouteAPIV1 || strings.HasPrefix(path, routing.RouteAPIV1+"/")
+}
+
+// refresh получает новый ID Token по refresh-токену из сессии, проверяет его
+// и сохраняет в cookie вместе с новым refresh-токеном (если провайдер его выдал).
+func (m *AuthMiddleware) refresh(c *fiber.Ctx) (*oidc.IDToken, error) {
+	refreshToken, err := m.sessions.RefreshToken(c)
+	if err != nil {
+		return nil, err
+	}
+	if refreshToken == "" {
+		return nil, errNoRefreshToken
+	}
+
+	ctx := c.UserContext()
+	tokens, err := m.oauth2Config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("refresh token request failed: %w", err)
+	}
+
+	rawIDToken, ok := tokens.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("ID token missing in refresh response")
+	}
+
+	verifier := m.provider.Verifier(&oidc.Config{ClientID: m.oauth2Config.ClientID})
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify refreshed ID token: %w", err)
+	}
+
+	// Провайдер может не ротировать refresh-токен; тогда продолжаем использовать прежний.
+	if tokens.RefreshToken != "" {
+		refreshToken = tokens.RefreshToken
+	}
+	if err := m.sessions.Save(c, rawIDToken, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return idToken, nil
+}
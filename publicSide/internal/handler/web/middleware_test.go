@@ -0,0 +1,174 @@
+package web
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/oauth2"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+)
+
+const testClientID = "public-side"
+
+// newTestProvider запускает провайдер OIDC, который публикует ключ key,
+// а на обмен refresh-токена всегда отвечает invalid_grant. Возвращает также issuer провайдера.
+func newTestProvider(t *testing.T, key *rsa.PrivateKey) (*oidc.Provider, *oauth2.Config, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := (&oidc.ProviderConfig{
+		IssuerURL:  server.URL,
+		JWKSURL:    server.URL + "/jwks",
+		TokenURL:   server.URL + "/token",
+		Algorithms: []string{oidc.RS256},
+	}).NewProvider(context.Background())
+
+	return provider, &oauth2.Config{ClientID: testClientID, Endpoint: provider.Endpoint()}, server.URL
+}
+
+// signIDToken подписывает ID Token с заданными issuer и сроком действия.
+func signIDToken(t *testing.T, key *rsa.PrivateKey, issuer string, expiry time.Time) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	payload, _ := json.Marshal(map[string]any{
+		"iss": issuer,
+		"aud": testClientID,
+		"sub": "user-1",
+		"iat": expiry.Add(-time.Hour).Unix(),
+		"exp": expiry.Unix(),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign ID token: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestWithUserExpiredSessionRefreshFailure(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	provider, oauth2Config, issuer := newTestProvider(t, key)
+
+	sessions, err := NewSessionStore(config.SessionConfig{
+		Lifetime:         time.Hour,
+		RefreshThreshold: time.Minute,
+		CookieSameSite:   fiber.CookieSameSiteLaxMode,
+	}, "secret")
+	if err != nil {
+		t.Fatalf("NewSessionStore() error = %v", err)
+	}
+	m := NewAuthMiddleware(provider, oauth2Config, sessions, config.PreviewConfig{}, config.ClaimsConfig{Subject: "sub"})
+
+	app := fiber.New()
+	app.Get("/seed", func(c *fiber.Ctx) error {
+		return sessions.Save(c, signIDToken(t, key, issuer, time.Now().Add(-time.Minute)), "refresh")
+	})
+	app.Use(m.WithUser)
+	handler := func(c *fiber.Ctx) error {
+		if claims, _ := c.Locals(domain.UserContextKey).(domain.UserClaims); claims.ID != "" {
+			return c.SendString("user " + claims.ID)
+		}
+		return c.SendString("guest")
+	}
+	app.Get(routing.RouteAPIV1+"/categories", handler)
+	app.Get(routing.RouteHome, handler)
+
+	seed, err := app.Test(httptest.NewRequest(http.MethodGet, "/seed", nil))
+	if err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	cookies := seed.Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("seeded %d cookies, want 2", len(cookies))
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{name: "web page redirects to login", path: routing.RouteHome, wantStatus: fiber.StatusTemporaryRedirect, wantLocation: routing.RouteLogin},
+		{name: "api continues as guest", path: routing.RouteAPIV1 + "/categories", wantStatus: fiber.StatusOK, wantBody: "guest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for _, cookie := range cookies {
+				req.AddCookie(cookie)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %q, want %q", body, tt.wantBody)
+				}
+			}
+
+			cleared := map[string]bool{}
+			for _, cookie := range resp.Cookies() {
+				if cookie.MaxAge < 0 {
+					cleared[cookie.Name] = true
+				}
+			}
+			if !cleared[domain.SessionTokenCookie] || !cleared[domain.RefreshTokenCookie] {
+				t.Errorf("cleared cookies = %v, want both session cookies cleared", cleared)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package web содержит обработчики для рендеринга веб-страниц.
+package web
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+)
+
+// SessionStore хранит токены пользовательской сессии в cookie с настраиваемой политикой
+// (домен, SameSite, Secure, HttpOnly, время жизни).
+// ID Token хранится как есть (он подписан провайдером и проверяется при каждом запросе),
+// а refresh-токен шифруется AES-GCM ключом, производным от секрета клиента OIDC,
+// чтобы его нельзя было прочитать или подменить на стороне клиента.
+type SessionStore struct {
+	cfg  config.SessionConfig
+	aead cipher.AEAD
+}
+
+// NewSessionStore создает новый экземпляр SessionStore.
+// secret используется для получения ключа шифрования refresh-токена.
+func NewSessionStore(cfg config.SessionConfig, secret string) (*SessionStore, error) {
+	key := sha256.Sum256([]byte("refresh-token:" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session cipher: %w", err)
+	}
+	return &SessionStore{cfg: cfg, aead: aead}, nil
+}
+
+// RefreshThreshold возвращает интервал до истечения ID Token, начиная с которого он обновляется.
+func (s *SessionStore) RefreshThreshold() time.Duration {
+	return s.cfg.RefreshThreshold
+}
+
+// Save сохраняет ID Token и refresh-токен в cookie сессии.
+// Пустой refreshToken удаляет ранее сохраненный refresh-токен.
+func (s *SessionStore) Save(c *fiber.Ctx, rawIDToken, refreshToken string) error {
+	s.setCookie(c, domain.SessionTokenCookie, rawIDToken)
+	if refreshToken == "" {
+		s.clearCookie(c, domain.RefreshTokenCookie)
+		return nil
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(refreshToken), nil)
+	s.setCookie(c, domain.RefreshTokenCookie, base64.RawURLEncoding.EncodeToString(sealed))
+	return nil
+}
+
+// RefreshToken возвращает расшифрованный refresh-токен из cookie.
+// Возвращает пустую строку, если cookie отсутствует.
+func (s *SessionStore) RefreshToken(c *fiber.Ctx) (string, error) {
+	value := c.Cookies(domain.RefreshTokenCookie)
+	if value == "" {
+		return "", nil
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return "", errors.New("malformed refresh token cookie")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.New("refresh token cookie cannot be decrypted")
+	}
+	return string(plain), nil
+}
+
+// Clear удаляет все cookie сессии.
+func (s *SessionStore) Clear(c *fiber.Ctx) {
+	s.clearCookie(c, domain.SessionTokenCookie)
+	s.clearCookie(c, domain.RefreshTokenCookie)
+}
+
+// setCookie устанавливает cookie сессии согласно настроенной политике.
+func (s *SessionStore) setCookie(c *fiber.Ctx, name, value string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   s.cfg.CookieDomain,
+		MaxAge:   int(s.cfg.Lifetime.Seconds()),
+		Expires:  time.Now().Add(s.cfg.Lifetime),
+		HTTPOnly: s.cfg.CookieHTTPOnly,
+		Secure:   s.cfg.CookieSecure,
+		SameSite: s.cfg.CookieSameSite,
+	})
+}
+
+// clearCookie удаляет cookie сессии; атрибуты совпадают с setCookie, иначе браузер не заменит cookie.
+func (s *SessionStore) clearCookie(c *fiber.Ctx, name string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     name,
+		Value:    "",
+		Domain:   s.cfg.CookieDomain,
+		MaxAge:   -1,
+		Expires:  time.Now().Add(-1 * time.Hour),
+		HTTPOnly: s.cfg.CookieHTTPOnly,
+		Secure:   s.cfg.CookieSecure,
+		SameSite: s.cfg.CookieSameSite,
+	})
+}
+
+// setLoginCookie устанавливает cookie, используемую на время входа через провайдера (state, nonce).
+// Домен и Secure берутся из политики сессии, но SameSite всегда Lax: при Strict браузер
+// не передаст cookie в обратном вызове, инициированном провайдером.
+func (s *SessionStore) setLoginCookie(c *fiber.Ctx, name, value string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   s.cfg.CookieDomain,
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   s.cfg.CookieSecure,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
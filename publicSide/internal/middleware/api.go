@@ -2,7 +2,6 @@
 package middleware
 
 import (
-	"errors"
 	"log/slog"
 
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
@@ -14,22 +13,16 @@ import (
 // Он перехватывает ошибки, преобразует их в стандартизированный JSON-формат
 // и отправляет клиенту с соответствующим HTTP-статусом.
 func APIErrorHandler(c *fiber.Ctx, err error) error {
-	var appErr *apperrors.AppError
-	// Пытаемся преобразовать ошибку в наш кастомный тип AppError.
-	if errors.As(err, &appErr) {
-		slog.Info("Handler error", "error", err)
-		return c.Status(appErr.HTTPStatus).JSON(response.ErrorResponse{
-			Status: response.StatusError,
-			Error: response.ErrorDetail{
-				Code:    appErr.Code,
-				Message: appErr.Message,
-			},
-		})
+	// Сопоставляем ошибку с AppError, в том числе обернутые ошибки репозиториев.
+	appErr := apperrors.FromError(err)
+	if appErr != nil {
+		logHandlerError(appErr, err)
+	} else {
+		// Если ошибку не удалось классифицировать, считаем ее непредвиденной внутренней ошибкой.
+		slog.Error("Unhandled API error", "error", err)
+		appErr = apperrors.NewInternal().(*apperrors.AppError)
 	}
 
-	// Если это не AppError, считаем ее непредвиденной внутренней ошибкой.
-	slog.Error("Unhandled API error", "error", err)
-	appErr = apperrors.NewInternal().(*apperrors.AppError)
 	return c.Status(appErr.HTTPStatus).JSON(response.ErrorResponse{
 		Status: response.StatusError,
 		Error: response.ErrorDetail{
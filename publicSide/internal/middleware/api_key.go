@@ -0,0 +1,103 @@
+// Package middleware предоставляет промежуточные обработчики для Fiber.
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIKeyHeader заголовок, в котором клиент передает API-ключ.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyIDLocal ключ c.Locals, под которым сохраняется идентификатор использованного API-ключа.
+const APIKeyIDLocal = "api_key_id"
+
+// APIKeyAuth проверяет API-ключ из заголовка X-API-Key по SHA-256 хешам из конфигурации.
+// Если ключи не настроены, пропускает все запросы, и API остается открытым.
+// Ключ с областью read допускает только безопасные методы (GET, HEAD, OPTIONS)
+// и POST-маршруты, которые только читают данные (см. readOnlyPostPaths).
+// В логи и трассировку попадает только идентификатор ключа, но не сам ключ.
+func APIKeyAuth(cfg config.APIKeysConfig) fiber.Handler {
+	keys := make([]config.APIKey, 0, len(cfg.Keys))
+	hashes := make([][]byte, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		hash, err := hex.DecodeString(key.Hash)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+		hashes = append(hashes, hash)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if len(keys) == 0 {
+			return c.Next()
+		}
+
+		raw := c.Get(APIKeyHeader)
+		if raw == "" {
+			return apperrors.NewUnauthorized("API key is required in the " + APIKeyHeader + " header")
+		}
+
+		// Сравниваются хеши и без раннего выхода, чтобы время ответа не зависело от совпадения.
+		sum := sha256.Sum256([]byte(raw))
+		matched := -1
+		for i, hash := range hashes {
+			if subtle.ConstantTimeCompare(sum[:], hash) == 1 {
+				matched = i
+			}
+		}
+		if matched < 0 {
+			slog.Warn("Rejected request with unknown API key", "method", c.Method(), "path", c.Path())
+			return apperrors.NewUnauthorized("Invalid API key")
+		}
+
+		key := keys[matched]
+		trace.SpanFromContext(c.UserContext()).SetAttributes(
+			attribute.String("api_key.id", key.ID),
+			attribute.String("api_key.scope", key.Scope),
+		)
+
+		if key.Scope == config.APIKeyScopeRead && !isReadRequest(c) {
+			slog.Warn("API key scope does not allow method", "key_id", key.ID, "scope", key.Scope, "method", c.Method(), "path", c.Path())
+			return apperrors.NewForbidden("API key is read-only")
+		}
+
+		slog.Info("API key used", "key_id", key.ID, "scope", key.Scope, "method", c.Method(), "path", c.Path())
+		c.Locals(APIKeyIDLocal, key.ID)
+		return c.Next()
+	}
+}
+
+// readOnlyPostPaths перечисляет POST-маршруты, которые не изменяют данные
+// (тело запроса используется только для передачи параметров выборки).
+var readOnlyPostPaths = map[string]bool{
+	routing.RouteAPIV1 + routing.RouteCoursesBatch: true,
+}
+
+// isReadRequest сообщает, только ли читает данные запрос.
+func isReadRequest(c *fiber.Ctx) bool {
+	if isSafeMethod(c.Method()) {
+		return true
+	}
+	return c.Method() == fiber.MethodPost && readOnlyPostPaths[strings.TrimSuffix(c.Path(), "/")]
+}
+
+// isSafeMethod сообщает, является ли метод HTTP только читающим.
+func isSafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"strings"
 
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"github.com/gofiber/fiber/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -53,3 +54,13 @@ func CommonErrorHandler(c *fiber.Ctx, err error) error {
 	}
 	return WebErrorHandler(c, err)
 }
+
+// logHandlerError логирует классифицированную ошибку обработчика: клиентские ошибки (4xx) -
+// на уровне Info, серверные (5xx) - на уровне Error, чтобы сбои базы данных не терялись среди 404.
+func logHandlerError(appErr *apperrors.AppError, err error) {
+	if appErr.HTTPStatus >= 500 {
+		slog.Error("Handler error", "code", appErr.Code, "error", err)
+		return
+	}
+	slog.Info("Handler error", "error", err)
+}
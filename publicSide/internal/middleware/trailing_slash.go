@@ -0,0 +1,27 @@
+// Package middleware предоставляет промежуточные обработчики для Fiber.
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NormalizeTrailingSlash удаляет завершающий слэш из пути запроса до сопоставления маршрутов.
+// Соглашение маршрутизации: канонический путь не содержит завершающего слэша
+// (/api/v1/categories, а не /api/v1/categories/). Благодаря перезаписи пути обе формы
+// обрабатываются одинаково, а последующие обработчики видят канонический путь.
+// Должен регистрироваться первым.
+func NormalizeTrailingSlash() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if len(path) > 1 && strings.HasSuffix(path, "/") {
+			trimmed := strings.TrimRight(path, "/")
+			if trimmed == "" {
+				trimmed = "/"
+			}
+			c.Path(trimmed)
+		}
+		return c.Next()
+	}
+}
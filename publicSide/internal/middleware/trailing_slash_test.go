@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNormalizeTrailingSlash(t *testing.T) {
+	app := fiber.New(fiber.Config{StrictRouting: false, CaseSensitive: false})
+	app.Use(NormalizeTrailingSlash())
+
+	var seenPath string
+	app.Use(func(c *fiber.Ctx) error {
+		seenPath = c.Path()
+		return c.Next()
+	})
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("root") })
+	app.Get("/categories/:category_id/courses", func(c *fiber.Ctx) error {
+		return c.SendString("courses of " + c.Params("category_id"))
+	})
+
+	tests := []struct {
+		path     string
+		wantBody string
+		wantPath string
+	}{
+		{path: "/categories/42/courses", wantBody: "courses of 42", wantPath: "/categories/42/courses"},
+		{path: "/categories/42/courses/", wantBody: "courses of 42", wantPath: "/categories/42/courses"},
+		{path: "/categories/42/courses//", wantBody: "courses of 42", wantPath: "/categories/42/courses"},
+		{path: "/Categories/42/Courses/", wantBody: "courses of 42", wantPath: "/Categories/42/Courses"},
+		{path: "/", wantBody: "root", wantPath: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != fiber.StatusOK || string(body) != tt.wantBody {
+				t.Errorf("GET %s = %d %q, want 200 %q", tt.path, resp.StatusCode, body, tt.wantBody)
+			}
+			if seenPath != tt.wantPath {
+				t.Errorf("path seen by middleware = %q, want %q", seenPath, tt.wantPath)
+			}
+		})
+	}
+}
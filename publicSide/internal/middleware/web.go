@@ -2,7 +2,6 @@
 package middleware
 
 import (
-	"errors"
 	"log/slog"
 	"strings"
 
@@ -14,15 +13,16 @@ import (
 
 // WebErrorHandler является обработчиком ошибок для веб-страниц (не API).
 // Он перехватывает ошибки и рендерит HTML-страницу с информацией об ошибке.
+// Ошибки сопоставляются со статусом через apperrors.FromError, в том числе обернутые ошибки репозиториев.
 func WebErrorHandler(c *fiber.Ctx, err error) error {
-	var appErr *apperrors.AppError
-	if errors.As(err, &appErr) {
-		slog.Info("Handler error", "error", err)
-		appErr = err.(*apperrors.AppError)
-	} else if strings.Contains(err.Error(), "Cannot GET") {
+	appErr := apperrors.FromError(err)
+	switch {
+	case appErr != nil:
+		logHandlerError(appErr, err)
+	case strings.Contains(err.Error(), "Cannot GET"):
 		// Обработка стандартной ошибки Fiber для несуществующих маршрутов.
 		appErr = apperrors.NewNotFound("Page").(*apperrors.AppError)
-	} else {
+	default:
 		// Все остальные ошибки считаются внутренними.
 		slog.Error("Unhandled web error", "error", err)
 		appErr = apperrors.NewInternal().(*apperrors.AppError)
@@ -31,7 +31,7 @@ func WebErrorHandler(c *fiber.Ctx, err error) error {
 	return c.Status(appErr.HTTPStatus).Render("pages/error", fiber.Map{
 		"Header":     viewmodel.NewHeader(),
 		"User":       viewmodel.NewUserViewModel(c.Locals(domain.UserContextKey).(domain.UserClaims)),
-		"Main":       viewmodel.NewMain("Home"),
+		"Main":       viewmodel.NewMain(c.UserContext(), "Home"),
 		"Title":      "Error",
 		"HTTPStatus": appErr.HTTPStatus,
 		"Message":    appErr.Message,
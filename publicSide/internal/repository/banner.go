@@ -0,0 +1,53 @@
+// Package repository предоставляет слой для взаимодействия с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BannerRepository определяет интерфейс для чтения баннера сайта из базы данных.
+type BannerRepository interface {
+	// Get получает баннер сайта. Если баннер не задан, возвращает неактивный баннер.
+	Get(ctx context.Context) (domain.Banner, error)
+}
+
+// bannerRepository является реализацией BannerRepository.
+type bannerRepository struct {
+	db   *pgxpool.Pool
+	psql squirrel.StatementBuilderType
+}
+
+// NewBannerRepository создает новый экземпляр bannerRepository.
+func NewBannerRepository(db *pgxpool.Pool) BannerRepository {
+	return &bannerRepository{
+		db:   db,
+		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// Get извлекает единственную строку таблицы баннера.
+func (r *bannerRepository) Get(ctx context.Context) (domain.Banner, error) {
+	query, args, err := r.psql.Select("message", "severity", "active").
+		From(bannerTable).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return domain.Banner{}, dbError("failed to build get banner query", err)
+	}
+
+	var banner domain.Banner
+	err = r.db.QueryRow(ctx, query, args...).Scan(&banner.Message, &banner.Severity, &banner.Active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Banner{}, nil
+		}
+		return domain.Banner{}, dbError("failed to get banner", err)
+	}
+	return banner, nil
+}
@@ -3,12 +3,10 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -49,6 +47,7 @@ func (r *categoryRepository) scanCategory(row scanner) (domain.Category, error)
 }
 
 // GetAll извлекает из базы данных срез категорий с учетом пагинации.
+// Категории с одинаковым временем создания упорядочиваются по ID, чтобы страницы не пересекались.
 // Возвращает срез категорий, общее количество категорий и ошибку.
 func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]domain.Category, int, error) {
 	countQuery := r.psql.Select("COUNT(*)").
@@ -56,13 +55,13 @@ func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]dom
 
 	countSql, countArgs, err := countQuery.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build count query for categories: %w", err)
+		return nil, 0, dbError("failed to build count query for categories", err)
 	}
 
 	var total int
 	err = r.db.QueryRow(ctx, countSql, countArgs...).Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count categories: %w", err)
+		return nil, 0, dbError("failed to count categories", err)
 	}
 
 	if total == 0 {
@@ -71,18 +70,18 @@ func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]dom
 
 	queryBuilder := r.psql.Select("id", "title", "created_at", "updated_at").
 		From(categoryTable).
-		OrderBy("created_at ASC").
+		OrderBy("created_at ASC", "id ASC").
 		Limit(uint64(limit)).
 		Offset(uint64((page - 1) * limit))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build get all categories query: %w", err)
+		return nil, 0, dbError("failed to build get all categories query", err)
 	}
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get categories: %w", err)
+		return nil, 0, dbError("failed to get categories", err)
 	}
 	defer rows.Close()
 
@@ -90,10 +89,13 @@ func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]dom
 	for rows.Next() {
 		category, err := r.scanCategory(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan category: %w", err)
+			return nil, 0, dbError("failed to scan category", err)
 		}
 		categories = append(categories, category)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, dbError("error iterating categories", err)
+	}
 
 	return categories, total, nil
 }
@@ -104,19 +106,17 @@ func (r *categoryRepository) GetAllNotEmpty(ctx context.Context, page, limit int
 	countQuery := r.psql.Select("COUNT(DISTINCT c.id)").
 		From(categoryTable + " AS c").
 		Join(courseTable + " AS co ON c.id = co.category_id").
-		Where(squirrel.Eq{
-			"visibility": "public",
-		})
+		Where(courseVisibility(ctx, "co"))
 
 	countSql, countArgs, err := countQuery.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build count query for not empty categories: %w", err)
+		return nil, 0, dbError("failed to build count query for not empty categories", err)
 	}
 
 	var total int
 	err = r.db.QueryRow(ctx, countSql, countArgs...).Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count not empty categories: %w", err)
+		return nil, 0, dbError("failed to count not empty categories", err)
 	}
 
 	if total == 0 {
@@ -126,22 +126,20 @@ func (r *categoryRepository) GetAllNotEmpty(ctx context.Context, page, limit int
 	queryBuilder := r.psql.Select("c.id", "c.title", "c.created_at", "c.updated_at").
 		From(categoryTable+" AS c").
 		Join(courseTable+" AS co ON c.id = co.category_id").
-		Where(squirrel.Eq{
-			"visibility": "public",
-		}).
+		Where(courseVisibility(ctx, "co")).
 		GroupBy("c.id", "c.title", "c.created_at", "c.updated_at").
-		OrderBy("c.created_at ASC").
+		OrderBy("c.created_at ASC", "c.id ASC").
 		Limit(uint64(limit)).
 		Offset(uint64((page - 1) * limit))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build get all not empty categories query: %w", err)
+		return nil, 0, dbError("failed to build get all not empty categories query", err)
 	}
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get not empty categories: %w", err)
+		return nil, 0, dbError("failed to get not empty categories", err)
 	}
 	defer rows.Close()
 
@@ -149,16 +147,19 @@ func (r *categoryRepository) GetAllNotEmpty(ctx context.Context, page, limit int
 	for rows.Next() {
 		category, err := r.scanCategory(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan category: %w", err)
+			return nil, 0, dbError("failed to scan category", err)
 		}
 		categories = append(categories, category)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, dbError("error iterating categories", err)
+	}
 
 	return categories, total, nil
 }
 
 // GetByID находит и возвращает одну категорию по её ID.
-// Если категория не найдена, возвращает ошибку, содержащую pgx.ErrNoRows.
+// Если категория не найдена, возвращает ошибку, обернутую в apperrors.ErrNotFound.
 func (r *categoryRepository) GetByID(ctx context.Context, categoryID string) (domain.Category, error) {
 	queryBuilder := r.psql.Select("id", "title", "created_at", "updated_at").
 		From(categoryTable).
@@ -168,16 +169,13 @@ func (r *categoryRepository) GetByID(ctx context.Context, categoryID string) (do
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return domain.Category{}, fmt.Errorf("failed to build get category by id query: %w", err)
+		return domain.Category{}, dbError("failed to build get category by id query", err)
 	}
 
 	row := r.db.QueryRow(ctx, query, args...)
 	category, err := r.scanCategory(row)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Category{}, fmt.Errorf("category with id %s not found", categoryID)
-		}
-		return domain.Category{}, fmt.Errorf("failed to get category by id: %w", err)
+		return domain.Category{}, dbError(fmt.Sprintf("failed to get category by id %s", categoryID), err)
 	}
 
 	return category, nil
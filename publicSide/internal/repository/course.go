@@ -4,7 +4,6 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
@@ -18,9 +17,25 @@ import (
 // CourseRepository определяет интерфейс для работы с курсами в базе данных.
 type CourseRepository interface {
 	// GetCoursesByCategoryID получает все публичные курсы для данной категории с пагинацией, фильтрацией и сортировкой.
-	GetCoursesByCategoryID(ctx context.Context, categoryID string, page, limit int, level, sortBy string) ([]domain.Course, int, error)
+	// Если `levels` не пуст, возвращаются только курсы с одним из перечисленных уровней.
+	GetCoursesByCategoryID(ctx context.Context, categoryID string, page, limit int, levels []string, sortBy string) ([]domain.Course, int, error)
 	// GetCourseByID получает один публичный курс по его ID и ID категории.
 	GetCourseByID(ctx context.Context, categoryID, courseID string) (domain.Course, error)
+	// GetCourseBySlug получает один публичный курс по его slug и ID категории.
+	GetCourseBySlug(ctx context.Context, categoryID, slug string) (domain.Course, error)
+	// GetCoursesByIDs получает видимые курсы из любых категорий по списку ID вместе с названием категории.
+	GetCoursesByIDs(ctx context.Context, ids []string) ([]domain.Course, error)
+	// GetRecentlyUpdated получает limit последних обновленных публичных курсов из всех категорий
+	// вместе с названием и slug категории.
+	GetRecentlyUpdated(ctx context.Context, limit int) ([]domain.Course, error)
+}
+
+// courseColumns перечисляет колонки курса в порядке, ожидаемом scanCourse.
+var courseColumns = []string{"id", "title", "slug", "description", "level", "category_id", "visibility", "image_key", "content_policy", "created_at", "updated_at"}
+
+// courseSortFields сопоставляет поля сортировки списка курсов с колонками запроса.
+var courseSortFields = map[string]string{
+	"updated_at": "updated_at",
 }
 
 // courseRepository является реализацией CourseRepository.
@@ -38,19 +53,21 @@ func NewCourseRepository(db *pgxpool.Pool) CourseRepository {
 }
 
 // scanCourse сканирует одну строку из результата запроса в структуру domain.Course.
-// Обрабатывает `image_key`, который может быть NULL.
+// Обрабатывает `slug` и `image_key`, которые могут быть NULL.
 func (r *courseRepository) scanCourse(row scanner) (domain.Course, error) {
 	var course domain.Course
-	var imageKey sql.NullString
+	var slug, imageKey sql.NullString
 
 	err := row.Scan(
 		&course.ID,
 		&course.Title,
+		&slug,
 		&course.Description,
 		&course.Level,
 		&course.CategoryID,
 		&course.Visibility,
 		&imageKey,
+		&course.ContentPolicy,
 		&course.CreatedAt,
 		&course.UpdatedAt,
 	)
@@ -58,6 +75,8 @@ func (r *courseRepository) scanCourse(row scanner) (domain.Course, error) {
 		return domain.Course{}, err
 	}
 
+	course.Slug = slug.String
+
 	if imageKey.Valid {
 		course.ImageKey = imageKey.String
 	} else {
@@ -68,9 +87,9 @@ func (r *courseRepository) scanCourse(row scanner) (domain.Course, error) {
 }
 
 // GetCoursesByCategoryID извлекает из базы данных срез курсов для указанной категории.
-// Поддерживает пагинацию, фильтрацию по уровню сложности и сортировку.
+// Поддерживает пагинацию, фильтрацию по одному или нескольким уровням сложности и сортировку.
 // Возвращает срез курсов, общее количество курсов, удовлетворяющих фильтрам, и ошибку.
-func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryID string, page, limit int, level, sortBy string) ([]domain.Course, int, error) {
+func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryID string, page, limit int, levels []string, sortBy string) ([]domain.Course, int, error) {
 	tracer := otel.Tracer("repository")
 	ctx, span := tracer.Start(ctx, "courseRepository.GetCoursesByCategoryID")
 	defer span.End()
@@ -79,27 +98,32 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 		attribute.String("category_id", categoryID),
 		attribute.Int("page", page),
 		attribute.Int("limit", limit),
-		attribute.String("level", level),
+		attribute.StringSlice("levels", levels),
 		attribute.String("sort_by", sortBy),
 	)
 
+	column, direction, err := utils.ParseSort(sortBy, courseSortFields, "updated_at", utils.DescendingDirection)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Сначала считаем общее количество курсов, удовлетворяющих фильтрам.
 	countQuery := r.psql.Select("COUNT(*)").
 		From(courseTable).
 		Where(squirrel.Eq{
 			"category_id": categoryID,
-			"visibility":  "public",
-		})
+		}).
+		Where(courseVisibility(ctx, ""))
 
-	if level != "" && level != "all" {
-		countQuery = countQuery.Where(squirrel.Eq{"level": level})
+	if len(levels) > 0 {
+		countQuery = countQuery.Where(squirrel.Expr("level = ANY(?)", levels))
 	}
 
 	countSql, countArgs, err := countQuery.ToSql()
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to build count query")
-		return nil, 0, fmt.Errorf("failed to build count query for courses: %w", err)
+		return nil, 0, dbError("failed to build count query for courses", err)
 	}
 
 	var total int
@@ -107,7 +131,7 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to count courses")
-		return nil, 0, fmt.Errorf("failed to count courses: %w", err)
+		return nil, 0, dbError("failed to count courses", err)
 	}
 
 	if total == 0 {
@@ -117,21 +141,17 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 	// Затем строим основной запрос для получения среза курсов.
 	offset := (page - 1) * limit
 
-	queryBuilder := r.psql.Select("id", "title", "description", "level", "category_id", "visibility", "image_key", "created_at", "updated_at").
+	queryBuilder := r.psql.Select(courseColumns...).
 		From(courseTable).
 		Where(squirrel.Eq{
 			"category_id": categoryID,
-			"visibility":  "public",
-		})
+		}).
+		Where(courseVisibility(ctx, ""))
 
-	if level != "" && level != "all" {
-		queryBuilder = queryBuilder.Where(squirrel.Eq{"level": level})
+	if len(levels) > 0 {
+		queryBuilder = queryBuilder.Where(squirrel.Expr("level = ANY(?)", levels))
 	}
 
-	column, direction := utils.UnpackSort(sortBy, "updated_at", utils.DescendingDirection, map[string]bool{
-		"updated_at": true,
-	})
-
 	queryBuilder = queryBuilder.
 		OrderBy(column + " " + direction).
 		Limit(uint64(limit)).
@@ -141,14 +161,14 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to build query")
-		return nil, 0, fmt.Errorf("failed to build get courses query: %w", err)
+		return nil, 0, dbError("failed to build get courses query", err)
 	}
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to query courses")
-		return nil, 0, fmt.Errorf("failed to retrieve courses: %w", err)
+		return nil, 0, dbError("failed to retrieve courses", err)
 	}
 	defer rows.Close()
 
@@ -158,7 +178,7 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 		if err != nil {
 			span.RecordError(err)
 			span.SetStatus(codes.Error, "Failed to scan course")
-			return nil, 0, fmt.Errorf("failed to scan course: %w", err)
+			return nil, 0, dbError("failed to scan course", err)
 		}
 		courses = append(courses, course)
 	}
@@ -166,7 +186,7 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 	if err := rows.Err(); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Error iterating courses")
-		return nil, 0, fmt.Errorf("error iterating courses: %w", err)
+		return nil, 0, dbError("error iterating courses", err)
 	}
 
 	span.SetAttributes(attribute.Int("courses_count", len(courses)))
@@ -174,7 +194,7 @@ func (r *courseRepository) GetCoursesByCategoryID(ctx context.Context, categoryI
 }
 
 // GetCourseByID находит и возвращает один видимый курс по его ID и ID категории.
-// Если курс не найден, возвращает ошибку.
+// Если курс не найден, возвращает ошибку, обернутую в apperrors.ErrNotFound.
 func (r *courseRepository) GetCourseByID(ctx context.Context, categoryID, courseID string) (domain.Course, error) {
 	tracer := otel.Tracer("repository")
 	ctx, span := tracer.Start(ctx, "courseRepository.GetCourseByID")
@@ -185,19 +205,19 @@ func (r *courseRepository) GetCourseByID(ctx context.Context, categoryID, course
 		attribute.String("course_id", courseID),
 	)
 
-	queryBuilder := r.psql.Select("id", "title", "description", "level", "category_id", "visibility", "image_key", "created_at", "updated_at").
+	queryBuilder := r.psql.Select(courseColumns...).
 		From(courseTable).
 		Where(squirrel.Eq{
 			"id":          courseID,
 			"category_id": categoryID,
-			"visibility":  "public",
-		})
+		}).
+		Where(courseVisibility(ctx, ""))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to build query")
-		return domain.Course{}, fmt.Errorf("failed to build get course by id query: %w", err)
+		return domain.Course{}, dbError("failed to build get course by id query", err)
 	}
 
 	row := r.db.QueryRow(ctx, query, args...)
@@ -205,8 +225,192 @@ func (r *courseRepository) GetCourseByID(ctx context.Context, categoryID, course
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to scan course")
-		return domain.Course{}, fmt.Errorf("failed to get course by id: %w", err)
+		return domain.Course{}, dbError("failed to get course by id", err)
 	}
 
 	return course, nil
 }
+
+// GetCourseBySlug находит и возвращает один видимый курс по его slug и ID категории.
+// Slug уникален только в пределах категории, поэтому поиск всегда ограничен ею.
+// Если курс не найден, возвращает ошибку, обернутую в apperrors.ErrNotFound.
+func (r *courseRepository) GetCourseBySlug(ctx context.Context, categoryID, slug string) (domain.Course, error) {
+	tracer := otel.Tracer("repository")
+	ctx, span := tracer.Start(ctx, "courseRepository.GetCourseBySlug")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("category_id", categoryID),
+		attribute.String("slug", slug),
+	)
+
+	queryBuilder := r.psql.Select(courseColumns...).
+		From(courseTable).
+		Where(squirrel.Eq{
+			"slug":        slug,
+			"category_id": categoryID,
+		}).
+		Where(courseVisibility(ctx, ""))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to build query")
+		return domain.Course{}, dbError("failed to build get course by slug query", err)
+	}
+
+	row := r.db.QueryRow(ctx, query, args...)
+	course, err := r.scanCourse(row)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to scan course")
+		return domain.Course{}, dbError("failed to get course by slug", err)
+	}
+
+	return course, nil
+}
+
+// courseWithCategoryScanner дополняет сканирование курса колонкой с названием категории,
+// которая идет в запросе сразу после courseColumns, и, если задан categorySlug, колонкой со slug категории.
+type courseWithCategoryScanner struct {
+	row           scanner
+	categoryTitle *string
+	categorySlug  *sql.NullString
+}
+
+// Scan сканирует колонки курса и колонки категории.
+func (s courseWithCategoryScanner) Scan(dest ...any) error {
+	dest = append(dest, s.categoryTitle)
+	if s.categorySlug != nil {
+		dest = append(dest, s.categorySlug)
+	}
+	return s.row.Scan(dest...)
+}
+
+// GetCoursesByIDs находит видимые курсы по списку ID независимо от категории одним запросом
+// и присоединяет название категории. Неизвестные и скрытые курсы пропускаются без ошибки.
+// Порядок результата не определен.
+func (r *courseRepository) GetCoursesByIDs(ctx context.Context, ids []string) ([]domain.Course, error) {
+	tracer := otel.Tracer("repository")
+	ctx, span := tracer.Start(ctx, "courseRepository.GetCoursesByIDs")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("ids_count", len(ids)))
+
+	if len(ids) == 0 {
+		return []domain.Course{}, nil
+	}
+
+	columns := make([]string, 0, len(courseColumns)+1)
+	for _, column := range courseColumns {
+		columns = append(columns, "c."+column)
+	}
+	columns = append(columns, "cat.title")
+
+	queryBuilder := r.psql.Select(columns...).
+		From(courseTable + " AS c").
+		Join(categoryTable + " AS cat ON cat.id = c.category_id").
+		Where(squirrel.Expr("c.id = ANY(?)", ids)).
+		Where(courseVisibility(ctx, "c"))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to build query")
+		return nil, dbError("failed to build get courses by ids query", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to query courses")
+		return nil, dbError("failed to retrieve courses by ids", err)
+	}
+	defer rows.Close()
+
+	courses := make([]domain.Course, 0, len(ids))
+	for rows.Next() {
+		var categoryTitle string
+		course, err := r.scanCourse(courseWithCategoryScanner{row: rows, categoryTitle: &categoryTitle})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to scan course")
+			return nil, dbError("failed to scan course", err)
+		}
+		course.CategoryTitle = categoryTitle
+		courses = append(courses, course)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Error iterating courses")
+		return nil, dbError("error iterating courses", err)
+	}
+
+	span.SetAttributes(attribute.Int("courses_count", len(courses)))
+	return courses, nil
+}
+
+// GetRecentlyUpdated извлекает limit публичных курсов из всех категорий, отсортированных по времени
+// последнего обновления (сначала новые), и присоединяет название и slug категории.
+// В отличие от остальных методов, черновики не возвращаются и в режиме предпросмотра:
+// лента последних обновлений показывает только опубликованные курсы.
+func (r *courseRepository) GetRecentlyUpdated(ctx context.Context, limit int) ([]domain.Course, error) {
+	tracer := otel.Tracer("repository")
+	ctx, span := tracer.Start(ctx, "courseRepository.GetRecentlyUpdated")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("limit", limit))
+
+	columns := make([]string, 0, len(courseColumns)+2)
+	for _, column := range courseColumns {
+		columns = append(columns, "c."+column)
+	}
+	columns = append(columns, "cat.title", "cat.slug")
+
+	queryBuilder := r.psql.Select(columns...).
+		From(courseTable+" AS c").
+		Join(categoryTable+" AS cat ON cat.id = c.category_id").
+		Where(squirrel.Eq{"c.visibility": "public", "c.deleted_at": nil}).
+		OrderBy("c.updated_at DESC", "c.id ASC").
+		Limit(uint64(limit))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to build query")
+		return nil, dbError("failed to build get recently updated courses query", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to query courses")
+		return nil, dbError("failed to retrieve recently updated courses", err)
+	}
+	defer rows.Close()
+
+	courses := make([]domain.Course, 0, limit)
+	for rows.Next() {
+		var categoryTitle string
+		var categorySlug sql.NullString
+		course, err := r.scanCourse(courseWithCategoryScanner{row: rows, categoryTitle: &categoryTitle, categorySlug: &categorySlug})
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to scan course")
+			return nil, dbError("failed to scan course", err)
+		}
+		course.CategoryTitle = categoryTitle
+		course.CategorySlug = categorySlug.String
+		courses = append(courses, course)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Error iterating courses")
+		return nil, dbError("error iterating courses", err)
+	}
+
+	span.SetAttributes(attribute.Int("courses_count", len(courses)))
+	return courses, nil
+}
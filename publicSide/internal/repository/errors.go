@@ -0,0 +1,32 @@
+// Package repository предоставляет слой для взаимодействия с базой данных.
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE ошибок PostgreSQL, которые означают конфликт с существующими данными.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// dbError добавляет к ошибке драйвера контекст msg и класс ошибки из apperrors:
+// ErrNotFound для pgx.ErrNoRows, ErrConflict для нарушений уникальности и внешних ключей,
+// ErrDB для остальных. Исходная ошибка остается в цепочке и доступна через errors.Is/As.
+func dbError(msg string, err error) error {
+	kind := apperrors.ErrDB
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		kind = apperrors.ErrNotFound
+	case errors.As(err, &pgErr) && (pgErr.Code == pgUniqueViolation || pgErr.Code == pgForeignKeyViolation):
+		kind = apperrors.ErrConflict
+	}
+	return fmt.Errorf("%s: %w: %w", msg, kind, err)
+}
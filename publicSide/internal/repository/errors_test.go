@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/middleware"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestDBErrorThroughErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        error
+		kind       error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "no rows", raw: pgx.ErrNoRows, kind: apperrors.ErrNotFound, wantStatus: 404, wantCode: "NOT_FOUND"},
+		{name: "unique violation", raw: &pgconn.PgError{Code: "23505"}, kind: apperrors.ErrConflict, wantStatus: 409, wantCode: "CONFLICT"},
+		{name: "foreign key violation", raw: &pgconn.PgError{Code: "23503"}, kind: apperrors.ErrConflict, wantStatus: 409, wantCode: "CONFLICT"},
+		{name: "undefined table", raw: &pgconn.PgError{Code: "42P01"}, kind: apperrors.ErrDB, wantStatus: 500, wantCode: "DATABASE_ERROR"},
+		{name: "deadline exceeded", raw: context.DeadlineExceeded, kind: apperrors.ErrDB, wantStatus: 500, wantCode: "DATABASE_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError("failed to get course by id", tt.raw)
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("dbError() = %v, want it to wrap %v", err, tt.kind)
+			}
+			if !errors.Is(err, tt.raw) {
+				t.Errorf("dbError() = %v, want it to keep the driver error %v", err, tt.raw)
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.CommonErrorHandler})
+			app.Get("/api/v1/courses", func(c *fiber.Ctx) error { return err })
+
+			resp, testErr := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/v1/courses", nil))
+			if testErr != nil {
+				t.Fatalf("app.Test() error = %v", testErr)
+			}
+			var body response.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus || body.Error.Code != tt.wantCode {
+				t.Errorf("response = %d %s, want %d %s", resp.StatusCode, body.Error.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -3,12 +3,13 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/lessoncontent"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,10 +21,22 @@ const (
 	DirectionPrevious = "previous"
 )
 
+// lessonSortFields сопоставляет поля сортировки списка уроков с колонками запроса.
+var lessonSortFields = map[string]string{
+	"order_index": "l.order_index",
+	"title":       "l.title",
+	"created_at":  "l.created_at",
+	"updated_at":  "l.updated_at",
+}
+
+// lessonColumns перечисляет колонки урока (с алиасом l) в порядке, ожидаемом scanLesson.
+// Уроки, созданные до появления slug и еще не прошедшие заполнение, имеют пустой slug.
+var lessonColumns = []string{"l.id", "l.title", "COALESCE(l.slug, '')", "l.course_id", "l.content", "l.order_index", "l.created_at", "l.updated_at"}
+
 // LessonChunkOptions определяет параметры для выборки "чанка" (порции) уроков.
 // Используется для получения соседних уроков.
 type LessonChunkOptions struct {
-	PivotValue interface{} // Значение поля, от которого идет выборка (например, `created_at` текущего урока).
+	PivotValue interface{} // Значение поля, от которого идет выборка (например, `order_index` текущего урока).
 	OrderBy    string      // Поле для сортировки.
 	Direction  string      // Направление выборки (`next` или `previous`).
 	Limit      int         // Количество записей для выборки.
@@ -35,8 +48,16 @@ type LessonRepository interface {
 	GetAllByCourseID(ctx context.Context, categoryID, courseID string, page, limit int, sort string) ([]domain.Lesson, int, error)
 	// GetByID получает один урок по его ID, ID курса и ID категории.
 	GetByID(ctx context.Context, categoryID, courseID, lessonID string) (domain.Lesson, error)
+	// GetPublishedLessonBySlug получает опубликованный урок по ID категории, slug курса и slug урока.
+	GetPublishedLessonBySlug(ctx context.Context, categoryID, courseSlug, lessonSlug string) (domain.Lesson, error)
 	// GetLessonsChunk получает порцию уроков на основе заданных опций.
 	GetLessonsChunk(ctx context.Context, courseID string, options LessonChunkOptions) ([]domain.Lesson, error)
+	// GetLessonWindow получает ID уроков, окружающих опорный урок, одним запросом.
+	GetLessonWindow(ctx context.Context, courseID string, options LessonChunkOptions) (prevIDs, nextIDs []string, err error)
+	// GetLessonsPageCursor получает страницу уроков после опорной пары (created_at, id) без OFFSET.
+	GetLessonsPageCursor(ctx context.Context, categoryID, courseID string, afterCreatedAt *time.Time, afterID string, limit int) ([]domain.Lesson, error)
+	// CountLessonsByCourseIDs подсчитывает уроки для набора курсов одним запросом.
+	CountLessonsByCourseIDs(ctx context.Context, courseIDs []string) (map[string]int, error)
 }
 
 // lessonRepository является реализацией LessonRepository.
@@ -59,16 +80,25 @@ type scanner interface {
 }
 
 // scanLesson сканирует одну строку из результата запроса в структуру domain.Lesson.
+// Контент хранится в БД байтами и может быть сжат gzip (см. lessoncontent.Decode).
 func (r *lessonRepository) scanLesson(row scanner) (domain.Lesson, error) {
 	var lesson domain.Lesson
+	var content []byte
 	err := row.Scan(
 		&lesson.ID,
 		&lesson.Title,
+		&lesson.Slug,
 		&lesson.CourseID,
-		&lesson.Content,
+		&content,
+		&lesson.OrderIndex,
 		&lesson.CreatedAt,
 		&lesson.UpdatedAt,
 	)
+	if err != nil {
+		return lesson, err
+	}
+
+	lesson.Content, err = lessoncontent.Decode(content)
 	return lesson, err
 }
 
@@ -79,7 +109,7 @@ func (r *lessonRepository) scanLessons(rows pgx.Rows) ([]domain.Lesson, error) {
 	for rows.Next() {
 		lesson, err := r.scanLesson(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan lesson: %w", err)
+			return nil, dbError("failed to scan lesson", err)
 		}
 		lessons = append(lessons, lesson)
 	}
@@ -87,8 +117,14 @@ func (r *lessonRepository) scanLessons(rows pgx.Rows) ([]domain.Lesson, error) {
 }
 
 // GetAllByCourseID извлекает срез уроков для указанного курса с пагинацией и сортировкой.
-// Возвращает срез уроков, общее количество уроков в курсе и ошибку.
+// Возвращает срез уроков, общее количество уроков в курсе и ошибку;
+// для неизвестного поля сортировки - ошибку VALIDATION_ERROR.
 func (r *lessonRepository) GetAllByCourseID(ctx context.Context, categoryID, courseID string, page, limit int, sort string) ([]domain.Lesson, int, error) {
+	column, direction, err := utils.ParseSort(sort, lessonSortFields, "l.order_index", utils.AscendingDirection)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// Сначала получаем общее количество уроков для пагинации.
 	countBuilder := r.psql.Select("COUNT(l.id)").
 		From(lessonsTable + " AS l").
@@ -96,18 +132,18 @@ func (r *lessonRepository) GetAllByCourseID(ctx context.Context, categoryID, cou
 		Where(squirrel.Eq{
 			"c.category_id": categoryID,
 			"l.course_id":   courseID,
-			"c.visibility":  "public",
-		})
+		}).
+		Where(courseVisibility(ctx, "c"))
 
 	countQuery, args, err := countBuilder.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build count query for lessons: %w", err)
+		return nil, 0, dbError("failed to build count query for lessons", err)
 	}
 
 	var total int
 	err = r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count lessons by course: %w", err)
+		return nil, 0, dbError("failed to count lessons by course", err)
 	}
 
 	if total == 0 {
@@ -115,27 +151,27 @@ func (r *lessonRepository) GetAllByCourseID(ctx context.Context, categoryID, cou
 	}
 
 	// Затем получаем срез уроков для текущей страницы.
-	queryBuilder := r.psql.Select("l.id", "l.title", "l.course_id", "l.content", "l.created_at", "l.updated_at").
+	queryBuilder := r.psql.Select(lessonColumns...).
 		From(lessonsTable + " AS l").
 		Join(courseTable + " AS c ON l.course_id = c.id").
 		Where(squirrel.Eq{
 			"c.category_id": categoryID,
 			"l.course_id":   courseID,
-			"c.visibility":  "public",
 		}).
+		Where(courseVisibility(ctx, "c")).
 		Limit(uint64(limit)).
 		Offset(uint64((page - 1) * limit))
 
-	queryBuilder = r.applySorting(queryBuilder, sort)
+	queryBuilder = queryBuilder.OrderBy(column+" "+direction, "l.created_at ASC")
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to build get all lessons query: %w", err)
+		return nil, 0, dbError("failed to build get all lessons query", err)
 	}
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get lessons by course: %w", err)
+		return nil, 0, dbError("failed to get lessons by course", err)
 	}
 
 	lessons, err := r.scanLessons(rows)
@@ -145,31 +181,98 @@ func (r *lessonRepository) GetAllByCourseID(ctx context.Context, categoryID, cou
 	return lessons, total, nil
 }
 
+// GetLessonsPageCursor извлекает до limit видимых уроков курса, упорядоченных по (created_at, id),
+// которые идут строго после пары (afterCreatedAt, afterID). Если afterCreatedAt равен nil,
+// выборка начинается с первого урока. id служит разрешителем равных created_at, поэтому порядок
+// стабилен и страницы не пересекаются даже при добавлении уроков между запросами.
+// В отличие от OFFSET стоимость запроса не растет с номером страницы (индекс idx_lesson_course_created_id).
+func (r *lessonRepository) GetLessonsPageCursor(ctx context.Context, categoryID, courseID string, afterCreatedAt *time.Time, afterID string, limit int) ([]domain.Lesson, error) {
+	queryBuilder := r.psql.Select(lessonColumns...).
+		From(lessonsTable+" AS l").
+		Join(courseTable+" AS c ON l.course_id = c.id").
+		Where(squirrel.Eq{
+			"c.category_id": categoryID,
+			"l.course_id":   courseID,
+		}).
+		Where(courseVisibility(ctx, "c")).
+		OrderBy("l.created_at ASC", "l.id ASC").
+		Limit(uint64(limit))
+
+	if afterCreatedAt != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Expr("(l.created_at, l.id) > (?::timestamp, ?::uuid)", *afterCreatedAt, afterID))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, dbError("failed to build lessons cursor query", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, dbError("failed to get lessons page by cursor", err)
+	}
+
+	lessons, err := r.scanLessons(rows)
+	if err != nil {
+		return nil, err
+	}
+	if lessons == nil {
+		lessons = []domain.Lesson{}
+	}
+	return lessons, nil
+}
+
 // GetByID находит и возвращает один видимый урок по его ID, ID курса и ID категории.
-// Если урок не найден, возвращает ошибку.
+// Если урок не найден, возвращает ошибку, обернутую в apperrors.ErrNotFound.
 func (r *lessonRepository) GetByID(ctx context.Context, categoryID, courseID, lessonID string) (domain.Lesson, error) {
-	queryBuilder := r.psql.Select("l.id", "l.title", "l.course_id", "l.content", "l.created_at", "l.updated_at").
+	queryBuilder := r.psql.Select(lessonColumns...).
 		From(lessonsTable + " AS l").
 		Join(courseTable + " AS c ON l.course_id = c.id").
 		Where(squirrel.Eq{
 			"c.category_id": categoryID,
 			"l.course_id":   courseID,
 			"l.id":          lessonID,
-			"c.visibility":  "public",
-		})
+		}).
+		Where(courseVisibility(ctx, "c"))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return domain.Lesson{}, fmt.Errorf("failed to build get lesson by id query: %w", err)
+		return domain.Lesson{}, dbError("failed to build get lesson by id query", err)
 	}
 
 	row := r.db.QueryRow(ctx, query, args...)
 	lesson, err := r.scanLesson(row)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Lesson{}, fmt.Errorf("lesson with id %s not found in course %s", lessonID, courseID)
-		}
-		return domain.Lesson{}, fmt.Errorf("failed to get lesson by id: %w", err)
+		return domain.Lesson{}, dbError(fmt.Sprintf("failed to get lesson by id %s in course %s", lessonID, courseID), err)
+	}
+
+	return lesson, nil
+}
+
+// GetPublishedLessonBySlug находит урок по цепочке категория -> slug курса -> slug урока.
+// Курс должен быть опубликован и не удален (см. courseVisibility), иначе урок не находится.
+// Если любое звено цепочки не разрешается, возвращает ошибку, обернутую в apperrors.ErrNotFound.
+func (r *lessonRepository) GetPublishedLessonBySlug(ctx context.Context, categoryID, courseSlug, lessonSlug string) (domain.Lesson, error) {
+	queryBuilder := r.psql.Select(lessonColumns...).
+		From(lessonsTable + " AS l").
+		Join(courseTable + " AS c ON l.course_id = c.id").
+		Join(categoryTable + " AS cat ON c.category_id = cat.id").
+		Where(squirrel.Eq{
+			"cat.id": categoryID,
+			"c.slug": courseSlug,
+			"l.slug": lessonSlug,
+		}).
+		Where(courseVisibility(ctx, "c"))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return domain.Lesson{}, dbError("failed to build get lesson by slug query", err)
+	}
+
+	row := r.db.QueryRow(ctx, query, args...)
+	lesson, err := r.scanLesson(row)
+	if err != nil {
+		return domain.Lesson{}, dbError(fmt.Sprintf("failed to get lesson by slug %s in course %s", lessonSlug, courseSlug), err)
 	}
 
 	return lesson, nil
@@ -182,7 +285,7 @@ func (r *lessonRepository) GetLessonsChunk(ctx context.Context, courseID string,
 		return nil, fmt.Errorf("invalid order by field: %s", options.OrderBy)
 	}
 
-	queryBuilder := r.psql.Select("l.id", "l.title", "l.course_id", "l.content", "l.created_at", "l.updated_at").
+	queryBuilder := r.psql.Select(lessonColumns...).
 		From(lessonsTable + " AS l").
 		Where(squirrel.Eq{"l.course_id": courseID})
 
@@ -198,58 +301,135 @@ func (r *lessonRepository) GetLessonsChunk(ctx context.Context, courseID string,
 
 	// Устанавливаем порядок сортировки для корректной выборки "следующего" или "предыдущего".
 	if options.Direction == DirectionNext {
-		queryBuilder = queryBuilder.OrderBy(fmt.Sprintf("l.%s ASC", options.OrderBy))
+		queryBuilder = queryBuilder.OrderBy(fmt.Sprintf("l.%s ASC", options.OrderBy), "l.created_at ASC")
 	} else {
-		queryBuilder = queryBuilder.OrderBy(fmt.Sprintf("l.%s DESC", options.OrderBy))
+		queryBuilder = queryBuilder.OrderBy(fmt.Sprintf("l.%s DESC", options.OrderBy), "l.created_at DESC")
 	}
 
 	queryBuilder = queryBuilder.Limit(uint64(options.Limit))
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build lessons chunk query: %w", err)
+		return nil, dbError("failed to build lessons chunk query", err)
 	}
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get lessons chunk: %w", err)
+		return nil, dbError("failed to get lessons chunk", err)
 	}
 
 	return r.scanLessons(rows)
 }
 
-// isValidOrderBy проверяет, является ли поле сортировки допустимым.
-func (r *lessonRepository) isValidOrderBy(field string) bool {
-	switch field {
-	case "created_at", "title", "updated_at":
-		return true
-	default:
-		return false
+// GetLessonWindow получает ID уроков, находящихся до и после опорного урока, одним запросом.
+// Поле `Direction` в опциях игнорируется: выборка делается в обе стороны, по `Limit` записей в каждую.
+// Предыдущие уроки возвращаются в порядке следования курса (от дальнего к ближнему).
+func (r *lessonRepository) GetLessonWindow(ctx context.Context, courseID string, options LessonChunkOptions) ([]string, []string, error) {
+	if !r.isValidOrderBy(options.OrderBy) {
+		return nil, nil, fmt.Errorf("invalid order by field: %s", options.OrderBy)
+	}
+
+	column := fmt.Sprintf("l.%s", options.OrderBy)
+	chunk := func(direction string, cond squirrel.Sqlizer, order string) squirrel.SelectBuilder {
+		return squirrel.Select("l.id", fmt.Sprintf("'%s' AS direction", direction)).
+			From(lessonsTable+" AS l").
+			Where(squirrel.Eq{"l.course_id": courseID}).
+			Where(cond).
+			OrderBy(fmt.Sprintf("%s %s", column, order), "l.created_at "+order).
+			Limit(uint64(options.Limit))
+	}
+
+	prevSQL, prevArgs, err := chunk(DirectionPrevious, squirrel.Lt{column: options.PivotValue}, "DESC").ToSql()
+	if err != nil {
+		return nil, nil, dbError("failed to build lesson window query", err)
+	}
+	nextSQL, nextArgs, err := chunk(DirectionNext, squirrel.Gt{column: options.PivotValue}, "ASC").ToSql()
+	if err != nil {
+		return nil, nil, dbError("failed to build lesson window query", err)
+	}
+
+	query, err := squirrel.Dollar.ReplacePlaceholders(fmt.Sprintf("(%s) UNION ALL (%s)", prevSQL, nextSQL))
+	if err != nil {
+		return nil, nil, dbError("failed to build lesson window query", err)
+	}
+
+	rows, err := r.db.Query(ctx, query, append(prevArgs, nextArgs...)...)
+	if err != nil {
+		return nil, nil, dbError("failed to get lesson window", err)
+	}
+	defer rows.Close()
+
+	prevIDs, nextIDs := []string{}, []string{}
+	for rows.Next() {
+		var id, direction string
+		if err := rows.Scan(&id, &direction); err != nil {
+			return nil, nil, dbError("failed to scan lesson window", err)
+		}
+		if direction == DirectionPrevious {
+			prevIDs = append(prevIDs, id)
+		} else {
+			nextIDs = append(nextIDs, id)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, dbError("failed to iterate lesson window", err)
 	}
+
+	// Предыдущие уроки выбраны в обратном порядке — разворачиваем их.
+	for i, j := 0, len(prevIDs)-1; i < j; i, j = i+1, j-1 {
+		prevIDs[i], prevIDs[j] = prevIDs[j], prevIDs[i]
+	}
+
+	return prevIDs, nextIDs, nil
 }
 
-// applySorting применяет к запросу сортировку на основе строки `sort`.
-func (r *lessonRepository) applySorting(builder squirrel.SelectBuilder, sort string) squirrel.SelectBuilder {
-	if sort == "" {
-		return builder.OrderBy("l.created_at ASC")
+// CountLessonsByCourseIDs возвращает количество уроков для каждого из переданных курсов,
+// выполняя один запрос с GROUP BY вместо отдельного подсчета на каждый курс.
+// Курсы без уроков (и недоступные по видимости) в результат не попадают.
+func (r *lessonRepository) CountLessonsByCourseIDs(ctx context.Context, courseIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return counts, nil
 	}
 
-	allowedFields := map[string]string{
-		"title":      "l.title",
-		"created_at": "l.created_at",
-		"updated_at": "l.updated_at",
+	query, args, err := r.psql.Select("l.course_id", "COUNT(l.id)").
+		From(lessonsTable + " AS l").
+		Join(courseTable + " AS c ON l.course_id = c.id").
+		Where(squirrel.Eq{"l.course_id": courseIDs}).
+		Where(courseVisibility(ctx, "c")).
+		GroupBy("l.course_id").
+		ToSql()
+	if err != nil {
+		return nil, dbError("failed to build lesson counts query", err)
 	}
 
-	direction := "ASC"
-	if strings.HasPrefix(sort, "-") {
-		direction = "DESC"
-		sort = strings.TrimPrefix(sort, "-")
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, dbError("failed to count lessons by courses", err)
 	}
+	defer rows.Close()
 
-	dbColumn, ok := allowedFields[sort]
-	if !ok {
-		return builder.OrderBy("l.created_at ASC") // Сортировка по умолчанию, если поле не разрешено
+	for rows.Next() {
+		var courseID string
+		var count int
+		if err := rows.Scan(&courseID, &count); err != nil {
+			return nil, dbError("failed to scan lesson count", err)
+		}
+		counts[courseID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("failed to iterate lesson counts", err)
 	}
 
-	return builder.OrderBy(fmt.Sprintf("%s %s", dbColumn, direction))
+	return counts, nil
+}
+
+// isValidOrderBy проверяет, является ли поле сортировки допустимым.
+func (r *lessonRepository) isValidOrderBy(field string) bool {
+	switch field {
+	case "order_index", "created_at", "title", "updated_at":
+		return true
+	default:
+		return false
+	}
 }
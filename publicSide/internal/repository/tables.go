@@ -8,4 +8,6 @@ const (
 	courseTable = "knowledge_base.course_b"
 	// lessonsTable - имя таблицы с уроками.
 	lessonsTable = "knowledge_base.lesson_d"
+	// bannerTable - имя таблицы с баннером сайта (не более одной строки).
+	bannerTable = "knowledge_base.site_banner"
 )
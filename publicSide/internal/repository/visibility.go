@@ -0,0 +1,26 @@
+// Package repository предоставляет слой для взаимодействия с базой данных.
+package repository
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+)
+
+// courseVisibility возвращает условие видимости курса для таблицы курсов с псевдонимом `alias`
+// (пустая строка — без псевдонима). Мягко удаленные курсы (deleted_at IS NOT NULL) скрыты всегда.
+// По умолчанию выбираются только публичные курсы; в режиме предпросмотра
+// (см. domain.WithPreview) ограничение по видимости снимается и доступны также черновики.
+func courseVisibility(ctx context.Context, alias string) squirrel.Sqlizer {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	notDeleted := squirrel.Eq{prefix + "deleted_at": nil}
+	if domain.IsPreview(ctx) {
+		return notDeleted
+	}
+	return squirrel.And{notDeleted, squirrel.Eq{prefix + "visibility": "public"}}
+}
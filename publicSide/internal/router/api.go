@@ -2,6 +2,7 @@
 package router
 
 import (
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
 	v1 "github.com/TaurineMerge/LMS_Tages/publicSide/internal/handler/api/v1"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
 	"github.com/gofiber/fiber/v2"
@@ -13,30 +14,49 @@ type APIRouter struct {
 	APICategoryHandler *v1.CategoryHandler
 	APICourseHandler   *v1.CourseHandler
 	APILessonHandler   *v1.LessonHandler
+	// APIKeyMiddleware проверяет API-ключ для маршрутов данных; nil - без проверки.
+	APIKeyMiddleware fiber.Handler
+	// Swagger управляет регистрацией документации API.
+	Swagger *config.SwaggerConfig
 }
 
 // Setup настраивает и регистрирует все маршруты API v1.
-// Он также настраивает маршрут для отображения документации Swagger.
+// Он также настраивает маршрут для отображения документации Swagger, если она включена.
 func (r *APIRouter) Setup(app *fiber.App) {
-	// Раздача статического файла swagger.json
-	app.Static("/doc", "./doc/swagger")
-
 	apiV1 := app.Group(routing.RouteAPIV1)
 
-	// Настройка Swagger UI
-	apiV1.Get("/swagger/*", swagger.New(swagger.Config{
-		URL: "/doc/swagger.json",
-	}))
+	if r.Swagger.Enabled {
+		// Раздача статического файла swagger.json
+		app.Static("/doc", "./doc/swagger")
+
+		// Настройка Swagger UI
+		apiV1.Get("/swagger/*", swagger.New(swagger.Config{
+			URL: "/doc/swagger.json",
+		}))
+	} else if r.Swagger.SpecWithAuth && r.APIKeyMiddleware != nil {
+		// Без Swagger UI спецификация отдается только с API-ключом.
+		app.Get("/doc/swagger.json", r.APIKeyMiddleware, func(c *fiber.Ctx) error {
+			return c.SendFile("./doc/swagger/swagger.json")
+		})
+	}
+
+	// Проверка API-ключа регистрируется после Swagger UI, чтобы документация оставалась доступной.
+	if r.APIKeyMiddleware != nil {
+		apiV1.Use(r.APIKeyMiddleware)
+	}
 
 	// Маршруты для категорий
 	apiV1.Get(routing.RouteCategories, r.APICategoryHandler.GetAllCategories)
 	apiV1.Get(routing.RouteCategory, r.APICategoryHandler.GetCategoryByID)
 
 	// Маршруты для курсов
+	apiV1.Post(routing.RouteCoursesBatch, r.APICourseHandler.GetCoursesByIDs)
 	apiV1.Get(routing.RouteCourses, r.APICourseHandler.GetCoursesByCategoryID)
+	apiV1.Get(routing.RouteCourseSlug, r.APICourseHandler.GetCourseBySlug)
 	apiV1.Get(routing.RouteCourse, r.APICourseHandler.GetCourseByID)
 
 	// Маршруты для уроков
 	apiV1.Get(routing.RouteLessons, r.APILessonHandler.GetLessonsByCourseID)
+	apiV1.Get(routing.RouteLessonSlug, r.APILessonHandler.GetLessonBySlug)
 	apiV1.Get(routing.RouteLesson, r.APILessonHandler.GetLessonByID)
 }
@@ -18,6 +18,8 @@ type WebRouter struct {
 	WebLessonHandler    *web.LessonHandler
 	AuthHandler         *web.AuthHandler
 	AuthMiddleware      *web.AuthMiddleware
+	BannerMiddleware    *web.BannerMiddleware
+	CodeHighlightCSS    string // Таблица стилей темы подсветки кода.
 }
 
 // Setup настраивает и регистрирует все маршруты для веб-интерфейса.
@@ -29,9 +31,15 @@ func (r *WebRouter) Setup(app *fiber.App) {
 	}
 
 	app.Static("/static", "./static")
+	app.Get(routing.RouteCodeHighlightCSS, func(c *fiber.Ctx) error {
+		c.Type("css", "utf-8")
+		return c.SendString(r.CodeHighlightCSS)
+	})
 
 	// Middleware для извлечения информации о пользователе из cookie.
 	app.Use(r.AuthMiddleware.WithUser)
+	// Middleware для загрузки баннера сайта, который показывается в основном макете.
+	app.Use(r.BannerMiddleware.WithBanner)
 
 	// Маршруты аутентификации
 	app.Get("/login", r.AuthHandler.Login)
@@ -41,7 +49,9 @@ func (r *WebRouter) Setup(app *fiber.App) {
 	// Основные маршруты веб-приложения
 	app.Get(routing.RouteHome, r.HomeHandler.RenderHome)
 	app.Get(routing.RouteCategories, r.CategoryPageHandler.RenderCategories)
+	app.Get(routing.RouteRecent, r.CoursesHandler.RenderRecent)
 	app.Get(routing.RouteCourses, r.CoursesHandler.RenderCourses)
 	app.Get(routing.RouteCourse, r.CoursesHandler.RenderCoursePage)
+	app.Get(routing.RouteLessonSlug, r.WebLessonHandler.RenderLessonBySlug)
 	app.Get(routing.RouteLesson, r.WebLessonHandler.RenderLesson)
 }
@@ -0,0 +1,55 @@
+// Package service предоставляет бизнес-логику приложения.
+package service
+
+import (
+	"context"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/repository"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/cache"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// bannerCacheKey - единственный ключ в кэше баннера.
+const bannerCacheKey = "banner"
+
+// BannerService определяет интерфейс для получения баннера сайта.
+type BannerService interface {
+	// GetActive возвращает баннер, который нужно показать, или nil, если активного баннера нет.
+	GetActive(ctx context.Context) (*domain.Banner, error)
+}
+
+// bannerService является реализацией BannerService.
+type bannerService struct {
+	repo  repository.BannerRepository
+	cache *cache.Cache[domain.Banner]
+}
+
+// NewBannerService создает новый экземпляр bannerService.
+// Баннер запрашивается на каждой странице, поэтому он кэшируется на короткое время;
+// при nil-кэше каждый вызов обращается к базе данных.
+func NewBannerService(repo repository.BannerRepository, cache *cache.Cache[domain.Banner]) BannerService {
+	return &bannerService{
+		repo:  repo,
+		cache: cache,
+	}
+}
+
+// GetActive получает баннер из кэша или базы данных и возвращает его, только если он активен и не пуст.
+func (s *bannerService) GetActive(ctx context.Context) (*domain.Banner, error) {
+	ctx, span := otel.Tracer("bannerService").Start(ctx, "GetActive")
+	defer span.End()
+
+	banner, err := s.cache.GetOrLoad(ctx, bannerCacheKey, s.repo.Get)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Bool("banner.active", banner.Active))
+	if !banner.Active || banner.Message == "" {
+		return nil, nil
+	}
+	return &banner, nil
+}
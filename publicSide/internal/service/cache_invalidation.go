@@ -0,0 +1,89 @@
+// Package service предоставляет бизнес-логику приложения.
+package service
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/cache"
+)
+
+// Сущности в событиях изменения контента от adminPanel.
+const (
+	ChangeEntityCategory = "category"
+	ChangeEntityCourse   = "course"
+)
+
+// ContentChangeEvent - событие изменения контента, которое adminPanel публикует
+// в канал CONTENT_EVENTS_CHANNEL после изменения категории или курса.
+type ContentChangeEvent struct {
+	Entity     string `json:"entity"`                // category или course.
+	Action     string `json:"action"`                // create, update или delete.
+	ID         string `json:"id"`                    // ID измененной сущности.
+	CategoryID string `json:"category_id,omitempty"` // Категория курса.
+}
+
+// CacheInvalidator сбрасывает записи кэшей публичных данных по событиям изменения.
+//
+// Согласованность: без канала событий изменения из панели администратора видны после
+// истечения CACHE_TTL. С каналом записи сбрасываются почти сразу, но доставка событий
+// не гарантирована (NOTIFY теряется при разрыве соединения), поэтому TTL остается
+// верхней границей устаревания, а после переподключения кэши сбрасываются целиком.
+type CacheInvalidator struct {
+	categories *cache.Cache[CategoryPage]
+	courses    *cache.Cache[response.CourseDTO]
+}
+
+// NewCacheInvalidator создает CacheInvalidator для кэшей списков категорий и отдельных курсов.
+func NewCacheInvalidator(categories *cache.Cache[CategoryPage], courses *cache.Cache[response.CourseDTO]) *CacheInvalidator {
+	return &CacheInvalidator{categories: categories, courses: courses}
+}
+
+// HandlePayload разбирает JSON-событие из уведомления и сбрасывает связанные с ним записи.
+// Нераспознанное событие сбрасывает кэши целиком, чтобы не оставить устаревшие данные.
+func (i *CacheInvalidator) HandlePayload(payload string) {
+	var event ContentChangeEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		slog.Warn("Invalid content change event, clearing caches", "payload", payload, "error", err)
+		i.InvalidateAll()
+		return
+	}
+	i.Invalidate(event)
+}
+
+// Invalidate сбрасывает записи, на которые влияет событие.
+// Списки категорий сбрасываются при любом изменении: изменение курса может сделать категорию
+// пустой или непустой, а страница списка не привязана к одной категории.
+// Курсы сбрасываются по ID курса или, при удалении категории, по ID категории.
+func (i *CacheInvalidator) Invalidate(event ContentChangeEvent) {
+	removed := 0
+	switch event.Entity {
+	case ChangeEntityCourse:
+		removed = i.courses.DeleteFunc(func(_ string, course response.CourseDTO) bool {
+			return course.ID == event.ID
+		})
+	case ChangeEntityCategory:
+		removed = i.courses.DeleteFunc(func(_ string, course response.CourseDTO) bool {
+			return course.CategoryID == event.ID
+		})
+	default:
+		slog.Warn("Unknown content change event entity, clearing caches", "entity", event.Entity, "id", event.ID)
+		i.InvalidateAll()
+		return
+	}
+	i.categories.Clear()
+
+	slog.Debug("Cache invalidated by content change event",
+		"entity", event.Entity,
+		"action", event.Action,
+		"id", event.ID,
+		"courses_removed", removed,
+	)
+}
+
+// InvalidateAll сбрасывает все кэши.
+func (i *CacheInvalidator) InvalidateAll() {
+	i.categories.Clear()
+	i.courses.Clear()
+}
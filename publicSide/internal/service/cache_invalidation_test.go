@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/cache"
+)
+
+func TestCacheInvalidatorHandlePayload(t *testing.T) {
+	courseA := response.CourseDTO{ID: "course-a", CategoryID: "category-1"}
+	courseB := response.CourseDTO{ID: "course-b", CategoryID: "category-1"}
+	courseC := response.CourseDTO{ID: "course-c", CategoryID: "category-2"}
+
+	tests := []struct {
+		name        string
+		payload     string
+		wantCourses []string
+	}{
+		{
+			name:        "course event evicts the course by id and slug",
+			payload:     `{"entity":"course","action":"update","id":"course-a","category_id":"category-1"}`,
+			wantCourses: []string{"id:course-b", "id:course-c"},
+		},
+		{
+			name:        "category event evicts its courses",
+			payload:     `{"entity":"category","action":"delete","id":"category-1"}`,
+			wantCourses: []string{"id:course-c"},
+		},
+		{
+			name:        "unknown entity clears everything",
+			payload:     `{"entity":"lesson","action":"update","id":"lesson-1"}`,
+			wantCourses: nil,
+		},
+		{
+			name:        "invalid payload clears everything",
+			payload:     `not json`,
+			wantCourses: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories := cache.New[CategoryPage]("categories", 10, time.Minute)
+			courses := cache.New[response.CourseDTO]("courses", 10, time.Minute)
+			categories.Set("page:1", CategoryPage{})
+			courses.Set("id:course-a", courseA)
+			courses.Set("slug:category-1/course-a", courseA)
+			courses.Set("id:course-b", courseB)
+			courses.Set("id:course-c", courseC)
+
+			NewCacheInvalidator(categories, courses).HandlePayload(tt.payload)
+
+			if _, ok := categories.Get("page:1"); ok {
+				t.Error("category page is still cached, want it evicted")
+			}
+			remaining := map[string]bool{}
+			for _, key := range []string{"id:course-a", "slug:category-1/course-a", "id:course-b", "id:course-c"} {
+				if _, ok := courses.Get(key); ok {
+					remaining[key] = true
+				}
+			}
+			if len(remaining) != len(tt.wantCourses) {
+				t.Errorf("cached courses = %v, want %v", remaining, tt.wantCourses)
+			}
+			for _, key := range tt.wantCourses {
+				if !remaining[key] {
+					t.Errorf("course %s was evicted, want it kept", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package service предоставляет бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/cache"
+)
+
+// CategoryPage - страница списка категорий, хранящаяся в кэше.
+type CategoryPage struct {
+	Items      []response.CategoryDTO
+	Pagination response.Pagination
+}
+
+// cachedCategoryService кэширует списки категорий поверх другой реализации CategoryService.
+// Остальные методы вызываются напрямую.
+type cachedCategoryService struct {
+	CategoryService
+	lists *cache.Cache[CategoryPage]
+}
+
+// NewCachedCategoryService оборачивает CategoryService кэшем списков категорий.
+// Запросы в режиме предпросмотра не используют кэш. Если кэш отключен (nil), возвращает next без изменений.
+func NewCachedCategoryService(next CategoryService, lists *cache.Cache[CategoryPage]) CategoryService {
+	if lists == nil {
+		return next
+	}
+	return &cachedCategoryService{CategoryService: next, lists: lists}
+}
+
+// GetAll возвращает страницу всех категорий из кэша или загружает ее.
+func (s *cachedCategoryService) GetAll(ctx context.Context, page, limit int) ([]response.CategoryDTO, response.Pagination, error) {
+	return s.load(ctx, fmt.Sprintf("all:%d:%d", page, limit), func(ctx context.Context) ([]response.CategoryDTO, response.Pagination, error) {
+		return s.CategoryService.GetAll(ctx, page, limit)
+	})
+}
+
+// GetAllNotEmpty возвращает страницу непустых категорий из кэша или загружает ее.
+func (s *cachedCategoryService) GetAllNotEmpty(ctx context.Context, page, limit int) ([]response.CategoryDTO, response.Pagination, error) {
+	return s.load(ctx, fmt.Sprintf("not_empty:%d:%d", page, limit), func(ctx context.Context) ([]response.CategoryDTO, response.Pagination, error) {
+		return s.CategoryService.GetAllNotEmpty(ctx, page, limit)
+	})
+}
+
+// List возвращает страницу категорий в режиме по умолчанию или переопределенном showEmpty из кэша или загружает ее.
+func (s *cachedCategoryService) List(ctx context.Context, page, limit int, showEmpty *bool) ([]response.CategoryDTO, response.Pagination, error) {
+	mode := "default"
+	if showEmpty != nil {
+		mode = strconv.FormatBool(*showEmpty)
+	}
+	return s.load(ctx, fmt.Sprintf("list:%s:%d:%d", mode, page, limit), func(ctx context.Context) ([]response.CategoryDTO, response.Pagination, error) {
+		return s.CategoryService.List(ctx, page, limit, showEmpty)
+	})
+}
+
+// load получает страницу категорий через кэш. Возвращается копия среза, чтобы вызывающий код
+// не мог изменить закэшированное значение.
+func (s *cachedCategoryService) load(
+	ctx context.Context,
+	key string,
+	fetch func(ctx context.Context) ([]response.CategoryDTO, response.Pagination, error),
+) ([]response.CategoryDTO, response.Pagination, error) {
+	if domain.IsPreview(ctx) {
+		return fetch(ctx)
+	}
+
+	result, err := s.lists.GetOrLoad(ctx, key, func(ctx context.Context) (CategoryPage, error) {
+		items, pagination, err := fetch(ctx)
+		return CategoryPage{Items: items, Pagination: pagination}, err
+	})
+	if err != nil {
+		return nil, response.Pagination{}, err
+	}
+	return slices.Clone(result.Items), result.Pagination, nil
+}
+
+// cachedCourseService кэширует получение одного курса поверх другой реализации CourseService.
+// Остальные методы вызываются напрямую.
+type cachedCourseService struct {
+	CourseService
+	courses *cache.Cache[response.CourseDTO]
+}
+
+// NewCachedCourseService оборачивает CourseService кэшем отдельных курсов (по ID и по slug).
+// Запросы в режиме предпросмотра не используют кэш. Если кэш отключен (nil), возвращает next без изменений.
+func NewCachedCourseService(next CourseService, courses *cache.Cache[response.CourseDTO]) CourseService {
+	if courses == nil {
+		return next
+	}
+	return &cachedCourseService{CourseService: next, courses: courses}
+}
+
+// GetCourseByID возвращает курс из кэша или загружает его.
+func (s *cachedCourseService) GetCourseByID(ctx context.Context, categoryID, courseID string) (response.CourseDTO, error) {
+	if domain.IsPreview(ctx) {
+		return s.CourseService.GetCourseByID(ctx, categoryID, courseID)
+	}
+	return s.courses.GetOrLoad(ctx, "id:"+categoryID+":"+courseID, func(ctx context.Context) (response.CourseDTO, error) {
+		return s.CourseService.GetCourseByID(ctx, categoryID, courseID)
+	})
+}
+
+// GetCourseBySlug возвращает курс из кэша или загружает его.
+func (s *cachedCourseService) GetCourseBySlug(ctx context.Context, categoryID, slug string) (response.CourseDTO, error) {
+	if domain.IsPreview(ctx) {
+		return s.CourseService.GetCourseBySlug(ctx, categoryID, slug)
+	}
+	key := "slug:" + categoryID + ":" + strings.ToLower(strings.TrimSpace(slug))
+	return s.courses.GetOrLoad(ctx, key, func(ctx context.Context) (response.CourseDTO, error) {
+		return s.CourseService.GetCourseBySlug(ctx, categoryID, slug)
+	})
+}
@@ -4,15 +4,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"math"
-	"strings"
 
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/config"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/repository"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // CategoryService определяет интерфейс для бизнес-логики, связанной с категориями.
@@ -21,6 +23,9 @@ type CategoryService interface {
 	GetAll(ctx context.Context, page, limit int) ([]response.CategoryDTO, response.Pagination, error)
 	// GetAllNotEmpty получает все категории, в которых есть хотя бы один публичный курс.
 	GetAllNotEmpty(ctx context.Context, page, limit int) ([]response.CategoryDTO, response.Pagination, error)
+	// List получает категории в режиме, заданном конфигурацией SHOW_EMPTY_CATEGORIES.
+	// Непустой showEmpty переопределяет режим для конкретного запроса.
+	List(ctx context.Context, page, limit int, showEmpty *bool) ([]response.CategoryDTO, response.Pagination, error)
 	// GetByID получает категорию по ее ID.
 	GetByID(ctx context.Context, categoryID string) (response.CategoryDTO, error)
 }
@@ -28,12 +33,14 @@ type CategoryService interface {
 // categoryService является реализацией CategoryService.
 type categoryService struct {
 	repo repository.CategoryRepository
+	cfg  config.CategoriesConfig
 }
 
 // NewCategoryService создает новый экземпляр categoryService.
-func NewCategoryService(repo repository.CategoryRepository) CategoryService {
+func NewCategoryService(repo repository.CategoryRepository, cfg config.CategoriesConfig) CategoryService {
 	return &categoryService{
 		repo: repo,
+		cfg:  cfg,
 	}
 }
 
@@ -119,6 +126,22 @@ func (s *categoryService) GetAllNotEmpty(ctx context.Context, page, limit int) (
 	return categoryDTOs, pagination, nil
 }
 
+// List выбирает между GetAll и GetAllNotEmpty: по умолчанию согласно настройке,
+// либо согласно переданному переопределению. Количество и пагинация считаются
+// тем же запросом, что и сами категории, поэтому всегда соответствуют выбранному режиму.
+func (s *categoryService) List(ctx context.Context, page, limit int, showEmpty *bool) ([]response.CategoryDTO, response.Pagination, error) {
+	show := s.cfg.ShowEmpty
+	if showEmpty != nil {
+		show = *showEmpty
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("categories.show_empty", show))
+
+	if show {
+		return s.GetAll(ctx, page, limit)
+	}
+	return s.GetAllNotEmpty(ctx, page, limit)
+}
+
 // GetByID находит категорию по ID. Если категория не найдена,
 // возвращает стандартизированную ошибку `apperrors.NewNotFound`.
 func (s *categoryService) GetByID(ctx context.Context, categoryID string) (response.CategoryDTO, error) {
@@ -128,7 +151,7 @@ func (s *categoryService) GetByID(ctx context.Context, categoryID string) (respo
 
 	category, err := s.repo.GetByID(ctx, categoryID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, apperrors.ErrNotFound) {
 			return response.CategoryDTO{}, apperrors.NewNotFound("Category")
 		}
 		return response.CategoryDTO{}, err
@@ -3,6 +3,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"unicode/utf8"
@@ -18,11 +20,27 @@ import (
 // CourseService определяет интерфейс для бизнес-логики, связанной с курсами.
 type CourseService interface {
 	// GetCoursesByCategoryID получает курсы для данной категории с пагинацией, фильтрацией и сортировкой.
+	// `level` может содержать несколько уровней через запятую (например, "easy,medium").
 	GetCoursesByCategoryID(ctx context.Context, categoryID string, page, limit int, level, sortBy string) ([]response.CourseDTO, response.Pagination, error)
 	// GetCourseByID получает один курс по его ID и ID категории.
 	GetCourseByID(ctx context.Context, categoryID, courseID string) (response.CourseDTO, error)
+	// GetCourseBySlug получает один курс по его slug и ID категории.
+	GetCourseBySlug(ctx context.Context, categoryID, slug string) (response.CourseDTO, error)
+	// GetCoursesByIDs получает видимые курсы из любых категорий по списку ID.
+	GetCoursesByIDs(ctx context.Context, ids []string) ([]response.CourseDTO, error)
+	// GetRecentlyUpdated получает последние обновленные публичные курсы из всех категорий.
+	GetRecentlyUpdated(ctx context.Context, limit int) ([]response.CourseDTO, error)
 }
 
+// MaxCoursesBatchSize - максимальное количество ID курсов в одном пакетном запросе.
+const MaxCoursesBatchSize = 100
+
+// Количество курсов в ленте последних обновлений: по умолчанию и максимальное.
+const (
+	DefaultRecentCoursesLimit = 10
+	MaxRecentCoursesLimit     = 50
+)
+
 // courseService является реализацией CourseService.
 type courseService struct {
 	repo         repository.CourseRepository
@@ -53,10 +71,15 @@ func (s *courseService) GetCoursesByCategoryID(ctx context.Context, categoryID s
 		attribute.String("sort_by", sortBy),
 	)
 
+	levels, err := domain.ParseLevels(level)
+	if err != nil {
+		return nil, response.Pagination{}, apperrors.NewInvalidRequest("Invalid level filter: " + err.Error())
+	}
+
 	// Проверяем, существует ли категория, прежде чем запрашивать курсы.
-	_, err := s.categoryRepo.GetByID(ctx, categoryID)
+	_, err = s.categoryRepo.GetByID(ctx, categoryID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, apperrors.ErrNotFound) {
 			return nil, response.Pagination{}, apperrors.NewNotFound("Category")
 		}
 		return nil, response.Pagination{}, err
@@ -69,7 +92,7 @@ func (s *courseService) GetCoursesByCategoryID(ctx context.Context, categoryID s
 		limit = 20
 	}
 
-	courses, total, err := s.repo.GetCoursesByCategoryID(ctx, categoryID, page, limit, level, sortBy)
+	courses, total, err := s.repo.GetCoursesByCategoryID(ctx, categoryID, page, limit, levels, sortBy)
 	if err != nil {
 		return nil, response.Pagination{}, err
 	}
@@ -100,14 +123,18 @@ func (s *courseService) mapCourseToDTO(course domain.Course) response.CourseDTO
 	}
 
 	return response.CourseDTO{
-		ID:          course.ID,
-		Title:       course.Title,
-		Description: course.Description,
-		Level:       course.Level,
-		CategoryID:  course.CategoryID,
-		ImageURL:    imageURL,
-		CreatedAt:   course.CreatedAt,
-		UpdatedAt:   course.UpdatedAt,
+		ID:            course.ID,
+		Title:         course.Title,
+		Slug:          course.Slug,
+		Description:   course.Description,
+		Level:         course.Level,
+		CategoryID:    course.CategoryID,
+		CategoryTitle: course.CategoryTitle,
+		CategorySlug:  course.CategorySlug,
+		ImageURL:      imageURL,
+		ContentPolicy: course.ContentPolicy,
+		CreatedAt:     course.CreatedAt,
+		UpdatedAt:     course.UpdatedAt,
 	}
 }
 
@@ -156,7 +183,7 @@ func (s *courseService) GetCourseByID(ctx context.Context, categoryID, courseID
 
 	_, err := s.categoryRepo.GetByID(ctx, categoryID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, apperrors.ErrNotFound) {
 			return response.CourseDTO{}, apperrors.NewNotFound("Category")
 		}
 		return response.CourseDTO{}, err
@@ -164,7 +191,7 @@ func (s *courseService) GetCourseByID(ctx context.Context, categoryID, courseID
 
 	course, err := s.repo.GetCourseByID(ctx, categoryID, courseID)
 	if err != nil {
-		if strings.Contains(err.Error(), "no rows") {
+		if errors.Is(err, apperrors.ErrNotFound) {
 			return response.CourseDTO{}, apperrors.NewNotFound("Course")
 		}
 		return response.CourseDTO{}, err
@@ -172,3 +199,115 @@ func (s *courseService) GetCourseByID(ctx context.Context, categoryID, courseID
 
 	return s.mapCourseToDTO(course), nil
 }
+
+// GetCourseBySlug находит курс по slug. Как и GetCourseByID, сначала проверяет
+// существование категории, затем запрашивает курс и обрабатывает случай "не найдено".
+func (s *courseService) GetCourseBySlug(ctx context.Context, categoryID, slug string) (response.CourseDTO, error) {
+	tracer := otel.Tracer("service")
+	ctx, span := tracer.Start(ctx, "courseService.GetCourseBySlug")
+	defer span.End()
+
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	span.SetAttributes(
+		attribute.String("category_id", categoryID),
+		attribute.String("slug", slug),
+	)
+
+	if slug == "" {
+		return response.CourseDTO{}, apperrors.NewInvalidRequest("Course slug must not be empty")
+	}
+
+	_, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return response.CourseDTO{}, apperrors.NewNotFound("Category")
+		}
+		return response.CourseDTO{}, err
+	}
+
+	course, err := s.repo.GetCourseBySlug(ctx, categoryID, slug)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return response.CourseDTO{}, apperrors.NewNotFound("Course")
+		}
+		return response.CourseDTO{}, err
+	}
+
+	return s.mapCourseToDTO(course), nil
+}
+
+// GetCoursesByIDs находит курсы по списку ID. Повторяющиеся ID учитываются один раз,
+// неизвестные и непубличные курсы пропускаются. Курсы возвращаются в порядке ID в запросе.
+// Возвращает ошибку, если список пуст или длиннее MaxCoursesBatchSize.
+func (s *courseService) GetCoursesByIDs(ctx context.Context, ids []string) ([]response.CourseDTO, error) {
+	tracer := otel.Tracer("service")
+	ctx, span := tracer.Start(ctx, "courseService.GetCoursesByIDs")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("ids_count", len(ids)))
+
+	if len(ids) == 0 {
+		return nil, apperrors.NewInvalidRequest("Field 'ids' must contain at least one course ID")
+	}
+	if len(ids) > MaxCoursesBatchSize {
+		return nil, apperrors.NewInvalidRequest(fmt.Sprintf("Field 'ids' must contain at most %d course IDs", MaxCoursesBatchSize))
+	}
+
+	uniqueIDs := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(id)
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	courses, err := s.repo.GetCoursesByIDs(ctx, uniqueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]domain.Course, len(courses))
+	for _, course := range courses {
+		byID[strings.ToLower(course.ID)] = course
+	}
+
+	courseDTOs := make([]response.CourseDTO, 0, len(courses))
+	for _, id := range uniqueIDs {
+		if course, ok := byID[id]; ok {
+			courseDTOs = append(courseDTOs, s.mapCourseToDTO(course))
+		}
+	}
+
+	return courseDTOs, nil
+}
+
+// GetRecentlyUpdated возвращает limit последних обновленных публичных курсов из всех категорий
+// (сначала новые) с названием и slug категории. Неположительный limit заменяется на
+// DefaultRecentCoursesLimit, а превышающий MaxRecentCoursesLimit ограничивается им.
+func (s *courseService) GetRecentlyUpdated(ctx context.Context, limit int) ([]response.CourseDTO, error) {
+	tracer := otel.Tracer("service")
+	ctx, span := tracer.Start(ctx, "courseService.GetRecentlyUpdated")
+	defer span.End()
+
+	if limit < 1 {
+		limit = DefaultRecentCoursesLimit
+	}
+	if limit > MaxRecentCoursesLimit {
+		limit = MaxRecentCoursesLimit
+	}
+	span.SetAttributes(attribute.Int("limit", limit))
+
+	courses, err := s.repo.GetRecentlyUpdated(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	courseDTOs := make([]response.CourseDTO, 0, len(courses))
+	for _, course := range courses {
+		courseDTOs = append(courseDTOs, s.mapCourseToDTO(course))
+	}
+
+	return courseDTOs, nil
+}
@@ -3,25 +3,43 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
-	"strings"
+	"time"
 
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/repository"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/lessoncontent"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
+const (
+	// DefaultPrefetchWindow — размер окна предзагрузки соседних уроков по умолчанию.
+	DefaultPrefetchWindow = 2
+	// MaxPrefetchWindow — максимально допустимый размер окна предзагрузки.
+	MaxPrefetchWindow = 10
+)
+
 // LessonService определяет интерфейс для бизнес-логики, связанной с уроками.
 type LessonService interface {
 	// GetAllByCourseID получает все уроки для данного курса с пагинацией и сортировкой.
 	GetAllByCourseID(ctx context.Context, categoryID, courseID string, page, limit int, sort string) ([]response.LessonDTO, response.Pagination, error)
+	// GetPageByCursor получает страницу уроков курса по курсору и курсор следующей страницы.
+	GetPageByCursor(ctx context.Context, categoryID, courseID, cursor string, limit int) ([]response.LessonDTO, string, error)
 	// GetByID получает один урок по его ID.
 	GetByID(ctx context.Context, categoryID, courseID, lessonID string) (response.LessonDTODetailed, error)
+	// GetPublishedLessonBySlug получает опубликованный урок по ID категории, slug курса и slug урока.
+	GetPublishedLessonBySlug(ctx context.Context, categoryID, courseSlug, lessonSlug string) (response.LessonDTODetailed, error)
+	// GetByIDWithPrefetch получает урок вместе с ID соседних уроков в окне заданного размера.
+	GetByIDWithPrefetch(ctx context.Context, categoryID, courseID, lessonID string, window int) (response.LessonDTODetailed, error)
 	// GetNeighboringLessons находит предыдущий и следующий уроки относительно текущего.
 	GetNeighboringLessons(ctx context.Context, categoryID, courseID, lessonID string) (prevLesson, nextLesson response.LessonDTO, err error)
+	// GetLessonCounts возвращает количество уроков для каждого курса из списка.
+	GetLessonCounts(ctx context.Context, courseIDs []string) (map[string]int, error)
 }
 
 // lessonService является реализацией LessonService.
@@ -35,25 +53,37 @@ func NewLessonService(repo repository.LessonRepository) LessonService {
 }
 
 // toLessonDTO преобразует доменную модель Lesson в краткую DTO LessonDTO.
+// Объем текста и время чтения вычисляются по контенту (см. lessoncontent.Measure).
 func toLessonDTO(lesson domain.Lesson) response.LessonDTO {
+	stats := lessoncontent.Measure(lesson.Content)
 	return response.LessonDTO{
-		ID:        lesson.ID,
-		Title:     lesson.Title,
-		CourseID:  lesson.CourseID,
-		CreatedAt: lesson.CreatedAt,
-		UpdatedAt: lesson.UpdatedAt,
+		ID:                 lesson.ID,
+		Title:              lesson.Title,
+		Slug:               lesson.Slug,
+		CourseID:           lesson.CourseID,
+		OrderIndex:         lesson.OrderIndex,
+		ContentLength:      stats.Length,
+		ReadingTimeMinutes: stats.ReadingTimeMinutes,
+		CreatedAt:          lesson.CreatedAt,
+		UpdatedAt:          lesson.UpdatedAt,
 	}
 }
 
 // toLessonDTODetailed преобразует доменную модель Lesson в детальную DTO LessonDTODetailed.
+// Объем текста и время чтения вычисляются по контенту (см. lessoncontent.Measure).
 func toLessonDTODetailed(lesson domain.Lesson) response.LessonDTODetailed {
+	stats := lessoncontent.Measure(lesson.Content)
 	return response.LessonDTODetailed{
-		ID:        lesson.ID,
-		Title:     lesson.Title,
-		CourseID:  lesson.CourseID,
-		Content:   lesson.Content,
-		CreatedAt: lesson.CreatedAt,
-		UpdatedAt: lesson.UpdatedAt,
+		ID:                 lesson.ID,
+		Title:              lesson.Title,
+		Slug:               lesson.Slug,
+		CourseID:           lesson.CourseID,
+		Content:            lesson.Content,
+		OrderIndex:         lesson.OrderIndex,
+		ContentLength:      stats.Length,
+		ReadingTimeMinutes: stats.ReadingTimeMinutes,
+		CreatedAt:          lesson.CreatedAt,
+		UpdatedAt:          lesson.UpdatedAt,
 	}
 }
 
@@ -94,6 +124,52 @@ func (s *lessonService) GetAllByCourseID(ctx context.Context, categoryID, course
 	return lessonDTOs, pagination, nil
 }
 
+// GetPageByCursor возвращает до limit уроков курса в порядке (created_at, id), следующих за курсором,
+// и непрозрачный курсор следующей страницы (пустой, если страница последняя).
+// Пустой cursor означает первую страницу. Поврежденный курсор отклоняется как неверный запрос.
+func (s *lessonService) GetPageByCursor(ctx context.Context, categoryID, courseID, cursor string, limit int) ([]response.LessonDTO, string, error) {
+	ctx, span := otel.Tracer("lessonService").Start(ctx, "GetPageByCursor")
+	span.SetAttributes(attribute.String("course.id", courseID), attribute.String("category.id", categoryID), attribute.Bool("cursor.first_page", cursor == ""))
+	defer span.End()
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var afterCreatedAt *time.Time
+	var afterID string
+	if cursor != "" {
+		createdAt, id, err := decodeLessonCursor(cursor)
+		if err != nil {
+			return nil, "", apperrors.NewInvalidRequest("Invalid cursor")
+		}
+		afterCreatedAt, afterID = &createdAt, id
+	}
+
+	// Запрашиваем на один урок больше, чтобы узнать, есть ли следующая страница.
+	lessons, err := s.repo.GetLessonsPageCursor(ctx, categoryID, courseID, afterCreatedAt, afterID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(lessons) > limit {
+		lessons = lessons[:limit]
+		last := lessons[len(lessons)-1]
+		nextCursor = encodeLessonCursor(last.CreatedAt, last.ID)
+	}
+
+	lessonDTOs := make([]response.LessonDTO, len(lessons))
+	for i, lesson := range lessons {
+		lessonDTOs[i] = toLessonDTO(lesson)
+	}
+
+	return lessonDTOs, nextCursor, nil
+}
+
 // GetByID находит урок по ID. Если урок не найден,
 // возвращает стандартизированную ошибку `apperrors.NewNotFound`.
 func (s *lessonService) GetByID(ctx context.Context, categoryID, courseID, lessonID string) (response.LessonDTODetailed, error) {
@@ -103,7 +179,25 @@ func (s *lessonService) GetByID(ctx context.Context, categoryID, courseID, lesso
 
 	lesson, err := s.repo.GetByID(ctx, categoryID, courseID, lessonID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return response.LessonDTODetailed{}, apperrors.NewNotFound("Lesson")
+		}
+		return response.LessonDTODetailed{}, err
+	}
+	return toLessonDTODetailed(lesson), nil
+}
+
+// GetPublishedLessonBySlug находит урок по slug курса и slug урока в заданной категории.
+// Если категория, курс или урок не найдены (или курс не опубликован),
+// возвращает стандартизированную ошибку `apperrors.NewNotFound`.
+func (s *lessonService) GetPublishedLessonBySlug(ctx context.Context, categoryID, courseSlug, lessonSlug string) (response.LessonDTODetailed, error) {
+	ctx, span := otel.Tracer("lessonService").Start(ctx, "GetPublishedLessonBySlug")
+	span.SetAttributes(attribute.String("lesson.slug", lessonSlug), attribute.String("course.slug", courseSlug), attribute.String("category.id", categoryID))
+	defer span.End()
+
+	lesson, err := s.repo.GetPublishedLessonBySlug(ctx, categoryID, courseSlug, lessonSlug)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
 			return response.LessonDTODetailed{}, apperrors.NewNotFound("Lesson")
 		}
 		return response.LessonDTODetailed{}, err
@@ -111,8 +205,50 @@ func (s *lessonService) GetByID(ctx context.Context, categoryID, courseID, lesso
 	return toLessonDTODetailed(lesson), nil
 }
 
+// GetByIDWithPrefetch находит урок по ID и добавляет к нему подсказки для предзагрузки:
+// ID `window` предыдущих и `window` следующих уроков курса. При `window` равном нулю
+// подсказки не добавляются.
+func (s *lessonService) GetByIDWithPrefetch(ctx context.Context, categoryID, courseID, lessonID string, window int) (response.LessonDTODetailed, error) {
+	ctx, span := otel.Tracer("lessonService").Start(ctx, "GetByIDWithPrefetch")
+	span.SetAttributes(attribute.String("lesson.id", lessonID), attribute.String("course.id", courseID), attribute.Int("window", window))
+	defer span.End()
+
+	if window < 0 || window > MaxPrefetchWindow {
+		return response.LessonDTODetailed{}, apperrors.NewInvalidRequest(fmt.Sprintf("window must be between 0 and %d", MaxPrefetchWindow))
+	}
+
+	lesson, err := s.repo.GetByID(ctx, categoryID, courseID, lessonID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return response.LessonDTODetailed{}, apperrors.NewNotFound("Lesson")
+		}
+		return response.LessonDTODetailed{}, err
+	}
+
+	dto := toLessonDTODetailed(lesson)
+	if window == 0 {
+		return dto, nil
+	}
+
+	prevIDs, nextIDs, err := s.repo.GetLessonWindow(ctx, courseID, repository.LessonChunkOptions{
+		PivotValue: lesson.OrderIndex,
+		OrderBy:    "order_index",
+		Limit:      window,
+	})
+	if err != nil {
+		return response.LessonDTODetailed{}, err
+	}
+
+	dto.Prefetch = &response.LessonPrefetch{
+		Window:   window,
+		Previous: prevIDs,
+		Next:     nextIDs,
+	}
+	return dto, nil
+}
+
 // GetNeighboringLessons находит предыдущий и следующий уроки для навигации.
-// Сначала получает текущий урок, чтобы использовать его `created_at` как опорную точку,
+// Сначала получает текущий урок, чтобы использовать его `order_index` как опорную точку,
 // затем делает два запроса к репозиторию для получения соседних уроков.
 func (s *lessonService) GetNeighboringLessons(ctx context.Context, categoryID, courseID, lessonID string) (response.LessonDTO, response.LessonDTO, error) {
 	ctx, span := otel.Tracer("lessonService").Start(ctx, "GetNeighboringLessons")
@@ -124,11 +260,11 @@ func (s *lessonService) GetNeighboringLessons(ctx context.Context, categoryID, c
 		return response.LessonDTO{}, response.LessonDTO{}, err
 	}
 
-	orderBy := "created_at"
+	orderBy := "order_index"
 
 	// Ищем один урок до текущего
 	prevLessons, err := s.repo.GetLessonsChunk(ctx, courseID, repository.LessonChunkOptions{
-		PivotValue: currentLesson.CreatedAt,
+		PivotValue: currentLesson.OrderIndex,
 		OrderBy:    orderBy,
 		Direction:  repository.DirectionPrevious,
 		Limit:      1,
@@ -139,7 +275,7 @@ func (s *lessonService) GetNeighboringLessons(ctx context.Context, categoryID, c
 
 	// Ищем один урок после текущего
 	nextLessons, err := s.repo.GetLessonsChunk(ctx, courseID, repository.LessonChunkOptions{
-		PivotValue: currentLesson.CreatedAt,
+		PivotValue: currentLesson.OrderIndex,
 		OrderBy:    orderBy,
 		Direction:  repository.DirectionNext,
 		Limit:      1,
@@ -158,3 +294,13 @@ func (s *lessonService) GetNeighboringLessons(ctx context.Context, categoryID, c
 
 	return prevLessonDTO, nextLessonDTO, nil
 }
+
+// GetLessonCounts получает количество уроков для набора курсов одним запросом к репозиторию.
+// Для курсов без уроков в карте нет записи, поэтому чтение по их ID дает 0.
+func (s *lessonService) GetLessonCounts(ctx context.Context, courseIDs []string) (map[string]int, error) {
+	ctx, span := otel.Tracer("lessonService").Start(ctx, "GetLessonCounts")
+	span.SetAttributes(attribute.Int("courses.count", len(courseIDs)))
+	defer span.End()
+
+	return s.repo.CountLessonsByCourseIDs(ctx, courseIDs)
+}
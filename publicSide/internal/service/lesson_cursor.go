@@ -0,0 +1,48 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/utils"
+)
+
+// errInvalidLessonCursor возвращается decodeLessonCursor для поврежденного или чужого курсора.
+var errInvalidLessonCursor = errors.New("invalid lesson cursor")
+
+// lessonCursorSeparator разделяет время создания и ID опорного урока в курсоре.
+const lessonCursorSeparator = "|"
+
+// encodeLessonCursor формирует непрозрачный курсор страницы уроков.
+// Формат: base64url без выравнивания от строки "<created_at в RFC 3339 с наносекундами в UTC>|<id урока>",
+// например base64url("2024-05-01T10:00:00.123456Z|6f1c...").
+// Клиенты должны передавать курсор без изменений: формат не является частью контракта API.
+func encodeLessonCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + lessonCursorSeparator + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeLessonCursor разбирает курсор, созданный encodeLessonCursor,
+// и возвращает время создания и ID последнего урока предыдущей страницы.
+func decodeLessonCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errInvalidLessonCursor
+	}
+
+	createdAtRaw, id, ok := strings.Cut(string(raw), lessonCursorSeparator)
+	if !ok {
+		return time.Time{}, "", errInvalidLessonCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtRaw)
+	if err != nil {
+		return time.Time{}, "", errInvalidLessonCursor
+	}
+	if err := utils.ValidateUUID(id); err != nil {
+		return time.Time{}, "", errInvalidLessonCursor
+	}
+
+	return createdAt, id, nil
+}
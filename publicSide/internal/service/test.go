@@ -17,6 +17,8 @@ import (
 type TestService interface {
 	// GetTest получает информацию о тесте для указанного курса.
 	GetTest(ctx context.Context, categoryID, courseID string) (*domain.Test, error)
+	// GetTestStats получает статистику прохождения теста для указанного курса.
+	GetTestStats(ctx context.Context, categoryID, courseID string) (*domain.TestStats, error)
 }
 
 // testService является реализацией TestService.
@@ -46,17 +48,7 @@ func (s *testService) GetTest(ctx context.Context, categoryID, courseID string)
 
 	testDTO, err := s.testingClient.GetTest(ctx, categoryID, courseID)
 	if err != nil {
-		if errors.Is(err, testing.ErrTestNotFound) {
-			return nil, apperrors.NewNotFound("Test")
-		}
-		if errors.Is(err, testing.ErrServiceUnavailable) ||
-			errors.Is(err, testing.ErrInvalidResponse) {
-			slog.Error("Testing service is unavailable", "error", err)
-			return nil, apperrors.NewServiceUnavailable("Testing")
-		}
-
-		slog.Error("Failed to get test from client", "error", err)
-		return nil, err
+		return nil, mapTestingClientError(err, "Failed to get test from client")
 	}
 
 	domainTest := mapTestDataToDomain(testDTO)
@@ -64,6 +56,47 @@ func (s *testService) GetTest(ctx context.Context, categoryID, courseID string)
 	return domainTest, nil
 }
 
+// GetTestStats обращается к клиенту сервиса тестирования для получения статистики теста.
+// Ошибки клиента преобразуются так же, как в GetTest: 404, если для курса нет теста,
+// и ServiceUnavailable, если сервис тестирования недоступен.
+func (s *testService) GetTestStats(ctx context.Context, categoryID, courseID string) (*domain.TestStats, error) {
+	tracer := otel.Tracer("service")
+	ctx, span := tracer.Start(ctx, "testService.GetTestStats")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("category_id", categoryID),
+		attribute.String("course_id", courseID),
+	)
+
+	statsDTO, err := s.testingClient.GetTestStats(ctx, categoryID, courseID)
+	if err != nil {
+		return nil, mapTestingClientError(err, "Failed to get test stats from client")
+	}
+
+	return mapTestStatsDataToDomain(statsDTO), nil
+}
+
+// mapTestingClientError преобразует ошибку клиента сервиса тестирования в ошибку приложения.
+// Неизвестные ошибки логируются с сообщением msg и возвращаются без изменений.
+func mapTestingClientError(err error, msg string) error {
+	if errors.Is(err, testing.ErrTestNotFound) {
+		return apperrors.NewNotFound("Test")
+	}
+	if errors.Is(err, testing.ErrTimeout) {
+		slog.Error("Testing service request timed out", "error", err)
+		return apperrors.NewServiceUnavailable("Testing")
+	}
+	if errors.Is(err, testing.ErrServiceUnavailable) ||
+		errors.Is(err, testing.ErrInvalidResponse) {
+		slog.Error("Testing service is unavailable", "error", err)
+		return apperrors.NewServiceUnavailable("Testing")
+	}
+
+	slog.Error(msg, "error", err)
+	return err
+}
+
 // mapTestDataToDomain преобразует DTO от клиента в доменную модель Test.
 func mapTestDataToDomain(dto *testing.TestData) *domain.Test {
 	if dto == nil {
@@ -77,3 +110,16 @@ func mapTestDataToDomain(dto *testing.TestData) *domain.Test {
 		Description: dto.Description,
 	}
 }
+
+// mapTestStatsDataToDomain преобразует DTO статистики от клиента в доменную модель TestStats.
+func mapTestStatsDataToDomain(dto *testing.TestStatsData) *domain.TestStats {
+	if dto == nil {
+		return nil
+	}
+	return &domain.TestStats{
+		TestID:         dto.TestID,
+		QuestionCount:  dto.QuestionCount,
+		AttemptsTotal:  dto.AttemptsTotal,
+		AttemptsPassed: dto.AttemptsPassed,
+	}
+}
@@ -0,0 +1,35 @@
+// Package viewmodel содержит структуры, которые используются для передачи данных в шаблоны (views).
+package viewmodel
+
+import "github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+
+// BannerViewModel представляет баннер сайта для шаблона `layouts/main.hbs`.
+type BannerViewModel struct {
+	Message  string
+	Severity string // info, warning или critical; неизвестные значения приводятся к info.
+	Class    string // CSS-модификатор блока banner, соответствующий уровню важности.
+	Role     string // ARIA-роль: alert для warning и critical, status для info.
+}
+
+// NewBannerViewModel создает модель представления баннера. Возвращает nil, если показывать нечего.
+func NewBannerViewModel(banner *domain.Banner) *BannerViewModel {
+	if banner == nil || !banner.Active || banner.Message == "" {
+		return nil
+	}
+
+	severity := banner.Severity
+	role := "alert"
+	switch severity {
+	case domain.BannerSeverityWarning, domain.BannerSeverityCritical:
+	default:
+		severity = domain.BannerSeverityInfo
+		role = "status"
+	}
+
+	return &BannerViewModel{
+		Message:  banner.Message,
+		Severity: severity,
+		Class:    "banner--" + severity,
+		Role:     role,
+	}
+}
@@ -36,6 +36,11 @@ func BreadcrumbsForCategoriesPage() []Breadcrumb {
 	return crumbs
 }
 
+// BreadcrumbsForRecentPage генерирует "хлебные крошки" для страницы последних обновленных курсов.
+func BreadcrumbsForRecentPage() []Breadcrumb {
+	return []Breadcrumb{home(), {Text: "Последние обновления", URL: ""}}
+}
+
 // BreadcrumbsForCoursesPage генерирует "хлебные крошки" для страницы курсов в определенной категории.
 func BreadcrumbsForCoursesPage(category response.CategoryDTO) []Breadcrumb {
 	crumbs := categories()
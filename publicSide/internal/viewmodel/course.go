@@ -10,48 +10,63 @@ import (
 
 // CourseViewModel представляет данные для отображения одной карточки курса в списке.
 type CourseViewModel struct {
-	Title         string
-	Ref           string
-	Level         string
-	LevelRu       string
-	Description   string
-	LessonsAmount int
-	UpdatedAt     time.Time
-	CreatedAt     time.Time
-	ImageURL      string
+	Title       string
+	Ref         string
+	Level       string
+	LevelRu     string
+	Description string
+	LessonCount int
+	UpdatedAt   time.Time
+	CreatedAt   time.Time
+	ImageURL    string
+	// CategoryTitle и CategoryRef - название категории и ссылка на ее курсы;
+	// заполняются, только если выборка курсов присоединяет категорию.
+	CategoryTitle string
+	CategoryRef   string
 }
 
 // NewCourseViewModel создает новую модель представления для карточки курса.
-func NewCourseViewModel(courseDTO *response.CourseDTO, lessonAmount int) *CourseViewModel {
+func NewCourseViewModel(courseDTO *response.CourseDTO, lessonCount int) *CourseViewModel {
+	categoryRef := ""
+	if courseDTO.CategoryTitle != "" {
+		categoryRef = routing.MakePathCourses(courseDTO.CategoryID)
+	}
+
 	return &CourseViewModel{
 		Title:         courseDTO.Title,
 		Ref:           routing.MakePathCourse(courseDTO.CategoryID, courseDTO.ID),
 		Level:         courseDTO.Level,
 		LevelRu:       "ПУСТО!!!", // Это поле заполняется позже в обработчике
 		Description:   courseDTO.Description,
-		LessonsAmount: lessonAmount,
+		LessonCount:   lessonCount,
 		UpdatedAt:     courseDTO.UpdatedAt,
 		CreatedAt:     courseDTO.CreatedAt,
 		ImageURL:      courseDTO.ImageURL,
+		CategoryTitle: courseDTO.CategoryTitle,
+		CategoryRef:   categoryRef,
 	}
 }
 
 // CourseDetailViewModel расширяет CourseViewModel, добавляя список уроков для детальной страницы курса.
 type CourseDetailViewModel struct {
 	CourseViewModel
-	Lessons []LessonViewModel
+	Lessons            []LessonViewModel
+	ReadingTimeMinutes int // Суммарное время чтения уроков курса в минутах.
 }
 
 // NewCourseDetailViewModel создает новую модель представления для детальной информации о курсе.
 func NewCourseDetailViewModel(courseDTO *response.CourseDTO, lessonsDTO []response.LessonDTO) *CourseDetailViewModel {
 	lessons := make([]LessonViewModel, 0, len(lessonsDTO))
+	readingTime := 0
 	for _, lDTO := range lessonsDTO {
-		lessons = append(lessons, *NewLessonViewModel(lDTO, courseDTO.CategoryID, courseDTO.ID))
+		lessons = append(lessons, *NewLessonViewModel(lDTO, courseDTO.CategoryID, courseDTO.ID, ""))
+		readingTime += lDTO.ReadingTimeMinutes
 	}
 
 	return &CourseDetailViewModel{
-		CourseViewModel: *NewCourseViewModel(courseDTO, len(lessonsDTO)),
-		Lessons:         lessons,
+		CourseViewModel:    *NewCourseViewModel(courseDTO, len(lessonsDTO)),
+		Lessons:            lessons,
+		ReadingTimeMinutes: readingTime,
 	}
 }
 
@@ -65,10 +80,10 @@ type CoursesPageViewModel struct {
 }
 
 // NewCoursesPageViewModel создает новую модель представления для страницы списка курсов.
-func NewCoursesPageViewModel(categoryDTO response.CategoryDTO, coursesDTO []response.CourseDTO, coursesPagination response.Pagination, lessonsAmount []int, level string, sortBy string) *CoursesPageViewModel {
+func NewCoursesPageViewModel(categoryDTO response.CategoryDTO, coursesDTO []response.CourseDTO, coursesPagination response.Pagination, lessonCounts map[string]int, level string, sortBy string) *CoursesPageViewModel {
 	courses := make([]CourseViewModel, 0, len(coursesDTO))
-	for i, c := range coursesDTO {
-		courses = append(courses, *NewCourseViewModel(&c, lessonsAmount[i]))
+	for _, c := range coursesDTO {
+		courses = append(courses, *NewCourseViewModel(&c, lessonCounts[c.ID]))
 	}
 
 	return &CoursesPageViewModel{
@@ -80,6 +95,25 @@ func NewCoursesPageViewModel(categoryDTO response.CategoryDTO, coursesDTO []resp
 	}
 }
 
+// RecentCoursesPageViewModel представляет данные для страницы последних обновленных курсов.
+type RecentCoursesPageViewModel struct {
+	PageHeader *PageHeaderViewModel
+	Courses    []CourseViewModel
+}
+
+// NewRecentCoursesPageViewModel создает новую модель представления для страницы последних обновленных курсов.
+func NewRecentCoursesPageViewModel(coursesDTO []response.CourseDTO, lessonCounts map[string]int) *RecentCoursesPageViewModel {
+	courses := make([]CourseViewModel, 0, len(coursesDTO))
+	for _, c := range coursesDTO {
+		courses = append(courses, *NewCourseViewModel(&c, lessonCounts[c.ID]))
+	}
+
+	return &RecentCoursesPageViewModel{
+		PageHeader: NewPageHeaderViewModel("Последние обновления", BreadcrumbsForRecentPage()),
+		Courses:    courses,
+	}
+}
+
 // CoursePageViewModel представляет данные для детальной страницы одного курса.
 type CoursePageViewModel struct {
 	PageHeader               *PageHeaderViewModel
@@ -87,6 +121,8 @@ type CoursePageViewModel struct {
 	Test                     *TestViewModel
 	TestIsNotFound           bool // Флаг, что тест для курса не найден.
 	TestServiceIsUnavailable bool // Флаг, что сервис тестов недоступен.
+	TestStats                *TestStatsViewModel
+	TestStatsIsUnavailable   bool // Флаг, что статистику теста не удалось получить.
 }
 
 // NewCoursePageViewModel создает новую модель представления для страницы курса.
@@ -97,6 +133,8 @@ func NewCoursePageViewModel(
 	testVM *TestViewModel,
 	testIsNotFound bool,
 	testServiceIsUnavailable bool,
+	testStatsVM *TestStatsViewModel,
+	testStatsIsUnavailable bool,
 ) *CoursePageViewModel {
 	return &CoursePageViewModel{
 		PageHeader:               NewPageHeaderViewModel("Курс: "+courseDTO.Title, BreadcrumbsForCoursePage(categoryDTO, courseDTO)),
@@ -104,5 +142,7 @@ func NewCoursePageViewModel(
 		Test:                     testVM,
 		TestIsNotFound:           testIsNotFound,
 		TestServiceIsUnavailable: testServiceIsUnavailable,
+		TestStats:                testStatsVM,
+		TestStatsIsUnavailable:   testStatsIsUnavailable,
 	}
 }
@@ -9,6 +9,7 @@ import (
 type HeaderViewModel struct {
 	HomeRoute       string
 	CategoriesRoute string
+	RecentRoute     string
 	ProfileRoute    string
 	LoginRoute      string
 	LogoutRoute     string
@@ -20,6 +21,7 @@ func NewHeader() *HeaderViewModel {
 	return &HeaderViewModel{
 		HomeRoute:       routing.RouteHome,
 		CategoriesRoute: routing.RouteCategories,
+		RecentRoute:     routing.RouteRecent,
 		ProfileRoute:    routing.ExternalServiceRouteProfile,
 		LoginRoute:      routing.RouteLogin,
 		LogoutRoute:     routing.RouteLogout,
@@ -3,22 +3,35 @@ package viewmodel
 
 import (
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/dto/response"
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/lessoncontent"
 	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/routing"
 )
 
 // LessonViewModel представляет данные для отображения одного урока в списке (например, в боковой панели).
 type LessonViewModel struct {
-	Title string
-	Ref   string // URL-адрес урока.
+	Title              string
+	Ref                string // URL-адрес урока.
+	ReadingTimeMinutes int    // Оценка времени чтения в минутах; 0 - не показывать.
+}
+
+// lessonPath строит URL урока. Если задан courseSlug и у урока есть slug, используется адрес
+// по slug (MakePathLessonBySlug), иначе - адрес по ID: уроки без slug остаются доступными.
+func lessonPath(categoryID, courseID, courseSlug, lessonID, lessonSlug string) string {
+	if courseSlug != "" && lessonSlug != "" {
+		return routing.MakePathLessonBySlug(categoryID, courseSlug, lessonSlug)
+	}
+	return routing.MakePathLesson(categoryID, courseID, lessonID)
 }
 
 // NewLessonViewModel создает новую модель представления для элемента списка уроков.
-func NewLessonViewModel(lessonDTO response.LessonDTO, categoryID, courseID string) *LessonViewModel {
+// Если передан courseSlug, ссылка на урок строится по slug (см. lessonPath).
+func NewLessonViewModel(lessonDTO response.LessonDTO, categoryID, courseID, courseSlug string) *LessonViewModel {
 	vm := LessonViewModel{
-		Title: lessonDTO.Title,
+		Title:              lessonDTO.Title,
+		ReadingTimeMinutes: lessonDTO.ReadingTimeMinutes,
 	}
 	if lessonDTO.ID != "" {
-		vm.Ref = routing.MakePathLesson(categoryID, courseID, lessonDTO.ID)
+		vm.Ref = lessonPath(categoryID, courseID, courseSlug, lessonDTO.ID, lessonDTO.Slug)
 	}
 	return &vm
 }
@@ -26,17 +39,20 @@ func NewLessonViewModel(lessonDTO response.LessonDTO, categoryID, courseID strin
 // LessonDetailedViewModel расширяет LessonViewModel, добавляя контент урока для детального отображения.
 type LessonDetailedViewModel struct {
 	LessonViewModel
-	Content string
+	Content string // HTML контента урока, готовый к выводу без экранирования.
 }
 
 // NewLessonDetailedViewModel создает новую модель представления для детальной информации об уроке.
-func NewLessonDetailedViewModel(lessonDTO response.LessonDTODetailed, categoryId string) *LessonDetailedViewModel {
+// Структурированный контент рендерится поблочно зарегистрированными рендерерами, HTML-контент
+// очищается по политике разметки курса contentPolicy (см. lessoncontent.Render).
+// Если передан courseSlug, ссылка на урок строится по slug (см. lessonPath).
+func NewLessonDetailedViewModel(lessonDTO response.LessonDTODetailed, categoryId, courseSlug, contentPolicy string) *LessonDetailedViewModel {
 	return &LessonDetailedViewModel{
 		LessonViewModel: LessonViewModel{
 			Title: lessonDTO.Title,
-			Ref:   routing.MakePathLesson(categoryId, lessonDTO.CourseID, lessonDTO.ID),
+			Ref:   lessonPath(categoryId, lessonDTO.CourseID, courseSlug, lessonDTO.ID, lessonDTO.Slug),
 		},
-		Content: lessonDTO.Content,
+		Content: lessoncontent.Render(lessonDTO.Content, contentPolicy),
 	}
 }
 
@@ -50,6 +66,8 @@ type LessonPageViewModel struct {
 }
 
 // NewLessonPageViewModel создает новую модель представления для страницы урока.
+// bySlug означает, что страница открыта по slug-адресу: тогда ссылки на текущий, соседние уроки
+// и уроки боковой панели тоже строятся по slug, чтобы навигация не уводила на адреса с ID.
 func NewLessonPageViewModel(
 	lessonDTODetailed response.LessonDTODetailed,
 	courseDTO response.CourseDTO,
@@ -57,17 +75,23 @@ func NewLessonPageViewModel(
 	nextLessonDTO response.LessonDTO,
 	prevLessonDTO response.LessonDTO,
 	lessonsDTOs []response.LessonDTO,
+	bySlug bool,
 ) *LessonPageViewModel {
+	courseSlug := ""
+	if bySlug {
+		courseSlug = courseDTO.Slug
+	}
+
 	lessons := make([]LessonViewModel, len(lessonsDTOs))
 	for i, ldto := range lessonsDTOs {
-		lessons[i] = *NewLessonViewModel(ldto, categoryDTO.ID, courseDTO.ID)
+		lessons[i] = *NewLessonViewModel(ldto, categoryDTO.ID, courseDTO.ID, courseSlug)
 	}
 
 	return &LessonPageViewModel{
 		PageHeader: NewPageHeaderViewModel("Урок: "+lessonDTODetailed.Title, BreadcrumbsForLessonPage(categoryDTO, courseDTO, lessonDTODetailed)),
-		Lesson:     NewLessonDetailedViewModel(lessonDTODetailed, categoryDTO.ID),
-		NextLesson: NewLessonViewModel(nextLessonDTO, categoryDTO.ID, courseDTO.ID),
-		PrevLesson: NewLessonViewModel(prevLessonDTO, categoryDTO.ID, courseDTO.ID),
+		Lesson:     NewLessonDetailedViewModel(lessonDTODetailed, categoryDTO.ID, courseSlug, courseDTO.ContentPolicy),
+		NextLesson: NewLessonViewModel(nextLessonDTO, categoryDTO.ID, courseDTO.ID, courseSlug),
+		PrevLesson: NewLessonViewModel(prevLessonDTO, categoryDTO.ID, courseDTO.ID, courseSlug),
 		Lessons:    lessons,
 	}
 }
@@ -1,14 +1,23 @@
 // Package viewmodel содержит структуры, которые используются для передачи данных в шаблоны (views).
 package viewmodel
 
+import (
+	"context"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
+)
+
 // MainViewModel представляет основные данные для корневого шаблона `layouts/main.hbs`.
 type MainViewModel struct {
-	Title string // Заголовок страницы, который будет отображаться в теге <title>.
+	Title  string           // Заголовок страницы, который будет отображаться в теге <title>.
+	Banner *BannerViewModel // Баннер сайта; nil, если баннер не показывается.
 }
 
 // NewMain создает новую модель представления для основного макета.
-func NewMain(title string) *MainViewModel {
+// Баннер сайта берется из контекста запроса (см. domain.WithBanner).
+func NewMain(ctx context.Context, title string) *MainViewModel {
 	return &MainViewModel{
-		Title: title,
+		Title:  title,
+		Banner: NewBannerViewModel(domain.BannerFromContext(ctx)),
 	}
 }
@@ -2,6 +2,8 @@
 package viewmodel
 
 import (
+	"math"
+
 	"github.com/TaurineMerge/LMS_Tages/publicSide/internal/domain"
 )
 
@@ -27,3 +29,31 @@ func NewTestViewModel(test *domain.Test, testURL string) *TestViewModel {
 		Ref:         testURL,
 	}
 }
+
+// TestStatsViewModel представляет статистику прохождения теста на странице курса.
+type TestStatsViewModel struct {
+	QuestionCount  int
+	AttemptsTotal  int
+	AttemptsPassed int
+	PassRate       int  // Доля успешных попыток в процентах.
+	HasAttempts    bool // Флаг, что тест уже проходили хотя бы раз.
+}
+
+// NewTestStatsViewModel создает новую модель представления для статистики теста.
+// Возвращает nil, если переданный доменный объект статистики равен nil.
+func NewTestStatsViewModel(stats *domain.TestStats) *TestStatsViewModel {
+	if stats == nil {
+		return nil
+	}
+
+	vm := &TestStatsViewModel{
+		QuestionCount:  stats.QuestionCount,
+		AttemptsTotal:  stats.AttemptsTotal,
+		AttemptsPassed: stats.AttemptsPassed,
+		HasAttempts:    stats.AttemptsTotal > 0,
+	}
+	if vm.HasAttempts {
+		vm.PassRate = int(math.Round(float64(stats.AttemptsPassed) * 100 / float64(stats.AttemptsTotal)))
+	}
+	return vm
+}
@@ -2,7 +2,22 @@
 // Это позволяет последовательно обрабатывать ошибки и преобразовывать их в соответствующие HTTP-ответы.
 package apperrors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// Классы ошибок слоя хранения данных. Репозитории оборачивают ими исходные ошибки драйвера
+// через %w, чтобы сервисы и обработчики ошибок распознавали их с помощью errors.Is
+// независимо от текста исходной ошибки (см. FromError).
+var (
+	// ErrNotFound означает, что запрошенная запись не найдена.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict означает нарушение ограничения уникальности или целостности данных.
+	ErrConflict = errors.New("conflict")
+	// ErrDB означает прочие ошибки базы данных: недоступность, таймаут, ошибку запроса.
+	ErrDB = errors.New("database error")
+)
 
 // AppError представляет собой стандартную ошибку приложения с дополнительной информацией
 // для преобразования в HTTP-ответ.
@@ -57,6 +72,61 @@ func NewInvalidRequest(message string) error {
 	}
 }
 
+// NewValidation создает новую ошибку AppError для значения параметра, не прошедшего проверку (HTTP 400).
+func NewValidation(message string) error {
+	return &AppError{
+		HTTPStatus: 400,
+		Code:       "VALIDATION_ERROR",
+		Message:    message,
+	}
+}
+
+// NewUnauthorized создает новую ошибку AppError для запросов без действительных учетных данных (HTTP 401).
+func NewUnauthorized(message string) error {
+	if message == "" {
+		message = "Authentication required"
+	}
+	return &AppError{
+		HTTPStatus: 401,
+		Code:       "UNAUTHORIZED",
+		Message:    message,
+	}
+}
+
+// NewForbidden создает новую ошибку AppError для запросов, недоступных с текущими правами (HTTP 403).
+func NewForbidden(message string) error {
+	if message == "" {
+		message = "Access denied"
+	}
+	return &AppError{
+		HTTPStatus: 403,
+		Code:       "FORBIDDEN",
+		Message:    message,
+	}
+}
+
+// NewConflict создает новую ошибку AppError для конфликта с текущим состоянием данных (HTTP 409).
+func NewConflict(message string) error {
+	if message == "" {
+		message = "Resource conflicts with existing data"
+	}
+	return &AppError{
+		HTTPStatus: 409,
+		Code:       "CONFLICT",
+		Message:    message,
+	}
+}
+
+// NewDatabase создает новую ошибку AppError для сбоя базы данных (HTTP 500).
+// Подробности исходной ошибки клиенту не передаются.
+func NewDatabase() error {
+	return &AppError{
+		HTTPStatus: 500,
+		Code:       "DATABASE_ERROR",
+		Message:    "A database error occurred",
+	}
+}
+
 // NewInternal создает новую ошибку AppError для непредвиденных внутренних ошибок сервера (HTTP 500).
 func NewInternal() error {
 	return &AppError{
@@ -70,3 +140,22 @@ func NewInternal() error {
 func NewServiceUnavailable(serviceName string) error {
 	return &ServiceUnavailableError{ServiceName: serviceName}
 }
+
+// FromError сопоставляет ошибку с AppError для ответа клиенту.
+// AppError в цепочке ошибок возвращается как есть, ErrNotFound, ErrConflict и ErrDB
+// преобразуются в ошибки 404, 409 и 500 соответственно. Для прочих ошибок возвращает nil.
+func FromError(err error) *AppError {
+	var appErr *AppError
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, ErrNotFound):
+		return NewNotFound("Resource").(*AppError)
+	case errors.Is(err, ErrConflict):
+		return NewConflict("").(*AppError)
+	case errors.Is(err, ErrDB):
+		return NewDatabase().(*AppError)
+	default:
+		return nil
+	}
+}
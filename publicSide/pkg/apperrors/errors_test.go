@@ -0,0 +1,33 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "wrapped app error", err: fmt.Errorf("handler: %w", NewInvalidUUID("course_id")), wantCode: "INVALID_UUID"},
+		{name: "not found", err: fmt.Errorf("get course: %w: %w", ErrNotFound, errors.New("no rows in result set")), wantCode: "NOT_FOUND"},
+		{name: "conflict", err: fmt.Errorf("insert: %w", ErrConflict), wantCode: "CONFLICT"},
+		{name: "database", err: fmt.Errorf("query: %w", ErrDB), wantCode: "DATABASE_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := FromError(tt.err)
+			if appErr == nil || appErr.Code != tt.wantCode {
+				t.Errorf("FromError(%v) = %v, want code %s", tt.err, appErr, tt.wantCode)
+			}
+		})
+	}
+
+	if appErr := FromError(errors.New("unexpected")); appErr != nil {
+		t.Errorf("FromError(unclassified) = %v, want nil", appErr)
+	}
+}
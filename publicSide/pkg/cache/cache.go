@@ -0,0 +1,267 @@
+// Package cache предоставляет потокобезопасный in-memory кэш с ограничением
+// по времени жизни записей (TTL) и по количеству записей (вытеснение LRU).
+package cache
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache - кэш значений типа V по строковому ключу.
+// Нулевой указатель (*Cache)(nil) - допустимый отключенный кэш: GetOrLoad всегда вызывает загрузку.
+type Cache[V any] struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List // Записи от недавно использованных (начало) к давно использованным (конец).
+	// generation увеличивается при каждом удалении записей; загрузка, начатая до удаления,
+	// не сохраняет свой результат, чтобы не вернуть в кэш устаревшее значение.
+	generation uint64
+
+	group  singleflight.Group
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// entry - запись кэша со временем истечения.
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// New создает кэш с именем name (используется в логах и трассировке), не более maxEntries
+// записями и временем жизни записи ttl. При ttl <= 0 кэш отключен и возвращается nil.
+func New[V any](name string, maxEntries int, ttl time.Duration) *Cache[V] {
+	if ttl <= 0 {
+		return nil
+	}
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &Cache[V]{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get возвращает значение по ключу, если оно есть в кэше и не истекло.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := elem.Value.(*entry[V])
+	if time.Now().After(e.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// Set сохраняет значение по ключу. При превышении размера вытесняется давно не использованная запись.
+func (c *Cache[V]) Set(key string, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setLocked(key, value)
+}
+
+// setLocked сохраняет значение; вызывается с захваченным c.mu.
+func (c *Cache[V]) setLocked(key string, value V) {
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[V]).key)
+	}
+}
+
+// Delete удаляет запись по ключу.
+func (c *Cache[V]) Delete(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+	c.generation++
+}
+
+// DeleteFunc удаляет все записи, для которых match возвращает true, и возвращает их количество.
+// Позволяет сбросить записи по содержимому, когда ключ заранее неизвестен (например, курс по slug).
+func (c *Cache[V]) DeleteFunc(match func(key string, value V) bool) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, elem := range c.items {
+		if match(key, elem.Value.(*entry[V]).value) {
+			c.order.Remove(elem)
+			delete(c.items, key)
+			removed++
+		}
+	}
+	c.generation++
+	return removed
+}
+
+// Clear удаляет все записи.
+func (c *Cache[V]) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+	c.generation++
+}
+
+// GetOrLoad возвращает значение из кэша или загружает его функцией load и сохраняет.
+// Одновременные промахи по одному ключу объединяются (singleflight): load вызывается один раз,
+// а остальные запросы получают ее результат. Ошибки загрузки не кэшируются.
+// Загрузка не прерывается отменой контекста одного из ожидающих запросов. Если во время загрузки
+// записи были удалены (Delete, DeleteFunc, Clear), результат возвращается, но не сохраняется.
+func (c *Cache[V]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if value, ok := c.Get(key); ok {
+		c.hits.Add(1)
+		span.SetAttributes(attribute.String("cache.name", c.name), attribute.Bool("cache.hit", true))
+		return value, nil
+	}
+	c.misses.Add(1)
+	span.SetAttributes(attribute.String("cache.name", c.name), attribute.Bool("cache.hit", false))
+
+	loadCtx := context.WithoutCancel(ctx)
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		c.mu.Lock()
+		generation := c.generation
+		c.mu.Unlock()
+
+		value, err := load(loadCtx)
+		if err != nil {
+			return value, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.setLocked(key, value)
+		}
+		c.mu.Unlock()
+		return value, nil
+	})
+	value, _ := result.(V)
+	return value, err
+}
+
+// Stats - счетчики использования кэша.
+type Stats struct {
+	Name   string
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// HitRatio возвращает долю попаданий среди всех обращений или 0, если обращений не было.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Stats возвращает текущие счетчики кэша. Для отключенного кэша возвращаются нули.
+func (c *Cache[V]) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{Name: c.name, Hits: c.hits.Load(), Misses: c.misses.Load(), Size: size}
+}
+
+// StatsProvider - источник статистики кэша; реализуется *Cache с любым типом значения.
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// LogStats периодически пишет в лог статистику кэшей (попадания, промахи, размер, доля попаданий),
+// пока не будет отменен ctx. Отключенные кэши пропускаются.
+func LogStats(ctx context.Context, interval time.Duration, caches ...StatsProvider) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, c := range caches {
+				stats := c.Stats()
+				if stats.Name == "" {
+					continue
+				}
+				slog.Info("Cache stats",
+					"cache", stats.Name,
+					"hits", stats.Hits,
+					"misses", stats.Misses,
+					"size", stats.Size,
+					"hit_ratio", stats.HitRatio(),
+				)
+			}
+		}
+	}
+}
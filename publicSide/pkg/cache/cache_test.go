@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteDuringLoadIsNotCached(t *testing.T) {
+	c := New[string]("test", 10, time.Minute)
+
+	value, err := c.GetOrLoad(context.Background(), "key", func(context.Context) (string, error) {
+		c.Delete("key")
+		return "stale", nil
+	})
+	if err != nil || value != "stale" {
+		t.Fatalf("GetOrLoad() = %q, %v; want the loaded value", value, err)
+	}
+	if _, ok := c.Get("key"); ok {
+		t.Error("value loaded before eviction was cached, want it dropped")
+	}
+
+	if _, err := c.GetOrLoad(context.Background(), "key", func(context.Context) (string, error) {
+		return "fresh", nil
+	}); err != nil {
+		t.Fatalf("GetOrLoad() error = %v", err)
+	}
+	if value, ok := c.Get("key"); !ok || value != "fresh" {
+		t.Errorf("Get() = %q, %v; want fresh value cached", value, ok)
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	c := New[int]("test", 10, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	if removed := c.DeleteFunc(func(_ string, v int) bool { return v%2 == 1 }); removed != 2 {
+		t.Errorf("DeleteFunc() = %d, want 2", removed)
+	}
+	for key, want := range map[string]bool{"a": false, "b": true, "c": false} {
+		if _, ok := c.Get(key); ok != want {
+			t.Errorf("Get(%q) present = %v, want %v", key, ok, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listenReconnectDelay - пауза перед повторной подпиской после потери соединения.
+const listenReconnectDelay = 5 * time.Second
+
+// Listen подписывается на канал PostgreSQL NOTIFY и вызывает handle для каждого уведомления,
+// пока не будет отменен ctx. Для подписки из пула забирается отдельное соединение.
+// При потере соединения Listen переподключается. После каждой успешной подписки вызывается
+// onSubscribe: уведомления, отправленные во время разрыва, не доставляются, и подписчик
+// должен сам восстановить согласованность (например, сбросить кэш).
+func Listen(ctx context.Context, pool *pgxpool.Pool, channel string, handle func(payload string), onSubscribe func()) {
+	for {
+		err := listenOnce(ctx, pool, channel, handle, onSubscribe)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Lost subscription to database notifications, reconnecting", "channel", channel, "error", err, "delay", listenReconnectDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenReconnectDelay):
+		}
+	}
+}
+
+// listenOnce подписывается на канал и обрабатывает уведомления до первой ошибки соединения.
+func listenOnce(ctx context.Context, pool *pgxpool.Pool, channel string, handle func(payload string), onSubscribe func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// Соединение с активной подпиской не возвращается в пул.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on channel: %w", err)
+	}
+	slog.Info("Subscribed to database notifications", "channel", channel)
+	onSubscribe()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
@@ -0,0 +1,43 @@
+// Package httpclient предоставляет общий HTTP-клиент для исходящих запросов
+// с настраиваемыми таймаутами подключения и чтения.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// New создает HTTP-клиент с заданными таймаутами.
+//
+// `connectTimeout` ограничивает время установки TCP-соединения и TLS-рукопожатия.
+// `readTimeout` ограничивает общее время запроса, включая чтение тела ответа.
+// Нулевое значение отключает соответствующий таймаут.
+func New(connectTimeout, readTimeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   readTimeout,
+	}
+}
+
+// IsTimeout сообщает, вызвана ли ошибка истечением таймаута или дедлайна контекста.
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
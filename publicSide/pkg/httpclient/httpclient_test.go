@@ -0,0 +1,44 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := New(time.Second, 50*time.Millisecond)
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Get() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Get() took %v, want it to stop at the read timeout", elapsed)
+	}
+	if !IsTimeout(err) {
+		t.Errorf("IsTimeout(%v) = false, want true", err)
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	if IsTimeout(nil) {
+		t.Error("IsTimeout(nil) = true, want false")
+	}
+	if IsTimeout(errors.New("connection refused")) {
+		t.Error("IsTimeout(plain error) = true, want false")
+	}
+}
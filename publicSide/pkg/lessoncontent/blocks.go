@@ -0,0 +1,183 @@
+package lessoncontent
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Типы блоков контента, для которых есть встроенные рендереры.
+const (
+	BlockText  = "text"
+	BlockCode  = "code"
+	BlockImage = "image"
+	BlockVideo = "video"
+)
+
+// Block - один блок структурированного контента урока.
+// Набор ключей Data зависит от типа: text - text; code - code, language;
+// image - url, alt, caption; video - provider, id, caption (или url для видеофайла, см. VideoEmbedder).
+type Block struct {
+	Type string            `json:"type"`
+	Data map[string]string `json:"data"`
+}
+
+// Document - структурированный контент урока, хранящийся в БД как JSON {"blocks": [...]}.
+type Document struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// Renderer преобразует блок в безопасный HTML: все данные блока экранируются
+// или проверяются рендерером, поэтому результат можно выводить в шаблоне без экранирования.
+type Renderer func(Block) string
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{
+		BlockText:  renderText,
+		BlockCode:  renderCode,
+		BlockImage: renderImage,
+		BlockVideo: defaultVideoEmbedder.Render,
+	}
+)
+
+// RegisterRenderer регистрирует рендерер для типа блока, заменяя существующий.
+// Добавление нового типа блока сводится к регистрации его рендерера.
+func RegisterRenderer(blockType string, renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[blockType] = renderer
+}
+
+// ParseDocument разбирает контент урока как структурированный документ.
+// Возвращает false, если контент не является JSON-документом с полем blocks.
+func ParseDocument(content string) (Document, bool) {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "{") {
+		return Document{}, false
+	}
+
+	var raw struct {
+		Blocks *[]Block `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &raw); err != nil || raw.Blocks == nil {
+		return Document{}, false
+	}
+	return Document{Blocks: *raw.Blocks}, true
+}
+
+// Render преобразует контент урока в HTML для страницы урока.
+// Структурированный документ рендерится поблочно зарегистрированными рендерерами,
+// неизвестные типы блоков заменяются безопасной заглушкой. Контент в формате HTML
+// из редактора панели администратора очищается по политике разметки курса policy (см. Sanitize).
+func Render(content, policy string) string {
+	doc, ok := ParseDocument(content)
+	if !ok {
+		return Sanitize(content, policy)
+	}
+	return RenderBlocks(doc.Blocks)
+}
+
+// RenderBlocks последовательно рендерит блоки зарегистрированными рендерерами.
+func RenderBlocks(blocks []Block) string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	var b strings.Builder
+	for _, block := range blocks {
+		renderer, ok := renderers[block.Type]
+		if !ok {
+			renderer = renderUnsupported
+		}
+		b.WriteString(renderer(block))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderText рендерит текст абзацами: пустая строка разделяет абзацы, перевод строки - <br>.
+func renderText(block Block) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(block.Data["text"], "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		fmt.Fprintf(&b, "<p>%s</p>", strings.Join(lines, "<br>"))
+	}
+	return fmt.Sprintf(`<div class="lesson-block lesson-block--text">%s</div>`, b.String())
+}
+
+// codeLanguagePattern ограничивает имя языка, которое попадает в атрибут class.
+var codeLanguagePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#_-]{0,31}$`)
+
+// renderCode рендерит фрагмент кода без подсветки, с классом language-<язык>.
+// Используется по умолчанию и как запасной вариант Highlighter для неизвестных языков.
+func renderCode(block Block) string {
+	class := ""
+	if language := strings.ToLower(strings.TrimSpace(block.Data["language"])); codeLanguagePattern.MatchString(language) {
+		class = fmt.Sprintf(` class="language-%s"`, language)
+	}
+	return fmt.Sprintf(`<pre class="lesson-block lesson-block--code"><code%s>%s</code></pre>`,
+		class, html.EscapeString(block.Data["code"]))
+}
+
+// renderImage рендерит изображение с необязательной подписью.
+// Допускаются только http(s) и относительные URL.
+func renderImage(block Block) string {
+	src, ok := safeURL(block.Data["url"])
+	if !ok {
+		return renderUnsupported(block)
+	}
+	return fmt.Sprintf(`<figure class="lesson-block lesson-block--image"><img src="%s" alt="%s" loading="lazy">%s</figure>`,
+		html.EscapeString(src), html.EscapeString(block.Data["alt"]), figcaption(block.Data["caption"]))
+}
+
+// renderUnsupported рендерит безопасную заглушку для блоков неизвестного типа или с некорректными данными.
+func renderUnsupported(block Block) string {
+	return fmt.Sprintf(`<div class="lesson-block lesson-block--unsupported" data-block-type="%s">Этот блок не может быть отображен.</div>`,
+		html.EscapeString(block.Type))
+}
+
+// figcaption возвращает элемент подписи к блоку или пустую строку, если подписи нет.
+func figcaption(caption string) string {
+	caption = strings.TrimSpace(caption)
+	if caption == "" {
+		return ""
+	}
+	return "<figcaption>" + html.EscapeString(caption) + "</figcaption>"
+}
+
+// safeURL проверяет, что URL абсолютный http(s) или относительный путь,
+// исключая javascript:, data: и другие схемы.
+func safeURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", false
+		}
+	case "":
+		if u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	return u.String(), true
+}
@@ -0,0 +1,114 @@
+package lessoncontent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		block     Block
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:      "text",
+			block:     Block{Type: BlockText, Data: map[string]string{"text": "first <b>line</b>\nsecond\n\nnext paragraph"}},
+			contains:  []string{`<p>first &lt;b&gt;line&lt;/b&gt;<br>second</p>`, `<p>next paragraph</p>`},
+			forbidden: []string{"<b>"},
+		},
+		{
+			name:      "code",
+			block:     Block{Type: BlockCode, Data: map[string]string{"code": "if a < b {}", "language": "go"}},
+			contains:  []string{`<code class="language-go">if a &lt; b {}</code>`},
+			forbidden: []string{"a < b"},
+		},
+		{
+			name:      "code with unsafe language",
+			block:     Block{Type: BlockCode, Data: map[string]string{"code": "x", "language": `go" onclick="alert(1)`}},
+			contains:  []string{`<code>x</code>`},
+			forbidden: []string{"onclick"},
+		},
+		{
+			name:     "image",
+			block:    Block{Type: BlockImage, Data: map[string]string{"url": "https://cdn.example.com/a.png", "alt": `"quoted"`, "caption": "Схема"}},
+			contains: []string{`<img src="https://cdn.example.com/a.png" alt="&#34;quoted&#34;"`, `<figcaption>Схема</figcaption>`},
+		},
+		{
+			name:      "image with javascript url",
+			block:     Block{Type: BlockImage, Data: map[string]string{"url": "javascript:alert(1)"}},
+			contains:  []string{"lesson-block--unsupported"},
+			forbidden: []string{"<img", "javascript"},
+		},
+		{
+			name:     "video",
+			block:    Block{Type: BlockVideo, Data: map[string]string{"provider": ProviderYouTube, "id": "dQw4w9WgXcQ"}},
+			contains: []string{`<iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"`},
+		},
+		{
+			name:      "unknown type",
+			block:     Block{Type: `<script>alert(1)</script>`, Data: map[string]string{"html": "<script>alert(2)</script>"}},
+			contains:  []string{`lesson-block--unsupported`, `data-block-type="&lt;script&gt;alert(1)&lt;/script&gt;"`},
+			forbidden: []string{"<script>", "alert(2)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := RenderBlocks([]Block{tt.block})
+			for _, s := range tt.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("RenderBlocks() = %q, want it to contain %q", out, s)
+				}
+			}
+			for _, s := range tt.forbidden {
+				if strings.Contains(out, s) {
+					t.Errorf("RenderBlocks() = %q, must not contain %q", out, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	renderersMu.RLock()
+	prev, had := renderers["quote"]
+	renderersMu.RUnlock()
+	t.Cleanup(func() {
+		renderersMu.Lock()
+		defer renderersMu.Unlock()
+		if had {
+			renderers["quote"] = prev
+		} else {
+			delete(renderers, "quote")
+		}
+	})
+
+	RegisterRenderer("quote", func(b Block) string { return "<blockquote>" + b.Data["text"] + "</blockquote>" })
+
+	if out := RenderBlocks([]Block{{Type: "quote", Data: map[string]string{"text": "hi"}}}); !strings.Contains(out, "<blockquote>hi</blockquote>") {
+		t.Errorf("RenderBlocks() = %q, want the registered renderer output", out)
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		content string
+		ok      bool
+		blocks  int
+	}{
+		{content: `{"blocks":[{"type":"text","data":{"text":"a"}},{"type":"code","data":{"code":"b"}}]}`, ok: true, blocks: 2},
+		{content: `  {"blocks":[]}`, ok: true, blocks: 0},
+		{content: `{"title":"no blocks"}`, ok: false},
+		{content: `<p>{"blocks":[]}</p>`, ok: false},
+		{content: `{"blocks":`, ok: false},
+	}
+
+	for _, tt := range tests {
+		doc, ok := ParseDocument(tt.content)
+		if ok != tt.ok || len(doc.Blocks) != tt.blocks {
+			t.Errorf("ParseDocument(%q) = %d blocks, %v; want %d blocks, %v", tt.content, len(doc.Blocks), ok, tt.blocks, tt.ok)
+		}
+	}
+}
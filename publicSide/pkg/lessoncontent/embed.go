@@ -0,0 +1,126 @@
+package lessoncontent
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Провайдеры встраиваемого видео, которые указываются в поле provider блока video.
+const (
+	ProviderYouTube = "youtube"
+	ProviderVimeo   = "vimeo"
+)
+
+// embedProvider описывает, как собрать URL встраивания из идентификатора видео.
+type embedProvider struct {
+	host      string         // Хост URL встраивания; именно он сверяется со списком разрешенных.
+	path      string         // Путь, к которому дописывается идентификатор.
+	idPattern *regexp.Regexp // Допустимые идентификаторы видео.
+}
+
+// embedProviders перечисляет известных провайдеров. URL встраивания собирается только
+// из проверенного идентификатора, поэтому HTML и произвольные адреса из блока в iframe не попадают.
+var embedProviders = map[string]embedProvider{
+	ProviderYouTube: {host: "www.youtube-nocookie.com", path: "/embed/", idPattern: regexp.MustCompile(`^[A-Za-z0-9_-]{6,20}$`)},
+	ProviderVimeo:   {host: "player.vimeo.com", path: "/video/", idPattern: regexp.MustCompile(`^[0-9]{1,15}$`)},
+}
+
+// DefaultEmbedHosts - хосты встраивания, разрешенные по умолчанию (YouTube без cookies и Vimeo).
+var DefaultEmbedHosts = []string{"www.youtube-nocookie.com", "player.vimeo.com"}
+
+// VideoEmbedder рендерит блоки видео, встраивая через iframe только провайдеров,
+// чей хост встраивания входит в список разрешенных. Остальные встраивания отклоняются.
+type VideoEmbedder struct {
+	allowedHosts []string
+}
+
+// defaultVideoEmbedder рендерит блоки видео, пока в RegisterRenderer не передан другой VideoEmbedder.
+var defaultVideoEmbedder = &VideoEmbedder{allowedHosts: DefaultEmbedHosts}
+
+// NewVideoEmbedder создает VideoEmbedder со списком разрешенных хостов встраивания.
+// Пустой список запрещает встраивание: остаются только прямые ссылки на видеофайлы.
+// Возвращает ошибку для хоста, который не является хостом встраивания известного провайдера.
+func NewVideoEmbedder(allowedHosts []string) (*VideoEmbedder, error) {
+	hosts := make([]string, 0, len(allowedHosts))
+	for _, host := range allowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		known := false
+		for _, provider := range embedProviders {
+			known = known || provider.host == host
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown video embed host %q", host)
+		}
+		hosts = append(hosts, host)
+	}
+	return &VideoEmbedder{allowedHosts: hosts}, nil
+}
+
+// Render рендерит блок типа video. Встраиваемое видео задается полями provider и id,
+// из которых собирается URL iframe; ссылки YouTube и Vimeo в поле url для совместимости
+// разбираются в те же provider и id. Прочие http(s) и относительные ссылки в url
+// считаются видеофайлами и выводятся через <video>. Неизвестный провайдер, некорректный
+// идентификатор или хост не из списка разрешенных дают заглушку.
+// Метод подходит для RegisterRenderer(BlockVideo, e.Render).
+func (e *VideoEmbedder) Render(block Block) string {
+	provider := strings.ToLower(strings.TrimSpace(block.Data["provider"]))
+	id := strings.TrimSpace(block.Data["id"])
+	caption := figcaption(block.Data["caption"])
+
+	if provider == "" {
+		raw, ok := safeURL(block.Data["url"])
+		if !ok {
+			return renderUnsupported(block)
+		}
+		if provider, id = parseVideoURL(raw); provider == "" {
+			return fmt.Sprintf(`<figure class="lesson-block lesson-block--video"><video src="%s" controls preload="metadata"></video>%s</figure>`,
+				html.EscapeString(raw), caption)
+		}
+	}
+
+	embed, ok := e.embedURL(provider, id)
+	if !ok {
+		return renderUnsupported(block)
+	}
+	return fmt.Sprintf(`<figure class="lesson-block lesson-block--video"><iframe src="%s" loading="lazy" allowfullscreen `+
+		`allow="accelerometer; clipboard-write; encrypted-media; gyroscope; picture-in-picture" `+
+		`referrerpolicy="strict-origin-when-cross-origin"></iframe>%s</figure>`,
+		html.EscapeString(embed), caption)
+}
+
+// embedURL собирает URL встраивания, если провайдер известен, его хост разрешен и идентификатор корректен.
+func (e *VideoEmbedder) embedURL(provider, id string) (string, bool) {
+	p, ok := embedProviders[provider]
+	if !ok || !slices.Contains(e.allowedHosts, p.host) || !p.idPattern.MatchString(id) {
+		return "", false
+	}
+	return "https://" + p.host + p.path + id, true
+}
+
+// parseVideoURL извлекает провайдера и идентификатор видео из ссылки YouTube или Vimeo.
+// Для остальных ссылок возвращает пустые строки.
+func parseVideoURL(raw string) (provider, id string) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ""
+	}
+
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+		id = u.Query().Get("v")
+		if rest, ok := strings.CutPrefix(u.Path, "/embed/"); ok {
+			id = rest
+		}
+		return ProviderYouTube, id
+	case "youtu.be":
+		return ProviderYouTube, strings.Trim(u.Path, "/")
+	case "vimeo.com", "player.vimeo.com":
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		return ProviderVimeo, segments[len(segments)-1]
+	}
+	return "", ""
+}
@@ -0,0 +1,90 @@
+package lessoncontent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVideoEmbedderRender(t *testing.T) {
+	youtubeOnly, err := NewVideoEmbedder([]string{"www.youtube-nocookie.com"})
+	if err != nil {
+		t.Fatalf("NewVideoEmbedder() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data map[string]string
+		want string
+	}{
+		{
+			name: "allowed provider",
+			data: map[string]string{"provider": "YouTube", "id": "dQw4w9WgXcQ"},
+			want: `<iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"`,
+		},
+		{
+			name: "legacy youtube url",
+			data: map[string]string{"url": "https://youtu.be/dQw4w9WgXcQ"},
+			want: `<iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"`,
+		},
+		{
+			name: "provider not in allowlist",
+			data: map[string]string{"provider": ProviderVimeo, "id": "76979871"},
+			want: "lesson-block--unsupported",
+		},
+		{
+			name: "legacy url of provider not in allowlist",
+			data: map[string]string{"url": "https://vimeo.com/76979871"},
+			want: "lesson-block--unsupported",
+		},
+		{
+			name: "unknown provider",
+			data: map[string]string{"provider": "evil", "id": "abcdef"},
+			want: "lesson-block--unsupported",
+		},
+		{
+			name: "id with path injection",
+			data: map[string]string{"provider": ProviderYouTube, "id": `x"/><script>alert(1)</script>`},
+			want: "lesson-block--unsupported",
+		},
+		{
+			name: "video file",
+			data: map[string]string{"url": "https://cdn.example.com/lesson.mp4"},
+			want: `<video src="https://cdn.example.com/lesson.mp4"`,
+		},
+		{
+			name: "javascript url",
+			data: map[string]string{"url": "javascript:alert(1)"},
+			want: "lesson-block--unsupported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := youtubeOnly.Render(Block{Type: BlockVideo, Data: tt.data})
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Render() = %q, want it to contain %q", out, tt.want)
+			}
+			if strings.Contains(out, "<script>") || strings.Contains(out, "javascript:") {
+				t.Errorf("Render() = %q, must not contain active content", out)
+			}
+		})
+	}
+}
+
+func TestNewVideoEmbedder(t *testing.T) {
+	if _, err := NewVideoEmbedder([]string{" Player.Vimeo.com "}); err != nil {
+		t.Errorf("NewVideoEmbedder(known host) error = %v", err)
+	}
+	if _, err := NewVideoEmbedder([]string{"evil.example.com"}); err == nil {
+		t.Error("NewVideoEmbedder(unknown host) error = nil, want error")
+	}
+
+	none, err := NewVideoEmbedder(nil)
+	if err != nil {
+		t.Fatalf("NewVideoEmbedder(nil) error = %v", err)
+	}
+	out := none.Render(Block{Type: BlockVideo, Data: map[string]string{"provider": ProviderYouTube, "id": "dQw4w9WgXcQ"}})
+	if strings.Contains(out, "<iframe") {
+		t.Errorf("Render() with empty allowlist = %q, want no iframe", out)
+	}
+}
@@ -0,0 +1,94 @@
+package lessoncontent
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// highlightClassPrefix префикс CSS-классов подсветки, чтобы классы темы (например, .bg)
+// не пересекались со стилями сайта.
+const highlightClassPrefix = "hl-"
+
+// Highlighter рендерит блоки кода с серверной подсветкой синтаксиса (chroma).
+// Токены размечаются CSS-классами, а цвета задаются таблицей стилей темы (см. CSS),
+// поэтому в HTML попадает только экранированный текст и классы.
+type Highlighter struct {
+	style *chroma.Style
+}
+
+// NewHighlighter создает Highlighter с темой theme из набора тем chroma (например, github, monokai).
+// Возвращает ошибку, если тема неизвестна.
+func NewHighlighter(theme string) (*Highlighter, error) {
+	style, ok := styles.Registry[strings.ToLower(theme)]
+	if !ok {
+		return nil, fmt.Errorf("unknown code highlight theme %q", theme)
+	}
+	return &Highlighter{style: style}, nil
+}
+
+// Render рендерит блок типа code с подсветкой по полю language.
+// Для неизвестного или не указанного языка возвращается обычный <pre><code>.
+// Метод подходит для RegisterRenderer(BlockCode, h.Render).
+func (h *Highlighter) Render(block Block) string {
+	language := strings.ToLower(strings.TrimSpace(block.Data["language"]))
+	if !codeLanguagePattern.MatchString(language) {
+		return renderCode(block)
+	}
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		return renderCode(block)
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, block.Data["code"])
+	if err != nil {
+		return renderCode(block)
+	}
+
+	var b strings.Builder
+	formatter := chromahtml.New(
+		chromahtml.WithClasses(true),
+		chromahtml.ClassPrefix(highlightClassPrefix),
+		chromahtml.WithPreWrapper(codePreWrapper{language: language}),
+	)
+	if err := formatter.Format(&b, h.style, iterator); err != nil {
+		return renderCode(block)
+	}
+	return b.String()
+}
+
+// CSS возвращает таблицу стилей выбранной темы для классов, которые использует Render.
+func (h *Highlighter) CSS() (string, error) {
+	var b strings.Builder
+	if err := chromahtml.New(chromahtml.WithClasses(true), chromahtml.ClassPrefix(highlightClassPrefix)).WriteCSS(&b, h.style); err != nil {
+		return "", fmt.Errorf("failed to generate code highlight CSS: %w", err)
+	}
+	return b.String(), nil
+}
+
+// codePreWrapper оборачивает подсвеченный код в ту же разметку, что и renderCode,
+// добавляя класс hl-chroma, к которому привязан фон темы.
+type codePreWrapper struct {
+	language string
+}
+
+// Start возвращает открывающие теги <pre> и <code>.
+func (w codePreWrapper) Start(code bool, _ string) string {
+	if !code {
+		return `<pre class="lesson-block lesson-block--code hl-chroma">`
+	}
+	return fmt.Sprintf(`<pre class="lesson-block lesson-block--code hl-chroma"><code class="language-%s">`, html.EscapeString(w.language))
+}
+
+// End возвращает закрывающие теги.
+func (w codePreWrapper) End(code bool) string {
+	if !code {
+		return "</pre>"
+	}
+	return "</code></pre>"
+}
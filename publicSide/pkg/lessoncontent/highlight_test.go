@@ -0,0 +1,69 @@
+package lessoncontent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlighterRender(t *testing.T) {
+	h, err := NewHighlighter("github")
+	if err != nil {
+		t.Fatalf("NewHighlighter() error = %v", err)
+	}
+
+	t.Run("go snippet", func(t *testing.T) {
+		out := h.Render(Block{Type: BlockCode, Data: map[string]string{
+			"language": "Go",
+			"code":     "package main\n\nfunc main() { println(\"<b>\") }\n",
+		}})
+
+		for _, s := range []string{
+			`<pre class="lesson-block lesson-block--code hl-chroma"><code class="language-go">`,
+			`class="hl-kn">package</span>`,
+			`class="hl-kd">func</span>`,
+			`&lt;b&gt;`,
+			`</code></pre>`,
+		} {
+			if !strings.Contains(out, s) {
+				t.Errorf("Render() = %q, want it to contain %q", out, s)
+			}
+		}
+		if strings.Contains(out, "<b>") || strings.Contains(out, "style=") {
+			t.Errorf("Render() = %q, want escaped code and classes without inline styles", out)
+		}
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		block := Block{Type: BlockCode, Data: map[string]string{"language": "no-such-language", "code": "a < b"}}
+		want := `<pre class="lesson-block lesson-block--code"><code class="language-no-such-language">a &lt; b</code></pre>`
+		if out := h.Render(block); out != want {
+			t.Errorf("Render() = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("missing language", func(t *testing.T) {
+		block := Block{Type: BlockCode, Data: map[string]string{"code": "plain"}}
+		want := `<pre class="lesson-block lesson-block--code"><code>plain</code></pre>`
+		if out := h.Render(block); out != want {
+			t.Errorf("Render() = %q, want %q", out, want)
+		}
+	})
+}
+
+func TestNewHighlighterTheme(t *testing.T) {
+	h, err := NewHighlighter("Monokai")
+	if err != nil {
+		t.Fatalf("NewHighlighter(Monokai) error = %v", err)
+	}
+	css, err := h.CSS()
+	if err != nil {
+		t.Fatalf("CSS() error = %v", err)
+	}
+	if !strings.Contains(css, ".hl-chroma") {
+		t.Errorf("CSS() = %q, want rules for the hl- class prefix", css)
+	}
+
+	if _, err := NewHighlighter("no-such-theme"); err == nil {
+		t.Error("NewHighlighter(no-such-theme) error = nil, want error")
+	}
+}
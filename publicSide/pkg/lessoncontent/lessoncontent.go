@@ -0,0 +1,33 @@
+// Package lessoncontent предоставляет декодирование контента уроков, хранящегося в БД.
+package lessoncontent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// gzipMagic - первые байты gzip-потока. Панель администратора может сохранять контент
+// в сжатом виде; по этим байтам он отличается от обычного текста в UTF-8.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decode восстанавливает текст контента урока из байтов, прочитанных из БД.
+// Сжатый gzip контент распаковывается, обычный возвращается как есть, NULL - как пустая строка.
+func Decode(raw []byte) (string, error) {
+	if !bytes.HasPrefix(raw, gzipMagic) {
+		return string(raw), nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress lesson content: %w", err)
+	}
+	defer zr.Close()
+
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress lesson content: %w", err)
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,40 @@
+package lessoncontent
+
+import "github.com/microcosm-cc/bluemonday"
+
+// Политики разметки HTML-контента уроков. Политика задается для курса в панели администратора
+// (content_policy) и определяет, какая разметка сохраняется при отображении его уроков.
+const (
+	// PolicyStrict допускает базовое форматирование пользовательского контента: текст, списки,
+	// таблицы, ссылки и изображения без классов и стилей.
+	PolicyStrict = "strict"
+	// PolicyRelaxed дополнительно допускает классы, data-атрибуты и элементы details, summary, mark,
+	// kbd, samp, figure и figcaption - для курсов, которым нужна более богатая разметка.
+	PolicyRelaxed = "relaxed"
+)
+
+// Политики bluemonday создаются один раз: после настройки они безопасны для конкурентного использования.
+var (
+	strictPolicy  = bluemonday.UGCPolicy()
+	relaxedPolicy = newRelaxedPolicy()
+)
+
+// newRelaxedPolicy расширяет strict-политику. Скрипты, обработчики событий, атрибут style
+// и встраивание внешних страниц остаются запрещены.
+func newRelaxedPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowStyling()
+	p.AllowDataAttributes()
+	p.AllowElements("details", "summary", "mark", "kbd", "samp", "var", "figure", "figcaption")
+	p.AllowAttrs("open").OnElements("details")
+	return p
+}
+
+// Sanitize очищает HTML-контент урока по политике policy.
+// Неизвестная или пустая политика обрабатывается как PolicyStrict.
+func Sanitize(content, policy string) string {
+	if policy == PolicyRelaxed {
+		return relaxedPolicy.Sanitize(content)
+	}
+	return strictPolicy.Sanitize(content)
+}
@@ -0,0 +1,92 @@
+package lessoncontent
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// WordsPerMinute - средняя скорость чтения, по которой оценивается время чтения урока.
+const WordsPerMinute = 200
+
+// Stats содержит объем текста урока и оценку времени его чтения.
+type Stats struct {
+	Length             int // Количество символов (рун) текста.
+	ReadingTimeMinutes int // Время чтения в минутах при WordsPerMinute, не меньше 1.
+}
+
+// textPolicy удаляет всю разметку из HTML-контента, оставляя только текст.
+var textPolicy = bluemonday.StrictPolicy()
+
+// Measure подсчитывает объем текста урока и время его чтения.
+// Учитывается только текст: в структурированном контенте - блоки типа text,
+// в HTML - содержимое без тегов; код, изображения и видео не учитываются.
+func Measure(content string) Stats {
+	text := PlainText(content)
+	minutes := (len(strings.Fields(text)) + WordsPerMinute - 1) / WordsPerMinute
+	return Stats{
+		Length:             utf8.RuneCountInString(text),
+		ReadingTimeMinutes: max(minutes, 1),
+	}
+}
+
+// PlainText извлекает текст из контента урока в любом из поддерживаемых форматов:
+// документ {"blocks": [...]} (см. ParseDocument), JSON-массив блоков панели администратора
+// [{"content_type": "text", "data": "..."}] или HTML/обычный текст. Тексты блоков разделяются переводом строки.
+func PlainText(content string) string {
+	if doc, ok := ParseDocument(content); ok {
+		var texts []string
+		for _, block := range doc.Blocks {
+			if block.Type == BlockText {
+				texts = append(texts, block.Data["text"])
+			}
+		}
+		return strings.TrimSpace(strings.Join(texts, "\n"))
+	}
+
+	if texts, ok := adminTextBlocks(content); ok {
+		return strings.TrimSpace(strings.Join(texts, "\n"))
+	}
+
+	return strings.TrimSpace(html.UnescapeString(textPolicy.Sanitize(content)))
+}
+
+// adminTextBlocks возвращает тексты блоков типа text из JSON-массива блоков, в котором
+// панель администратора сохраняет структурированный контент. Данные текстового блока - строка
+// или объект с полем text. Возвращает false, если контент не является таким массивом.
+func adminTextBlocks(content string) ([]string, bool) {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+
+	var blocks []struct {
+		ContentType string          `json:"content_type"`
+		Data        json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(trimmed), &blocks); err != nil {
+		return nil, false
+	}
+
+	var texts []string
+	for _, block := range blocks {
+		if block.ContentType != BlockText {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(block.Data, &text); err != nil {
+			var data struct {
+				Text string `json:"text"`
+			}
+			if json.Unmarshal(block.Data, &data) != nil {
+				continue
+			}
+			text = data.Text
+		}
+		texts = append(texts, text)
+	}
+	return texts, true
+}
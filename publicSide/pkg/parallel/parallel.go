@@ -0,0 +1,28 @@
+// Package parallel предоставляет ограниченное параллельное выполнение задач в рамках одного запроса.
+package parallel
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Run вызывает fn для каждого индекса от 0 до n-1, выполняя одновременно не более limit вызовов.
+// Ограничение не дает одному запросу с множеством независимых обращений к БД занять весь пул соединений.
+// При limit < 1 вызовы выполняются последовательно.
+// После первой ошибки контекст, переданный в fn, отменяется, а оставшиеся вызовы не запускаются.
+// Возвращает первую ошибку.
+func Run(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(limit, 1))
+
+	for i := range n {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return fn(ctx, i)
+		})
+	}
+	return g.Wait()
+}
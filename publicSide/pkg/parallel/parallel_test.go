@@ -0,0 +1,66 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunCapsConcurrency(t *testing.T) {
+	for _, limit := range []int{0, 1, 3} {
+		var inFlight, peak atomic.Int32
+		var calls atomic.Int32
+
+		err := Run(context.Background(), 12, limit, func(ctx context.Context, i int) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				old := peak.Load()
+				if current <= old || peak.CompareAndSwap(old, current) {
+					break
+				}
+			}
+			calls.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Run(limit=%d) error = %v", limit, err)
+		}
+
+		want := int32(max(limit, 1))
+		if got := peak.Load(); got != want {
+			t.Errorf("Run(limit=%d) peak concurrency = %d, want %d", limit, got, want)
+		}
+		if got := calls.Load(); got != 12 {
+			t.Errorf("Run(limit=%d) calls = %d, want 12", limit, got)
+		}
+	}
+}
+
+func TestRunStopsAfterFirstError(t *testing.T) {
+	errBoom := errors.New("boom")
+	var mu sync.Mutex
+	started := map[int]bool{}
+
+	err := Run(context.Background(), 10, 1, func(ctx context.Context, i int) error {
+		mu.Lock()
+		started[i] = true
+		mu.Unlock()
+		if i == 2 {
+			return errBoom
+		}
+		return nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Run() error = %v, want %v", err, errBoom)
+	}
+	for i := 3; i < 10; i++ {
+		if started[i] {
+			t.Errorf("task %d started after the error, want it skipped", i)
+		}
+	}
+}
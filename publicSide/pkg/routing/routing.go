@@ -13,6 +13,8 @@ const (
 	PathVariableCategoryID = "category_id" // Имя переменной для ID категории.
 	PathVariableCourseID   = "course_id"   // Имя переменной для ID курса.
 	PathVariableLessonID   = "lesson_id"   // Имя переменной для ID урока.
+	PathVariableCourseSlug = "slug"        // Имя переменной для slug курса.
+	PathVariableLessonSlug = "lesson_slug" // Имя переменной для slug урока.
 )
 
 // --- Route Definitions (для шаблонов Fiber `app.Get` и `app.Group`) ---
@@ -27,6 +29,9 @@ const (
 	RouteAuthCallback = "/auth/callback"
 	RouteReg          = "/reg"
 
+	// Таблица стилей подсветки кода, генерируемая для настроенной темы
+	RouteCodeHighlightCSS = "/assets/code-highlight.css"
+
 	// Внешние сервисы
 	ExternalServiceRouteProfile = "http://localhost/account/profile"
 
@@ -34,12 +39,16 @@ const (
 	RouteAPIV1 = "/api/v1"
 
 	// Ресурсы
-	RouteCategories = "/categories"
-	RouteCategory   = "/categories/:" + PathVariableCategoryID
-	RouteCourses    = "/categories/:" + PathVariableCategoryID + "/courses"
-	RouteCourse     = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID
-	RouteLessons    = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID + "/lessons"
-	RouteLesson     = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID + "/lessons/:" + PathVariableLessonID
+	RouteCategories   = "/categories"
+	RouteRecent       = "/recent"
+	RouteCategory     = "/categories/:" + PathVariableCategoryID
+	RouteCourses      = "/categories/:" + PathVariableCategoryID + "/courses"
+	RouteCoursesBatch = "/courses/batch"
+	RouteCourse       = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID
+	RouteCourseSlug   = "/categories/:" + PathVariableCategoryID + "/courses/slug/:" + PathVariableCourseSlug
+	RouteLessons      = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID + "/lessons"
+	RouteLesson       = "/categories/:" + PathVariableCategoryID + "/courses/:" + PathVariableCourseID + "/lessons/:" + PathVariableLessonID
+	RouteLessonSlug   = "/categories/:" + PathVariableCategoryID + "/courses/slug/:" + PathVariableCourseSlug + "/lessons/:" + PathVariableLessonSlug
 )
 
 // --- Path Constructors (для генерации URL в шаблонах, редиректах и т.д.) ---
@@ -57,6 +66,11 @@ func MakePathCategories() string {
 	return RouteCategories
 }
 
+// MakePathRecent создает путь к странице последних обновленных курсов.
+func MakePathRecent() string {
+	return RouteRecent
+}
+
 // MakePathCourses создает путь к странице курсов для указанной категории.
 func MakePathCourses(categoryID string) string {
 	return fmt.Sprintf("/categories/%s/courses", categoryID)
@@ -71,3 +85,8 @@ func MakePathCourse(categoryID, courseID string) string {
 func MakePathLesson(categoryID, courseID, lessonID string) string {
 	return fmt.Sprintf("%s/lessons/%s", MakePathCourse(categoryID, courseID), lessonID)
 }
+
+// MakePathLessonBySlug создает путь к странице урока по slug курса и slug урока.
+func MakePathLessonBySlug(categoryID, courseSlug, lessonSlug string) string {
+	return fmt.Sprintf("%s/slug/%s/lessons/%s", MakePathCourses(categoryID), courseSlug, lessonSlug)
+}
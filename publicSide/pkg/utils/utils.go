@@ -1,36 +1,48 @@
 // Package utils предоставляет общие вспомогательные функции, используемые в разных частях приложения.
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/TaurineMerge/LMS_Tages/publicSide/pkg/apperrors"
+)
 
 const (
 	AscendingDirection  = "ASC"  // Направление сортировки по возрастанию.
 	DescendingDirection = "DESC" // Направление сортировки по убыванию.
 )
 
-// UnpackSort разбирает строку `sortBy` для определения поля и направления сортировки.
+// ParseSort разбирает строку сортировки `input` для определения колонки и направления.
 //
-// `sortBy`: строка, задающая сортировку. Префикс "-" означает сортировку по убыванию (например, "-created_at").
-// `defaultColumn`: поле для сортировки по умолчанию, если `sortBy` пуста или недопустима.
-// `defaultDirection`: направление сортировки по умолчанию.
-// `allowedColumn`: карта с разрешенными для сортировки полями. Если пуста, проверка не производится.
+// `input`: строка, задающая сортировку. Префикс "-" означает сортировку по убыванию (например, "-created_at").
+// `allowed`: карта допустимых полей запроса и соответствующих им колонок SQL.
+// `defaultCol`: колонка для сортировки по умолчанию, если `input` пуста.
+// `defaultDir`: направление сортировки по умолчанию.
 //
-// Возвращает имя колонки и направление сортировки ("ASC" или "DESC").
-func UnpackSort(
-	sortBy string,
-	defaultColumn string,
-	defaultDirection string,
-	allowedColumn map[string]bool,
-) (string, string) {
-	if sortBy == "" || (len(allowedColumn) > 0 && !allowedColumn[sortBy]) {
-		return defaultColumn, defaultDirection
+// Возвращает колонку и направление ("ASC" или "DESC"). Для неизвестного поля возвращает
+// ошибку VALIDATION_ERROR (HTTP 400) с его именем и списком допустимых полей.
+func ParseSort(input string, allowed map[string]string, defaultCol, defaultDir string) (string, string, error) {
+	if input == "" {
+		return defaultCol, defaultDir, nil
 	}
 
-	direction := AscendingDirection
-	if strings.HasPrefix(sortBy, "-") {
-		direction = DescendingDirection
-		sortBy = strings.TrimPrefix(sortBy, "-")
+	field, desc := strings.CutPrefix(input, "-")
+	column, ok := allowed[field]
+	if !ok {
+		fields := make([]string, 0, len(allowed))
+		for name := range allowed {
+			fields = append(fields, name)
+		}
+		slices.Sort(fields)
+		return "", "", apperrors.NewValidation(fmt.Sprintf(
+			"Unsupported sort field '%s'. Allowed: %s", field, strings.Join(fields, ", "),
+		))
 	}
 
-	return sortBy, direction
+	if desc {
+		return column, DescendingDirection, nil
+	}
+	return column, AscendingDirection, nil
 }
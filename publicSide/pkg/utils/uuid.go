@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNilUUID возвращается в строгом режиме для нулевого UUID.
+	ErrNilUUID = errors.New("nil UUID is not allowed")
+	// ErrUnsupportedUUIDVersion возвращается в строгом режиме для UUID, отличных от версии 4.
+	ErrUnsupportedUUIDVersion = errors.New("only UUID version 4 is allowed")
+)
+
+// strictUUID определяет, включена ли строгая проверка UUID.
+var strictUUID atomic.Bool
+
+// SetStrictUUIDValidation включает или выключает строгую проверку UUID.
+// В строгом режиме допускаются только ненулевые UUID версии 4 (RFC 4122),
+// в мягком режиме — любая строка, которую удается распарсить как UUID.
+func SetStrictUUIDValidation(strict bool) {
+	strictUUID.Store(strict)
+}
+
+// ValidateUUID проверяет строку `s` на соответствие формату UUID
+// с учетом текущего режима проверки.
+func ValidateUUID(s string) error {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !strictUUID.Load() {
+		return nil
+	}
+	if id == uuid.Nil {
+		return ErrNilUUID
+	}
+	if id.Version() != 4 || id.Variant() != uuid.RFC4122 {
+		return ErrUnsupportedUUIDVersion
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	testUUIDv1 = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testUUIDv4 = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	testNilID  = "00000000-0000-0000-0000-000000000000"
+)
+
+func TestValidateUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		input   string
+		wantErr error
+		invalid bool
+	}{
+		{name: "lenient nil", input: testNilID},
+		{name: "lenient v1", input: testUUIDv1},
+		{name: "lenient v4", input: testUUIDv4},
+		{name: "lenient malformed", input: "not-a-uuid", invalid: true},
+		{name: "strict nil", strict: true, input: testNilID, wantErr: ErrNilUUID},
+		{name: "strict v1", strict: true, input: testUUIDv1, wantErr: ErrUnsupportedUUIDVersion},
+		{name: "strict v4", strict: true, input: testUUIDv4},
+		{name: "strict malformed", strict: true, input: "f47ac10b-58cc-4372-a567", invalid: true},
+	}
+
+	t.Cleanup(func() { SetStrictUUIDValidation(false) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetStrictUUIDValidation(tt.strict)
+
+			err := ValidateUUID(tt.input)
+			switch {
+			case tt.invalid:
+				if err == nil {
+					t.Errorf("ValidateUUID(%q) error = nil, want parse error", tt.input)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("ValidateUUID(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}